@@ -29,6 +29,7 @@ import (
 
 	"github.com/uber-go/tally"
 
+	"go.temporal.io/sdk/client"
 	"go.temporal.io/sdk/internal"
 	"go.temporal.io/sdk/log"
 )
@@ -61,7 +62,16 @@ func GetLogger(ctx context.Context) log.Logger {
 	return internal.GetActivityLogger(ctx)
 }
 
-// GetMetricsScope returns a metrics scope that can be used in activity
+// GetMetricsHandler returns a metrics handler that can be used in activity
+func GetMetricsHandler(ctx context.Context) client.MetricsHandler {
+	return internal.GetActivityMetricsHandler(ctx)
+}
+
+// GetMetricsScope returns a metrics scope that can be used in activity,
+// reporting to the metrics handler. Histograms keep their buckets only if the client MetricsHandler was created with
+// client.NewTallyMetricsHandler, otherwise histogram values are reported as gauges and histogram durations as timers.
+//
+// Deprecated: use GetMetricsHandler instead.
 func GetMetricsScope(ctx context.Context) tally.Scope {
 	return internal.GetActivityMetricsScope(ctx)
 }
@@ -30,7 +30,9 @@ package client
 
 import (
 	"context"
+	"time"
 
+	"github.com/uber-go/tally"
 	commonpb "go.temporal.io/api/common/v1"
 	enumspb "go.temporal.io/api/enums/v1"
 	"go.temporal.io/api/workflowservice/v1"
@@ -53,6 +55,28 @@ const (
 	// QueryTypeOpenSessions is the build in query type for Client.QueryWorkflow() call. Use this query type to get all open
 	// sessions in the workflow. The result will be a list of SessionInfo encoded in the converter.EncodedValue.
 	QueryTypeOpenSessions string = internal.QueryTypeOpenSessions
+
+	// ListWorkflowSourceList lists workflow executions using Client.ListWorkflow.
+	ListWorkflowSourceList = internal.ListWorkflowSourceList
+
+	// ListWorkflowSourceScan lists workflow executions using Client.ScanWorkflow.
+	ListWorkflowSourceScan = internal.ListWorkflowSourceScan
+
+	// ListWorkflowSourceArchived lists archived workflow executions using Client.ListArchivedWorkflow.
+	ListWorkflowSourceArchived = internal.ListWorkflowSourceArchived
+
+	// BatchResetLastWorkflowTask resets a workflow execution to its last completed workflow task.
+	BatchResetLastWorkflowTask = internal.BatchResetLastWorkflowTask
+
+	// BatchResetFirstWorkflowTask resets a workflow execution to its first completed workflow task.
+	BatchResetFirstWorkflowTask = internal.BatchResetFirstWorkflowTask
+
+	// EndpointSelectionRoundRobin distributes calls between all the healthy endpoints listed in Options.HostPorts.
+	EndpointSelectionRoundRobin = internal.EndpointSelectionRoundRobin
+
+	// EndpointSelectionPriorityFailover sends calls to the first healthy endpoint listed in Options.HostPorts and
+	// fails over to the next one when the connection is lost.
+	EndpointSelectionPriorityFailover = internal.EndpointSelectionPriorityFailover
 )
 
 type (
@@ -62,21 +86,96 @@ type (
 	// ConnectionOptions are optional parameters that can be specified in ClientOptions
 	ConnectionOptions = internal.ConnectionOptions
 
+	// EndpointSelection defines how the client chooses between multiple endpoints.
+	EndpointSelection = internal.EndpointSelection
+
+	// ClientCertificateProvider provides a client certificate for every new TLS handshake with the server.
+	ClientCertificateProvider = internal.ClientCertificateProvider
+
+	// BearerTokenSource returns a new bearer token and its expiration time.
+	BearerTokenSource = internal.BearerTokenSource
+
+	// HeadersProvider returns a map of gRPC headers that should be used on every request.
+	HeadersProvider = internal.HeadersProvider
+
+	// MetricsHandler is a handler for metrics emitted by the SDK. Set it as Options.MetricsHandler.
+	MetricsHandler = internal.MetricsHandler
+
+	// MetricsCounter is an ever-increasing counter.
+	MetricsCounter = internal.MetricsCounter
+
+	// MetricsGauge can be set to any value.
+	MetricsGauge = internal.MetricsGauge
+
+	// MetricsTimer records the distribution of durations.
+	MetricsTimer = internal.MetricsTimer
+
+	// PrometheusMetricsHandler is a MetricsHandler which exposes metrics in the Prometheus text exposition format.
+	PrometheusMetricsHandler = internal.PrometheusMetricsHandler
+
+	// PrometheusMetricsHandlerOptions are optional parameters for NewPrometheusMetricsHandler.
+	PrometheusMetricsHandlerOptions = internal.PrometheusMetricsHandlerOptions
+
 	// StartWorkflowOptions configuration parameters for starting a workflow execution.
 	StartWorkflowOptions = internal.StartWorkflowOptions
 
 	// HistoryEventIterator is a iterator which can return history events.
 	HistoryEventIterator = internal.HistoryEventIterator
 
+	// ListWorkflowOptions configuration parameters for listing workflow executions.
+	ListWorkflowOptions = internal.ListWorkflowOptions
+
+	// ListWorkflowSource defines visibility API used to list workflow executions.
+	ListWorkflowSource = internal.ListWorkflowSource
+
+	// WorkflowExecutionIterator is a iterator which can return workflow executions.
+	WorkflowExecutionIterator = internal.WorkflowExecutionIterator
+
+	// WorkflowExecutionInfo is a workflow execution with decoded memo and search attributes.
+	WorkflowExecutionInfo = internal.WorkflowExecutionInfo
+
+	// VisibilityQuery is a builder of visibility queries.
+	VisibilityQuery = internal.VisibilityQuery
+
+	// BatchOperationOptions configuration parameters for Client.ExecuteBatchOperation.
+	BatchOperationOptions = internal.BatchOperationOptions
+
+	// BatchOperation is an operation applied to every workflow execution selected by a batch.
+	BatchOperation = internal.BatchOperation
+
+	// BatchOperationProgress is reported to BatchOperationOptions.OnProgress.
+	BatchOperationProgress = internal.BatchOperationProgress
+
+	// BatchOperationReport is the result of Client.ExecuteBatchOperation.
+	BatchOperationReport = internal.BatchOperationReport
+
+	// BatchOperationFailure is a failure of the batch operation for a single workflow execution.
+	BatchOperationFailure = internal.BatchOperationFailure
+
+	// BatchResetType defines the event a workflow execution is reset to by NewBatchResetOperation.
+	BatchResetType = internal.BatchResetType
+
 	// WorkflowRun represents a started non child workflow.
 	WorkflowRun = internal.WorkflowRun
 
+	// WorkflowExecutionDescription is a workflow execution returned by WorkflowRun.Describe.
+	WorkflowExecutionDescription = internal.WorkflowExecutionDescription
+
+	// PendingActivityInfo is an activity of a described workflow execution which hasn't completed yet.
+	PendingActivityInfo = internal.PendingActivityInfo
+
+	// PendingChildExecutionInfo is a child workflow of a described workflow execution which hasn't completed yet.
+	PendingChildExecutionInfo = internal.PendingChildExecutionInfo
+
 	// QueryWorkflowWithOptionsRequest defines the request to QueryWorkflowWithOptions.
 	QueryWorkflowWithOptionsRequest = internal.QueryWorkflowWithOptionsRequest
 
 	// QueryWorkflowWithOptionsResponse defines the response to QueryWorkflowWithOptions.
 	QueryWorkflowWithOptionsResponse = internal.QueryWorkflowWithOptionsResponse
 
+	// UpdateWorkflowWithOptionsRequest defines the request to UpdateWorkflowWithOptions.
+	UpdateWorkflowWithOptionsRequest = internal.UpdateWorkflowWithOptionsRequest
+
 	// Client is the client for starting and getting information about a workflow executions as well as
 	// completing activities asynchronously.
 	Client interface {
@@ -290,6 +389,28 @@ type (
 		//  - InternalServiceError
 		CountWorkflow(ctx context.Context, request *workflowservice.CountWorkflowExecutionsRequest) (*workflowservice.CountWorkflowExecutionsResponse, error)
 
+		// ListWorkflowExecutions returns an iterator over workflow executions matching options.Query.
+		// Next pages are requested from the server as the iterator advances, so callers don't need to deal
+		// with page tokens. Memo and search attributes of the returned executions are decoded.
+		// Use NewVisibilityQuery to build the query. Supported queries depend on options.Source
+		// (see ListWorkflow, ScanWorkflow and ListArchivedWorkflow).
+		// The errors iterator can return:
+		//  - BadRequestError
+		//  - InternalServiceError
+		ListWorkflowExecutions(ctx context.Context, options ListWorkflowOptions) WorkflowExecutionIterator
+
+		// CountWorkflowExecutions gets number of workflow executions matching the query (see CountWorkflow).
+		// The errors it can return:
+		//  - BadRequestError
+		//  - InternalServiceError
+		CountWorkflowExecutions(ctx context.Context, query string) (int64, error)
+
+		// ExecuteBatchOperation applies the operation to all workflow executions matching the query in
+		// options. The operation is applied with bounded concurrency and rate. The call blocks until all
+		// matching executions are processed and returns the report with executions the operation failed for.
+		// An error is returned with the partial report if listing the executions fails or ctx is canceled.
+		ExecuteBatchOperation(ctx context.Context, options BatchOperationOptions) (*BatchOperationReport, error)
+
 		// GetSearchAttributes returns valid search attributes keys and value types.
 		// The search attributes can be used in query of List/Scan/Count APIs. Adding new search attributes requires temporal server
 		// to update dynamic config ValidSearchAttributes.
@@ -315,6 +436,28 @@ type (
 		//  - QueryFailError
 		QueryWorkflow(ctx context.Context, workflowID string, runID string, queryType string, args ...interface{}) (converter.EncodedValue, error)
 
+		// UpdateWorkflow sends an update request to a running workflow execution and waits for the result returned by
+		// the update handler registered with workflow.SetUpdateHandler. The request is delivered as a signal and the
+		// result is polled with a query, so updates work with any server version. The call blocks until the update
+		// completes, the workflow execution is closed or ctx is done, so ctx should have a deadline. The workflow keeps
+		// the results of the last 1000 completed updates only, so the result of an update has to be waited for before
+		// that many newer updates complete. Use UpdateWorkflowWithOptions to set the update ID, so the call can be
+		// retried without running the update twice.
+		// - workflowID is required.
+		// - runID can be default(empty string). if empty string then it will pick the running execution of that workflow ID.
+		// - updateName is the name the handler is registered with.
+		// - args... are the optional update parameters.
+		// The errors it can return:
+		//  - EntityNotExistError
+		//  - QueryFailError
+		//  - error returned by the update handler
+		UpdateWorkflow(ctx context.Context, workflowID string, runID string, updateName string, args ...interface{}) (converter.EncodedValue, error)
+
+		// UpdateWorkflowWithOptions sends an update request to a running workflow execution and waits for the result
+		// returned by the update handler. See UpdateWorkflowWithOptionsRequest and UpdateWorkflow for more
+		// information.
+		UpdateWorkflowWithOptions(ctx context.Context, request *UpdateWorkflowWithOptionsRequest) (converter.EncodedValue, error)
+
 		// QueryWorkflowWithOptions queries a given workflow execution and returns the query result synchronously.
 		// See QueryWorkflowWithOptionsRequest and QueryWorkflowWithOptionsResponse for more information.
 		// The errors it can return:
@@ -392,6 +535,76 @@ func NewNamespaceClient(options Options) (NamespaceClient, error) {
 	return internal.NewNamespaceClient(options)
 }
 
+// NewNamespaceClientFromClient creates an instance of a namespace client which shares the gRPC connection with
+// the client created by NewClient. The connection is closed when both clients are closed.
+func NewNamespaceClientFromClient(c Client) (NamespaceClient, error) {
+	return internal.NewNamespaceClientFromClient(c)
+}
+
+// NewFileClientCertificateProvider creates ClientCertificateProvider which reads a certificate from PEM encoded
+// certFile and keyFile and reloads it when the files are modified. Use it as ConnectionOptions.ClientCertificateProvider
+// to rotate the client certificate without restarting the client and workers.
+func NewFileClientCertificateProvider(certFile, keyFile string, checkInterval time.Duration) (ClientCertificateProvider, error) {
+	return internal.NewFileClientCertificateProvider(certFile, keyFile, checkInterval)
+}
+
+// NewBearerTokenHeadersProvider creates a headers provider which sets "authorization" header to the bearer token
+// returned by the source and refreshes the token refreshBefore it expires. Use it as Options.HeadersProvider.
+func NewBearerTokenHeadersProvider(source BearerTokenSource, refreshBefore time.Duration) HeadersProvider {
+	return internal.NewBearerTokenHeadersProvider(source, refreshBefore)
+}
+
+// MetricsNopHandler is a MetricsHandler that does nothing.
+var MetricsNopHandler = internal.MetricsNopHandler
+
+// NewTallyMetricsHandler creates a MetricsHandler which reports metrics to the given tally scope.
+func NewTallyMetricsHandler(scope tally.Scope) MetricsHandler {
+	return internal.NewTallyMetricsHandler(scope)
+}
+
+// NewPrometheusMetricsHandler creates a MetricsHandler which keeps metrics in memory. It is an http.Handler that
+// serves them in the Prometheus text exposition format, for example:
+//   handler := NewPrometheusMetricsHandler(PrometheusMetricsHandlerOptions{})
+//   http.Handle("/metrics", handler)
+//   c, err := NewClient(Options{MetricsHandler: handler})
+// It is a standalone exporter which doesn't use the Prometheus client library, so its metrics can't be registered
+// with a prometheus.Registerer. To add SDK metrics to an existing registry, use NewTallyMetricsHandler with a scope
+// reporting through the Prometheus reporter of tally instead:
+//   reporter := tallyprom.NewReporter(tallyprom.Options{Registerer: registry})
+//   scope, closer := tally.NewRootScope(tally.ScopeOptions{CachedReporter: reporter, Separator: tallyprom.DefaultSeparator}, time.Second)
+//   c, err := NewClient(Options{MetricsHandler: NewTallyMetricsHandler(scope)})
+func NewPrometheusMetricsHandler(options PrometheusMetricsHandlerOptions) *PrometheusMetricsHandler {
+	return internal.NewPrometheusMetricsHandler(options)
+}
+
+// NewVisibilityQuery creates a builder of visibility queries for Client.ListWorkflowExecutions and
+// Client.CountWorkflowExecutions. For example:
+//   query := NewVisibilityQuery().WorkflowType("orderWorkflow").Open().String()
+func NewVisibilityQuery() *VisibilityQuery {
+	return internal.NewVisibilityQuery()
+}
+
+// NewBatchSignalOperation creates a BatchOperation which signals workflow executions.
+func NewBatchSignalOperation(signalName string, arg interface{}) BatchOperation {
+	return internal.NewBatchSignalOperation(signalName, arg)
+}
+
+// NewBatchCancelOperation creates a BatchOperation which requests cancellation of workflow executions.
+func NewBatchCancelOperation() BatchOperation {
+	return internal.NewBatchCancelOperation()
+}
+
+// NewBatchTerminateOperation creates a BatchOperation which terminates workflow executions.
+func NewBatchTerminateOperation(reason string, details ...interface{}) BatchOperation {
+	return internal.NewBatchTerminateOperation(reason, details...)
+}
+
+// NewBatchResetOperation creates a BatchOperation which resets workflow executions to the completed workflow task
+// defined by resetType.
+func NewBatchResetOperation(reason string, resetType BatchResetType) BatchOperation {
+	return internal.NewBatchResetOperation(reason, resetType)
+}
+
 // make sure if new methods are added to internal.Client they are also added to public Client.
 var _ Client = internal.Client(nil)
 var _ internal.Client = Client(nil)
@@ -0,0 +1,64 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package opentelemetry provides OpenTelemetry tracing and metrics support for the Temporal Go SDK.
+//
+// Tracing is enabled by registering the interceptor returned by NewTracingInterceptor with the client and the
+// worker, and the propagator returned by NewContextPropagator with the client:
+//
+//  tracing := opentelemetry.NewTracingInterceptor(opentelemetry.TracerOptions{})
+//  c, err := client.NewClient(client.Options{
+//      Interceptors:       []interceptors.ClientInterceptor{tracing},
+//      ContextPropagators: []workflow.ContextPropagator{opentelemetry.NewContextPropagator(opentelemetry.TracerOptions{})},
+//  })
+//  w := worker.New(c, "task-queue", worker.Options{
+//      WorkflowInterceptorChainFactories: []interceptors.WorkflowInterceptor{tracing},
+//      ActivityInterceptorChainFactories: []interceptors.ActivityInterceptor{tracing},
+//  })
+//
+// The following spans are created:
+//  StartWorkflow:<type>, SignalWorkflow:<name> and SignalWithStartWorkflow:<type> by the client,
+//  RunWorkflow:<type> and HandleSignal:<name> by the workflow worker,
+//  StartActivity:<type>, StartLocalActivity:<type>, StartChildWorkflow:<type> and SignalExternalWorkflow:<name> by
+//  the workflow code,
+//  RunActivity:<type> by the activity worker.
+//
+// Trace context is propagated in W3C Trace Context format through workflow and activity headers. Signals do not
+// carry headers, so signal spans are parented to the span active in the caller only, not linked across processes.
+// Spans created from workflow code are only emitted when the workflow is not replaying, so a workflow that is
+// replayed after a cache eviction or worker restart does not produce duplicate spans.
+//
+// The RunWorkflow span covers execution of the run by the worker which caches it, rather than the whole run. When the
+// run is evicted from the worker cache before it completes, its span is ended at eviction and has the
+// temporal.workflow.evicted attribute set. The worker which rebuilds the run by replaying its history doesn't create
+// another RunWorkflow span, so spans created by the workflow code afterwards are parented to the span which started the
+// workflow, the one carried in the workflow header.
+//
+// Metrics are reported through an OpenTelemetry Meter by passing the handler returned by NewMetricsHandler as
+// client.Options.MetricsHandler:
+//
+//  c, err := client.NewClient(client.Options{
+//      MetricsHandler: opentelemetry.NewMetricsHandler(otel.Meter("temporal")),
+//  })
+package opentelemetry
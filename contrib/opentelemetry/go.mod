@@ -0,0 +1,16 @@
+module go.temporal.io/sdk/contrib/opentelemetry
+
+go 1.20
+
+require (
+	github.com/stretchr/testify v1.8.4
+	go.opentelemetry.io/otel v1.24.0
+	go.opentelemetry.io/otel/metric v1.24.0
+	go.opentelemetry.io/otel/sdk v1.24.0
+	go.opentelemetry.io/otel/sdk/metric v1.24.0
+	go.opentelemetry.io/otel/trace v1.24.0
+	go.temporal.io/api v1.4.1-0.20210420220407-6f00f7f98373
+	go.temporal.io/sdk v1.8.0
+)
+
+replace go.temporal.io/sdk => ../../
@@ -0,0 +1,218 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package opentelemetry
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"go.temporal.io/sdk/client"
+)
+
+type (
+	// metricsHandler implements client.MetricsHandler on top of an OpenTelemetry Meter. Counters are reported as
+	// Int64Counter, timers as Float64Histogram in seconds and gauges as Float64ObservableGauge. Tags are reported
+	// as attributes.
+	metricsHandler struct {
+		instruments *instruments
+		tags        map[string]string
+		attributes  attribute.Set
+	}
+
+	// instruments is shared by a handler and all handlers created from it, as OpenTelemetry instruments are
+	// identified by name and attributes are passed on every measurement.
+	instruments struct {
+		meter metric.Meter
+
+		sync.Mutex
+		counters   map[string]metric.Int64Counter
+		histograms map[string]metric.Float64Histogram
+		gauges     map[string]*gaugeValues
+	}
+
+	// gaugeValues holds the last value set for every attribute set of a gauge until it is observed.
+	gaugeValues struct {
+		sync.Mutex
+		values map[attribute.Distinct]gaugeValue
+	}
+
+	gaugeValue struct {
+		attributes attribute.Set
+		value      float64
+	}
+
+	counter struct {
+		counter    metric.Int64Counter
+		attributes metric.MeasurementOption
+	}
+
+	gauge struct {
+		values     *gaugeValues
+		attributes attribute.Set
+	}
+
+	timer struct {
+		histogram  metric.Float64Histogram
+		attributes metric.MeasurementOption
+	}
+)
+
+var _ client.MetricsHandler = (*metricsHandler)(nil)
+
+// NewMetricsHandler returns a client.MetricsHandler that reports all metrics emitted by the SDK through the given
+// OpenTelemetry Meter. Set it as client.Options.MetricsHandler.
+func NewMetricsHandler(meter metric.Meter) client.MetricsHandler {
+	return &metricsHandler{
+		instruments: &instruments{
+			meter:      meter,
+			counters:   make(map[string]metric.Int64Counter),
+			histograms: make(map[string]metric.Float64Histogram),
+			gauges:     make(map[string]*gaugeValues),
+		},
+		tags:       map[string]string{},
+		attributes: attribute.NewSet(),
+	}
+}
+
+func (h *metricsHandler) WithTags(tags map[string]string) client.MetricsHandler {
+	merged := make(map[string]string, len(h.tags)+len(tags))
+	for k, v := range h.tags {
+		merged[k] = v
+	}
+	for k, v := range tags {
+		merged[k] = v
+	}
+	return &metricsHandler{
+		instruments: h.instruments,
+		tags:        merged,
+		attributes:  toAttributeSet(merged),
+	}
+}
+
+func (h *metricsHandler) Counter(name string) client.MetricsCounter {
+	c, err := h.instruments.counter(name)
+	if err != nil {
+		otel.Handle(err)
+		return client.MetricsNopHandler.Counter(name)
+	}
+	return &counter{counter: c, attributes: metric.WithAttributeSet(h.attributes)}
+}
+
+func (h *metricsHandler) Gauge(name string) client.MetricsGauge {
+	values, err := h.instruments.gauge(name)
+	if err != nil {
+		otel.Handle(err)
+		return client.MetricsNopHandler.Gauge(name)
+	}
+	return &gauge{values: values, attributes: h.attributes}
+}
+
+func (h *metricsHandler) Timer(name string) client.MetricsTimer {
+	hist, err := h.instruments.histogram(name)
+	if err != nil {
+		otel.Handle(err)
+		return client.MetricsNopHandler.Timer(name)
+	}
+	return &timer{histogram: hist, attributes: metric.WithAttributeSet(h.attributes)}
+}
+
+func (i *instruments) counter(name string) (metric.Int64Counter, error) {
+	i.Lock()
+	defer i.Unlock()
+	if c, ok := i.counters[name]; ok {
+		return c, nil
+	}
+	c, err := i.meter.Int64Counter(name)
+	if err != nil {
+		return nil, err
+	}
+	i.counters[name] = c
+	return c, nil
+}
+
+func (i *instruments) histogram(name string) (metric.Float64Histogram, error) {
+	i.Lock()
+	defer i.Unlock()
+	if h, ok := i.histograms[name]; ok {
+		return h, nil
+	}
+	h, err := i.meter.Float64Histogram(name)
+	if err != nil {
+		return nil, err
+	}
+	i.histograms[name] = h
+	return h, nil
+}
+
+func (i *instruments) gauge(name string) (*gaugeValues, error) {
+	i.Lock()
+	defer i.Unlock()
+	if g, ok := i.gauges[name]; ok {
+		return g, nil
+	}
+	g := &gaugeValues{values: make(map[attribute.Distinct]gaugeValue)}
+	_, err := i.meter.Float64ObservableGauge(name, metric.WithFloat64Callback(g.observe))
+	if err != nil {
+		return nil, err
+	}
+	i.gauges[name] = g
+	return g, nil
+}
+
+func (g *gaugeValues) observe(_ context.Context, observer metric.Float64Observer) error {
+	g.Lock()
+	defer g.Unlock()
+	for _, v := range g.values {
+		observer.Observe(v.value, metric.WithAttributeSet(v.attributes))
+	}
+	return nil
+}
+
+func (c *counter) Inc(delta int64) {
+	c.counter.Add(context.Background(), delta, c.attributes)
+}
+
+func (g *gauge) Update(value float64) {
+	g.values.Lock()
+	defer g.values.Unlock()
+	g.values.values[g.attributes.Equivalent()] = gaugeValue{attributes: g.attributes, value: value}
+}
+
+func (t *timer) Record(value time.Duration) {
+	t.histogram.Record(context.Background(), value.Seconds(), t.attributes)
+}
+
+func toAttributeSet(tags map[string]string) attribute.Set {
+	attributes := make([]attribute.KeyValue, 0, len(tags))
+	for k, v := range tags {
+		attributes = append(attributes, attribute.String(k, v))
+	}
+	return attribute.NewSet(attributes...)
+}
@@ -0,0 +1,115 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package opentelemetry
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func collectMetrics(t *testing.T, reader sdkmetric.Reader) map[string]metricdata.Aggregation {
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	result := make(map[string]metricdata.Aggregation)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			result[m.Name] = m.Data
+		}
+	}
+	return result
+}
+
+// dataPoint holds the fields of metric data points, which are generic types in the SDK.
+type dataPoint struct {
+	Attributes []struct {
+		Key   string
+		Value struct{ Value interface{} }
+	}
+	Value float64
+	Count uint64
+	Sum   float64
+}
+
+func (p dataPoint) attribute(key string) interface{} {
+	for _, a := range p.Attributes {
+		if a.Key == key {
+			return a.Value.Value
+		}
+	}
+	return nil
+}
+
+func dataPoints(t *testing.T, aggregation metricdata.Aggregation, typeName string) []dataPoint {
+	require.Equal(t, typeName, reflect.TypeOf(aggregation).Name())
+	data, err := json.Marshal(aggregation)
+	require.NoError(t, err)
+	var result struct{ DataPoints []dataPoint }
+	require.NoError(t, json.Unmarshal(data, &result))
+	return result.DataPoints
+}
+
+func TestMetricsHandler(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	meter := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test")
+
+	handler := NewMetricsHandler(meter)
+	tagged := handler.WithTags(map[string]string{"namespace": "default"})
+	tagged.Counter("temporal_request").Inc(2)
+	tagged.Counter("temporal_request").Inc(1)
+	tagged.Timer("temporal_request_latency").Record(time.Second)
+	tagged.Gauge("temporal_worker_task_slots_available").Update(5)
+	tagged.Gauge("temporal_worker_task_slots_available").Update(3)
+	tagged.WithTags(map[string]string{"namespace": "other"}).Counter("temporal_request").Inc(7)
+
+	metrics := collectMetrics(t, reader)
+	require.Len(t, metrics, 3)
+
+	counter := dataPoints(t, metrics["temporal_request"], "Sum[int64]")
+	require.Len(t, counter, 2)
+	for _, dp := range counter {
+		if dp.attribute("namespace") == "default" {
+			require.EqualValues(t, 3, dp.Value)
+		} else {
+			require.EqualValues(t, 7, dp.Value)
+		}
+	}
+
+	timer := dataPoints(t, metrics["temporal_request_latency"], "Histogram[float64]")
+	require.Len(t, timer, 1)
+	require.EqualValues(t, 1, timer[0].Count)
+	require.EqualValues(t, 1, timer[0].Sum)
+
+	gauge := dataPoints(t, metrics["temporal_worker_task_slots_available"], "Gauge[float64]")
+	require.Len(t, gauge, 1)
+	require.EqualValues(t, 3, gauge[0].Value)
+	require.Equal(t, "default", gauge[0].attribute("namespace"))
+}
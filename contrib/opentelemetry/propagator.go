@@ -0,0 +1,157 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package opentelemetry
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/workflow"
+)
+
+const (
+	// tracerHeaderKey is the header under which the trace context is stored. It differs from the key used by the
+	// opentracing propagator so that both can be enabled at the same time.
+	tracerHeaderKey = "_otel-tracer-data"
+
+	tracerName = "go.temporal.io/sdk"
+)
+
+type (
+	// TracerOptions are the options for NewTracingInterceptor and NewContextPropagator.
+	TracerOptions struct {
+		// Optional: Tracer that is used to create spans.
+		// default: otel.Tracer("go.temporal.io/sdk") of the global TracerProvider
+		Tracer trace.Tracer
+
+		// Optional: TextMapPropagator that is used to serialize the trace context into headers.
+		// default: propagation.TraceContext, the W3C Trace Context format
+		TextMapPropagator propagation.TextMapPropagator
+	}
+
+	// contextPropagator implements the workflow.ContextPropagator interface for OpenTelemetry trace context.
+	//
+	// Inject -> context.Context to Header - serializes the span context of the context into the header
+	// Extract -> Header to context.Context - returns a context.Context with the remote span context of the header
+	// InjectFromWorkflow -> Context to Header - serializes the span context stored in the workflow context
+	// ExtractToWorkflow -> Header to Context - stores the remote span context of the header in the workflow context
+	contextPropagator struct {
+		propagator propagation.TextMapPropagator
+	}
+
+	spanContextKey struct{}
+)
+
+var _ workflow.ContextPropagator = (*contextPropagator)(nil)
+
+// NewContextPropagator returns a context propagator that propagates OpenTelemetry trace context through workflow,
+// activity and child workflow headers.
+func NewContextPropagator(options TracerOptions) workflow.ContextPropagator {
+	options = options.withDefaults()
+	return &contextPropagator{propagator: options.TextMapPropagator}
+}
+
+func (o TracerOptions) withDefaults() TracerOptions {
+	if o.Tracer == nil {
+		o.Tracer = otel.Tracer(tracerName)
+	}
+	if o.TextMapPropagator == nil {
+		o.TextMapPropagator = propagation.TraceContext{}
+	}
+	return o
+}
+
+func (c *contextPropagator) Inject(ctx context.Context, hw workflow.HeaderWriter) error {
+	return c.writeToHeader(ctx, hw)
+}
+
+func (c *contextPropagator) Extract(ctx context.Context, hr workflow.HeaderReader) (context.Context, error) {
+	carrier, err := readFromHeader(hr)
+	if err != nil || carrier == nil {
+		return ctx, err
+	}
+	return c.propagator.Extract(ctx, carrier), nil
+}
+
+func (c *contextPropagator) InjectFromWorkflow(ctx workflow.Context, hw workflow.HeaderWriter) error {
+	spanContext := spanContextFromWorkflow(ctx)
+	if !spanContext.IsValid() {
+		return nil
+	}
+	return c.writeToHeader(trace.ContextWithSpanContext(context.Background(), spanContext), hw)
+}
+
+func (c *contextPropagator) ExtractToWorkflow(ctx workflow.Context, hr workflow.HeaderReader) (workflow.Context, error) {
+	carrier, err := readFromHeader(hr)
+	if err != nil || carrier == nil {
+		return ctx, err
+	}
+	spanContext := trace.SpanContextFromContext(c.propagator.Extract(context.Background(), carrier))
+	if !spanContext.IsValid() {
+		return ctx, nil
+	}
+	return workflowWithSpanContext(ctx, spanContext), nil
+}
+
+func (c *contextPropagator) writeToHeader(ctx context.Context, hw workflow.HeaderWriter) error {
+	carrier := propagation.MapCarrier{}
+	c.propagator.Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return nil
+	}
+	payload, err := converter.GetDefaultDataConverter().ToPayload(map[string]string(carrier))
+	if err != nil {
+		return err
+	}
+	hw.Set(tracerHeaderKey, payload)
+	return nil
+}
+
+func readFromHeader(hr workflow.HeaderReader) (propagation.MapCarrier, error) {
+	payload, ok := hr.Get(tracerHeaderKey)
+	if !ok {
+		return nil, nil
+	}
+	var tracerData map[string]string
+	if err := converter.GetDefaultDataConverter().FromPayload(payload, &tracerData); err != nil {
+		return nil, err
+	}
+	return propagation.MapCarrier(tracerData), nil
+}
+
+func spanContextFromWorkflow(ctx workflow.Context) trace.SpanContext {
+	if spanContext, ok := ctx.Value(spanContextKey{}).(trace.SpanContext); ok {
+		return spanContext
+	}
+	return trace.SpanContext{}
+}
+
+func workflowWithSpanContext(ctx workflow.Context, spanContext trace.SpanContext) workflow.Context {
+	return workflow.WithValue(ctx, spanContextKey{}, spanContext)
+}
@@ -0,0 +1,279 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package opentelemetry
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/interceptors"
+	"go.temporal.io/sdk/workflow"
+
+	commonpb "go.temporal.io/api/common/v1"
+)
+
+const (
+	workflowIDAttributeKey = "temporal.workflow.id"
+	runIDAttributeKey      = "temporal.run.id"
+	activityIDAttributeKey = "temporal.activity.id"
+	evictedAttributeKey    = "temporal.workflow.evicted"
+)
+
+type (
+	// TracingInterceptor creates OpenTelemetry spans for client, workflow and activity calls. The same instance is
+	// registered as client interceptor and as workflow and activity interceptor of the worker.
+	TracingInterceptor interface {
+		interceptors.ClientInterceptor
+		interceptors.WorkflowInterceptor
+		interceptors.ActivityInterceptor
+	}
+
+	tracingInterceptor struct {
+		tracer trace.Tracer
+	}
+
+	tracingClientOutboundInterceptor struct {
+		interceptors.ClientOutboundInterceptorBase
+		tracer trace.Tracer
+	}
+
+	tracingWorkflowInboundInterceptor struct {
+		interceptors.WorkflowInboundCallsInterceptorBase
+		tracer trace.Tracer
+		info   *workflow.Info
+	}
+
+	tracingWorkflowOutboundInterceptor struct {
+		interceptors.WorkflowOutboundCallsInterceptorBase
+		inbound *tracingWorkflowInboundInterceptor
+	}
+
+	tracingActivityInboundInterceptor struct {
+		interceptors.ActivityInboundCallsInterceptorBase
+		tracer trace.Tracer
+		info   *activity.Info
+	}
+)
+
+var _ TracingInterceptor = (*tracingInterceptor)(nil)
+
+// NewTracingInterceptor returns an interceptor that creates OpenTelemetry spans. NewContextPropagator must be
+// registered with the client as well for the spans to be connected across processes.
+func NewTracingInterceptor(options TracerOptions) TracingInterceptor {
+	options = options.withDefaults()
+	return &tracingInterceptor{tracer: options.Tracer}
+}
+
+func (t *tracingInterceptor) InterceptClient(next interceptors.ClientOutboundInterceptor) interceptors.ClientOutboundInterceptor {
+	return &tracingClientOutboundInterceptor{
+		ClientOutboundInterceptorBase: interceptors.ClientOutboundInterceptorBase{Next: next},
+		tracer:                        t.tracer,
+	}
+}
+
+func (t *tracingInterceptor) InterceptWorkflow(info *workflow.Info, next interceptors.WorkflowInboundCallsInterceptor) interceptors.WorkflowInboundCallsInterceptor {
+	return &tracingWorkflowInboundInterceptor{
+		WorkflowInboundCallsInterceptorBase: interceptors.WorkflowInboundCallsInterceptorBase{Next: next},
+		tracer:                              t.tracer,
+		info:                                info,
+	}
+}
+
+func (t *tracingInterceptor) InterceptActivity(info *activity.Info, next interceptors.ActivityInboundCallsInterceptor) interceptors.ActivityInboundCallsInterceptor {
+	return &tracingActivityInboundInterceptor{
+		ActivityInboundCallsInterceptorBase: interceptors.ActivityInboundCallsInterceptorBase{Next: next},
+		tracer:                              t.tracer,
+		info:                                info,
+	}
+}
+
+func (c *tracingClientOutboundInterceptor) ExecuteWorkflow(ctx context.Context, options client.StartWorkflowOptions, workflowFunc interface{}, args ...interface{}) (client.WorkflowRun, error) {
+	ctx, span := c.tracer.Start(ctx, "StartWorkflow:"+workflowTypeName(workflowFunc), trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	run, err := c.Next.ExecuteWorkflow(ctx, options, workflowFunc, args...)
+	if run != nil {
+		span.SetAttributes(attribute.String(workflowIDAttributeKey, run.GetID()), attribute.String(runIDAttributeKey, run.GetRunID()))
+	}
+	recordError(span, err)
+	return run, err
+}
+
+func (c *tracingClientOutboundInterceptor) SignalWorkflow(ctx context.Context, workflowID string, runID string, signalName string, arg interface{}) error {
+	ctx, span := c.tracer.Start(ctx, "SignalWorkflow:"+signalName, trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String(workflowIDAttributeKey, workflowID), attribute.String(runIDAttributeKey, runID)))
+	defer span.End()
+
+	err := c.Next.SignalWorkflow(ctx, workflowID, runID, signalName, arg)
+	recordError(span, err)
+	return err
+}
+
+func (c *tracingClientOutboundInterceptor) SignalWithStartWorkflow(ctx context.Context, workflowID string, signalName string, signalArg interface{},
+	options client.StartWorkflowOptions, workflowFunc interface{}, workflowArgs ...interface{}) (client.WorkflowRun, error) {
+	ctx, span := c.tracer.Start(ctx, "SignalWithStartWorkflow:"+workflowTypeName(workflowFunc), trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String(workflowIDAttributeKey, workflowID)))
+	defer span.End()
+
+	run, err := c.Next.SignalWithStartWorkflow(ctx, workflowID, signalName, signalArg, options, workflowFunc, workflowArgs...)
+	if run != nil {
+		span.SetAttributes(attribute.String(runIDAttributeKey, run.GetRunID()))
+	}
+	recordError(span, err)
+	return run, err
+}
+
+func (w *tracingWorkflowInboundInterceptor) Init(outbound interceptors.WorkflowOutboundCallsInterceptor) error {
+	return w.Next.Init(&tracingWorkflowOutboundInterceptor{
+		WorkflowOutboundCallsInterceptorBase: interceptors.WorkflowOutboundCallsInterceptorBase{Next: outbound},
+		inbound:                              w,
+	})
+}
+
+// ExecuteWorkflow creates the RunWorkflow span which covers execution of the run by the worker. No span is created
+// when the run is rebuilt by replaying its history, e.g. after a cache eviction, so spans created by the workflow code
+// afterwards are parented to the span carried in the workflow header, the one which started the workflow. If the run
+// is evicted before it completes, its span is ended at eviction and marked as evicted.
+func (w *tracingWorkflowInboundInterceptor) ExecuteWorkflow(ctx workflow.Context, workflowType string, args ...interface{}) []interface{} {
+	if workflow.IsReplaying(ctx) {
+		return w.Next.ExecuteWorkflow(ctx, workflowType, args...)
+	}
+	ctx, span := w.startSpan(ctx, "RunWorkflow:"+workflowType, trace.SpanKindServer)
+	completed := false
+	defer func() {
+		// Workflow coroutine is torn down without returning when the run is evicted from the cache.
+		if !completed {
+			span.SetAttributes(attribute.Bool(evictedAttributeKey, true))
+		}
+		span.End()
+	}()
+
+	results := w.Next.ExecuteWorkflow(ctx, workflowType, args...)
+	completed = true
+	if len(results) > 0 {
+		if err, ok := results[len(results)-1].(error); ok && !workflow.IsContinueAsNewError(err) {
+			recordError(span, err)
+		}
+	}
+	return results
+}
+
+func (w *tracingWorkflowInboundInterceptor) HandleSignal(ctx workflow.Context, signalName string, arg *commonpb.Payloads) error {
+	if workflow.IsReplaying(ctx) {
+		return w.Next.HandleSignal(ctx, signalName, arg)
+	}
+	ctx, span := w.startSpan(ctx, "HandleSignal:"+signalName, trace.SpanKindServer)
+	defer span.End()
+
+	err := w.Next.HandleSignal(ctx, signalName, arg)
+	recordError(span, err)
+	return err
+}
+
+// startSpan starts a span that is a child of the span stored in the workflow context and returns a workflow context
+// that carries the new span, so that it is propagated by calls made with that context.
+func (w *tracingWorkflowInboundInterceptor) startSpan(ctx workflow.Context, name string, kind trace.SpanKind) (workflow.Context, trace.Span) {
+	parent := trace.ContextWithSpanContext(context.Background(), spanContextFromWorkflow(ctx))
+	_, span := w.tracer.Start(parent, name, trace.WithSpanKind(kind), trace.WithAttributes(
+		attribute.String(workflowIDAttributeKey, w.info.WorkflowExecution.ID),
+		attribute.String(runIDAttributeKey, w.info.WorkflowExecution.RunID),
+	))
+	return workflowWithSpanContext(ctx, span.SpanContext()), span
+}
+
+func (t *tracingWorkflowOutboundInterceptor) ExecuteActivity(ctx workflow.Context, activityType string, args ...interface{}) workflow.Future {
+	if t.Next.IsReplaying(ctx) {
+		return t.Next.ExecuteActivity(ctx, activityType, args...)
+	}
+	ctx, span := t.inbound.startSpan(ctx, "StartActivity:"+activityType, trace.SpanKindClient)
+	defer span.End()
+	return t.Next.ExecuteActivity(ctx, activityType, args...)
+}
+
+func (t *tracingWorkflowOutboundInterceptor) ExecuteLocalActivity(ctx workflow.Context, activityType string, args ...interface{}) workflow.Future {
+	if t.Next.IsReplaying(ctx) {
+		return t.Next.ExecuteLocalActivity(ctx, activityType, args...)
+	}
+	ctx, span := t.inbound.startSpan(ctx, "StartLocalActivity:"+activityType, trace.SpanKindClient)
+	defer span.End()
+	return t.Next.ExecuteLocalActivity(ctx, activityType, args...)
+}
+
+func (t *tracingWorkflowOutboundInterceptor) ExecuteChildWorkflow(ctx workflow.Context, childWorkflowType string, args ...interface{}) workflow.ChildWorkflowFuture {
+	if t.Next.IsReplaying(ctx) {
+		return t.Next.ExecuteChildWorkflow(ctx, childWorkflowType, args...)
+	}
+	ctx, span := t.inbound.startSpan(ctx, "StartChildWorkflow:"+childWorkflowType, trace.SpanKindClient)
+	defer span.End()
+	return t.Next.ExecuteChildWorkflow(ctx, childWorkflowType, args...)
+}
+
+func (t *tracingWorkflowOutboundInterceptor) SignalExternalWorkflow(ctx workflow.Context, workflowID, runID, signalName string, arg interface{}) workflow.Future {
+	if t.Next.IsReplaying(ctx) {
+		return t.Next.SignalExternalWorkflow(ctx, workflowID, runID, signalName, arg)
+	}
+	ctx, span := t.inbound.startSpan(ctx, "SignalExternalWorkflow:"+signalName, trace.SpanKindClient)
+	defer span.End()
+	return t.Next.SignalExternalWorkflow(ctx, workflowID, runID, signalName, arg)
+}
+
+func (a *tracingActivityInboundInterceptor) ExecuteActivity(ctx context.Context, activityType string, args ...interface{}) (interface{}, error) {
+	ctx, span := a.tracer.Start(ctx, "RunActivity:"+activityType, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(
+		attribute.String(workflowIDAttributeKey, a.info.WorkflowExecution.ID),
+		attribute.String(runIDAttributeKey, a.info.WorkflowExecution.RunID),
+		attribute.String(activityIDAttributeKey, a.info.ActivityID),
+	))
+	defer span.End()
+
+	result, err := a.Next.ExecuteActivity(ctx, activityType, args...)
+	recordError(span, err)
+	return result, err
+}
+
+func recordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// workflowTypeName returns the workflow type the same way the client derives it from a workflow function.
+func workflowTypeName(workflowFunc interface{}) string {
+	if name, ok := workflowFunc.(string); ok {
+		return name
+	}
+	fullName := runtime.FuncForPC(reflect.ValueOf(workflowFunc).Pointer()).Name()
+	elements := strings.Split(fullName, ".")
+	return strings.TrimSuffix(elements[len(elements)-1], "-fm")
+}
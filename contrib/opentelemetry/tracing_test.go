@@ -0,0 +1,194 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package opentelemetry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/interceptors"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+
+	commonpb "go.temporal.io/api/common/v1"
+)
+
+type testHeader map[string]*commonpb.Payload
+
+func (h testHeader) Set(key string, value *commonpb.Payload) {
+	h[key] = value
+}
+
+func (h testHeader) Get(key string) (*commonpb.Payload, bool) {
+	value, ok := h[key]
+	return value, ok
+}
+
+func (h testHeader) ForEachKey(handler func(string, *commonpb.Payload) error) error {
+	for key, value := range h {
+		if err := handler(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func tracedWorkflow(ctx workflow.Context) error {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{StartToCloseTimeout: time.Minute})
+	return workflow.ExecuteActivity(ctx, tracedActivity).Get(ctx, nil)
+}
+
+func tracedActivity(ctx context.Context) error {
+	return nil
+}
+
+func tracedTwoActivitiesWorkflow(ctx workflow.Context) error {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{StartToCloseTimeout: time.Minute})
+	if err := workflow.ExecuteActivity(ctx, tracedActivity).Get(ctx, nil); err != nil {
+		return err
+	}
+	return workflow.ExecuteActivity(ctx, tracedActivity).Get(ctx, nil)
+}
+
+func TestTracingInterceptor(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	options := TracerOptions{Tracer: sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")}
+	tracing := NewTracingInterceptor(options)
+
+	var suite testsuite.WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+	env.SetWorkerOptions(worker.Options{
+		WorkflowInterceptorChainFactories: []interceptors.WorkflowInterceptor{tracing},
+		ActivityInterceptorChainFactories: []interceptors.ActivityInterceptor{tracing},
+	})
+	env.SetContextPropagators([]workflow.ContextPropagator{NewContextPropagator(options)})
+	env.RegisterWorkflow(tracedWorkflow)
+	env.RegisterActivity(tracedActivity)
+	env.ExecuteWorkflow(tracedWorkflow)
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	require.Len(t, spans, 3)
+	runWorkflow := spans["RunWorkflow:tracedWorkflow"]
+	startActivity := spans["StartActivity:tracedActivity"]
+	runActivity := spans["RunActivity:tracedActivity"]
+	require.NotNil(t, runWorkflow)
+	require.NotNil(t, startActivity)
+	require.NotNil(t, runActivity)
+
+	require.Equal(t, runWorkflow.SpanContext().TraceID(), runActivity.SpanContext().TraceID())
+	require.Equal(t, runWorkflow.SpanContext().SpanID(), startActivity.Parent().SpanID())
+	require.Equal(t, startActivity.SpanContext().SpanID(), runActivity.Parent().SpanID())
+}
+
+func TestTracingInterceptor_Eviction(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	options := TracerOptions{Tracer: sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")}
+	tracing := NewTracingInterceptor(options)
+
+	// With no workflow cache the run is evicted after every workflow task and replayed by the next one.
+	worker.SetStickyWorkflowCacheSize(0)
+	server := testsuite.NewDevServer(testsuite.DevServerOptions{})
+	defer server.Close()
+	c := server.NewClient(client.Options{
+		Interceptors:       []interceptors.ClientInterceptor{tracing},
+		ContextPropagators: []workflow.ContextPropagator{NewContextPropagator(options)},
+	})
+	w := worker.New(c, "tracing", worker.Options{
+		WorkflowInterceptorChainFactories: []interceptors.WorkflowInterceptor{tracing},
+		ActivityInterceptorChainFactories: []interceptors.ActivityInterceptor{tracing},
+	})
+	w.RegisterWorkflow(tracedTwoActivitiesWorkflow)
+	w.RegisterActivity(tracedActivity)
+	require.NoError(t, w.Start())
+	defer w.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	run, err := c.ExecuteWorkflow(ctx, client.StartWorkflowOptions{TaskQueue: "tracing"}, tracedTwoActivitiesWorkflow)
+	require.NoError(t, err)
+	require.NoError(t, run.Get(ctx, nil))
+
+	spans := make(map[string][]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = append(spans[span.Name()], span)
+	}
+	require.Len(t, spans["StartWorkflow:tracedTwoActivitiesWorkflow"], 1)
+	startWorkflow := spans["StartWorkflow:tracedTwoActivitiesWorkflow"][0]
+	runWorkflow := spans["RunWorkflow:tracedTwoActivitiesWorkflow"]
+	startActivity := spans["StartActivity:tracedActivity"]
+
+	// The run is executed by three workflow tasks: the first one starts the run and the other ones replay it. Only
+	// the first one creates a RunWorkflow span, which is ended when the run is evicted after the task.
+	require.Len(t, runWorkflow, 1)
+	require.Equal(t, startWorkflow.SpanContext().SpanID(), runWorkflow[0].Parent().SpanID())
+	attributes := attribute.NewSet(runWorkflow[0].Attributes()...)
+	evicted, _ := attributes.Value(evictedAttributeKey)
+	require.True(t, evicted.AsBool())
+
+	// The activity scheduled by the replaying task is parented to the span carried in the workflow header.
+	require.Len(t, startActivity, 2)
+	require.Equal(t, runWorkflow[0].SpanContext().SpanID(), startActivity[0].Parent().SpanID())
+	require.Equal(t, startWorkflow.SpanContext().SpanID(), startActivity[1].Parent().SpanID())
+}
+
+func TestContextPropagator(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+	propagator := NewContextPropagator(TracerOptions{Tracer: tracer})
+	ctx, span := tracer.Start(context.Background(), "test")
+	defer span.End()
+
+	header := testHeader{}
+	require.NoError(t, propagator.Inject(ctx, header))
+	require.Contains(t, header, tracerHeaderKey)
+
+	extracted, err := propagator.Extract(context.Background(), header)
+	require.NoError(t, err)
+	spanContext := trace.SpanContextFromContext(extracted)
+	require.True(t, spanContext.IsRemote())
+	require.Equal(t, span.SpanContext().TraceID(), spanContext.TraceID())
+	require.Equal(t, span.SpanContext().SpanID(), spanContext.SpanID())
+
+	empty := testHeader{}
+	require.NoError(t, propagator.Inject(context.Background(), empty))
+	require.Empty(t, empty)
+	extracted, err = propagator.Extract(context.Background(), empty)
+	require.NoError(t, err)
+	require.False(t, trace.SpanContextFromContext(extracted).IsValid())
+}
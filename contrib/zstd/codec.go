@@ -0,0 +1,116 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package zstd provides a payload codec which compresses payloads with Zstandard.
+//
+// Wrap the data converter of the client with the codec to compress workflow and activity inputs and results,
+// heartbeats, memos, signals and queries:
+//
+//  codec, err := zstd.NewCodec(converter.CompressionCodecOptions{})
+//  c, err := client.NewClient(client.Options{
+//      DataConverter: converter.NewCodecDataConverter(converter.GetDefaultDataConverter(), codec),
+//  })
+//
+// Unlike the SDK, which supports Go 1.16, this module requires Go 1.22, because github.com/klauspost/compress, which
+// implements Zstandard, requires it. The module is kept separate from the SDK, so applications which don't use the
+// codec are not affected.
+package zstd
+
+import (
+	"fmt"
+
+	zstdlib "github.com/klauspost/compress/zstd"
+	commonpb "go.temporal.io/api/common/v1"
+
+	"go.temporal.io/sdk/converter"
+)
+
+// MetadataEncodingZstd is "binary/zstd"
+const MetadataEncodingZstd = "binary/zstd"
+
+type codec struct {
+	options converter.CompressionCodecOptions
+	encoder *zstdlib.Encoder
+	decoder *zstdlib.Decoder
+}
+
+// NewCodec creates a converter.PayloadCodec which compresses payloads with Zstandard. The whole payload, including
+// its metadata, is compressed. The compressed payload has MetadataEncodingZstd encoding.
+func NewCodec(options converter.CompressionCodecOptions) (converter.PayloadCodec, error) {
+	encoder, err := zstdlib.NewWriter(nil)
+	if err != nil {
+		return nil, err
+	}
+	maxDecompressedSize := options.MaxDecompressedSize
+	if maxDecompressedSize == 0 {
+		maxDecompressedSize = converter.DefaultMaxDecompressedSize
+	}
+	decoder, err := zstdlib.NewReader(nil, zstdlib.WithDecoderMaxMemory(uint64(maxDecompressedSize)))
+	if err != nil {
+		return nil, err
+	}
+	return &codec{options: options, encoder: encoder, decoder: decoder}, nil
+}
+
+// Encode implements converter.PayloadCodec.Encode.
+func (c *codec) Encode(payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+	result := make([]*commonpb.Payload, len(payloads))
+	for i, payload := range payloads {
+		data, err := payload.Marshal()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", converter.ErrUnableToEncode, err)
+		}
+		compressed := c.encoder.EncodeAll(data, nil)
+		if !c.options.AlwaysEncode && len(compressed) >= len(data) {
+			result[i] = payload
+			continue
+		}
+		result[i] = &commonpb.Payload{
+			Metadata: map[string][]byte{converter.MetadataEncoding: []byte(MetadataEncodingZstd)},
+			Data:     compressed,
+		}
+	}
+	return result, nil
+}
+
+// Decode implements converter.PayloadCodec.Decode.
+func (c *codec) Decode(payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+	result := make([]*commonpb.Payload, len(payloads))
+	for i, payload := range payloads {
+		if string(payload.GetMetadata()[converter.MetadataEncoding]) != MetadataEncodingZstd {
+			result[i] = payload
+			continue
+		}
+		data, err := c.decoder.DecodeAll(payload.GetData(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", converter.ErrUnableToDecode, err)
+		}
+		decoded := &commonpb.Payload{}
+		if err := decoded.Unmarshal(data); err != nil {
+			return nil, fmt.Errorf("%w: %v", converter.ErrUnableToDecode, err)
+		}
+		result[i] = decoded
+	}
+	return result, nil
+}
@@ -0,0 +1,91 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zstd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+
+	"go.temporal.io/sdk/converter"
+)
+
+func TestCodec(t *testing.T) {
+	codec, err := NewCodec(converter.CompressionCodecOptions{})
+	require.NoError(t, err)
+	small := &commonpb.Payload{Data: []byte("data")}
+	large := &commonpb.Payload{
+		Metadata: map[string][]byte{converter.MetadataEncoding: []byte(converter.MetadataEncodingBinary)},
+		Data:     bytes.Repeat([]byte("data"), 1000),
+	}
+
+	encoded, err := codec.Encode([]*commonpb.Payload{small, large})
+	require.NoError(t, err)
+	require.Equal(t, small, encoded[0])
+	require.Equal(t, MetadataEncodingZstd, string(encoded[1].GetMetadata()[converter.MetadataEncoding]))
+	require.Less(t, len(encoded[1].GetData()), len(large.GetData()))
+
+	decoded, err := codec.Decode(encoded)
+	require.NoError(t, err)
+	require.Equal(t, small, decoded[0])
+	require.Equal(t, large.GetMetadata(), decoded[1].GetMetadata())
+	require.Equal(t, large.GetData(), decoded[1].GetData())
+
+	_, err = codec.Decode([]*commonpb.Payload{{
+		Metadata: map[string][]byte{converter.MetadataEncoding: []byte(MetadataEncodingZstd)},
+		Data:     []byte("data"),
+	}})
+	require.True(t, errors.Is(err, converter.ErrUnableToDecode))
+}
+
+func TestCodecDataConverter(t *testing.T) {
+	codec, err := NewCodec(converter.CompressionCodecOptions{AlwaysEncode: true})
+	require.NoError(t, err)
+	dc := converter.NewCodecDataConverter(converter.GetDefaultDataConverter(), codec)
+
+	payloads, err := dc.ToPayloads("value", 42)
+	require.NoError(t, err)
+	for _, payload := range payloads.GetPayloads() {
+		require.Equal(t, MetadataEncodingZstd, string(payload.GetMetadata()[converter.MetadataEncoding]))
+	}
+
+	var str string
+	var num int
+	require.NoError(t, dc.FromPayloads(payloads, &str, &num))
+	require.Equal(t, "value", str)
+	require.Equal(t, 42, num)
+}
+
+func TestCodecMaxDecompressedSize(t *testing.T) {
+	codec, err := NewCodec(converter.CompressionCodecOptions{MaxDecompressedSize: 1000})
+	require.NoError(t, err)
+	encoded, err := codec.Encode([]*commonpb.Payload{{Data: bytes.Repeat([]byte("data"), 1000)}})
+	require.NoError(t, err)
+	_, err = codec.Decode(encoded)
+	require.True(t, errors.Is(err, converter.ErrUnableToDecode))
+}
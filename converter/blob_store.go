@@ -0,0 +1,195 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package converter
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	commonpb "go.temporal.io/api/common/v1"
+)
+
+// DefaultBlobStoreThreshold is the default size in bytes above which payloads are stored in a BlobStore.
+const DefaultBlobStoreThreshold = 128 * 1024
+
+type (
+	// BlobStore stores payloads which are too large to be sent to the server. Keys are SHA-256 hashes of the stored
+	// data in hex, so storing the same data again must succeed.
+	BlobStore interface {
+		// Put stores data under key.
+		Put(ctx context.Context, key string, data []byte) error
+		// Get returns data stored under key.
+		Get(ctx context.Context, key string) ([]byte, error)
+	}
+
+	// BlobStoreCodecOptions are parameters for NewBlobStoreCodec.
+	BlobStoreCodecOptions struct {
+		// Required: Store that large payloads are stored in.
+		Store BlobStore
+
+		// Optional: Payloads larger than Threshold bytes are stored in Store.
+		// default: DefaultBlobStoreThreshold
+		Threshold int
+	}
+
+	blobStoreCodec struct {
+		store     BlobStore
+		threshold int
+	}
+
+	fileBlobStore struct {
+		dir string
+	}
+)
+
+// NewBlobStoreCodec creates a PayloadCodec which stores payloads larger than the threshold in a BlobStore and
+// replaces them with reference payloads with MetadataEncodingBlobReference encoding. The reference is resolved when
+// the payload is decoded, so every worker and client that decodes the payloads must have access to the store.
+// Add it before encryption codecs to store encrypted payloads:
+//   dc := NewCodecDataConverter(GetDefaultDataConverter(), NewBlobStoreCodec(options), NewAESGCMCodec(keyProvider))
+// It panics if options.Store is not set.
+func NewBlobStoreCodec(options BlobStoreCodecOptions) PayloadCodec {
+	if options.Store == nil {
+		panic("BlobStoreCodecOptions.Store must be set")
+	}
+	threshold := options.Threshold
+	if threshold == 0 {
+		threshold = DefaultBlobStoreThreshold
+	}
+	return &blobStoreCodec{store: options.Store, threshold: threshold}
+}
+
+// NewBlobStoreDataConverter creates a DataConverter which stores payloads of the parent DataConverter larger than the
+// threshold in a BlobStore. It is a CodecDataConverter with a single NewBlobStoreCodec codec.
+func NewBlobStoreDataConverter(parent DataConverter, options BlobStoreCodecOptions) DataConverter {
+	return NewCodecDataConverter(parent, NewBlobStoreCodec(options))
+}
+
+// Encode implements PayloadCodec.Encode.
+func (c *blobStoreCodec) Encode(payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+	result := make([]*commonpb.Payload, len(payloads))
+	for i, payload := range payloads {
+		if payload.Size() <= c.threshold {
+			result[i] = payload
+			continue
+		}
+		data, err := marshalPayload(payload)
+		if err != nil {
+			return nil, err
+		}
+		key := blobKey(data)
+		if err := c.store.Put(context.Background(), key, data); err != nil {
+			return nil, fmt.Errorf("%w: unable to store payload: %v", ErrUnableToEncode, err)
+		}
+		result[i] = &commonpb.Payload{
+			Metadata: map[string][]byte{MetadataEncoding: []byte(MetadataEncodingBlobReference)},
+			Data:     []byte(key),
+		}
+	}
+	return result, nil
+}
+
+// Decode implements PayloadCodec.Decode.
+func (c *blobStoreCodec) Decode(payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+	result := make([]*commonpb.Payload, len(payloads))
+	for i, payload := range payloads {
+		if !hasEncoding(payload, MetadataEncodingBlobReference) {
+			result[i] = payload
+			continue
+		}
+		key := string(payload.GetData())
+		data, err := c.store.Get(context.Background(), key)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to load payload %s: %v", ErrUnableToDecode, key, err)
+		}
+		if blobKey(data) != key {
+			return nil, fmt.Errorf("%w: stored payload %s is corrupted", ErrUnableToDecode, key)
+		}
+		if result[i], err = unmarshalPayload(data); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func blobKey(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// NewFileBlobStore creates a BlobStore which stores every blob in a file in dir. The directory is created if it
+// doesn't exist. Use it when all workers and clients share a file system, for example a network volume.
+func NewFileBlobStore(dir string) (BlobStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &fileBlobStore{dir: dir}, nil
+}
+
+// Put implements BlobStore.Put. The file is written to a temporary file first, so readers never see partial data.
+func (s *fileBlobStore) Put(_ context.Context, key string, data []byte) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	f, err := ioutil.TempFile(s.dir, key+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// Get implements BlobStore.Get.
+func (s *fileBlobStore) Get(_ context.Context, key string) ([]byte, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadFile(path)
+}
+
+func (s *fileBlobStore) path(key string) (string, error) {
+	if key == "" || key == "." || key == ".." || filepath.Base(key) != key {
+		return "", fmt.Errorf("%w: %q", ErrBlobKeyIsInvalid, key)
+	}
+	return filepath.Join(s.dir, key), nil
+}
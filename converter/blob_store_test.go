@@ -0,0 +1,98 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package converter
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+)
+
+func TestBlobStoreDataConverter(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	store, err := NewFileBlobStore(dir)
+	require.NoError(t, err)
+	dc := NewBlobStoreDataConverter(GetDefaultDataConverter(), BlobStoreCodecOptions{Store: store, Threshold: 100})
+
+	large := strings.Repeat("large", 100)
+	payloads, err := dc.ToPayloads("small", large)
+	require.NoError(t, err)
+	require.Equal(t, MetadataEncodingJSON, string(payloads.GetPayloads()[0].GetMetadata()[MetadataEncoding]))
+	reference := payloads.GetPayloads()[1]
+	require.Equal(t, MetadataEncodingBlobReference, string(reference.GetMetadata()[MetadataEncoding]))
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Equal(t, string(reference.GetData()), files[0].Name())
+
+	var small, decodedLarge string
+	require.NoError(t, dc.FromPayloads(payloads, &small, &decodedLarge))
+	require.Equal(t, "small", small)
+	require.Equal(t, large, decodedLarge)
+	require.Equal(t, `"`+large+`"`, dc.ToString(reference))
+
+	// Storing the same payload again reuses the stored blob.
+	again, err := dc.ToPayload(large)
+	require.NoError(t, err)
+	require.Equal(t, reference, again)
+	files, err = ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+}
+
+func TestBlobStoreCodecErrors(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	store, err := NewFileBlobStore(dir)
+	require.NoError(t, err)
+	codec := NewBlobStoreCodec(BlobStoreCodecOptions{Store: store, Threshold: 1})
+
+	encoded, err := codec.Encode([]*commonpb.Payload{{Data: []byte("data")}})
+	require.NoError(t, err)
+	key := string(encoded[0].GetData())
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, key), []byte("corrupted"), 0644))
+	_, err = codec.Decode(encoded)
+	require.True(t, errors.Is(err, ErrUnableToDecode))
+
+	missing := &commonpb.Payload{
+		Metadata: map[string][]byte{MetadataEncoding: []byte(MetadataEncodingBlobReference)},
+		Data:     []byte(blobKey([]byte("missing"))),
+	}
+	_, err = codec.Decode([]*commonpb.Payload{missing})
+	require.True(t, errors.Is(err, ErrUnableToDecode))
+
+	_, err = store.Get(context.Background(), "../secret")
+	require.True(t, errors.Is(err, ErrBlobKeyIsInvalid))
+	require.True(t, errors.Is(store.Put(context.Background(), "", nil), ErrBlobKeyIsInvalid))
+
+	require.Panics(t, func() { NewBlobStoreCodec(BlobStoreCodecOptions{}) })
+}
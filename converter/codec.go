@@ -0,0 +1,204 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package converter
+
+import (
+	"fmt"
+
+	commonpb "go.temporal.io/api/common/v1"
+)
+
+type (
+	// PayloadCodec transforms payloads after they are encoded by a DataConverter and before they are decoded by it,
+	// for example to encrypt or compress them. Decode must reverse Encode and both must return as many payloads as
+	// they are given. Decode must return payloads it did not encode as is, so a codec can be added to a namespace
+	// which already has workflows started without it.
+	PayloadCodec interface {
+		// Encode encodes payloads.
+		Encode(payloads []*commonpb.Payload) ([]*commonpb.Payload, error)
+		// Decode decodes payloads.
+		Decode(payloads []*commonpb.Payload) ([]*commonpb.Payload, error)
+	}
+
+	// CodecDataConverter is a DataConverter which applies PayloadCodecs to the payloads of its parent DataConverter.
+	CodecDataConverter struct {
+		parent DataConverter
+		codecs []PayloadCodec
+	}
+)
+
+// NewCodecDataConverter creates new instance of CodecDataConverter which wraps the parent DataConverter with codecs.
+// Codecs are applied in reverse order on encoding and in order on decoding, so the first codec produces the payloads
+// which are sent to the server. For example, to compress payloads before encrypting them:
+//   dc := NewCodecDataConverter(GetDefaultDataConverter(), NewAESGCMCodec(keyProvider), NewGzipCodec(CompressionCodecOptions{}))
+// Set the result as client.Options.DataConverter to apply codecs to workflow and activity inputs and results,
+// heartbeats, memos, signals and queries.
+func NewCodecDataConverter(parent DataConverter, codecs ...PayloadCodec) DataConverter {
+	return &CodecDataConverter{
+		parent: parent,
+		codecs: codecs,
+	}
+}
+
+// ToPayload converts single value to payload.
+func (dc *CodecDataConverter) ToPayload(value interface{}) (*commonpb.Payload, error) {
+	payload, err := dc.parent.ToPayload(value)
+	if err != nil || payload == nil {
+		return payload, err
+	}
+
+	encoded, err := dc.encode([]*commonpb.Payload{payload})
+	if err != nil {
+		return nil, err
+	}
+	return encoded[0], nil
+}
+
+// FromPayload converts single value from payload.
+func (dc *CodecDataConverter) FromPayload(payload *commonpb.Payload, valuePtr interface{}) error {
+	if payload == nil {
+		return nil
+	}
+
+	decoded, err := dc.decode([]*commonpb.Payload{payload})
+	if err != nil {
+		return err
+	}
+	return dc.parent.FromPayload(decoded[0], valuePtr)
+}
+
+// ToPayloads converts a list of values.
+func (dc *CodecDataConverter) ToPayloads(values ...interface{}) (*commonpb.Payloads, error) {
+	payloads, err := dc.parent.ToPayloads(values...)
+	if err != nil || payloads == nil {
+		return payloads, err
+	}
+
+	encoded, err := dc.encode(payloads.GetPayloads())
+	if err != nil {
+		return nil, err
+	}
+	return &commonpb.Payloads{Payloads: encoded}, nil
+}
+
+// FromPayloads converts to a list of values of different types.
+func (dc *CodecDataConverter) FromPayloads(payloads *commonpb.Payloads, valuePtrs ...interface{}) error {
+	if payloads == nil {
+		return nil
+	}
+
+	decoded, err := dc.decode(payloads.GetPayloads())
+	if err != nil {
+		return err
+	}
+	return dc.parent.FromPayloads(&commonpb.Payloads{Payloads: decoded}, valuePtrs...)
+}
+
+// ToString converts payload object into human readable string.
+func (dc *CodecDataConverter) ToString(payload *commonpb.Payload) string {
+	if payload == nil {
+		return ""
+	}
+
+	decoded, err := dc.decode([]*commonpb.Payload{payload})
+	if err != nil {
+		return err.Error()
+	}
+	return dc.parent.ToString(decoded[0])
+}
+
+// ToStrings converts payloads object into human readable strings.
+func (dc *CodecDataConverter) ToStrings(payloads *commonpb.Payloads) []string {
+	if payloads == nil {
+		return nil
+	}
+
+	var result []string
+	for _, payload := range payloads.GetPayloads() {
+		result = append(result, dc.ToString(payload))
+	}
+	return result
+}
+
+func (dc *CodecDataConverter) encode(payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+	return encodePayloads(dc.codecs, payloads)
+}
+
+func (dc *CodecDataConverter) decode(payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+	return decodePayloads(dc.codecs, payloads)
+}
+
+// encodePayloads applies codecs in reverse order.
+func encodePayloads(codecs []PayloadCodec, payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+	var err error
+	for i := len(codecs) - 1; i >= 0; i-- {
+		count := len(payloads)
+		if payloads, err = codecs[i].Encode(payloads); err != nil {
+			return nil, err
+		}
+		if len(payloads) != count {
+			return nil, fmt.Errorf("codec %T returned %d payloads instead of %d: %w", codecs[i], len(payloads), count, ErrUnableToEncode)
+		}
+	}
+	return payloads, nil
+}
+
+// decodePayloads applies codecs in order.
+func decodePayloads(codecs []PayloadCodec, payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+	var err error
+	for _, codec := range codecs {
+		count := len(payloads)
+		if payloads, err = codec.Decode(payloads); err != nil {
+			return nil, err
+		}
+		if len(payloads) != count {
+			return nil, fmt.Errorf("codec %T returned %d payloads instead of %d: %w", codec, len(payloads), count, ErrUnableToDecode)
+		}
+	}
+	return payloads, nil
+}
+
+// marshalPayload returns the wire format of payload, which keeps its metadata along with its data.
+func marshalPayload(payload *commonpb.Payload) ([]byte, error) {
+	data, err := payload.Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnableToEncode, err)
+	}
+	return data, nil
+}
+
+// unmarshalPayload reverses marshalPayload.
+func unmarshalPayload(data []byte) (*commonpb.Payload, error) {
+	payload := &commonpb.Payload{}
+	if err := payload.Unmarshal(data); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnableToDecode, err)
+	}
+	return payload, nil
+}
+
+// hasEncoding returns whether payload metadata has the given encoding.
+func hasEncoding(payload *commonpb.Payload, encoding string) bool {
+	return string(payload.GetMetadata()[MetadataEncoding]) == encoding
+}
@@ -0,0 +1,163 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package converter
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gogo/protobuf/jsonpb"
+	commonpb "go.temporal.io/api/common/v1"
+)
+
+const (
+	codecEncodePath = "/encode"
+	codecDecodePath = "/decode"
+
+	codecCORSAllowedHeaders = "Content-Type, Authorization, X-Namespace"
+
+	defaultCodecMaxRequestBodySize = 4 << 20
+)
+
+type (
+	// PayloadCodecHTTPHandlerOptions are optional parameters for NewPayloadCodecHTTPHandler.
+	PayloadCodecHTTPHandlerOptions struct {
+		// Optional: Origins which are allowed to call the handler from a browser, for example the origin of
+		// Temporal Web UI. Requests from the listed origins may carry credentials, like cookies. "*" allows any
+		// origin, but without credentials.
+		// default: cross-origin requests are not allowed
+		CORSAllowedOrigins []string
+
+		// Optional: Maximum size of a request body in bytes. Larger requests are rejected with 400 Bad Request.
+		// default: 4 MiB
+		MaxRequestBodySize int64
+
+		// Required: Called before a request is handled. If it returns an error the request is rejected with
+		// 401 Unauthorized. Use it to check the credentials of the caller, for example the Authorization header and
+		// the namespace in the X-Namespace header. The handler can decode any payload encrypted with its codecs, so
+		// requests are rejected when Authorize is not set. To allow all requests, for example when the handler is
+		// only reachable from a trusted network, set it to a function which always returns nil.
+		Authorize func(r *http.Request) error
+	}
+
+	payloadCodecHTTPHandler struct {
+		codecs      []PayloadCodec
+		options     PayloadCodecHTTPHandlerOptions
+		marshaler   jsonpb.Marshaler
+		unmarshaler jsonpb.Unmarshaler
+	}
+)
+
+// NewPayloadCodecHTTPHandler creates an http.Handler which runs payloads through codecs, so tools like Temporal
+// Web UI and tctl can show payloads which are encrypted or compressed by workers. It serves POST requests to paths
+// ending with "/encode" and "/decode", so it can be mounted under any prefix. Both take and return commonpb.Payloads
+// in protobuf JSON format. Codecs are applied in the same order as NewCodecDataConverter applies them.
+// Requests are rejected unless options.Authorize is set, see PayloadCodecHTTPHandlerOptions.
+func NewPayloadCodecHTTPHandler(options PayloadCodecHTTPHandlerOptions, codecs ...PayloadCodec) http.Handler {
+	return &payloadCodecHTTPHandler{
+		codecs:  codecs,
+		options: options,
+	}
+}
+
+// ServeHTTP implements http.Handler.ServeHTTP.
+func (h *payloadCodecHTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.setCORSHeaders(w, r)
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	var apply func([]PayloadCodec, []*commonpb.Payload) ([]*commonpb.Payload, error)
+	switch {
+	case strings.HasSuffix(r.URL.Path, codecEncodePath):
+		apply = encodePayloads
+	case strings.HasSuffix(r.URL.Path, codecDecodePath):
+		apply = decodePayloads
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	if h.options.Authorize == nil {
+		http.Error(w, "payload codec handler has no Authorize function", http.StatusUnauthorized)
+		return
+	}
+	if err := h.options.Authorize(r); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	maxRequestBodySize := h.options.MaxRequestBodySize
+	if maxRequestBodySize == 0 {
+		maxRequestBodySize = defaultCodecMaxRequestBodySize
+	}
+	var payloads commonpb.Payloads
+	if err := h.unmarshaler.Unmarshal(http.MaxBytesReader(w, r.Body, maxRequestBodySize), &payloads); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	result, err := apply(h.codecs, payloads.GetPayloads())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = h.marshaler.Marshal(w, &commonpb.Payloads{Payloads: result})
+}
+
+func (h *payloadCodecHTTPHandler) setCORSHeaders(w http.ResponseWriter, r *http.Request) {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return
+	}
+	// Only explicitly listed origins are echoed and allowed to send credentials, otherwise any web page would be able
+	// to call the handler with the credentials of the user.
+	allowedOrigin := ""
+	for _, allowed := range h.options.CORSAllowedOrigins {
+		if allowed == origin {
+			allowedOrigin = origin
+			break
+		}
+		if allowed == "*" {
+			allowedOrigin = allowed
+		}
+	}
+	if allowedOrigin == "" {
+		return
+	}
+	w.Header().Set("Access-Control-Allow-Origin", allowedOrigin)
+	if allowedOrigin != "*" {
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+		w.Header().Add("Vary", "Origin")
+	}
+	w.Header().Set("Access-Control-Allow-Methods", http.MethodPost)
+	w.Header().Set("Access-Control-Allow-Headers", codecCORSAllowedHeaders)
+}
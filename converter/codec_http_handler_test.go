@@ -0,0 +1,190 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package converter
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+)
+
+func allowAllCodecRequests(*http.Request) error {
+	return nil
+}
+
+func newTestPayloadCodecHTTPHandler(options PayloadCodecHTTPHandlerOptions) http.Handler {
+	return NewPayloadCodecHTTPHandler(options,
+		NewAESGCMCodec(newTestKeyProvider("key1")), NewGzipCodec(CompressionCodecOptions{AlwaysEncode: true}))
+}
+
+func postPayloads(t *testing.T, handler http.Handler, path string, payloads *commonpb.Payloads) *httptest.ResponseRecorder {
+	var body bytes.Buffer
+	require.NoError(t, (&jsonpb.Marshaler{}).Marshal(&body, payloads))
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, path, &body))
+	return recorder
+}
+
+func TestPayloadCodecHTTPHandler(t *testing.T) {
+	t.Parallel()
+	handler := newTestPayloadCodecHTTPHandler(PayloadCodecHTTPHandlerOptions{Authorize: allowAllCodecRequests})
+	payloads, err := GetDefaultDataConverter().ToPayloads("value", 42)
+	require.NoError(t, err)
+
+	recorder := postPayloads(t, handler, "/codec/encode", payloads)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	var encoded commonpb.Payloads
+	require.NoError(t, jsonpb.Unmarshal(recorder.Body, &encoded))
+	require.Len(t, encoded.GetPayloads(), 2)
+	require.Equal(t, MetadataEncodingEncrypted, string(encoded.GetPayloads()[0].GetMetadata()[MetadataEncoding]))
+
+	// Payloads encoded by the handler can be decoded by a data converter with the same codecs and vice versa.
+	dc := NewCodecDataConverter(GetDefaultDataConverter(),
+		NewAESGCMCodec(newTestKeyProvider("key1")), NewGzipCodec(CompressionCodecOptions{}))
+	var str string
+	var num int
+	require.NoError(t, dc.FromPayloads(&encoded, &str, &num))
+	require.Equal(t, "value", str)
+	require.Equal(t, 42, num)
+
+	encodedByConverter, err := dc.ToPayloads("value", 42)
+	require.NoError(t, err)
+	recorder = postPayloads(t, handler, "/decode", encodedByConverter)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var decoded commonpb.Payloads
+	require.NoError(t, jsonpb.Unmarshal(recorder.Body, &decoded))
+	require.Equal(t, payloads, &decoded)
+}
+
+func TestPayloadCodecHTTPHandlerErrors(t *testing.T) {
+	t.Parallel()
+	handler := newTestPayloadCodecHTTPHandler(PayloadCodecHTTPHandlerOptions{Authorize: allowAllCodecRequests})
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/other", strings.NewReader("{}")))
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/decode", nil))
+	require.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/decode", strings.NewReader("not json")))
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	handler = newTestPayloadCodecHTTPHandler(PayloadCodecHTTPHandlerOptions{MaxRequestBodySize: 10, Authorize: allowAllCodecRequests})
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/decode", strings.NewReader(`{"payloads": []}`)))
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	recorder = postPayloads(t, handler, "/decode", &commonpb.Payloads{Payloads: []*commonpb.Payload{{
+		Metadata: map[string][]byte{MetadataEncoding: []byte(MetadataEncodingEncrypted), MetadataEncryptionKeyID: []byte("key1")},
+		Data:     []byte("not encrypted"),
+	}}})
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestPayloadCodecHTTPHandlerAuthorize(t *testing.T) {
+	t.Parallel()
+	handler := newTestPayloadCodecHTTPHandler(PayloadCodecHTTPHandlerOptions{
+		Authorize: func(r *http.Request) error {
+			if r.Header.Get("Authorization") != "Bearer token" {
+				return errors.New("invalid token")
+			}
+			return nil
+		},
+	})
+
+	recorder := postPayloads(t, handler, "/decode", &commonpb.Payloads{})
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+	require.Equal(t, "invalid token\n", recorder.Body.String())
+
+	request := httptest.NewRequest(http.MethodPost, "/decode", strings.NewReader("{}"))
+	request.Header.Set("Authorization", "Bearer token")
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	// Requests are rejected if the handler has no Authorize function.
+	handler = newTestPayloadCodecHTTPHandler(PayloadCodecHTTPHandlerOptions{})
+	recorder = postPayloads(t, handler, "/decode", &commonpb.Payloads{})
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
+
+func TestPayloadCodecHTTPHandlerCORS(t *testing.T) {
+	t.Parallel()
+	handler := newTestPayloadCodecHTTPHandler(PayloadCodecHTTPHandlerOptions{
+		CORSAllowedOrigins: []string{"http://localhost:8088"},
+		Authorize: func(r *http.Request) error {
+			return errors.New("unauthorized")
+		},
+	})
+
+	request := httptest.NewRequest(http.MethodOptions, "/decode", nil)
+	request.Header.Set("Origin", "http://localhost:8088")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "http://localhost:8088", recorder.Header().Get("Access-Control-Allow-Origin"))
+	require.Equal(t, "true", recorder.Header().Get("Access-Control-Allow-Credentials"))
+	require.Equal(t, http.MethodPost, recorder.Header().Get("Access-Control-Allow-Methods"))
+	require.Contains(t, recorder.Header().Get("Access-Control-Allow-Headers"), "X-Namespace")
+
+	request = httptest.NewRequest(http.MethodOptions, "/decode", nil)
+	request.Header.Set("Origin", "http://example.com")
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	require.Empty(t, recorder.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestPayloadCodecHTTPHandlerCORSAnyOrigin(t *testing.T) {
+	t.Parallel()
+	handler := newTestPayloadCodecHTTPHandler(PayloadCodecHTTPHandlerOptions{
+		CORSAllowedOrigins: []string{"*", "http://localhost:8088"},
+		Authorize:          allowAllCodecRequests,
+	})
+
+	request := httptest.NewRequest(http.MethodOptions, "/decode", nil)
+	request.Header.Set("Origin", "http://example.com")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	require.Equal(t, "*", recorder.Header().Get("Access-Control-Allow-Origin"))
+	require.Empty(t, recorder.Header().Get("Access-Control-Allow-Credentials"))
+
+	request = httptest.NewRequest(http.MethodOptions, "/decode", nil)
+	request.Header.Set("Origin", "http://localhost:8088")
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	require.Equal(t, "http://localhost:8088", recorder.Header().Get("Access-Control-Allow-Origin"))
+	require.Equal(t, "true", recorder.Header().Get("Access-Control-Allow-Credentials"))
+}
@@ -0,0 +1,166 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package converter
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+)
+
+type testCodecValue struct {
+	Name  string
+	Items []string
+}
+
+func newTestKeyProvider(keyID string) EncryptionKeyProvider {
+	return NewStaticEncryptionKeyProvider(keyID, map[string][]byte{
+		"key1": bytes.Repeat([]byte{1}, 32),
+		"key2": bytes.Repeat([]byte{2}, 16),
+	})
+}
+
+func TestCodecDataConverter(t *testing.T) {
+	t.Parallel()
+	dc := NewCodecDataConverter(GetDefaultDataConverter(),
+		NewAESGCMCodec(newTestKeyProvider("key1")), NewGzipCodec(CompressionCodecOptions{AlwaysEncode: true}))
+	value := testCodecValue{Name: "name", Items: []string{strings.Repeat("item", 100)}}
+
+	payloads, err := dc.ToPayloads(value, "str", 42)
+	require.NoError(t, err)
+	require.Len(t, payloads.GetPayloads(), 3)
+	for _, payload := range payloads.GetPayloads() {
+		require.Equal(t, MetadataEncodingEncrypted, string(payload.GetMetadata()[MetadataEncoding]))
+		require.Equal(t, "key1", string(payload.GetMetadata()[MetadataEncryptionKeyID]))
+		require.NotContains(t, string(payload.GetData()), "item")
+	}
+
+	var decodedValue testCodecValue
+	var decodedStr string
+	var decodedInt int
+	require.NoError(t, dc.FromPayloads(payloads, &decodedValue, &decodedStr, &decodedInt))
+	require.Equal(t, value, decodedValue)
+	require.Equal(t, "str", decodedStr)
+	require.Equal(t, 42, decodedInt)
+	require.Equal(t, []string{`{"Name":"name","Items":["` + strings.Repeat("item", 100) + `"]}`, `"str"`, "42"}, dc.ToStrings(payloads))
+
+	payload, err := dc.ToPayload(value)
+	require.NoError(t, err)
+	decodedValue = testCodecValue{}
+	require.NoError(t, dc.FromPayload(payload, &decodedValue))
+	require.Equal(t, value, decodedValue)
+
+	nilPayloads, err := dc.ToPayloads()
+	require.NoError(t, err)
+	require.Nil(t, nilPayloads)
+}
+
+func TestCodecDataConverterDecodesPlainPayloads(t *testing.T) {
+	t.Parallel()
+	payload, err := GetDefaultDataConverter().ToPayload("plain")
+	require.NoError(t, err)
+
+	dc := NewCodecDataConverter(GetDefaultDataConverter(),
+		NewAESGCMCodec(newTestKeyProvider("key1")), NewGzipCodec(CompressionCodecOptions{}))
+	var decoded string
+	require.NoError(t, dc.FromPayload(payload, &decoded))
+	require.Equal(t, "plain", decoded)
+}
+
+func TestAESGCMCodecKeyRotation(t *testing.T) {
+	t.Parallel()
+	encoded, err := NewAESGCMCodec(newTestKeyProvider("key2")).Encode([]*commonpb.Payload{{Data: []byte("data")}})
+	require.NoError(t, err)
+	require.Equal(t, "key2", string(encoded[0].GetMetadata()[MetadataEncryptionKeyID]))
+
+	decoded, err := NewAESGCMCodec(newTestKeyProvider("key1")).Decode(encoded)
+	require.NoError(t, err)
+	require.Equal(t, "data", string(decoded[0].GetData()))
+
+	_, err = NewAESGCMCodec(NewStaticEncryptionKeyProvider("key1", map[string][]byte{
+		"key1": bytes.Repeat([]byte{1}, 32),
+	})).Decode(encoded)
+	require.True(t, errors.Is(err, ErrUnableToDecode))
+}
+
+func TestAESGCMCodecErrors(t *testing.T) {
+	t.Parallel()
+	codec := NewAESGCMCodec(newTestKeyProvider("key1"))
+	encoded, err := codec.Encode([]*commonpb.Payload{{Data: []byte("data")}})
+	require.NoError(t, err)
+
+	tampered := &commonpb.Payload{Metadata: encoded[0].GetMetadata(), Data: append([]byte{}, encoded[0].GetData()...)}
+	tampered.Data[len(tampered.Data)-1] ^= 1
+	_, err = codec.Decode([]*commonpb.Payload{tampered})
+	require.True(t, errors.Is(err, ErrUnableToDecode))
+
+	otherKeyID := &commonpb.Payload{
+		Metadata: map[string][]byte{MetadataEncoding: []byte(MetadataEncodingEncrypted), MetadataEncryptionKeyID: []byte("key2")},
+		Data:     encoded[0].GetData(),
+	}
+	_, err = codec.Decode([]*commonpb.Payload{otherKeyID})
+	require.True(t, errors.Is(err, ErrUnableToDecode))
+
+	noKeyID := &commonpb.Payload{Metadata: map[string][]byte{MetadataEncoding: []byte(MetadataEncodingEncrypted)}}
+	_, err = codec.Decode([]*commonpb.Payload{noKeyID})
+	require.Equal(t, ErrEncryptionKeyIDIsNotSet, err)
+
+	_, err = NewAESGCMCodec(newTestKeyProvider("key3")).Encode([]*commonpb.Payload{{Data: []byte("data")}})
+	require.True(t, errors.Is(err, ErrUnableToEncode))
+}
+
+func TestGzipCodec(t *testing.T) {
+	t.Parallel()
+	codec := NewGzipCodec(CompressionCodecOptions{})
+	small := &commonpb.Payload{Data: []byte("data")}
+	large := &commonpb.Payload{Data: bytes.Repeat([]byte("data"), 1000)}
+
+	encoded, err := codec.Encode([]*commonpb.Payload{small, large})
+	require.NoError(t, err)
+	require.Equal(t, small, encoded[0])
+	require.Equal(t, MetadataEncodingGzip, string(encoded[1].GetMetadata()[MetadataEncoding]))
+	require.Less(t, len(encoded[1].GetData()), len(large.GetData()))
+
+	decoded, err := codec.Decode(encoded)
+	require.NoError(t, err)
+	require.Equal(t, small, decoded[0])
+	require.Equal(t, large.GetData(), decoded[1].GetData())
+
+	_, err = codec.Decode([]*commonpb.Payload{{Metadata: map[string][]byte{MetadataEncoding: []byte(MetadataEncodingGzip)}, Data: []byte("data")}})
+	require.True(t, errors.Is(err, ErrUnableToDecode))
+}
+
+func TestGzipCodecMaxDecompressedSize(t *testing.T) {
+	t.Parallel()
+	codec := NewGzipCodec(CompressionCodecOptions{MaxDecompressedSize: 1000})
+	encoded, err := codec.Encode([]*commonpb.Payload{{Data: bytes.Repeat([]byte("data"), 1000)}})
+	require.NoError(t, err)
+	_, err = codec.Decode(encoded)
+	require.True(t, errors.Is(err, ErrUnableToDecode))
+}
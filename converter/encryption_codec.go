@@ -0,0 +1,158 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package converter
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"io"
+
+	commonpb "go.temporal.io/api/common/v1"
+)
+
+type (
+	// EncryptionKeyProvider provides keys to the AES-GCM codec. Keys must be 16, 24 or 32 bytes long to select
+	// AES-128, AES-192 or AES-256. Keys are identified by IDs which are stored in payload metadata, so keys can be
+	// rotated by changing the encryption key while the previous keys are still returned for decryption.
+	EncryptionKeyProvider interface {
+		// EncryptionKey returns the key new payloads are encrypted with and its ID.
+		EncryptionKey() (keyID string, key []byte, err error)
+		// DecryptionKey returns the key with the given ID.
+		DecryptionKey(keyID string) (key []byte, err error)
+	}
+
+	staticEncryptionKeyProvider struct {
+		keyID string
+		keys  map[string][]byte
+	}
+
+	aesGCMCodec struct {
+		keyProvider EncryptionKeyProvider
+	}
+)
+
+// NewStaticEncryptionKeyProvider creates an EncryptionKeyProvider which encrypts payloads with the key with
+// encryptionKeyID and decrypts them with any of the keys.
+func NewStaticEncryptionKeyProvider(encryptionKeyID string, keys map[string][]byte) EncryptionKeyProvider {
+	return &staticEncryptionKeyProvider{keyID: encryptionKeyID, keys: keys}
+}
+
+func (p *staticEncryptionKeyProvider) EncryptionKey() (string, []byte, error) {
+	key, err := p.DecryptionKey(p.keyID)
+	return p.keyID, key, err
+}
+
+func (p *staticEncryptionKeyProvider) DecryptionKey(keyID string) ([]byte, error) {
+	key, ok := p.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("encryption key %q is not found", keyID)
+	}
+	return key, nil
+}
+
+// NewAESGCMCodec creates a PayloadCodec which encrypts payloads with AES-GCM. The whole payload, including its
+// metadata, is encrypted. The encrypted payload has MetadataEncodingEncrypted encoding and the ID of the key in
+// MetadataEncryptionKeyID metadata.
+func NewAESGCMCodec(keyProvider EncryptionKeyProvider) PayloadCodec {
+	return &aesGCMCodec{keyProvider: keyProvider}
+}
+
+// Encode implements PayloadCodec.Encode.
+func (c *aesGCMCodec) Encode(payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+	keyID, key, err := c.keyProvider.EncryptionKey()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnableToEncode, err)
+	}
+	aead, err := newAESGCM(key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnableToEncode, err)
+	}
+
+	result := make([]*commonpb.Payload, len(payloads))
+	for i, payload := range payloads {
+		data, err := marshalPayload(payload)
+		if err != nil {
+			return nil, err
+		}
+		nonce := make([]byte, aead.NonceSize())
+		if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnableToEncode, err)
+		}
+		result[i] = &commonpb.Payload{
+			Metadata: map[string][]byte{
+				MetadataEncoding:        []byte(MetadataEncodingEncrypted),
+				MetadataEncryptionKeyID: []byte(keyID),
+			},
+			Data: aead.Seal(nonce, nonce, data, []byte(keyID)),
+		}
+	}
+	return result, nil
+}
+
+// Decode implements PayloadCodec.Decode.
+func (c *aesGCMCodec) Decode(payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+	result := make([]*commonpb.Payload, len(payloads))
+	for i, payload := range payloads {
+		if !hasEncoding(payload, MetadataEncodingEncrypted) {
+			result[i] = payload
+			continue
+		}
+		keyID, ok := payload.GetMetadata()[MetadataEncryptionKeyID]
+		if !ok {
+			return nil, ErrEncryptionKeyIDIsNotSet
+		}
+		key, err := c.keyProvider.DecryptionKey(string(keyID))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnableToDecode, err)
+		}
+		aead, err := newAESGCM(key)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnableToDecode, err)
+		}
+		data := payload.GetData()
+		if len(data) < aead.NonceSize() {
+			return nil, fmt.Errorf("%w: encrypted payload is too short", ErrUnableToDecode)
+		}
+		nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+		plaintext, err := aead.Open(nil, nonce, ciphertext, keyID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnableToDecode, err)
+		}
+		if result[i], err = unmarshalPayload(plaintext); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func newAESGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
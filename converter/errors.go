@@ -45,6 +45,20 @@ var (
 	ErrUnableToFindConverter = errors.New("unable to find converter")
 	// ErrTypeNotImplementProtoMessage is returned when value doesn't implement proto.Message.
 	ErrTypeNotImplementProtoMessage = errors.New("type doesn't implement proto.Message")
+	// ErrMessageTypeIsNotSet is returned when proto message type metadata of a payload is not set.
+	ErrMessageTypeIsNotSet = errors.New("payload message type metadata is not set")
+	// ErrMessageTypeIsNotRegistered is returned when proto message type is not found in registry.
+	ErrMessageTypeIsNotRegistered = errors.New("message type is not registered")
+	// ErrMessageTypeMismatch is returned when google.protobuf.Any holds message of different type.
+	ErrMessageTypeMismatch = errors.New("message type mismatch")
+	// ErrUnknownField is returned when JSON payload has field which value type doesn't have.
+	ErrUnknownField = errors.New("unknown field")
+	// ErrSchemaVersionMismatch is returned when JSON payload schema version doesn't match value type and can't be migrated.
+	ErrSchemaVersionMismatch = errors.New("schema version mismatch")
 	// ErrValuePtrIsNotPointer is returned when proto value is not a pointer.
 	ErrValuePtrIsNotPointer = errors.New("not a pointer type")
+	// ErrEncryptionKeyIDIsNotSet is returned when encryption key ID metadata of an encrypted payload is not set.
+	ErrEncryptionKeyIDIsNotSet = errors.New("payload encryption key ID metadata is not set")
+	// ErrBlobKeyIsInvalid is returned when blob reference payload has invalid key.
+	ErrBlobKeyIsInvalid = errors.New("blob key is invalid")
 )
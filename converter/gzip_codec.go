@@ -0,0 +1,124 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package converter
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	commonpb "go.temporal.io/api/common/v1"
+)
+
+// DefaultMaxDecompressedSize is the default maximum size of a payload decompressed by compression codecs.
+const DefaultMaxDecompressedSize = 64 << 20
+
+type (
+	// CompressionCodecOptions are optional parameters for compression codecs.
+	CompressionCodecOptions struct {
+		// Optional: If set payloads are compressed even if the compressed payload is not smaller than the original.
+		// default: false
+		AlwaysEncode bool
+
+		// Optional: Maximum size of a decompressed payload in bytes. Decoding a payload which decompresses to more
+		// bytes fails, so a small crafted payload can't exhaust the memory.
+		// default: DefaultMaxDecompressedSize
+		MaxDecompressedSize int
+	}
+
+	gzipCodec struct {
+		options CompressionCodecOptions
+	}
+)
+
+// NewGzipCodec creates a PayloadCodec which compresses payloads with gzip. The whole payload, including its metadata,
+// is compressed. The compressed payload has MetadataEncodingGzip encoding.
+func NewGzipCodec(options CompressionCodecOptions) PayloadCodec {
+	return &gzipCodec{options: options}
+}
+
+func (o CompressionCodecOptions) maxDecompressedSize() int {
+	if o.MaxDecompressedSize == 0 {
+		return DefaultMaxDecompressedSize
+	}
+	return o.MaxDecompressedSize
+}
+
+// Encode implements PayloadCodec.Encode.
+func (c *gzipCodec) Encode(payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+	result := make([]*commonpb.Payload, len(payloads))
+	for i, payload := range payloads {
+		data, err := marshalPayload(payload)
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(data); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnableToEncode, err)
+		}
+		if err := w.Close(); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnableToEncode, err)
+		}
+		if !c.options.AlwaysEncode && buf.Len() >= len(data) {
+			result[i] = payload
+			continue
+		}
+		result[i] = &commonpb.Payload{
+			Metadata: map[string][]byte{MetadataEncoding: []byte(MetadataEncodingGzip)},
+			Data:     buf.Bytes(),
+		}
+	}
+	return result, nil
+}
+
+// Decode implements PayloadCodec.Decode.
+func (c *gzipCodec) Decode(payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+	result := make([]*commonpb.Payload, len(payloads))
+	for i, payload := range payloads {
+		if !hasEncoding(payload, MetadataEncodingGzip) {
+			result[i] = payload
+			continue
+		}
+		r, err := gzip.NewReader(bytes.NewReader(payload.GetData()))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnableToDecode, err)
+		}
+		maxSize := c.options.maxDecompressedSize()
+		data, err := ioutil.ReadAll(io.LimitReader(r, int64(maxSize)+1))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnableToDecode, err)
+		}
+		if len(data) > maxSize {
+			return nil, fmt.Errorf("%w: decompressed payload exceeds %d bytes", ErrUnableToDecode, maxSize)
+		}
+		if result[i], err = unmarshalPayload(data); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
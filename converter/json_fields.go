@@ -0,0 +1,158 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package converter
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// walkUnknownJSONFields calls fn with payload path of every JSON object key of value (decoded into interface{})
+// which doesn't map to a field of type t. Keys are visited in sorted order. Walking stops when fn returns false.
+// Types which decode themselves (json.Unmarshaler or registered in marshalers) are not walked into.
+func walkUnknownJSONFields(
+	value interface{},
+	t reflect.Type,
+	path string,
+	marshalers map[reflect.Type]JSONTypeMarshaler,
+	fn func(path string) bool,
+) bool {
+	t = indirectType(t)
+	if _, ok := marshalers[t]; ok || reflect.PtrTo(t).Implements(jsonUnmarshalerType) {
+		return true
+	}
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		switch t.Kind() {
+		case reflect.Struct:
+			fields := jsonFields(t)
+			for _, key := range sortedKeys(value) {
+				fieldPath := jsonPath(path, key)
+				fieldType, ok := lookupJSONField(fields, key)
+				if !ok {
+					if !fn(fieldPath) {
+						return false
+					}
+					continue
+				}
+				if !walkUnknownJSONFields(value[key], fieldType, fieldPath, marshalers, fn) {
+					return false
+				}
+			}
+		case reflect.Map:
+			for _, key := range sortedKeys(value) {
+				if !walkUnknownJSONFields(value[key], t.Elem(), jsonPath(path, key), marshalers, fn) {
+					return false
+				}
+			}
+		}
+	case []interface{}:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for i, item := range value {
+				if !walkUnknownJSONFields(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i), marshalers, fn) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// jsonFields returns types of struct fields keyed by their JSON names following encoding/json rules:
+// "json" tag names, skipped "-" fields, and promoted fields of embedded structs.
+// Fields of outer struct take precedence over promoted ones.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	// Embedded structs are walked breadth first, so fields of shallower structs take precedence. Like encoding/json,
+	// every struct type is walked once, which stops at self-referential embedded types like struct{ *Node }.
+	visited := make(map[reflect.Type]bool)
+	next := []reflect.Type{t}
+	for len(next) > 0 {
+		current := next
+		next = nil
+		for _, structType := range current {
+			if visited[structType] {
+				continue
+			}
+			visited[structType] = true
+			for i := 0; i < structType.NumField(); i++ {
+				field := structType.Field(i)
+				tag := field.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name := strings.Split(tag, ",")[0]
+				if field.Anonymous && name == "" && indirectType(field.Type).Kind() == reflect.Struct {
+					next = append(next, indirectType(field.Type))
+					continue
+				}
+				if field.PkgPath != "" && !field.Anonymous {
+					// Unexported field.
+					continue
+				}
+				if name == "" {
+					name = field.Name
+				}
+				if _, ok := fields[name]; !ok {
+					fields[name] = field.Type
+				}
+			}
+		}
+	}
+	return fields
+}
+
+// lookupJSONField finds field by exact JSON name first and then case-insensitively, as encoding/json does.
+func lookupJSONField(fields map[string]reflect.Type, key string) (reflect.Type, bool) {
+	if fieldType, ok := fields[key]; ok {
+		return fieldType, true
+	}
+	for name, fieldType := range fields {
+		if strings.EqualFold(name, key) {
+			return fieldType, true
+		}
+	}
+	return nil, false
+}
+
+// jsonPath appends field to payload path.
+func jsonPath(path string, field string) string {
+	if field == "" {
+		return path
+	}
+	return path + "." + field
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
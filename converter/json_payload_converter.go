@@ -25,34 +25,157 @@
 package converter
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"reflect"
+	"strconv"
 
 	commonpb "go.temporal.io/api/common/v1"
 )
 
-// JSONPayloadConverter converts to/from JSON.
-type JSONPayloadConverter struct {
-}
+type (
+	// JSONPayloadConverter converts to/from JSON.
+	JSONPayloadConverter struct {
+		options JSONPayloadConverterOptions
+	}
+
+	// JSONPayloadConverterOptions represents options for JSONPayloadConverter.
+	JSONPayloadConverterOptions struct {
+		// DisallowUnknownFields makes decoding fail with JSONDecodeError if payload has JSON object key which doesn't
+		// map to a field of the value type. Use it to detect renamed or removed fields of long running workflows
+		// instead of silently decoding them to zero values.
+		DisallowUnknownFields bool
+
+		// UseNumber makes decoding into interface{} values use json.Number instead of float64 for numbers.
+		UseNumber bool
+
+		// OnUnknownField is called with payload path (i.e. "$.items[2].name") of every JSON object key which doesn't
+		// map to a field of the value type. If DisallowUnknownFields is set, it is called only for the first one.
+		// Optional: unknown fields are not reported by default.
+		OnUnknownField func(path string)
+
+		// TypeMarshalers overrides JSON encoding of top level values of specific types. Types are looked up with
+		// pointers stripped, i.e. reflect.TypeOf(MyStruct{}) is used for both MyStruct and *MyStruct values.
+		// Use it for types which can't implement json.Marshaler and json.Unmarshaler.
+		TypeMarshalers map[reflect.Type]JSONTypeMarshaler
+
+		// Migrations holds migration hooks for types implementing JSONSchemaVersioned keyed by type with pointers
+		// stripped. Migration is called when schema version recorded in payload metadata is older than
+		// the current one. If migration is not registered for such type, decoding fails with ErrSchemaVersionMismatch.
+		Migrations map[reflect.Type]JSONMigrationFunc
+	}
+
+	// JSONTypeMarshaler encodes and decodes values of a single type.
+	JSONTypeMarshaler struct {
+		// Marshal encodes value to JSON. Value is of registered type or pointer to it.
+		Marshal func(value interface{}) ([]byte, error)
+		// Unmarshal decodes JSON data to valuePtr. valuePtr is always non nil pointer to registered type.
+		Unmarshal func(data []byte, valuePtr interface{}) error
+	}
+
+	// JSONSchemaVersioned is implemented by types which JSON schema is versioned. Version is recorded in
+	// MetadataJSONSchemaVersion payload metadata. Payloads without this metadata are considered version 0,
+	// therefore versions should start from 1.
+	JSONSchemaVersioned interface {
+		JSONSchemaVersion() int
+	}
+
+	// JSONMigrationFunc migrates JSON data of schema version fromVersion to the current schema version of the type.
+	JSONMigrationFunc func(fromVersion int, data []byte) ([]byte, error)
+
+	// JSONDecodeError is returned by JSONPayloadConverter when payload doesn't match value type.
+	// It matches ErrUnableToDecode with errors.Is.
+	JSONDecodeError struct {
+		// Path is payload path of mismatched value, i.e. "$.items[2].name". Root value path is "$".
+		// Paths of type mismatches are taken from encoding/json errors and may omit array indexes.
+		Path string
+		// Err is the cause.
+		Err error
+	}
+)
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	jsonVersionedType   = reflect.TypeOf((*JSONSchemaVersioned)(nil)).Elem()
+)
 
 // NewJSONPayloadConverter creates new instance of JSONPayloadConverter.
 func NewJSONPayloadConverter() *JSONPayloadConverter {
-	return &JSONPayloadConverter{}
+	return NewJSONPayloadConverterWithOptions(JSONPayloadConverterOptions{})
+}
+
+// NewJSONPayloadConverterWithOptions creates new instance of JSONPayloadConverter with provided options.
+func NewJSONPayloadConverterWithOptions(options JSONPayloadConverterOptions) *JSONPayloadConverter {
+	return &JSONPayloadConverter{
+		options: options,
+	}
 }
 
 // ToPayload converts single value to payload.
 func (c *JSONPayloadConverter) ToPayload(value interface{}) (*commonpb.Payload, error) {
-	data, err := json.Marshal(value)
+	valueType := indirectType(reflect.TypeOf(value))
+
+	var data []byte
+	var err error
+	if marshaler, ok := c.options.TypeMarshalers[valueType]; ok && marshaler.Marshal != nil {
+		data, err = marshaler.Marshal(value)
+	} else {
+		data, err = json.Marshal(value)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrUnableToEncode, err)
 	}
-	return newPayload(data, c), nil
+
+	payload := newPayload(data, c)
+	if version, ok := jsonSchemaVersion(valueType); ok {
+		payload.Metadata[MetadataJSONSchemaVersion] = []byte(strconv.Itoa(version))
+	}
+	return payload, nil
 }
 
 // FromPayload converts single value from payload.
 func (c *JSONPayloadConverter) FromPayload(payload *commonpb.Payload, valuePtr interface{}) error {
-	err := json.Unmarshal(payload.GetData(), valuePtr)
-	if err != nil {
+	data := payload.GetData()
+
+	value := reflect.ValueOf(valuePtr)
+	if value.Kind() == reflect.Ptr && !value.IsNil() {
+		valueType := indirectType(value.Type())
+
+		var err error
+		if data, err = c.migrate(payload, valueType); err != nil {
+			return err
+		}
+		if err := c.checkUnknownFields(data, valueType); err != nil {
+			return err
+		}
+
+		if marshaler, ok := c.options.TypeMarshalers[valueType]; ok && marshaler.Unmarshal != nil {
+			// Allocate intermediate pointers (i.e. for **MyStruct) to pass pointer to registered type.
+			for value.Elem().Kind() == reflect.Ptr {
+				if value.Elem().IsNil() {
+					value.Elem().Set(reflect.New(value.Elem().Type().Elem()))
+				}
+				value = value.Elem()
+			}
+			if err := marshaler.Unmarshal(data, value.Interface()); err != nil {
+				return &JSONDecodeError{Path: "$", Err: err}
+			}
+			return nil
+		}
+	}
+
+	if err := c.unmarshal(data, valuePtr); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return &JSONDecodeError{Path: jsonPath("$", typeErr.Field), Err: err}
+		}
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return &JSONDecodeError{Path: "$", Err: err}
+		}
 		return fmt.Errorf("%w: %v", ErrUnableToDecode, err)
 	}
 	return nil
@@ -67,3 +190,111 @@ func (c *JSONPayloadConverter) ToString(payload *commonpb.Payload) string {
 func (c *JSONPayloadConverter) Encoding() string {
 	return MetadataEncodingJSON
 }
+
+func (c *JSONPayloadConverter) unmarshal(data []byte, valuePtr interface{}) error {
+	if !c.options.UseNumber {
+		return json.Unmarshal(data, valuePtr)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(valuePtr); err != nil {
+		return err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return errors.New("json: invalid data after top-level value")
+	}
+	return nil
+}
+
+// migrate returns payload data migrated to the current schema version of valueType.
+func (c *JSONPayloadConverter) migrate(payload *commonpb.Payload, valueType reflect.Type) ([]byte, error) {
+	currentVersion, ok := jsonSchemaVersion(valueType)
+	if !ok {
+		return payload.GetData(), nil
+	}
+
+	version := 0
+	if versionData, ok := payload.GetMetadata()[MetadataJSONSchemaVersion]; ok {
+		var err error
+		if version, err = strconv.Atoi(string(versionData)); err != nil {
+			return nil, &JSONDecodeError{Path: "$", Err: fmt.Errorf("invalid schema version metadata: %v", err)}
+		}
+	}
+	if version == currentVersion {
+		return payload.GetData(), nil
+	}
+
+	migration := c.options.Migrations[valueType]
+	if version > currentVersion || migration == nil {
+		return nil, &JSONDecodeError{Path: "$", Err: fmt.Errorf("type: %s: payload version %d, current version %d: %w",
+			valueType, version, currentVersion, ErrSchemaVersionMismatch)}
+	}
+	data, err := migration(version, payload.GetData())
+	if err != nil {
+		return nil, &JSONDecodeError{Path: "$", Err: fmt.Errorf("type: %s: migration from version %d failed: %w", valueType, version, err)}
+	}
+	return data, nil
+}
+
+// checkUnknownFields reports JSON object keys of data which don't map to fields of valueType.
+func (c *JSONPayloadConverter) checkUnknownFields(data []byte, valueType reflect.Type) error {
+	if !c.options.DisallowUnknownFields && c.options.OnUnknownField == nil {
+		return nil
+	}
+
+	var tree interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&tree); err != nil {
+		// Invalid JSON is reported by decoding itself.
+		return nil
+	}
+
+	var unknownFieldPath string
+	walkUnknownJSONFields(tree, valueType, "$", c.options.TypeMarshalers, func(path string) bool {
+		if c.options.OnUnknownField != nil {
+			c.options.OnUnknownField(path)
+		}
+		if c.options.DisallowUnknownFields {
+			unknownFieldPath = path
+			return false
+		}
+		return true
+	})
+	if unknownFieldPath != "" {
+		return &JSONDecodeError{Path: unknownFieldPath, Err: ErrUnknownField}
+	}
+	return nil
+}
+
+// Error implements error interface.
+func (e *JSONDecodeError) Error() string {
+	return fmt.Sprintf("%v: %s: %v", ErrUnableToDecode, e.Path, e.Err)
+}
+
+// Unwrap returns the cause.
+func (e *JSONDecodeError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrUnableToDecode.
+func (e *JSONDecodeError) Is(target error) bool {
+	return target == ErrUnableToDecode
+}
+
+// jsonSchemaVersion returns current schema version of valueType if it implements JSONSchemaVersioned.
+func jsonSchemaVersion(valueType reflect.Type) (int, bool) {
+	if valueType == nil || !reflect.PtrTo(valueType).Implements(jsonVersionedType) {
+		return 0, false
+	}
+	return reflect.New(valueType).Interface().(JSONSchemaVersioned).JSONSchemaVersion(), true
+}
+
+// indirectType returns t with all pointers stripped.
+func indirectType(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
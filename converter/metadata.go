@@ -37,4 +37,17 @@ const (
 	MetadataEncodingProtoJSON = "json/protobuf"
 	// MetadataEncodingProto is "binary/protobuf"
 	MetadataEncodingProto = "binary/protobuf"
+	// MetadataEncodingEncrypted is "binary/encrypted"
+	MetadataEncodingEncrypted = "binary/encrypted"
+	// MetadataEncodingGzip is "binary/gzip"
+	MetadataEncodingGzip = "binary/gzip"
+	// MetadataEncodingBlobReference is "binary/blob-reference"
+	MetadataEncodingBlobReference = "binary/blob-reference"
+
+	// MetadataMessageType is "messageType"
+	MetadataMessageType = "messageType"
+	// MetadataJSONSchemaVersion is "json-schema-version"
+	MetadataJSONSchemaVersion = "json-schema-version"
+	// MetadataEncryptionKeyID is "encryption-key-id"
+	MetadataEncryptionKeyID = "encryption-key-id"
 )
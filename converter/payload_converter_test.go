@@ -25,7 +25,9 @@
 package converter
 
 import (
+	"encoding/json"
 	"errors"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -34,6 +36,8 @@ import (
 	commonpb "go.temporal.io/api/common/v1"
 	enumspb "go.temporal.io/api/enums/v1"
 	historypb "go.temporal.io/api/history/v1"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 type testStruct struct {
@@ -133,6 +137,10 @@ func TestProtoPayloadConverter_Gogo(t *testing.T) {
 	assert.Equal(t, int64(1978), wt4.EventId)
 
 	s := pc.ToString(payload)
+	assert.Equal(t, `{"eventId":"1978","eventType":"WorkflowTaskTimedOut","workflowTaskTimedOutEventAttributes":{"scheduledEventId":"2","timeoutType":"ScheduleToStart"}}`, s)
+
+	delete(payload.Metadata, MetadataMessageType)
+	s = pc.ToString(payload)
 	assert.Equal(t, "CLoPGAhqBAgCGAI", s)
 }
 
@@ -163,9 +171,80 @@ func TestProtoPayloadConverter_Google(t *testing.T) {
 	assert.Equal(t, "qwe", wt4.Name)
 
 	s := pc.ToString(payload)
+	assert.Equal(t, `{"name":"qwe","birthDay":"12","type":"TYPEV2_R","valueS":"asd"}`, strings.Replace(s, " ", "", -1))
+
+	delete(payload.Metadata, MetadataMessageType)
+	s = pc.ToString(payload)
 	assert.Equal(t, "CgNxd2UQDDgBQgNhc2Q", s)
 }
 
+func TestProtoPayloadConverters_Interface(t *testing.T) {
+	for _, pc := range []PayloadConverter{NewProtoPayloadConverter(), NewProtoJSONPayloadConverter()} {
+		t.Run(pc.Encoding(), func(t *testing.T) {
+			payload, err := pc.ToPayload(&commonpb.WorkflowType{Name: "qwe"})
+			require.NoError(t, err)
+			assert.Equal(t, "temporal.api.common.v1.WorkflowType", string(payload.Metadata[MetadataMessageType]))
+			var wt interface{}
+			err = pc.FromPayload(payload, &wt)
+			require.NoError(t, err)
+			assert.Equal(t, &commonpb.WorkflowType{Name: "qwe"}, wt)
+
+			payload, err = pc.ToPayload(&GoV2{Name: "qwe", BirthDay: 12})
+			require.NoError(t, err)
+			assert.Equal(t, "protobench.GoV2", string(payload.Metadata[MetadataMessageType]))
+			var wt2 interface{}
+			err = pc.FromPayload(payload, &wt2)
+			require.NoError(t, err)
+			require.IsType(t, &GoV2{}, wt2)
+			assert.Equal(t, "qwe", wt2.(*GoV2).Name)
+			assert.Equal(t, int64(12), wt2.(*GoV2).BirthDay)
+
+			payload.Metadata[MetadataMessageType] = []byte("unknown.Message")
+			var wt3 interface{}
+			err = pc.FromPayload(payload, &wt3)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, ErrMessageTypeIsNotRegistered))
+			assert.Nil(t, wt3)
+		})
+	}
+}
+
+func TestProtoPayloadConverters_Any(t *testing.T) {
+	for _, pc := range []PayloadConverter{NewProtoPayloadConverter(), NewProtoJSONPayloadConverter()} {
+		t.Run(pc.Encoding(), func(t *testing.T) {
+			value, err := anypb.New(&GoV2{Name: "qwe"})
+			require.NoError(t, err)
+			payload, err := pc.ToPayload(value)
+			require.NoError(t, err)
+			assert.Equal(t, "google.protobuf.Any", string(payload.Metadata[MetadataMessageType]))
+
+			var wt *anypb.Any
+			err = pc.FromPayload(payload, &wt)
+			require.NoError(t, err)
+			assert.True(t, wt.MessageIs(&GoV2{}))
+
+			var wt2 interface{}
+			err = pc.FromPayload(payload, &wt2)
+			require.NoError(t, err)
+			require.IsType(t, &anypb.Any{}, wt2)
+
+			var wt3 *GoV2
+			err = pc.FromPayload(payload, &wt3)
+			require.NoError(t, err)
+			assert.Equal(t, "qwe", wt3.Name)
+
+			var wt4 *wrapperspb.StringValue
+			err = pc.FromPayload(payload, &wt4)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, ErrUnableToDecode))
+			assert.Contains(t, err.Error(), ErrMessageTypeMismatch.Error())
+
+			s := pc.ToString(payload)
+			assert.Contains(t, strings.Replace(s, " ", "", -1), `"@type":"type.googleapis.com/protobench.GoV2"`)
+		})
+	}
+}
+
 func TestJsonPayloadConverter(t *testing.T) {
 	pc := NewJSONPayloadConverter()
 
@@ -360,8 +439,9 @@ func TestProtoJsonPayloadConverter_FromPayload_Errors(t *testing.T) {
 	assert.Equal(t, "type: *interface {}: unable to set value", err.Error())
 	assert.True(t, errors.Is(err, ErrUnableToSetValue))
 
-	// supported by JSON serializer but not by ProtoJson
+	// supported by ProtoJson only if message type is recorded in metadata
 	var wt7 interface{}
+	delete(payload.Metadata, MetadataMessageType)
 	err = pc.FromPayload(payload, &wt7)
 	require.Error(t, err)
 	assert.Equal(t, "type: <nil>: type doesn't implement proto.Message", err.Error())
@@ -378,7 +458,7 @@ func TestJsonPayloadConverter_FromPayload_Errors(t *testing.T) {
 	var wt2 *int
 	err = pc.FromPayload(payload, &wt2)
 	require.Error(t, err)
-	assert.Equal(t, "unable to decode: json: cannot unmarshal object into Go value of type int", err.Error())
+	assert.Equal(t, "unable to decode: $: json: cannot unmarshal object into Go value of type int", err.Error())
 
 	var wt3 *testStruct
 	err = pc.FromPayload(payload, wt3)
@@ -423,3 +503,221 @@ func TestJsonPayloadConverter_FromPayload_Errors(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "qwe", wt7.(map[string]interface{})["Name"])
 }
+
+type (
+	testOrderV1 struct {
+		ID    string
+		Items []testOrderItem
+	}
+
+	testOrderItem struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}
+
+	testEmbedded struct {
+		Comment string `json:"comment"`
+	}
+
+	testOrder struct {
+		testEmbedded
+		ID    string          `json:"id"`
+		Items []testOrderItem `json:"items"`
+		Tags  map[string]testOrderItem
+		Raw   json.RawMessage
+	}
+
+	testOrderV2 struct {
+		ID    string          `json:"id"`
+		Items []testOrderItem `json:"items"`
+	}
+
+	testPoint struct {
+		X, Y int
+	}
+
+	testNode struct {
+		*testNode
+		Value int
+	}
+)
+
+func (testOrderV2) JSONSchemaVersion() int {
+	return 2
+}
+
+func TestJsonPayloadConverter_UnknownFields(t *testing.T) {
+	var unknownFields []string
+	pc := NewJSONPayloadConverterWithOptions(JSONPayloadConverterOptions{
+		OnUnknownField: func(path string) {
+			unknownFields = append(unknownFields, path)
+		},
+	})
+	payload := newPayload([]byte(`{"id":"1","comment":"c","items":[{"name":"a","count":1},{"title":"b"}],"Tags":{"x":{"price":1}},"Raw":{"any":1},"Total":3}`), pc)
+
+	var order testOrder
+	err := pc.FromPayload(payload, &order)
+	require.NoError(t, err)
+	assert.Equal(t, "c", order.Comment)
+	assert.Equal(t, []string{"$.Tags.x.price", "$.Total", "$.items[1].title"}, unknownFields)
+
+	unknownFields = nil
+	strict := NewJSONPayloadConverterWithOptions(JSONPayloadConverterOptions{
+		DisallowUnknownFields: true,
+		OnUnknownField: func(path string) {
+			unknownFields = append(unknownFields, path)
+		},
+	})
+	var order2 *testOrder
+	err = strict.FromPayload(payload, &order2)
+	require.Error(t, err)
+	assert.Equal(t, "unable to decode: $.Tags.x.price: unknown field", err.Error())
+	assert.True(t, errors.Is(err, ErrUnableToDecode))
+	assert.True(t, errors.Is(err, ErrUnknownField))
+	var decodeErr *JSONDecodeError
+	require.True(t, errors.As(err, &decodeErr))
+	assert.Equal(t, "$.Tags.x.price", decodeErr.Path)
+	assert.Equal(t, []string{"$.Tags.x.price"}, unknownFields)
+	assert.Nil(t, order2)
+
+	// Field names are matched case-insensitively as encoding/json does.
+	payload = newPayload([]byte(`{"ID":"1","Items":[{"Name":"a"}]}`), pc)
+	err = strict.FromPayload(payload, &order2)
+	require.NoError(t, err)
+	assert.Equal(t, "a", order2.Items[0].Name)
+
+	var i interface{}
+	err = strict.FromPayload(payload, &i)
+	require.NoError(t, err)
+}
+
+func TestJsonPayloadConverter_UnknownFieldsOfSelfReferentialType(t *testing.T) {
+	strict := NewJSONPayloadConverterWithOptions(JSONPayloadConverterOptions{DisallowUnknownFields: true})
+	var node testNode
+	err := strict.FromPayload(newPayload([]byte(`{"Value":1}`), strict), &node)
+	require.NoError(t, err)
+	assert.Equal(t, 1, node.Value)
+
+	err = strict.FromPayload(newPayload([]byte(`{"Value":1,"Next":2}`), strict), &node)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrUnknownField))
+}
+
+func TestJsonPayloadConverter_TypeMismatch(t *testing.T) {
+	pc := NewJSONPayloadConverter()
+	payload := newPayload([]byte(`{"id":"1","Tags":{"x":{"name":"a","count":"many"}}}`), pc)
+
+	var order testOrder
+	err := pc.FromPayload(payload, &order)
+	require.Error(t, err)
+	var decodeErr *JSONDecodeError
+	require.True(t, errors.As(err, &decodeErr))
+	assert.Equal(t, "$.Tags.x.count", decodeErr.Path)
+	assert.True(t, errors.Is(err, ErrUnableToDecode))
+
+	payload = newPayload([]byte(`{"ID":`), pc)
+	err = pc.FromPayload(payload, &order)
+	require.Error(t, err)
+	require.True(t, errors.As(err, &decodeErr))
+	assert.Equal(t, "$", decodeErr.Path)
+}
+
+func TestJsonPayloadConverter_UseNumber(t *testing.T) {
+	pc := NewJSONPayloadConverterWithOptions(JSONPayloadConverterOptions{UseNumber: true})
+	payload, err := pc.ToPayload(map[string]int64{"big": 9007199254740993})
+	require.NoError(t, err)
+
+	var m interface{}
+	err = pc.FromPayload(payload, &m)
+	require.NoError(t, err)
+	assert.Equal(t, json.Number("9007199254740993"), m.(map[string]interface{})["big"])
+
+	err = pc.FromPayload(newPayload([]byte(`{} {}`), pc), &m)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrUnableToDecode))
+}
+
+func TestJsonPayloadConverter_TypeMarshalers(t *testing.T) {
+	pc := NewJSONPayloadConverterWithOptions(JSONPayloadConverterOptions{
+		TypeMarshalers: map[reflect.Type]JSONTypeMarshaler{
+			reflect.TypeOf(testPoint{}): {
+				Marshal: func(value interface{}) ([]byte, error) {
+					p := reflect.Indirect(reflect.ValueOf(value)).Interface().(testPoint)
+					return json.Marshal([]int{p.X, p.Y})
+				},
+				Unmarshal: func(data []byte, valuePtr interface{}) error {
+					var xy []int
+					if err := json.Unmarshal(data, &xy); err != nil {
+						return err
+					}
+					*valuePtr.(*testPoint) = testPoint{X: xy[0], Y: xy[1]}
+					return nil
+				},
+			},
+		},
+	})
+
+	payload, err := pc.ToPayload(&testPoint{X: 1, Y: 2})
+	require.NoError(t, err)
+	assert.Equal(t, "[1,2]", pc.ToString(payload))
+
+	var p testPoint
+	err = pc.FromPayload(payload, &p)
+	require.NoError(t, err)
+	assert.Equal(t, testPoint{X: 1, Y: 2}, p)
+
+	var p2 *testPoint
+	err = pc.FromPayload(payload, &p2)
+	require.NoError(t, err)
+	assert.Equal(t, &testPoint{X: 1, Y: 2}, p2)
+
+	err = pc.FromPayload(newPayload([]byte(`{}`), pc), &p)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrUnableToDecode))
+}
+
+func TestJsonPayloadConverter_Migrations(t *testing.T) {
+	pc := NewJSONPayloadConverter()
+	payload, err := pc.ToPayload(testOrderV2{ID: "1"})
+	require.NoError(t, err)
+	assert.Equal(t, "2", string(payload.Metadata[MetadataJSONSchemaVersion]))
+
+	var order testOrderV2
+	err = pc.FromPayload(payload, &order)
+	require.NoError(t, err)
+	assert.Equal(t, "1", order.ID)
+
+	// Payload created before testOrderV2 became versioned.
+	payloadV0, err := pc.ToPayload(testOrderV1{ID: "1", Items: []testOrderItem{{Name: "a"}}})
+	require.NoError(t, err)
+	_, ok := payloadV0.Metadata[MetadataJSONSchemaVersion]
+	assert.False(t, ok)
+	err = pc.FromPayload(payloadV0, &order)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrSchemaVersionMismatch))
+	assert.True(t, errors.Is(err, ErrUnableToDecode))
+
+	payload.Metadata[MetadataJSONSchemaVersion] = []byte("3")
+	err = pc.FromPayload(payload, &order)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrSchemaVersionMismatch))
+
+	migrating := NewJSONPayloadConverterWithOptions(JSONPayloadConverterOptions{
+		DisallowUnknownFields: true,
+		Migrations: map[reflect.Type]JSONMigrationFunc{
+			reflect.TypeOf(testOrderV2{}): func(fromVersion int, data []byte) ([]byte, error) {
+				assert.Equal(t, 0, fromVersion)
+				var v1 testOrderV1
+				if err := json.Unmarshal(data, &v1); err != nil {
+					return nil, err
+				}
+				return json.Marshal(testOrderV2{ID: v1.ID, Items: v1.Items})
+			},
+		},
+	})
+	var order2 *testOrderV2
+	err = migrating.FromPayload(payloadV0, &order2)
+	require.NoError(t, err)
+	assert.Equal(t, "1", order2.ID)
+	assert.Equal(t, "a", order2.Items[0].Name)
+}
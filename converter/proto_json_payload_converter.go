@@ -38,9 +38,12 @@ import (
 )
 
 // ProtoJSONPayloadConverter converts proto objects to/from JSON.
+// Fully qualified message type name is recorded in MetadataMessageType payload metadata,
+// which allows decoding payloads into interface{} values.
 type ProtoJSONPayloadConverter struct {
 	gogoMarshaler   gogojsonpb.Marshaler
 	gogoUnmarshaler gogojsonpb.Unmarshaler
+	options         ProtoPayloadConverterOptions
 }
 
 var (
@@ -49,9 +52,15 @@ var (
 
 // NewProtoJSONPayloadConverter creates new instance of ProtoJSONPayloadConverter.
 func NewProtoJSONPayloadConverter() *ProtoJSONPayloadConverter {
+	return NewProtoJSONPayloadConverterWithOptions(ProtoPayloadConverterOptions{})
+}
+
+// NewProtoJSONPayloadConverterWithOptions creates new instance of ProtoJSONPayloadConverter with provided options.
+func NewProtoJSONPayloadConverterWithOptions(options ProtoPayloadConverterOptions) *ProtoJSONPayloadConverter {
 	return &ProtoJSONPayloadConverter{
 		gogoMarshaler:   gogojsonpb.Marshaler{},
 		gogoUnmarshaler: gogojsonpb.Unmarshaler{},
+		options:         options,
 	}
 }
 
@@ -74,11 +83,11 @@ func (c *ProtoJSONPayloadConverter) ToPayload(value interface{}) (*commonpb.Payl
 	builtPointer := false
 	for {
 		if valueProto, ok := value.(proto.Message); ok {
-			byteSlice, err := protojson.Marshal(valueProto)
+			byteSlice, err := protojson.MarshalOptions{Resolver: c.options.typeResolver()}.Marshal(valueProto)
 			if err != nil {
 				return nil, fmt.Errorf("%w: %v", ErrUnableToEncode, err)
 			}
-			return newPayload(byteSlice, c), nil
+			return newProtoPayload(byteSlice, c, valueProto), nil
 		}
 		if valueGogoProto, ok := value.(gogoproto.Message); ok {
 			var buf bytes.Buffer
@@ -86,7 +95,7 @@ func (c *ProtoJSONPayloadConverter) ToPayload(value interface{}) (*commonpb.Payl
 			if err != nil {
 				return nil, fmt.Errorf("%w: %v", ErrUnableToEncode, err)
 			}
-			return newPayload(buf.Bytes(), c), nil
+			return newProtoPayload(buf.Bytes(), c, valueGogoProto), nil
 		}
 		if builtPointer {
 			break
@@ -115,6 +124,13 @@ func (c *ProtoJSONPayloadConverter) FromPayload(payload *commonpb.Payload, value
 		return nil
 	}
 
+	// If original value is nil interface (i.e. interface{}), create new instance of type recorded in metadata.
+	if originalValue.Kind() == reflect.Interface && originalValue.IsNil() && len(payload.GetMetadata()[MetadataMessageType]) > 0 {
+		return setProtoInterfaceValue(payload, originalValue, c.options.typeResolver(), func(message interface{}) error {
+			return c.unmarshal(payload, message)
+		})
+	}
+
 	value := originalValue
 	// In case if original value is of value type (i.e. commonpb.WorkflowType), create a pointer to it.
 	if originalValue.Kind() != reflect.Ptr && originalValue.Kind() != reflect.Interface {
@@ -122,8 +138,8 @@ func (c *ProtoJSONPayloadConverter) FromPayload(payload *commonpb.Payload, value
 	}
 
 	protoValue := value.Interface() // protoValue is for sure of pointer type (i.e. *commonpb.WorkflowType).
-	gogoProtoMessage, isGogoProtoMessage := protoValue.(gogoproto.Message)
-	protoMessage, isProtoMessage := protoValue.(proto.Message)
+	_, isGogoProtoMessage := protoValue.(gogoproto.Message)
+	_, isProtoMessage := protoValue.(proto.Message)
 	if !isGogoProtoMessage && !isProtoMessage {
 		return fmt.Errorf("type: %T: %w", protoValue, ErrTypeNotImplementProtoMessage)
 	}
@@ -132,19 +148,9 @@ func (c *ProtoJSONPayloadConverter) FromPayload(payload *commonpb.Payload, value
 	if originalValue.Kind() == reflect.Ptr && originalValue.IsNil() {
 		value = newOfSameType(originalValue)
 		protoValue = value.Interface()
-		if isProtoMessage {
-			protoMessage = protoValue.(proto.Message) // type assertion must always succeed
-		} else if isGogoProtoMessage {
-			gogoProtoMessage = protoValue.(gogoproto.Message) // type assertion must always succeed
-		}
 	}
 
-	var err error
-	if isProtoMessage {
-		err = protojson.Unmarshal(payload.GetData(), protoMessage)
-	} else if isGogoProtoMessage {
-		err = c.gogoUnmarshaler.Unmarshal(bytes.NewReader(payload.GetData()), gogoProtoMessage)
-	}
+	err := c.unmarshal(payload, protoValue)
 	// If original value wasn't a pointer then set value back to where valuePtr points to.
 	if originalValue.Kind() != reflect.Ptr {
 		originalValue.Set(value.Elem())
@@ -162,6 +168,20 @@ func (c *ProtoJSONPayloadConverter) ToString(payload *commonpb.Payload) string {
 	return string(payload.GetData())
 }
 
+func (c *ProtoJSONPayloadConverter) unmarshal(payload *commonpb.Payload, message interface{}) error {
+	// It is important to check for proto.Message first because APIv2 messages also implements gogoproto.Message.
+	if protoMessage, ok := message.(proto.Message); ok {
+		resolver := c.options.typeResolver()
+		return unmarshalProtoMessage(payload, protoMessage, resolver, func(data []byte, message proto.Message) error {
+			return protojson.UnmarshalOptions{Resolver: resolver}.Unmarshal(data, message)
+		})
+	}
+	if gogoProtoMessage, ok := message.(gogoproto.Message); ok {
+		return c.gogoUnmarshaler.Unmarshal(bytes.NewReader(payload.GetData()), gogoProtoMessage)
+	}
+	return fmt.Errorf("type: %T: %w", message, ErrTypeNotImplementProtoMessage)
+}
+
 // Encoding returns MetadataEncodingProtoJSON.
 func (c *ProtoJSONPayloadConverter) Encoding() string {
 	return MetadataEncodingProtoJSON
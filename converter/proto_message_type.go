@@ -0,0 +1,164 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package converter
+
+import (
+	"fmt"
+	"reflect"
+
+	gogojsonpb "github.com/gogo/protobuf/jsonpb"
+	gogoproto "github.com/gogo/protobuf/proto"
+	commonpb "go.temporal.io/api/common/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// anyMessageType is fully qualified name of google.protobuf.Any message.
+var anyMessageType = (&anypb.Any{}).ProtoReflect().Descriptor().FullName()
+
+type (
+	// ProtoTypeResolver resolves proto message types by their fully qualified names and google.protobuf.Any type URLs.
+	// It is used by proto payload converters to decode payloads into interface{} and to expand google.protobuf.Any values.
+	// protoregistry.GlobalTypes is used by default.
+	ProtoTypeResolver interface {
+		protoregistry.MessageTypeResolver
+		protoregistry.ExtensionTypeResolver
+	}
+
+	// ProtoPayloadConverterOptions represents options for proto payload converters.
+	ProtoPayloadConverterOptions struct {
+		// TypeResolver resolves message types written to MetadataMessageType payload metadata.
+		// Messages generated with github.com/gogo/protobuf are always resolved using gogo proto registry.
+		// Optional: defaults to protoregistry.GlobalTypes.
+		TypeResolver ProtoTypeResolver
+	}
+)
+
+func (o ProtoPayloadConverterOptions) typeResolver() ProtoTypeResolver {
+	if o.TypeResolver != nil {
+		return o.TypeResolver
+	}
+	return protoregistry.GlobalTypes
+}
+
+// protoMessageType returns fully qualified name of proto message type or empty string if type is not registered.
+func protoMessageType(value interface{}) string {
+	if valueProto, ok := value.(proto.Message); ok {
+		return string(valueProto.ProtoReflect().Descriptor().FullName())
+	}
+	if valueGogoProto, ok := value.(gogoproto.Message); ok {
+		return gogoproto.MessageName(valueGogoProto)
+	}
+	return ""
+}
+
+// newProtoPayload creates new payload and records fully qualified name of message type in its metadata.
+func newProtoPayload(data []byte, c PayloadConverter, value interface{}) *commonpb.Payload {
+	payload := newPayload(data, c)
+	if messageType := protoMessageType(value); messageType != "" {
+		payload.Metadata[MetadataMessageType] = []byte(messageType)
+	}
+	return payload
+}
+
+// newProtoMessage creates new empty message of type recorded in payload metadata.
+// Message types are looked up in resolver first and then in gogo proto registry.
+func newProtoMessage(payload *commonpb.Payload, resolver ProtoTypeResolver) (interface{}, error) {
+	messageType := string(payload.GetMetadata()[MetadataMessageType])
+	if messageType == "" {
+		return nil, ErrMessageTypeIsNotSet
+	}
+
+	mt, err := resolver.FindMessageByName(protoreflect.FullName(messageType))
+	if err == nil {
+		return mt.New().Interface(), nil
+	}
+	if gogoType := gogoproto.MessageType(messageType); gogoType != nil && gogoType.Kind() == reflect.Ptr {
+		return reflect.New(gogoType.Elem()).Interface(), nil
+	}
+
+	return nil, fmt.Errorf("message type: %s: %w", messageType, ErrMessageTypeIsNotRegistered)
+}
+
+// unmarshalProtoMessage unmarshals payload data into message using unmarshal function.
+// If payload holds google.protobuf.Any and message is of other type, message is unpacked from google.protobuf.Any.
+func unmarshalProtoMessage(
+	payload *commonpb.Payload,
+	message proto.Message,
+	resolver ProtoTypeResolver,
+	unmarshal func(data []byte, message proto.Message) error,
+) error {
+	if string(payload.GetMetadata()[MetadataMessageType]) != string(anyMessageType) ||
+		message.ProtoReflect().Descriptor().FullName() == anyMessageType {
+		return unmarshal(payload.GetData(), message)
+	}
+
+	anyValue := &anypb.Any{}
+	if err := unmarshal(payload.GetData(), anyValue); err != nil {
+		return err
+	}
+	if !anyValue.MessageIs(message) {
+		return fmt.Errorf("type: %s: %w: %s", message.ProtoReflect().Descriptor().FullName(), ErrMessageTypeMismatch, anyValue.GetTypeUrl())
+	}
+	return proto.UnmarshalOptions{Resolver: resolver}.Unmarshal(anyValue.GetValue(), message)
+}
+
+// setProtoInterfaceValue creates new message of type recorded in payload metadata, decodes payload into it with
+// decode function, and sets result to interfaceValue.
+func setProtoInterfaceValue(
+	payload *commonpb.Payload,
+	interfaceValue reflect.Value,
+	resolver ProtoTypeResolver,
+	decode func(message interface{}) error,
+) error {
+	message, err := newProtoMessage(payload, resolver)
+	if err != nil {
+		return fmt.Errorf("type: %s: %w", interfaceValue.Type(), err)
+	}
+	value := reflect.ValueOf(message)
+	if !value.Type().AssignableTo(interfaceValue.Type()) {
+		return fmt.Errorf("type: %s: %w", interfaceValue.Type(), ErrUnableToSetValue)
+	}
+	if err := decode(message); err != nil {
+		return fmt.Errorf("%w: %v", ErrUnableToDecode, err)
+	}
+	interfaceValue.Set(value)
+	return nil
+}
+
+// protoMessageToJSON renders proto message as JSON string.
+func protoMessageToJSON(message interface{}, resolver ProtoTypeResolver) (string, error) {
+	if messageProto, ok := message.(proto.Message); ok {
+		data, err := protojson.MarshalOptions{Resolver: resolver}.Marshal(messageProto)
+		return string(data), err
+	}
+	if messageGogoProto, ok := message.(gogoproto.Message); ok {
+		return (&gogojsonpb.Marshaler{}).MarshalToString(messageGogoProto)
+	}
+	return "", fmt.Errorf("type: %T: %w", message, ErrTypeNotImplementProtoMessage)
+}
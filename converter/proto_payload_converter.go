@@ -35,12 +35,22 @@ import (
 )
 
 // ProtoPayloadConverter converts proto objects to protobuf binary format.
+// Fully qualified message type name is recorded in MetadataMessageType payload metadata,
+// which allows decoding payloads into interface{} values.
 type ProtoPayloadConverter struct {
+	options ProtoPayloadConverterOptions
 }
 
 // NewProtoPayloadConverter creates new instance of ProtoPayloadConverter.
 func NewProtoPayloadConverter() *ProtoPayloadConverter {
-	return &ProtoPayloadConverter{}
+	return NewProtoPayloadConverterWithOptions(ProtoPayloadConverterOptions{})
+}
+
+// NewProtoPayloadConverterWithOptions creates new instance of ProtoPayloadConverter with provided options.
+func NewProtoPayloadConverterWithOptions(options ProtoPayloadConverterOptions) *ProtoPayloadConverter {
+	return &ProtoPayloadConverter{
+		options: options,
+	}
 }
 
 // ToPayload converts single proto value to payload.
@@ -62,14 +72,14 @@ func (c *ProtoPayloadConverter) ToPayload(value interface{}) (*commonpb.Payload,
 			if err != nil {
 				return nil, fmt.Errorf("%w: %v", ErrUnableToEncode, err)
 			}
-			return newPayload(byteSlice, c), nil
+			return newProtoPayload(byteSlice, c, valueProto), nil
 		}
 		if valueGogoProto, ok := value.(gogoproto.Message); ok {
 			data, err := gogoproto.Marshal(valueGogoProto)
 			if err != nil {
 				return nil, fmt.Errorf("%w: %v", ErrUnableToEncode, err)
 			}
-			return newPayload(data, c), nil
+			return newProtoPayload(data, c, valueGogoProto), nil
 		}
 		if builtPointer {
 			break
@@ -93,6 +103,13 @@ func (c *ProtoPayloadConverter) FromPayload(payload *commonpb.Payload, valuePtr
 		return fmt.Errorf("type: %T: %w", valuePtr, ErrUnableToSetValue)
 	}
 
+	// If original value is nil interface (i.e. interface{}), create new instance of type recorded in metadata.
+	if originalValue.Kind() == reflect.Interface && originalValue.IsNil() && len(payload.GetMetadata()[MetadataMessageType]) > 0 {
+		return setProtoInterfaceValue(payload, originalValue, c.options.typeResolver(), func(message interface{}) error {
+			return c.unmarshal(payload, message)
+		})
+	}
+
 	value := originalValue
 	// In case if original value is of value type (i.e. commonpb.WorkflowType), create a pointer to it.
 	if originalValue.Kind() != reflect.Ptr && originalValue.Kind() != reflect.Interface {
@@ -100,8 +117,8 @@ func (c *ProtoPayloadConverter) FromPayload(payload *commonpb.Payload, valuePtr
 	}
 
 	protoValue := value.Interface() // protoValue is for sure of pointer type (i.e. *commonpb.WorkflowType).
-	gogoProtoMessage, isGogoProtoMessage := protoValue.(gogoproto.Message)
-	protoMessage, isProtoMessage := protoValue.(proto.Message)
+	_, isGogoProtoMessage := protoValue.(gogoproto.Message)
+	_, isProtoMessage := protoValue.(proto.Message)
 	if !isGogoProtoMessage && !isProtoMessage {
 		return fmt.Errorf("type: %T: %w", protoValue, ErrTypeNotImplementProtoMessage)
 	}
@@ -110,19 +127,9 @@ func (c *ProtoPayloadConverter) FromPayload(payload *commonpb.Payload, valuePtr
 	if originalValue.Kind() == reflect.Ptr && originalValue.IsNil() {
 		value = newOfSameType(originalValue)
 		protoValue = value.Interface()
-		if isProtoMessage {
-			protoMessage = protoValue.(proto.Message) // type assertion must always succeed
-		} else if isGogoProtoMessage {
-			gogoProtoMessage = protoValue.(gogoproto.Message) // type assertion must always succeed
-		}
 	}
 
-	var err error
-	if isProtoMessage {
-		err = proto.Unmarshal(payload.GetData(), protoMessage)
-	} else if isGogoProtoMessage {
-		err = gogoproto.Unmarshal(payload.GetData(), gogoProtoMessage)
-	}
+	err := c.unmarshal(payload, protoValue)
 	// If original value wasn't a pointer then set value back to where valuePtr points to.
 	if originalValue.Kind() != reflect.Ptr {
 		originalValue.Set(value.Elem())
@@ -136,11 +143,33 @@ func (c *ProtoPayloadConverter) FromPayload(payload *commonpb.Payload, valuePtr
 }
 
 // ToString converts payload object into human readable string.
+// If message type recorded in payload metadata is registered, message is rendered as JSON.
 func (c *ProtoPayloadConverter) ToString(payload *commonpb.Payload) string {
+	if message, err := newProtoMessage(payload, c.options.typeResolver()); err == nil {
+		if err := c.unmarshal(payload, message); err == nil {
+			if s, err := protoMessageToJSON(message, c.options.typeResolver()); err == nil {
+				return s
+			}
+		}
+	}
 	// We can't do anything better here.
 	return base64.RawStdEncoding.EncodeToString(payload.GetData())
 }
 
+func (c *ProtoPayloadConverter) unmarshal(payload *commonpb.Payload, message interface{}) error {
+	// It is important to check for proto.Message first because APIv2 messages also implements gogoproto.Message.
+	if protoMessage, ok := message.(proto.Message); ok {
+		resolver := c.options.typeResolver()
+		return unmarshalProtoMessage(payload, protoMessage, resolver, func(data []byte, message proto.Message) error {
+			return proto.UnmarshalOptions{Resolver: resolver}.Unmarshal(data, message)
+		})
+	}
+	if gogoProtoMessage, ok := message.(gogoproto.Message); ok {
+		return gogoproto.Unmarshal(payload.GetData(), gogoProtoMessage)
+	}
+	return fmt.Errorf("type: %T: %w", message, ErrTypeNotImplementProtoMessage)
+}
+
 // Encoding returns MetadataEncodingProto.
 func (c *ProtoPayloadConverter) Encoding() string {
 	return MetadataEncodingProto
@@ -38,6 +38,9 @@ type (
 	// All code in the interceptor is executed in the workflow.Context of a workflow. So all the rules and restrictions
 	// that apply to the workflow code should be obeyed by the interceptor implementation.
 	// Use workflow.IsReplaying(ctx) to filter out duplicated calls.
+	// Embed WorkflowInboundCallsInterceptorBase to keep compiling when methods are added to the interface.
+	// HandleSignal and HandleQuery get encoded arguments, unlike ExecuteWorkflow, as they are decoded later by the
+	// workflow code.
 	WorkflowInboundCallsInterceptor = internal.WorkflowInboundCallsInterceptor
 
 	// WorkflowOutboundCallsInterceptor is an interface that can be implemented to intercept calls to the SDK APIs done
@@ -35,6 +35,7 @@ import (
 
 	"go.temporal.io/sdk/converter"
 	"go.temporal.io/sdk/internal/common"
+	"go.temporal.io/sdk/internal/common/metrics"
 	"go.temporal.io/sdk/log"
 )
 
@@ -151,7 +152,15 @@ type (
 
 // GetActivityInfo returns information about currently executing activity.
 func GetActivityInfo(ctx context.Context) ActivityInfo {
-	env := getActivityEnv(ctx)
+	i := getActivityOutboundCallsInterceptor(ctx)
+	return i.GetActivityInfo(ctx)
+}
+
+func (a *activityEnvironmentInterceptor) GetActivityInfo(ctx context.Context) ActivityInfo {
+	return getActivityEnv(ctx).getActivityInfo()
+}
+
+func (env *activityEnvironment) getActivityInfo() ActivityInfo {
 	return ActivityInfo{
 		ActivityID:        env.activityID,
 		ActivityType:      env.activityType,
@@ -190,14 +199,39 @@ func GetHeartbeatDetails(ctx context.Context, d ...interface{}) error {
 
 // GetActivityLogger returns a logger that can be used in activity
 func GetActivityLogger(ctx context.Context) log.Logger {
+	i := getActivityOutboundCallsInterceptor(ctx)
+	return i.GetActivityLogger(ctx)
+}
+
+func (a *activityEnvironmentInterceptor) GetActivityLogger(ctx context.Context) log.Logger {
 	env := getActivityEnv(ctx)
 	return env.logger
 }
 
-// GetActivityMetricsScope returns a metrics scope that can be used in activity
+// GetActivityMetricsHandler returns a metrics handler that can be used in activity
+func GetActivityMetricsHandler(ctx context.Context) MetricsHandler {
+	i := getActivityOutboundCallsInterceptor(ctx)
+	return i.GetActivityMetricsHandler(ctx)
+}
+
+func (a *activityEnvironmentInterceptor) GetActivityMetricsHandler(ctx context.Context) MetricsHandler {
+	env := getActivityEnv(ctx)
+	return env.metricsHandler
+}
+
+// GetActivityMetricsScope returns a metrics scope that can be used in activity,
+// reporting to the metrics handler. Histograms keep their buckets only if the client MetricsHandler was created with
+// NewTallyMetricsHandler, otherwise histogram values are reported as gauges and histogram durations as timers.
+//
+// Deprecated: use GetActivityMetricsHandler instead.
 func GetActivityMetricsScope(ctx context.Context) tally.Scope {
+	i := getActivityOutboundCallsInterceptor(ctx)
+	return i.GetActivityMetricsScope(ctx)
+}
+
+func (a *activityEnvironmentInterceptor) GetActivityMetricsScope(ctx context.Context) tally.Scope {
 	env := getActivityEnv(ctx)
-	return env.metricsScope
+	return metrics.NewTallyScope(env.metricsHandler, nil)
 }
 
 // GetWorkerStopChannel returns a read-only channel. The closure of this channel indicates the activity worker is stopping.
@@ -218,6 +252,11 @@ func GetWorkerStopChannel(ctx context.Context) <-chan struct{} {
 // details - the details that you provided here can be seen in the worflow when it receives TimeoutError, you
 // can check error TimeoutType()/Details().
 func RecordActivityHeartbeat(ctx context.Context, details ...interface{}) {
+	i := getActivityOutboundCallsInterceptor(ctx)
+	i.RecordActivityHeartbeat(ctx, details...)
+}
+
+func (a *activityEnvironmentInterceptor) RecordActivityHeartbeat(ctx context.Context, details ...interface{}) {
 	env := getActivityEnv(ctx)
 	if env.isLocalActivity {
 		// no-op for local activity
@@ -257,7 +296,7 @@ func WithActivityTask(
 	taskQueue string,
 	invoker ServiceInvoker,
 	logger log.Logger,
-	scope tally.Scope,
+	metricsHandler MetricsHandler,
 	dataConverter converter.DataConverter,
 	workerStopChannel <-chan struct{},
 	contextPropagators []ContextPropagator,
@@ -301,7 +340,7 @@ func WithActivityTask(
 			RunID: task.WorkflowExecution.RunId,
 			ID:    task.WorkflowExecution.WorkflowId},
 		logger:           logger,
-		metricsScope:     scope,
+		metricsHandler:   metricsHandler,
 		deadline:         deadline,
 		heartbeatTimeout: heartbeatTimeout,
 		scheduledTime:    scheduled,
@@ -0,0 +1,312 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	commonpb "go.temporal.io/api/common/v1"
+	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/workflowservice/v1"
+	"golang.org/x/time/rate"
+)
+
+const (
+	defaultBatchOperationConcurrency = 10
+)
+
+type (
+	// BatchOperationOptions configuration parameters for Client.ExecuteBatchOperation.
+	BatchOperationOptions struct {
+		// Query - visibility query selecting workflow executions the operation is applied to.
+		// NewVisibilityQuery can be used to build it. Executions are retrieved using Client.ScanWorkflow.
+		// This is a required field.
+		Query string
+
+		// Operation - operation applied to every workflow execution matching the Query.
+		// Use NewBatchSignalOperation, NewBatchCancelOperation, NewBatchTerminateOperation or NewBatchResetOperation.
+		// This is a required field.
+		Operation BatchOperation
+
+		// Concurrency - maximum number of workflow executions the operation is applied to concurrently.
+		// Optional: default to 10.
+		Concurrency int
+
+		// RPS - maximum number of workflow executions the operation is applied to per second.
+		// Optional: default to no limit.
+		RPS float64
+
+		// TrafficController - optional, invoked before the operation is applied to every workflow execution with
+		// the operation name as method and WorkflowExecution as request. An error returned by it is reported as
+		// the failure of that execution. Can be used to pause or throttle the batch further.
+		TrafficController TrafficController
+
+		// OnProgress - optional, invoked after the operation is applied to every workflow execution.
+		// It is called sequentially and must not block.
+		OnProgress func(progress BatchOperationProgress)
+	}
+
+	// BatchOperation is an operation applied to every workflow execution selected by a batch.
+	BatchOperation interface {
+		// Name of the operation, passed to BatchOperationOptions.TrafficController as method.
+		Name() string
+		// Execute applies the operation to a single workflow execution.
+		Execute(ctx context.Context, client Client, execution WorkflowExecution) error
+	}
+
+	// BatchOperationProgress is reported to BatchOperationOptions.OnProgress.
+	BatchOperationProgress struct {
+		// Processed is the number of workflow executions the operation was applied to so far.
+		Processed int
+		Succeeded int
+		Failed    int
+	}
+
+	// BatchOperationReport is the result of Client.ExecuteBatchOperation.
+	BatchOperationReport struct {
+		// Processed is the number of workflow executions the operation was applied to.
+		Processed int
+		Succeeded int
+		// Failures contains executions the operation failed for.
+		Failures []*BatchOperationFailure
+	}
+
+	// BatchOperationFailure is a failure of the batch operation for a single workflow execution.
+	BatchOperationFailure struct {
+		Execution WorkflowExecution
+		Error     error
+	}
+
+	// BatchResetType defines the event a workflow execution is reset to by NewBatchResetOperation.
+	BatchResetType int
+
+	batchSignalOperation struct {
+		signalName string
+		arg        interface{}
+	}
+
+	batchCancelOperation struct{}
+
+	batchTerminateOperation struct {
+		reason  string
+		details []interface{}
+	}
+
+	batchResetOperation struct {
+		reason    string
+		resetType BatchResetType
+	}
+
+	batchOperationRunner struct {
+		client            Client
+		operation         BatchOperation
+		limiter           *rate.Limiter
+		trafficController TrafficController
+		onProgress        func(progress BatchOperationProgress)
+
+		lock   sync.Mutex
+		report BatchOperationReport
+	}
+)
+
+const (
+	// BatchResetLastWorkflowTask resets a workflow execution to its last completed workflow task.
+	BatchResetLastWorkflowTask BatchResetType = iota
+	// BatchResetFirstWorkflowTask resets a workflow execution to its first completed workflow task.
+	BatchResetFirstWorkflowTask
+)
+
+// NewBatchSignalOperation creates a BatchOperation which signals workflow executions.
+func NewBatchSignalOperation(signalName string, arg interface{}) BatchOperation {
+	return &batchSignalOperation{signalName: signalName, arg: arg}
+}
+
+// NewBatchCancelOperation creates a BatchOperation which requests cancellation of workflow executions.
+func NewBatchCancelOperation() BatchOperation {
+	return &batchCancelOperation{}
+}
+
+// NewBatchTerminateOperation creates a BatchOperation which terminates workflow executions.
+func NewBatchTerminateOperation(reason string, details ...interface{}) BatchOperation {
+	return &batchTerminateOperation{reason: reason, details: details}
+}
+
+// NewBatchResetOperation creates a BatchOperation which resets workflow executions to the completed workflow task
+// defined by resetType.
+func NewBatchResetOperation(reason string, resetType BatchResetType) BatchOperation {
+	return &batchResetOperation{reason: reason, resetType: resetType}
+}
+
+// ExecuteBatchOperation applies the operation to all workflow executions matching the query.
+func (wc *WorkflowClient) ExecuteBatchOperation(ctx context.Context, options BatchOperationOptions) (*BatchOperationReport, error) {
+	return wc.getInterceptor().ExecuteBatchOperation(ctx, options)
+}
+
+func (w *workflowClientInterceptor) ExecuteBatchOperation(ctx context.Context, options BatchOperationOptions) (*BatchOperationReport, error) {
+	if options.Query == "" {
+		return nil, errors.New("batch operation query is required")
+	}
+	if options.Operation == nil {
+		return nil, errors.New("batch operation is required")
+	}
+	concurrency := options.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultBatchOperationConcurrency
+	}
+	limit := rate.Inf
+	if options.RPS > 0 {
+		limit = rate.Limit(options.RPS)
+	}
+
+	runner := &batchOperationRunner{
+		client:            w.client,
+		operation:         options.Operation,
+		limiter:           rate.NewLimiter(limit, 1),
+		trafficController: options.TrafficController,
+		onProgress:        options.OnProgress,
+	}
+
+	executions := make(chan WorkflowExecution)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for execution := range executions {
+				runner.execute(ctx, execution)
+			}
+		}()
+	}
+
+	var err error
+	iter := w.ListWorkflowExecutions(ctx, ListWorkflowOptions{Query: options.Query, Source: ListWorkflowSourceScan})
+Loop:
+	for iter.HasNext() {
+		info, nextErr := iter.Next()
+		if nextErr != nil {
+			err = nextErr
+			break
+		}
+		select {
+		case executions <- WorkflowExecution{ID: info.WorkflowID, RunID: info.RunID}:
+		case <-ctx.Done():
+			err = ctx.Err()
+			break Loop
+		}
+	}
+	close(executions)
+	wg.Wait()
+
+	report := runner.report
+	return &report, err
+}
+
+func (r *batchOperationRunner) execute(ctx context.Context, execution WorkflowExecution) {
+	err := r.limiter.Wait(ctx)
+	if err == nil && r.trafficController != nil {
+		err = r.trafficController.CheckCallAllowed(ctx, r.operation.Name(), execution, nil)
+	}
+	if err == nil {
+		err = r.operation.Execute(ctx, r.client, execution)
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.report.Processed++
+	if err != nil {
+		r.report.Failures = append(r.report.Failures, &BatchOperationFailure{Execution: execution, Error: err})
+	} else {
+		r.report.Succeeded++
+	}
+	if r.onProgress != nil {
+		r.onProgress(BatchOperationProgress{
+			Processed: r.report.Processed,
+			Succeeded: r.report.Succeeded,
+			Failed:    len(r.report.Failures),
+		})
+	}
+}
+
+func (o *batchSignalOperation) Name() string {
+	return "Signal"
+}
+
+func (o *batchSignalOperation) Execute(ctx context.Context, client Client, execution WorkflowExecution) error {
+	return client.SignalWorkflow(ctx, execution.ID, execution.RunID, o.signalName, o.arg)
+}
+
+func (o *batchCancelOperation) Name() string {
+	return "Cancel"
+}
+
+func (o *batchCancelOperation) Execute(ctx context.Context, client Client, execution WorkflowExecution) error {
+	return client.CancelWorkflow(ctx, execution.ID, execution.RunID)
+}
+
+func (o *batchTerminateOperation) Name() string {
+	return "Terminate"
+}
+
+func (o *batchTerminateOperation) Execute(ctx context.Context, client Client, execution WorkflowExecution) error {
+	return client.TerminateWorkflow(ctx, execution.ID, execution.RunID, o.reason, o.details...)
+}
+
+func (o *batchResetOperation) Name() string {
+	return "Reset"
+}
+
+func (o *batchResetOperation) Execute(ctx context.Context, client Client, execution WorkflowExecution) error {
+	var resetEventID int64
+	iter := client.GetWorkflowHistory(ctx, execution.ID, execution.RunID, false, enumspb.HISTORY_EVENT_FILTER_TYPE_ALL_EVENT)
+	for iter.HasNext() {
+		event, err := iter.Next()
+		if err != nil {
+			return err
+		}
+		if event.GetEventType() != enumspb.EVENT_TYPE_WORKFLOW_TASK_COMPLETED {
+			continue
+		}
+		resetEventID = event.GetEventId()
+		if o.resetType == BatchResetFirstWorkflowTask {
+			break
+		}
+	}
+	if resetEventID == 0 {
+		return fmt.Errorf("workflow execution %v doesn't have completed workflow tasks to reset to", execution.ID)
+	}
+
+	_, err := client.ResetWorkflowExecution(ctx, &workflowservice.ResetWorkflowExecutionRequest{
+		WorkflowExecution: &commonpb.WorkflowExecution{
+			WorkflowId: execution.ID,
+			RunId:      execution.RunID,
+		},
+		Reason:                    o.reason,
+		WorkflowTaskFinishEventId: resetEventID,
+	})
+	return err
+}
@@ -0,0 +1,189 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+	commonpb "go.temporal.io/api/common/v1"
+	enumspb "go.temporal.io/api/enums/v1"
+	historypb "go.temporal.io/api/history/v1"
+	"go.temporal.io/api/serviceerror"
+	workflowpb "go.temporal.io/api/workflow/v1"
+	"go.temporal.io/api/workflowservice/v1"
+	"go.temporal.io/api/workflowservicemock/v1"
+)
+
+type (
+	batchOperationTestSuite struct {
+		suite.Suite
+		mockCtrl *gomock.Controller
+		service  *workflowservicemock.MockWorkflowServiceClient
+		client   Client
+	}
+
+	rejectingTrafficController struct {
+		workflowID string
+	}
+)
+
+func (tc *rejectingTrafficController) CheckCallAllowed(_ context.Context, _ string, req, _ interface{}) error {
+	if req.(WorkflowExecution).ID == tc.workflowID {
+		return errors.New("rejected")
+	}
+	return nil
+}
+
+func TestBatchOperationSuite(t *testing.T) {
+	suite.Run(t, new(batchOperationTestSuite))
+}
+
+func (s *batchOperationTestSuite) SetupTest() {
+	s.mockCtrl = gomock.NewController(s.T())
+	s.service = workflowservicemock.NewMockWorkflowServiceClient(s.mockCtrl)
+	s.client = NewServiceClient(s.service, nil, ClientOptions{})
+}
+
+func (s *batchOperationTestSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *batchOperationTestSuite) expectScan(workflowIDs ...string) {
+	var executions []*workflowpb.WorkflowExecutionInfo
+	for _, id := range workflowIDs {
+		executions = append(executions, &workflowpb.WorkflowExecutionInfo{
+			Execution: &commonpb.WorkflowExecution{WorkflowId: id, RunId: id + "-run"},
+		})
+	}
+	s.service.EXPECT().ScanWorkflowExecutions(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&workflowservice.ScanWorkflowExecutionsResponse{Executions: executions}, nil).
+		Do(func(_ interface{}, req *workflowservice.ScanWorkflowExecutionsRequest, _ ...interface{}) {
+			s.Equal("WorkflowType = 'batchWorkflow'", req.GetQuery())
+		})
+}
+
+func (s *batchOperationTestSuite) TestTerminate() {
+	s.expectScan("wid1", "wid2", "wid3")
+	s.service.EXPECT().TerminateWorkflowExecution(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *workflowservice.TerminateWorkflowExecutionRequest, _ ...interface{}) (*workflowservice.TerminateWorkflowExecutionResponse, error) {
+			s.Equal(req.GetWorkflowExecution().GetWorkflowId()+"-run", req.GetWorkflowExecution().GetRunId())
+			s.Equal("incident", req.GetReason())
+			if req.GetWorkflowExecution().GetWorkflowId() == "wid2" {
+				return nil, serviceerror.NewNotFound("")
+			}
+			return &workflowservice.TerminateWorkflowExecutionResponse{}, nil
+		}).Times(3)
+
+	var progress []BatchOperationProgress
+	report, err := s.client.ExecuteBatchOperation(context.Background(), BatchOperationOptions{
+		Query:       NewVisibilityQuery().WorkflowType("batchWorkflow").String(),
+		Operation:   NewBatchTerminateOperation("incident"),
+		Concurrency: 2,
+		RPS:         1000,
+		OnProgress: func(p BatchOperationProgress) {
+			progress = append(progress, p)
+		},
+	})
+	s.NoError(err)
+	s.Equal(3, report.Processed)
+	s.Equal(2, report.Succeeded)
+	s.Equal(1, len(report.Failures))
+	s.Equal(WorkflowExecution{ID: "wid2", RunID: "wid2-run"}, report.Failures[0].Execution)
+	s.IsType(&serviceerror.NotFound{}, report.Failures[0].Error)
+	s.Equal(3, len(progress))
+	s.Equal(BatchOperationProgress{Processed: 3, Succeeded: 2, Failed: 1}, progress[2])
+}
+
+func (s *batchOperationTestSuite) TestSignalWithTrafficController() {
+	s.expectScan("wid1", "wid2")
+	s.service.EXPECT().SignalWorkflowExecution(gomock.Any(), gomock.Any(), gomock.Any()).Return(&workflowservice.SignalWorkflowExecutionResponse{}, nil).
+		Do(func(_ interface{}, req *workflowservice.SignalWorkflowExecutionRequest, _ ...interface{}) {
+			s.Equal("wid1", req.GetWorkflowExecution().GetWorkflowId())
+			s.Equal("signal", req.GetSignalName())
+		})
+
+	report, err := s.client.ExecuteBatchOperation(context.Background(), BatchOperationOptions{
+		Query:             NewVisibilityQuery().WorkflowType("batchWorkflow").String(),
+		Operation:         NewBatchSignalOperation("signal", "arg"),
+		TrafficController: &rejectingTrafficController{workflowID: "wid2"},
+	})
+	s.NoError(err)
+	s.Equal(2, report.Processed)
+	s.Equal(1, report.Succeeded)
+	s.Equal(1, len(report.Failures))
+	s.EqualError(report.Failures[0].Error, "rejected")
+}
+
+func (s *batchOperationTestSuite) TestReset() {
+	s.expectScan("wid1")
+	history := &workflowservice.GetWorkflowExecutionHistoryResponse{
+		History: &historypb.History{
+			Events: []*historypb.HistoryEvent{
+				{EventId: 1, EventType: enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_STARTED},
+				{EventId: 4, EventType: enumspb.EVENT_TYPE_WORKFLOW_TASK_COMPLETED},
+				{EventId: 5, EventType: enumspb.EVENT_TYPE_ACTIVITY_TASK_SCHEDULED},
+				{EventId: 9, EventType: enumspb.EVENT_TYPE_WORKFLOW_TASK_COMPLETED},
+			},
+		},
+	}
+	s.service.EXPECT().GetWorkflowExecutionHistory(gomock.Any(), gomock.Any(), gomock.Any()).Return(history, nil)
+	s.service.EXPECT().ResetWorkflowExecution(gomock.Any(), gomock.Any(), gomock.Any()).Return(&workflowservice.ResetWorkflowExecutionResponse{}, nil).
+		Do(func(_ interface{}, req *workflowservice.ResetWorkflowExecutionRequest, _ ...interface{}) {
+			s.Equal(DefaultNamespace, req.GetNamespace())
+			s.Equal("wid1-run", req.GetWorkflowExecution().GetRunId())
+			s.Equal(int64(9), req.GetWorkflowTaskFinishEventId())
+			s.NotEmpty(req.GetRequestId())
+		})
+
+	report, err := s.client.ExecuteBatchOperation(context.Background(), BatchOperationOptions{
+		Query:     NewVisibilityQuery().WorkflowType("batchWorkflow").String(),
+		Operation: NewBatchResetOperation("bad deployment", BatchResetLastWorkflowTask),
+	})
+	s.NoError(err)
+	s.Equal(1, report.Succeeded)
+	s.Empty(report.Failures)
+}
+
+func (s *batchOperationTestSuite) TestScanError() {
+	s.service.EXPECT().ScanWorkflowExecutions(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, serviceerror.NewInvalidArgument("bad query"))
+
+	report, err := s.client.ExecuteBatchOperation(context.Background(), BatchOperationOptions{
+		Query:     "bad query",
+		Operation: NewBatchCancelOperation(),
+	})
+	s.IsType(&serviceerror.InvalidArgument{}, err)
+	s.Equal(0, report.Processed)
+}
+
+func (s *batchOperationTestSuite) TestValidation() {
+	_, err := s.client.ExecuteBatchOperation(context.Background(), BatchOperationOptions{Operation: NewBatchCancelOperation()})
+	s.Error(err)
+	_, err = s.client.ExecuteBatchOperation(context.Background(), BatchOperationOptions{Query: "WorkflowId = 'wid'"})
+	s.Error(err)
+}
@@ -27,6 +27,7 @@ package internal
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
 	"time"
@@ -270,6 +271,28 @@ type (
 		//  - InternalServiceError
 		CountWorkflow(ctx context.Context, request *workflowservice.CountWorkflowExecutionsRequest) (*workflowservice.CountWorkflowExecutionsResponse, error)
 
+		// ListWorkflowExecutions returns an iterator over workflow executions matching options.Query.
+		// Next pages are requested from the server as the iterator advances, so callers don't need to deal
+		// with page tokens. Memo and search attributes of the returned executions are decoded.
+		// Use NewVisibilityQuery to build the query. Supported queries depend on options.Source
+		// (see ListWorkflow, ScanWorkflow and ListArchivedWorkflow).
+		// The errors iterator can return:
+		//  - BadRequestError
+		//  - InternalServiceError
+		ListWorkflowExecutions(ctx context.Context, options ListWorkflowOptions) WorkflowExecutionIterator
+
+		// CountWorkflowExecutions gets number of workflow executions matching the query (see CountWorkflow).
+		// The errors it can return:
+		//  - BadRequestError
+		//  - InternalServiceError
+		CountWorkflowExecutions(ctx context.Context, query string) (int64, error)
+
+		// ExecuteBatchOperation applies the operation to all workflow executions matching the query in
+		// options. The operation is applied with bounded concurrency and rate. The call blocks until all
+		// matching executions are processed and returns the report with executions the operation failed for.
+		// An error is returned with the partial report if listing the executions fails or ctx is canceled.
+		ExecuteBatchOperation(ctx context.Context, options BatchOperationOptions) (*BatchOperationReport, error)
+
 		// GetSearchAttributes returns valid search attributes keys and value types.
 		// The search attributes can be used in query of List/Scan/Count APIs. Adding new search attributes requires temporal server
 		// to update dynamic config ValidSearchAttributes.
@@ -295,6 +318,28 @@ type (
 		//  - QueryFailError
 		QueryWorkflow(ctx context.Context, workflowID string, runID string, queryType string, args ...interface{}) (converter.EncodedValue, error)
 
+		// UpdateWorkflow sends an update request to a running workflow execution and waits for the result returned by
+		// the update handler registered with workflow.SetUpdateHandler. The request is delivered as a signal and the
+		// result is polled with a query, so updates work with any server version. The call blocks until the update
+		// completes, the workflow execution is closed or ctx is done, so ctx should have a deadline. The workflow keeps
+		// the results of the last 1000 completed updates only, so the result of an update has to be waited for before
+		// that many newer updates complete. Use UpdateWorkflowWithOptions to set the update ID, so the call can be
+		// retried without running the update twice.
+		// - workflowID is required.
+		// - runID can be default(empty string). if empty string then it will pick the running execution of that workflow ID.
+		// - updateName is the name the handler is registered with.
+		// - args... are the optional update parameters.
+		// The errors it can return:
+		//  - EntityNotExistError
+		//  - QueryFailError
+		//  - error returned by the update handler
+		UpdateWorkflow(ctx context.Context, workflowID string, runID string, updateName string, args ...interface{}) (converter.EncodedValue, error)
+
+		// UpdateWorkflowWithOptions sends an update request to a running workflow execution and waits for the result
+		// returned by the update handler. See UpdateWorkflowWithOptionsRequest and UpdateWorkflow for more
+		// information.
+		UpdateWorkflowWithOptions(ctx context.Context, request *UpdateWorkflowWithOptionsRequest) (converter.EncodedValue, error)
+
 		// QueryWorkflowWithOptions queries a given workflow execution and returns the query result synchronously.
 		// See QueryWorkflowWithOptionsRequest and QueryWorkflowWithOptionsResponse for more information.
 		// The errors it can return:
@@ -335,15 +380,30 @@ type (
 		// default: localhost:7233
 		HostPort string
 
+		// Optional: To set multiple host:port endpoints of the same cluster for this client to connect to. A single
+		// connection is created for all the endpoints and calls are distributed between them according to
+		// ConnectionOptions.EndpointSelection. Takes precedence over HostPort.
+		// default: nil
+		HostPorts []string
+
 		// Optional: To set the namespace name for this client to work with.
 		// default: default
 		Namespace string
 
 		// Optional: Logger framework can use to log.
+		// Use log.NewStructuredLogger, log.NewZapAdapter or log.NewLogrusAdapter to write to an existing logging library.
 		// default: default logger provided.
 		Logger log.Logger
 
-		// Optional: Metrics to be reported.
+		// Optional: Metrics handler for reporting metrics.
+		// Use NewTallyMetricsHandler to report through a tally scope, NewPrometheusMetricsHandler to expose metrics in
+		// Prometheus text format or implement MetricsHandler to plug in any other metrics library.
+		// To report metrics through OpenTelemetry use go.temporal.io/sdk/contrib/opentelemetry.NewMetricsHandler.
+		// default: no metrics.
+		MetricsHandler MetricsHandler
+
+		// Optional: Tally scope for reporting metrics. Ignored if MetricsHandler is set.
+		// Deprecated: use MetricsHandler with NewTallyMetricsHandler instead.
 		// Default metrics are Prometheus compatible but default separator (.) should be replaced with some other character:
 		// opts := tally.ScopeOptions{
 		//   Separator: "_",
@@ -382,10 +442,13 @@ type (
 		Identity string
 
 		// Optional: Sets DataConverter to customize serialization/deserialization of arguments in Temporal
+		// Wrap it with converter.NewCodecDataConverter to encrypt or compress payloads before they are sent to the server.
 		// default: defaultDataConverter, an combination of google protobuf converter, gogo protobuf converter and json converter
 		DataConverter converter.DataConverter
 
 		// Optional: Sets opentracing Tracer that is to be used to emit tracing information
+		// For OpenTelemetry tracing use the interceptor and context propagator of go.temporal.io/sdk/contrib/opentelemetry
+		// instead.
 		// default: no tracer - opentracing.NoopTracer
 		Tracer opentracing.Tracer
 
@@ -404,6 +467,20 @@ type (
 		// Optional parameter that is designed to be used *in tests*. It gets invoked last in
 		// the gRPC interceptor chain and can be used to induce artificial failures in test scenarios.
 		TrafficController TrafficController
+
+		// Optional: Sets interceptors that are invoked for every Client call before arguments are converted to
+		// payloads. The first interceptor in the list is the outermost link of the chain.
+		// default: no interceptors
+		Interceptors []ClientInterceptor
+
+		// Optional: If set, search attributes passed to StartWorkflowOptions and SignalWithStartWorkflow are validated
+		// against the search attributes registered on the server (see Client.GetSearchAttributes). Unknown search
+		// attributes and values of a wrong type are rejected before the request is sent to the server.
+		// Search attributes set from workflow code (ChildWorkflowOptions and UpsertSearchAttributes) are not rejected,
+		// because the result would depend on the schema at the time of replay. Workers log a warning for them instead.
+		// A worker which fails to load the search attributes when it starts logs a warning and doesn't check them.
+		// default: false
+		SearchAttributesValidation bool
 	}
 
 	// HeadersProvider returns a map of gRPC headers that should be used on every request.
@@ -423,6 +500,11 @@ type (
 		// TLS configures connection level security credentials.
 		TLS *tls.Config
 
+		// ClientCertificateProvider provides a client certificate for every TLS handshake with the server, which allows
+		// to rotate certificates without recreating the client. It overrides TLS.GetClientCertificate and enables TLS
+		// with default settings if TLS is nil. See NewFileClientCertificateProvider.
+		ClientCertificateProvider ClientCertificateProvider
+
 		// Authority specifies the value to be used as the :authority pseudo-header.
 		// This value only used when TLS is nil.
 		Authority string
@@ -459,9 +541,17 @@ type (
 		KeepAlivePermitWithoutStream bool
 
 		// MaxPayloadSize is a number of bytes that gRPC would allow to travel to and from server. Defaults to 64 MB.
+		// It doesn't raise the blob size limits of the server, use converter.NewBlobStoreCodec to keep large payloads out of history.
 		MaxPayloadSize int
+
+		// EndpointSelection defines how calls are distributed between endpoints when ClientOptions.HostPorts
+		// has more than one endpoint. Defaults to EndpointSelectionRoundRobin.
+		EndpointSelection EndpointSelection
 	}
 
+	// EndpointSelection defines how the client chooses between multiple endpoints.
+	EndpointSelection int
+
 	// StartWorkflowOptions configuration parameters for starting a workflow execution.
 	// The current timeout resolution implementation is in seconds and uses math.Ceil(d.Seconds()) as the duration. But is
 	// subjected to change in the future.
@@ -536,6 +626,21 @@ type (
 		SearchAttributes map[string]interface{}
 	}
 
+	// ListWorkflowOptions configuration parameters for listing workflow executions with Client.ListWorkflowExecutions.
+	ListWorkflowOptions struct {
+		// Query - visibility query, the SQL WHERE clause. NewVisibilityQuery can be used to build it.
+		// Optional: all workflow executions of the namespace are listed if empty.
+		Query string
+
+		// PageSize - maximum number of workflow executions requested from the server in one call.
+		// Optional: defaulted by the server.
+		PageSize int32
+
+		// Source - visibility API which is used to list workflow executions.
+		// Optional: defaulted to ListWorkflowSourceList.
+		Source ListWorkflowSource
+	}
+
 	// RetryPolicy defines the retry policy.
 	// Note that the history of activity with retry policy will be different: the started event will be written down into
 	// history only when the activity completes or "finally" timeouts/fails. And the started event only records the last
@@ -599,14 +704,36 @@ type (
 	}
 )
 
+// ListWorkflowSource defines visibility API used by Client.ListWorkflowExecutions.
+type ListWorkflowSource int
+
+const (
+	// ListWorkflowSourceList lists workflow executions using Client.ListWorkflow.
+	ListWorkflowSourceList ListWorkflowSource = iota
+	// ListWorkflowSourceScan lists workflow executions using Client.ScanWorkflow. Executions are not ordered
+	// but scanning is faster when retrieving large amount of workflow executions.
+	ListWorkflowSourceScan
+	// ListWorkflowSourceArchived lists archived workflow executions using Client.ListArchivedWorkflow.
+	ListWorkflowSourceArchived
+)
+
+const (
+	// EndpointSelectionRoundRobin distributes calls between all the endpoints which pass gRPC health check.
+	EndpointSelectionRoundRobin EndpointSelection = iota
+	// EndpointSelectionPriorityFailover sends all the calls to the first healthy endpoint in the order they are
+	// listed in ClientOptions.HostPorts. The client fails over to the next endpoint when the connection to the
+	// current one is lost.
+	EndpointSelectionPriorityFailover
+)
+
 // NewClient creates an instance of a workflow client
 func NewClient(options ClientOptions) (Client, error) {
 	if options.Namespace == "" {
 		options.Namespace = DefaultNamespace
 	}
 
-	// Initializes the root metric scope.  These tags are included on each metric which creates a child scope from it.
-	options.MetricsScope = metrics.GetRootScope(options.MetricsScope, options.Namespace)
+	// Initializes the root metrics handler. These tags are included on each metric created from it.
+	options.MetricsHandler = metrics.GetRootHandler(options.metricsHandler(), options.Namespace)
 
 	if options.HostPort == "" {
 		options.HostPort = LocalHostPort
@@ -629,15 +756,19 @@ func NewClient(options ClientOptions) (Client, error) {
 		return nil, err
 	}
 
-	return NewServiceClient(workflowservice.NewWorkflowServiceClient(connection), connection, options), nil
+	shared := newSharedConnection(connection)
+	client := NewServiceClient(workflowservice.NewWorkflowServiceClient(connection), shared.acquire(), options)
+	client.connection = shared
+	return client, nil
 }
 
 func newDialParameters(options *ClientOptions) dialParameters {
 	return dialParameters{
 		UserConnectionOptions: options.ConnectionOptions,
 		HostPort:              options.HostPort,
-		RequiredInterceptors:  requiredInterceptors(options.MetricsScope, options.HeadersProvider, options.TrafficController),
+		RequiredInterceptors:  requiredInterceptors(options.MetricsHandler, options.HeadersProvider, options.TrafficController),
 		DefaultServiceConfig:  defaultServiceConfig,
+		Endpoints:             options.HostPorts,
 	}
 }
 
@@ -662,24 +793,36 @@ func NewServiceClient(workflowServiceClient workflowservice.WorkflowServiceClien
 		options.Tracer = opentracing.NoopTracer{}
 	}
 
-	return &WorkflowClient{
+	client := &WorkflowClient{
 		workflowService:    workflowServiceClient,
 		connectionCloser:   connectionCloser,
 		namespace:          options.Namespace,
 		registry:           newRegistry(),
-		metricsScope:       options.MetricsScope,
+		metricsHandler:     options.metricsHandler(),
 		logger:             options.Logger,
 		identity:           options.Identity,
 		dataConverter:      options.DataConverter,
 		contextPropagators: options.ContextPropagators,
 		tracer:             options.Tracer,
+
+		searchAttributesValidation: options.SearchAttributesValidation,
 	}
+	client.interceptor = newClientInterceptors(client, options.Interceptors)
+	return client
+}
+
+func newClientInterceptors(client *WorkflowClient, interceptors []ClientInterceptor) ClientOutboundInterceptor {
+	var interceptor ClientOutboundInterceptor = &workflowClientInterceptor{client: client}
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor = interceptors[i].InterceptClient(interceptor)
+	}
+	return interceptor
 }
 
 // NewNamespaceClient creates an instance of a namespace client, to manager lifecycle of namespaces.
 func NewNamespaceClient(options ClientOptions) (NamespaceClient, error) {
-	// Initializes the root metric scope.  These tags are included on each metric which creates a child scope from it.
-	options.MetricsScope = metrics.GetRootScope(options.MetricsScope, metrics.NoneTagValue)
+	// Initializes the root metrics handler. These tags are included on each metric created from it.
+	options.MetricsHandler = metrics.GetRootHandler(options.metricsHandler(), metrics.NoneTagValue)
 
 	if options.HostPort == "" {
 		options.HostPort = LocalHostPort
@@ -700,20 +843,49 @@ func NewNamespaceClient(options ClientOptions) (NamespaceClient, error) {
 	return newNamespaceServiceClient(workflowservice.NewWorkflowServiceClient(connection), connection, options), nil
 }
 
-func newNamespaceServiceClient(workflowServiceClient workflowservice.WorkflowServiceClient, clientConn *grpc.ClientConn, options ClientOptions) NamespaceClient {
+// NewNamespaceClientFromClient creates an instance of a namespace client which shares the connection, logger,
+// metrics handler and identity with the client. The connection is closed when both clients are closed.
+func NewNamespaceClientFromClient(c Client) (NamespaceClient, error) {
+	wc, ok := c.(*WorkflowClient)
+	if !ok {
+		return nil, fmt.Errorf("unable to create namespace client from %T", c)
+	}
+	if wc.connection == nil {
+		return nil, errors.New("client doesn't own a connection which can be shared")
+	}
+	options := ClientOptions{
+		MetricsHandler: wc.metricsHandler,
+		Logger:         wc.logger,
+		Identity:       wc.identity,
+	}
+	return newNamespaceServiceClient(wc.workflowService, wc.connection.acquire(), options), nil
+}
+
+func newNamespaceServiceClient(workflowServiceClient workflowservice.WorkflowServiceClient, connectionCloser io.Closer, options ClientOptions) NamespaceClient {
 	if options.Identity == "" {
 		options.Identity = getWorkerIdentity("")
 	}
 
 	return &namespaceClient{
 		workflowService:  workflowServiceClient,
-		connectionCloser: clientConn,
-		metricsScope:     options.MetricsScope,
+		connectionCloser: connectionCloser,
+		metricsHandler:   options.metricsHandler(),
 		logger:           options.Logger,
 		identity:         options.Identity,
 	}
 }
 
+// metricsHandler returns MetricsHandler if set, otherwise a handler reporting to the deprecated MetricsScope.
+func (o *ClientOptions) metricsHandler() MetricsHandler {
+	if o.MetricsHandler != nil {
+		return o.MetricsHandler
+	}
+	if o.MetricsScope != nil {
+		return metrics.NewTallyHandler(o.MetricsScope)
+	}
+	return metrics.NopHandler
+}
+
 // NewValue creates a new converter.EncodedValue which can be used to decode binary data returned by Temporal.  For example:
 // User had Activity.RecordHeartbeat(ctx, "my-heartbeat") and then got response from calling Client.DescribeWorkflowExecution.
 // The response contains binary field PendingActivityInfo.HeartbeatDetails,
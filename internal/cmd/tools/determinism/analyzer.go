@@ -0,0 +1,370 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+)
+
+type (
+	// issue is a non-deterministic construct reachable from a workflow function
+	issue struct {
+		File     string `json:"file"`
+		Line     int    `json:"line"`
+		Column   int    `json:"column"`
+		Category string `json:"category"`
+		Message  string `json:"message"`
+		Workflow string `json:"workflow"`
+		Function string `json:"function"`
+	}
+
+	// checker walks call graph of registered workflow functions within the loaded packages
+	checker struct {
+		fset     *token.FileSet
+		packages []*packageInfo
+		funcs    map[string]funcDecl // declarations by types.Func.FullName
+		reported map[reportKey]bool
+		issues   []issue
+	}
+
+	// reportKey identifies a reported issue. A function called by several workflows is reported for each of them.
+	reportKey struct {
+		workflow string
+		pos      token.Pos
+	}
+
+	funcDecl struct {
+		decl *ast.FuncDecl
+		pkg  *packageInfo
+	}
+
+	// walk state of a single workflow
+	workflowWalk struct {
+		workflow string
+		visited  map[string]bool
+	}
+)
+
+// issue categories
+const (
+	categoryTime      = "time"
+	categoryRandom    = "random"
+	categoryGoroutine = "goroutine"
+	categoryChannel   = "channel"
+	categorySelect    = "select"
+	categoryMapRange  = "map-range"
+)
+
+var (
+	// names of the methods which register workflow function passed as the first argument
+	registerWorkflowMethods = map[string]bool{"RegisterWorkflow": true, "RegisterWorkflowWithOptions": true}
+
+	// functions which are allowed to run non-deterministic code passed to them
+	sideEffectFuncs = map[string]bool{
+		"go.temporal.io/sdk/workflow.SideEffect":        true,
+		"go.temporal.io/sdk/workflow.MutableSideEffect": true,
+		"go.temporal.io/sdk/internal.SideEffect":        true,
+		"go.temporal.io/sdk/internal.MutableSideEffect": true,
+	}
+
+	// functions which run activity function passed to them outside of the workflow code
+	localActivityFuncs = map[string]bool{
+		"go.temporal.io/sdk/workflow.ExecuteLocalActivity": true,
+		"go.temporal.io/sdk/internal.ExecuteLocalActivity": true,
+	}
+
+	// non-deterministic functions of the time package and their workflow replacements
+	timeFuncs = map[string]string{
+		"Now":       "workflow.Now",
+		"Since":     "workflow.Now",
+		"Until":     "workflow.Now",
+		"Sleep":     "workflow.Sleep",
+		"After":     "workflow.NewTimer",
+		"AfterFunc": "workflow.NewTimer",
+		"NewTimer":  "workflow.NewTimer",
+		"Tick":      "workflow.NewTimer",
+		"NewTicker": "workflow.NewTimer",
+	}
+
+	// functions of math/rand which are deterministic when seeded deterministically
+	seededRandFuncs = map[string]bool{"New": true, "NewSource": true, "NewZipf": true}
+)
+
+func (i issue) String() string {
+	if i.Function != i.Workflow {
+		return fmt.Sprintf("%v:%v:%v: %v (workflow %v via %v)", i.File, i.Line, i.Column, i.Message, i.Workflow, i.Function)
+	}
+	return fmt.Sprintf("%v:%v:%v: %v (workflow %v)", i.File, i.Line, i.Column, i.Message, i.Workflow)
+}
+
+func newChecker(fset *token.FileSet, packages []*packageInfo) *checker {
+	c := &checker{
+		fset:     fset,
+		packages: packages,
+		funcs:    make(map[string]funcDecl),
+		reported: make(map[reportKey]bool),
+	}
+	for _, pkg := range packages {
+		for _, file := range pkg.files {
+			for _, d := range file.Decls {
+				decl, ok := d.(*ast.FuncDecl)
+				if !ok || decl.Body == nil {
+					continue
+				}
+				if f, ok := pkg.info.Defs[decl.Name].(*types.Func); ok {
+					c.funcs[f.FullName()] = funcDecl{decl: decl, pkg: pkg}
+				}
+			}
+		}
+	}
+	return c
+}
+
+// check returns issues sorted by position.
+func (c *checker) check() []issue {
+	for _, pkg := range c.packages {
+		for _, file := range pkg.files {
+			ast.Inspect(file, func(n ast.Node) bool {
+				if call, ok := n.(*ast.CallExpr); ok {
+					c.checkRegistration(pkg, call)
+				}
+				return true
+			})
+		}
+	}
+	sort.Slice(c.issues, func(i, j int) bool {
+		a, b := c.issues[i], c.issues[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.Column != b.Column {
+			return a.Column < b.Column
+		}
+		return a.Workflow < b.Workflow
+	})
+	return c.issues
+}
+
+func (c *checker) checkRegistration(pkg *packageInfo, call *ast.CallExpr) {
+	sel, ok := unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok || !registerWorkflowMethods[sel.Sel.Name] || len(call.Args) == 0 {
+		return
+	}
+	switch fn := unparen(call.Args[0]).(type) {
+	case *ast.FuncLit:
+		w := &workflowWalk{workflow: "func literal at " + c.fset.Position(fn.Pos()).String(), visited: make(map[string]bool)}
+		c.walk(w, pkg, w.workflow, fn.Body)
+	default:
+		f := funcObject(pkg.info, fn)
+		if f == nil {
+			return
+		}
+		w := &workflowWalk{workflow: funcName(f), visited: make(map[string]bool)}
+		c.walkFunc(w, f)
+	}
+}
+
+func (c *checker) walkFunc(w *workflowWalk, f *types.Func) {
+	key := f.FullName()
+	if w.visited[key] {
+		return
+	}
+	w.visited[key] = true
+	fd, ok := c.funcs[key]
+	if !ok || hasIgnoreDirective(fd.decl.Doc) {
+		return
+	}
+	c.walk(w, fd.pkg, funcName(f), fd.decl.Body)
+}
+
+func (c *checker) walk(w *workflowWalk, pkg *packageInfo, function string, body ast.Node) {
+	report := func(node ast.Node, category, message string) {
+		c.report(w, pkg, function, node, category, message)
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.GoStmt:
+			report(n, categoryGoroutine, "go statement is not deterministic, use workflow.Go")
+		case *ast.SelectStmt:
+			report(n, categorySelect, "select statement is not deterministic, use workflow.Selector")
+			// channel operations of the cases are covered by the select issue
+			for _, clause := range n.Body.List {
+				for _, stmt := range clause.(*ast.CommClause).Body {
+					c.walk(w, pkg, function, stmt)
+				}
+			}
+			return false
+		case *ast.SendStmt:
+			report(n, categoryChannel, "send to native channel is not deterministic, use workflow.Channel")
+		case *ast.UnaryExpr:
+			if n.Op == token.ARROW {
+				report(n, categoryChannel, "receive from native channel is not deterministic, use workflow.Channel")
+			}
+		case *ast.RangeStmt:
+			if t := pkg.info.TypeOf(n.X); t != nil {
+				switch t.Underlying().(type) {
+				case *types.Map:
+					report(n, categoryMapRange, "range over map is not deterministic, use workflow.DeterministicKeys")
+				case *types.Chan:
+					report(n, categoryChannel, "range over native channel is not deterministic, use workflow.Channel")
+				}
+			}
+		case *ast.CallExpr:
+			return c.walkCall(w, pkg, function, n, report)
+		}
+		return true
+	})
+}
+
+// walkCall checks the call and follows it if callee is declared in the loaded packages.
+// Returns false if arguments of the call must not be inspected.
+func (c *checker) walkCall(w *workflowWalk, pkg *packageInfo, function string, call *ast.CallExpr, report func(ast.Node, string, string)) bool {
+	if ident, ok := unparen(call.Fun).(*ast.Ident); ok && ident.Name == "make" && len(call.Args) > 0 {
+		if _, isBuiltin := pkg.info.Uses[ident].(*types.Builtin); isBuiltin {
+			if _, isChan := call.Args[0].(*ast.ChanType); isChan {
+				report(call, categoryChannel, "native channel is not deterministic, use workflow.NewChannel")
+			}
+		}
+		return true
+	}
+	f := funcObject(pkg.info, call.Fun)
+	if f == nil {
+		return true
+	}
+	if sideEffectFuncs[f.FullName()] {
+		return false
+	}
+	if localActivityFuncs[f.FullName()] {
+		// function literal passed as the activity is not workflow code, the other arguments are
+		for _, arg := range call.Args {
+			if _, ok := unparen(arg).(*ast.FuncLit); !ok {
+				c.walk(w, pkg, function, arg)
+			}
+		}
+		return false
+	}
+	if f.Pkg() != nil && f.Type().(*types.Signature).Recv() == nil {
+		switch f.Pkg().Path() {
+		case "time":
+			if replacement, ok := timeFuncs[f.Name()]; ok {
+				report(call, categoryTime, fmt.Sprintf("time.%v is not deterministic, use %v", f.Name(), replacement))
+			}
+		case "math/rand":
+			if !seededRandFuncs[f.Name()] {
+				report(call, categoryRandom, fmt.Sprintf("rand.%v is not deterministic, use workflow.SideEffect", f.Name()))
+			}
+		case "crypto/rand":
+			report(call, categoryRandom, fmt.Sprintf("rand.%v is not deterministic, use workflow.SideEffect", f.Name()))
+		}
+	}
+	c.walkFunc(w, f)
+	return true
+}
+
+func (c *checker) report(w *workflowWalk, pkg *packageInfo, function string, node ast.Node, category, message string) {
+	key := reportKey{workflow: w.workflow, pos: node.Pos()}
+	if c.reported[key] {
+		return
+	}
+	position := c.fset.Position(node.Pos())
+	if lines := pkg.ignored[position.Filename]; lines[position.Line] || lines[position.Line-1] {
+		return
+	}
+	c.reported[key] = true
+	c.issues = append(c.issues, issue{
+		File:     position.Filename,
+		Line:     position.Line,
+		Column:   position.Column,
+		Category: category,
+		Message:  message,
+		Workflow: w.workflow,
+		Function: function,
+	})
+}
+
+// funcObject resolves function or method referenced by the expression.
+func funcObject(info *types.Info, expr ast.Expr) *types.Func {
+	var obj types.Object
+	switch e := unparen(expr).(type) {
+	case *ast.Ident:
+		obj = info.Uses[e]
+	case *ast.SelectorExpr:
+		if sel, ok := info.Selections[e]; ok {
+			obj = sel.Obj()
+		} else {
+			obj = info.Uses[e.Sel]
+		}
+	}
+	f, _ := obj.(*types.Func)
+	return f
+}
+
+// funcName returns name of the function qualified by package name or receiver type.
+func funcName(f *types.Func) string {
+	if recv := f.Type().(*types.Signature).Recv(); recv != nil {
+		t := recv.Type()
+		if p, ok := t.(*types.Pointer); ok {
+			t = p.Elem()
+		}
+		if named, ok := t.(*types.Named); ok {
+			return named.Obj().Name() + "." + f.Name()
+		}
+		return f.Name()
+	}
+	if f.Pkg() == nil {
+		return f.Name()
+	}
+	return f.Pkg().Name() + "." + f.Name()
+}
+
+func hasIgnoreDirective(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+	for _, comment := range doc.List {
+		if strings.HasPrefix(comment.Text, ignoreDirective) {
+			return true
+		}
+	}
+	return false
+}
+
+func unparen(expr ast.Expr) ast.Expr {
+	for {
+		p, ok := expr.(*ast.ParenExpr)
+		if !ok {
+			return expr
+		}
+		expr = p.X
+	}
+}
@@ -0,0 +1,104 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/importer"
+	"go/token"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func checkSample(t *testing.T) []issue {
+	fset := token.NewFileSet()
+	pkg, err := loadPackage(fset, importer.ForCompiler(fset, "source", nil), filepath.Join("testdata", "src", "sample"), "sample")
+	require.NoError(t, err)
+	require.NotNil(t, pkg)
+	require.Empty(t, pkg.typeErrors)
+	return newChecker(fset, []*packageInfo{pkg}).check()
+}
+
+func TestChecker(t *testing.T) {
+	// The sample package declares its own ExecuteLocalActivity as it can't import the workflow package.
+	localActivityFuncs["sample.ExecuteLocalActivity"] = true
+	defer delete(localActivityFuncs, "sample.ExecuteLocalActivity")
+	issues := checkSample(t)
+
+	var actual []string
+	for _, i := range issues {
+		assert.Equal(t, "sample.go", filepath.Base(i.File))
+		actual = append(actual, fmt.Sprintf("%v:%v %v %v", i.Line, i.Category, i.Workflow, i.Function))
+	}
+	expected := []string{
+		"42:goroutine func literal at testdata/src/sample/sample.go:41:21 func literal at testdata/src/sample/sample.go:41:21",
+		"47:time sample.SampleWorkflow sample.SampleWorkflow",
+		"48:random sample.SampleWorkflow sample.SampleWorkflow",
+		"50:channel sample.SampleWorkflow sample.SampleWorkflow",
+		"51:channel sample.SampleWorkflow sample.SampleWorkflow",
+		"52:select sample.SampleWorkflow sample.SampleWorkflow",
+		"58:map-range sample.SampleWorkflow sample.SampleWorkflow",
+		"69:channel workflows.MethodWorkflow workflows.MethodWorkflow",
+		"69:time workflows.MethodWorkflow workflows.MethodWorkflow",
+		"77:time func literal at testdata/src/sample/sample.go:41:21 sample.helper",
+		"77:time sample.SampleWorkflow sample.helper",
+		"77:time workflows.MethodWorkflow sample.helper",
+		"94:time sample.LocalActivityWorkflow sample.LocalActivityWorkflow",
+	}
+	assert.Equal(t, expected, actual)
+}
+
+func TestLoadPackageTypeErrors(t *testing.T) {
+	fset := token.NewFileSet()
+	pkg, err := loadPackage(fset, importer.ForCompiler(fset, "source", nil), filepath.Join("testdata", "src", "broken"), "broken")
+	require.NoError(t, err)
+	require.Len(t, pkg.typeErrors, 1)
+	assert.Contains(t, pkg.typeErrors[0].Error(), "broken.go:28:2")
+	assert.Contains(t, pkg.typeErrors[0].Error(), "undefinedFunc")
+}
+
+func TestPrintIssues(t *testing.T) {
+	issues := checkSample(t)
+
+	var text bytes.Buffer
+	require.NoError(t, printIssues(&text, issues, false))
+	assert.Contains(t, text.String(),
+		"testdata/src/sample/sample.go:77:6: time.Now is not deterministic, use workflow.Now (workflow sample.SampleWorkflow via sample.helper)\n")
+
+	var encoded bytes.Buffer
+	require.NoError(t, printIssues(&encoded, issues, true))
+	var decoded []issue
+	require.NoError(t, json.Unmarshal(encoded.Bytes(), &decoded))
+	assert.Equal(t, issues, decoded)
+
+	encoded.Reset()
+	require.NoError(t, printIssues(&encoded, nil, true))
+	assert.Equal(t, "[]\n", encoded.String())
+}
@@ -0,0 +1,285 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type (
+	// command line config params
+	config struct {
+		jsonOutput bool
+		patterns   []string
+	}
+
+	// loaded and type checked package
+	packageInfo struct {
+		path       string
+		files      []*ast.File
+		info       *types.Info
+		ignored    map[string]map[int]bool // lines with the ignore directive by file name
+		typeErrors []error
+	}
+)
+
+// ignoreDirective suppresses issues reported on the same or the next line.
+// Placed in the doc comment of a function it excludes the whole function from the check.
+const ignoreDirective = "//determinism:ignore"
+
+// command line utility that reports non-deterministic constructs in workflow code.
+// Workflow functions are found by calls to RegisterWorkflow and RegisterWorkflowWithOptions,
+// and every function they call from the checked packages is inspected too. Usage as follows:
+//
+//  go run ./internal/cmd/tools/determinism [-json] ./...
+//
+// Exits with code 1 if any issues are found. Packages which fail to type check are reported and
+// the tool exits with code -1, as issues of the code without type information might be missed.
+func main() {
+	var cfg config
+	flag.BoolVar(&cfg.jsonOutput, "json", false, "print issues as a JSON array")
+	flag.Parse()
+	cfg.patterns = flag.Args()
+	if len(cfg.patterns) == 0 {
+		cfg.patterns = []string{"."}
+	}
+
+	issues, typeErrors, err := run(&cfg)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(-1)
+	}
+	for _, typeErr := range typeErrors {
+		fmt.Fprintln(os.Stderr, typeErr)
+	}
+	if err := printIssues(os.Stdout, issues, cfg.jsonOutput); err != nil {
+		fmt.Println(err)
+		os.Exit(-1)
+	}
+	if len(typeErrors) > 0 {
+		os.Exit(-1)
+	}
+	if len(issues) > 0 {
+		os.Exit(1)
+	}
+}
+
+// run returns issues found in the packages matching the patterns together with type errors of the packages.
+func run(cfg *config) ([]issue, []error, error) {
+	dirs, err := expandPatterns(cfg.patterns)
+	if err != nil {
+		return nil, nil, err
+	}
+	fset := token.NewFileSet()
+	imp := importer.ForCompiler(fset, "source", nil)
+	var packages []*packageInfo
+	var typeErrors []error
+	for _, dir := range dirs {
+		importPath, err := dirImportPath(dir)
+		if err != nil {
+			return nil, nil, err
+		}
+		pkg, err := loadPackage(fset, imp, dir, importPath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load %v: %w", dir, err)
+		}
+		if pkg != nil {
+			packages = append(packages, pkg)
+			typeErrors = append(typeErrors, pkg.typeErrors...)
+		}
+	}
+	return newChecker(fset, packages).check(), typeErrors, nil
+}
+
+func printIssues(w io.Writer, issues []issue, jsonOutput bool) error {
+	if jsonOutput {
+		if issues == nil {
+			issues = []issue{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(issues)
+	}
+	for _, i := range issues {
+		if _, err := fmt.Fprintln(w, i.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expandPatterns converts package patterns to directories. Only directory patterns
+// optionally ending with /... are supported.
+func expandPatterns(patterns []string) ([]string, error) {
+	var dirs []string
+	seen := make(map[string]bool)
+	add := func(dir string) {
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	for _, pattern := range patterns {
+		if !strings.HasSuffix(pattern, "...") {
+			add(filepath.Clean(pattern))
+			continue
+		}
+		root := filepath.Clean(strings.TrimSuffix(strings.TrimSuffix(pattern, "..."), "/"))
+		err := filepath.Walk(root, func(path string, fileInfo os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !fileInfo.IsDir() {
+				return nil
+			}
+			name := fileInfo.Name()
+			if path != root && (name == "testdata" || name == "vendor" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			add(path)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(dirs)
+	return dirs, nil
+}
+
+// dirImportPath derives import path of the package in dir from the enclosing go.mod file.
+func dirImportPath(dir string) (string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for moduleDir := absDir; ; moduleDir = filepath.Dir(moduleDir) {
+		modulePath, err := readModulePath(filepath.Join(moduleDir, "go.mod"))
+		if err != nil {
+			return "", err
+		}
+		if modulePath != "" {
+			rel, err := filepath.Rel(moduleDir, absDir)
+			if err != nil {
+				return "", err
+			}
+			if rel == "." {
+				return modulePath, nil
+			}
+			return modulePath + "/" + filepath.ToSlash(rel), nil
+		}
+		if filepath.Dir(moduleDir) == moduleDir {
+			return "", fmt.Errorf("%v is not inside a module", dir)
+		}
+	}
+}
+
+// readModulePath returns module path declared in go.mod file or empty string if the file doesn't exist.
+func readModulePath(goModPath string) (string, error) {
+	// Reads go.mod files of the checked module, does not use user supplied input so marked as nosec
+	// #nosec
+	f, err := os.Open(goModPath)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "module ") {
+			return strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "module ")), `"`), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("%v has no module directive", goModPath)
+}
+
+// loadPackage parses and type checks non test go files of the package in dir.
+// Returns nil if dir has no go files. Type errors are collected in typeErrors of the result,
+// packages which don't fully compile are still checked as far as type information allows.
+func loadPackage(fset *token.FileSet, imp types.Importer, dir, importPath string) (*packageInfo, error) {
+	buildPkg, err := build.ImportDir(dir, 0)
+	if _, ok := err.(*build.NoGoError); ok {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	pkg := &packageInfo{
+		path: importPath,
+		info: &types.Info{
+			Types:      make(map[ast.Expr]types.TypeAndValue),
+			Defs:       make(map[*ast.Ident]types.Object),
+			Uses:       make(map[*ast.Ident]types.Object),
+			Selections: make(map[*ast.SelectorExpr]*types.Selection),
+		},
+		ignored: make(map[string]map[int]bool),
+	}
+	for _, name := range buildPkg.GoFiles {
+		fileName := filepath.Join(dir, name)
+		file, err := parser.ParseFile(fset, fileName, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		pkg.files = append(pkg.files, file)
+		for _, group := range file.Comments {
+			for _, comment := range group.List {
+				if strings.HasPrefix(comment.Text, ignoreDirective) {
+					if pkg.ignored[fileName] == nil {
+						pkg.ignored[fileName] = make(map[int]bool)
+					}
+					pkg.ignored[fileName][fset.Position(comment.Slash).Line] = true
+				}
+			}
+		}
+	}
+
+	conf := types.Config{Importer: imp, Error: func(err error) {
+		pkg.typeErrors = append(pkg.typeErrors, err)
+	}}
+	_, _ = conf.Check(importPath, fset, pkg.files, pkg.info)
+	return pkg, nil
+}
@@ -0,0 +1,95 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sample
+
+import (
+	"math/rand"
+	"time"
+)
+
+type registry struct{}
+
+func (r *registry) RegisterWorkflow(interface{}) {}
+
+type workflows struct{}
+
+func register(r *registry) {
+	r.RegisterWorkflow(SampleWorkflow)
+	r.RegisterWorkflow((&workflows{}).MethodWorkflow)
+	r.RegisterWorkflow(func() {
+		go helper()
+	})
+}
+
+func SampleWorkflow() error {
+	start := time.Now()
+	_ = rand.Intn(10)
+	_ = rand.New(rand.NewSource(42)).Intn(10)
+	ch := make(chan int, 1)
+	ch <- 1
+	select {
+	case v := <-ch:
+		_ = v
+	default:
+	}
+	m := map[string]int{"a": 1}
+	for range m {
+	}
+	_ = time.Since(start) //determinism:ignore measured for logging only
+	helper()
+	ignoredHelper()
+	return nil
+}
+
+func (w *workflows) MethodWorkflow() {
+	//determinism:ignore
+	time.Sleep(time.Second)
+	<-time.After(time.Second)
+	for _, v := range []int{1, 2} {
+		_ = v
+	}
+	helper()
+}
+
+func helper() {
+	_ = time.Now()
+}
+
+//determinism:ignore
+func ignoredHelper() {
+	_ = time.Now()
+}
+
+func ExecuteLocalActivity(ctx interface{}, activity interface{}, args ...interface{}) {}
+
+func registerLocalActivityWorkflow(r *registry) {
+	r.RegisterWorkflow(LocalActivityWorkflow)
+}
+
+func LocalActivityWorkflow() {
+	ExecuteLocalActivity(nil, func(t time.Time) time.Time {
+		return time.Now()
+	}, time.Now())
+}
@@ -34,28 +34,20 @@ import (
 
 // This file contains test helpers only. They are not private because they are used by other tests.
 
-// NewMetricsScope returns a new metric scope
-func NewMetricsScope(isReplay *bool) (tally.Scope, io.Closer, *CapturingStatsReporter) {
-	reporter := &CapturingStatsReporter{}
-	opts := tally.ScopeOptions{Reporter: reporter}
-	scope, closer := tally.NewRootScope(opts, time.Second)
-	return WrapScope(isReplay, scope, &realClock{}), closer, reporter
+// NewMetricsHandler returns a new metrics handler that skips recording metrics when isReplay is true
+func NewMetricsHandler(isReplay *bool) (Handler, io.Closer, *CapturingStatsReporter) {
+	scope, closer, reporter := NewTaggedMetricsScope()
+	return NewReplayAwareHandler(isReplay, NewTallyHandler(scope)), closer, reporter
 }
 
-// NewTaggedMetricsScope return NewTaggedMetricsScope
+// NewTaggedMetricsScope returns a new tally scope
 func NewTaggedMetricsScope() (tally.Scope, io.Closer, *CapturingStatsReporter) {
-	isReplay := false
-	scope, closer, reporter := NewMetricsScope(&isReplay)
+	reporter := &CapturingStatsReporter{}
+	opts := tally.ScopeOptions{Reporter: reporter}
+	scope, closer := tally.NewRootScope(opts, time.Second)
 	return scope, closer, reporter
 }
 
-type realClock struct {
-}
-
-func (c *realClock) Now() time.Time {
-	return time.Now()
-}
-
 // CapturingStatsReporter is a reporter used by tests to capture the metric so we can verify our tests.
 type CapturingStatsReporter struct {
 	sync.Mutex
@@ -30,6 +30,6 @@ type (
 
 // Context keys
 const (
-	ScopeContextKey    = contextKey("MetricsScope")
+	HandlerContextKey  = contextKey("MetricsHandler")
 	LongPollContextKey = contextKey("IsLongPoll")
 )
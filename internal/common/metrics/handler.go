@@ -0,0 +1,117 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package metrics
+
+import (
+	"time"
+)
+
+type (
+	// Handler is a handler for metrics emitted by the SDK. Metrics are emitted with the tags of the handler
+	// they were created from.
+	Handler interface {
+		// WithTags returns a new handler with the given tags set on every metric created from it. Tags of the
+		// handler are overridden by the given tags with the same key.
+		WithTags(map[string]string) Handler
+
+		// Counter returns the Counter object corresponding to the name.
+		Counter(name string) Counter
+
+		// Gauge returns the Gauge object corresponding to the name.
+		Gauge(name string) Gauge
+
+		// Timer returns the Timer object corresponding to the name.
+		Timer(name string) Timer
+	}
+
+	// Counter is an ever-increasing counter.
+	Counter interface {
+		// Inc increments the counter by a delta.
+		Inc(delta int64)
+	}
+
+	// Gauge can be set to any value.
+	Gauge interface {
+		// Update sets the gauges absolute value.
+		Update(value float64)
+	}
+
+	// Timer records the distribution of durations.
+	Timer interface {
+		// Record a specific duration directly.
+		Record(value time.Duration)
+	}
+
+	// CounterFunc implements Counter with a single function.
+	CounterFunc func(delta int64)
+
+	// GaugeFunc implements Gauge with a single function.
+	GaugeFunc func(value float64)
+
+	// TimerFunc implements Timer with a single function.
+	TimerFunc func(value time.Duration)
+
+	nopHandler struct{}
+)
+
+// NopHandler is a Handler that does nothing.
+var NopHandler Handler = nopHandler{}
+
+// Inc implements Counter.Inc.
+func (c CounterFunc) Inc(delta int64) {
+	c(delta)
+}
+
+// Update implements Gauge.Update.
+func (g GaugeFunc) Update(value float64) {
+	g(value)
+}
+
+// Record implements Timer.Record.
+func (t TimerFunc) Record(value time.Duration) {
+	t(value)
+}
+
+func (nopHandler) WithTags(map[string]string) Handler {
+	return nopHandler{}
+}
+
+func (nopHandler) Counter(string) Counter {
+	return nopHandler{}
+}
+
+func (nopHandler) Gauge(string) Gauge {
+	return nopHandler{}
+}
+
+func (nopHandler) Timer(string) Timer {
+	return nopHandler{}
+}
+
+func (nopHandler) Inc(int64) {}
+
+func (nopHandler) Update(float64) {}
+
+func (nopHandler) Record(time.Duration) {}
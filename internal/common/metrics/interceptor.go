@@ -27,22 +27,21 @@ package metrics
 import (
 	"context"
 
-	"github.com/uber-go/tally"
 	"google.golang.org/grpc"
 )
 
-// NewGRPCMetricsInterceptor creates new metrics scope interceptor.
-func NewGRPCMetricsInterceptor(defaultScope tally.Scope, metricSuffix string) grpc.UnaryClientInterceptor {
+// NewGRPCMetricsInterceptor creates new metrics interceptor.
+func NewGRPCMetricsInterceptor(defaultHandler Handler, metricSuffix string) grpc.UnaryClientInterceptor {
 	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
-		scope, ok := ctx.Value(ScopeContextKey).(tally.Scope)
-		if !ok || scope == nil {
-			scope = defaultScope
+		handler, ok := ctx.Value(HandlerContextKey).(Handler)
+		if !ok || handler == nil {
+			handler = defaultHandler
 		}
 		isLongPoll, ok := ctx.Value(LongPollContextKey).(bool)
 		if !ok {
 			isLongPoll = false
 		}
-		rs := newRequestScope(scope, method, isLongPoll, metricSuffix)
+		rs := newRequestScope(handler, method, isLongPoll, metricSuffix)
 		rs.recordStart()
 		err := invoker(ctx, method, req, reply, cc, opts...)
 		rs.recordEnd(err)
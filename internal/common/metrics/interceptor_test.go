@@ -73,12 +73,12 @@ func TestMetricsInterceptor(t *testing.T) {
 		},
 	}
 
-	// Normal metrics scope.
+	// Normal metrics handler.
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			scope, closer, reporter := NewMetricsScope(&isReplay)
-			interceptor := NewGRPCMetricsInterceptor(scope, "")
+			handler, closer, reporter := NewMetricsHandler(&isReplay)
+			interceptor := NewGRPCMetricsInterceptor(handler, "")
 
 			invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
 				return tc.err
@@ -99,12 +99,12 @@ func TestMetricsInterceptor(t *testing.T) {
 		})
 	}
 
-	// Prometheus metrics scope
+	// Prometheus metrics handler
 	for _, tc := range testCases {
 		t.Run(tc.name+"_Prometheus", func(t *testing.T) {
 			t.Parallel()
-			scope, closer, reporter := newPrometheusScope(&isReplay)
-			interceptor := NewGRPCMetricsInterceptor(scope, "")
+			handler, closer, reporter := newPrometheusHandler(&isReplay)
+			interceptor := NewGRPCMetricsInterceptor(handler, "")
 
 			invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
 				return tc.err
@@ -146,12 +146,12 @@ func assertPrometheusMetrics(assert *assert.Assertions, reporter *CapturingStats
 	assertMetrics(assert, reporter, counterNames)
 }
 
-func newPrometheusScope(isReplay *bool) (tally.Scope, io.Closer, *CapturingStatsReporter) {
+func newPrometheusHandler(isReplay *bool) (Handler, io.Closer, *CapturingStatsReporter) {
 	reporter := &CapturingStatsReporter{}
 	opts := tally.ScopeOptions{
 		Reporter:  reporter,
 		Separator: "_",
 	}
 	scope, closer := tally.NewRootScope(opts, time.Second)
-	return WrapScope(isReplay, scope, &realClock{}), closer, reporter
+	return NewReplayAwareHandler(isReplay, NewTallyHandler(scope)), closer, reporter
 }
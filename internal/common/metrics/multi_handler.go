@@ -0,0 +1,84 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package metrics
+
+import (
+	"time"
+)
+
+type multiHandler []Handler
+
+// NewMultiHandler returns a Handler that reports every metric to all the given handlers.
+func NewMultiHandler(handlers ...Handler) Handler {
+	return multiHandler(handlers)
+}
+
+// WithTags implements Handler.WithTags.
+func (m multiHandler) WithTags(tags map[string]string) Handler {
+	result := make(multiHandler, len(m))
+	for i, h := range m {
+		result[i] = h.WithTags(tags)
+	}
+	return result
+}
+
+// Counter implements Handler.Counter.
+func (m multiHandler) Counter(name string) Counter {
+	counters := make([]Counter, len(m))
+	for i, h := range m {
+		counters[i] = h.Counter(name)
+	}
+	return CounterFunc(func(delta int64) {
+		for _, c := range counters {
+			c.Inc(delta)
+		}
+	})
+}
+
+// Gauge implements Handler.Gauge.
+func (m multiHandler) Gauge(name string) Gauge {
+	gauges := make([]Gauge, len(m))
+	for i, h := range m {
+		gauges[i] = h.Gauge(name)
+	}
+	return GaugeFunc(func(value float64) {
+		for _, g := range gauges {
+			g.Update(value)
+		}
+	})
+}
+
+// Timer implements Handler.Timer.
+func (m multiHandler) Timer(name string) Timer {
+	timers := make([]Timer, len(m))
+	for i, h := range m {
+		timers[i] = h.Timer(name)
+	}
+	return TimerFunc(func(value time.Duration) {
+		for _, t := range timers {
+			t.Record(value)
+		}
+	})
+}
@@ -0,0 +1,50 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiHandler(t *testing.T) {
+	first := NewPrometheusHandler(PrometheusHandlerOptions{TimerBuckets: []time.Duration{time.Second}})
+	second := NewPrometheusHandler(PrometheusHandlerOptions{TimerBuckets: []time.Duration{time.Second}})
+	handler := NewMultiHandler(first, second).WithTags(map[string]string{"namespace": "default"})
+	handler.Counter("temporal_request").Inc(2)
+	handler.Gauge("temporal_sticky_cache_size").Update(3)
+	handler.Timer("temporal_request_latency").Record(time.Second)
+
+	var firstOutput, secondOutput strings.Builder
+	require.NoError(t, first.Write(&firstOutput))
+	require.NoError(t, second.Write(&secondOutput))
+	require.Equal(t, firstOutput.String(), secondOutput.String())
+	require.Contains(t, firstOutput.String(), `temporal_request{namespace="default"} 2`)
+	require.Contains(t, firstOutput.String(), `temporal_sticky_cache_size{namespace="default"} 3`)
+	require.Contains(t, firstOutput.String(), `temporal_request_latency_count{namespace="default"} 1`)
+}
@@ -0,0 +1,336 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	prometheusCounterType   = "counter"
+	prometheusGaugeType     = "gauge"
+	prometheusHistogramType = "histogram"
+
+	// PrometheusContentType is the content type of the Prometheus text exposition format.
+	PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+// DefaultPrometheusTimerBuckets are the default upper bounds of the histogram buckets timers are reported in. They
+// match the default buckets of the Prometheus client libraries.
+var DefaultPrometheusTimerBuckets = []time.Duration{
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+type (
+	// PrometheusHandlerOptions are the options for NewPrometheusHandler.
+	PrometheusHandlerOptions struct {
+		// Optional: Upper bounds of the histogram buckets timers are reported in.
+		// default: DefaultPrometheusTimerBuckets
+		TimerBuckets []time.Duration
+	}
+
+	// PrometheusHandler is a Handler that keeps metrics in memory and serves them over HTTP in the Prometheus text
+	// exposition format. Counters are reported as counters, gauges as gauges and timers as histograms in seconds.
+	// Metric and tag names are sanitized to the characters allowed by Prometheus.
+	// It is a standalone exporter which doesn't depend on the Prometheus client library, so its metrics can't be
+	// registered with a prometheus.Registerer. To report to an existing registry, pass a scope created with the
+	// Prometheus reporter of tally (github.com/uber-go/tally/prometheus) to NewTallyHandler instead.
+	PrometheusHandler struct {
+		registry *prometheusRegistry
+		tags     map[string]string
+		labels   string
+	}
+
+	prometheusRegistry struct {
+		sync.Mutex
+		buckets  []float64
+		families map[string]*prometheusFamily
+	}
+
+	prometheusFamily struct {
+		metricType string
+		series     map[string]*prometheusSeries
+	}
+
+	prometheusSeries struct {
+		sync.Mutex
+		value   float64
+		buckets []uint64
+		sum     float64
+		count   uint64
+	}
+
+	prometheusCounter struct {
+		series *prometheusSeries
+	}
+
+	prometheusGauge struct {
+		series *prometheusSeries
+	}
+
+	prometheusTimer struct {
+		series  *prometheusSeries
+		buckets []float64
+	}
+)
+
+var _ Handler = (*PrometheusHandler)(nil)
+var _ http.Handler = (*PrometheusHandler)(nil)
+
+// NewPrometheusHandler returns a new PrometheusHandler.
+func NewPrometheusHandler(options PrometheusHandlerOptions) *PrometheusHandler {
+	timerBuckets := options.TimerBuckets
+	if len(timerBuckets) == 0 {
+		timerBuckets = DefaultPrometheusTimerBuckets
+	}
+	buckets := make([]float64, len(timerBuckets))
+	for i, b := range timerBuckets {
+		buckets[i] = b.Seconds()
+	}
+	sort.Float64s(buckets)
+	return &PrometheusHandler{
+		registry: &prometheusRegistry{buckets: buckets, families: make(map[string]*prometheusFamily)},
+		tags:     map[string]string{},
+	}
+}
+
+// WithTags implements Handler.WithTags.
+func (p *PrometheusHandler) WithTags(tags map[string]string) Handler {
+	merged := make(map[string]string, len(p.tags)+len(tags))
+	for k, v := range p.tags {
+		merged[k] = v
+	}
+	for k, v := range tags {
+		merged[sanitizePrometheusName(k, false)] = v
+	}
+	return &PrometheusHandler{registry: p.registry, tags: merged, labels: prometheusLabels(merged)}
+}
+
+// Counter implements Handler.Counter. A counter is not reported if a metric of another type with the same name exists.
+func (p *PrometheusHandler) Counter(name string) Counter {
+	series := p.registry.series(name, prometheusCounterType, p.labels)
+	if series == nil {
+		return NopHandler.Counter(name)
+	}
+	return prometheusCounter{series: series}
+}
+
+// Gauge implements Handler.Gauge. A gauge is not reported if a metric of another type with the same name exists.
+func (p *PrometheusHandler) Gauge(name string) Gauge {
+	series := p.registry.series(name, prometheusGaugeType, p.labels)
+	if series == nil {
+		return NopHandler.Gauge(name)
+	}
+	return prometheusGauge{series: series}
+}
+
+// Timer implements Handler.Timer. A timer is not reported if a metric of another type with the same name exists.
+func (p *PrometheusHandler) Timer(name string) Timer {
+	series := p.registry.series(name, prometheusHistogramType, p.labels)
+	if series == nil {
+		return NopHandler.Timer(name)
+	}
+	return prometheusTimer{series: series, buckets: p.registry.buckets}
+}
+
+// ServeHTTP writes all metrics in the Prometheus text exposition format.
+func (p *PrometheusHandler) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", PrometheusContentType)
+	_ = p.Write(w)
+}
+
+// Write writes all metrics in the Prometheus text exposition format to w.
+func (p *PrometheusHandler) Write(w io.Writer) error {
+	return p.registry.write(w)
+}
+
+func (r *prometheusRegistry) series(name, metricType, labels string) *prometheusSeries {
+	name = sanitizePrometheusName(name, true)
+	r.Lock()
+	defer r.Unlock()
+	family, ok := r.families[name]
+	if !ok {
+		family = &prometheusFamily{metricType: metricType, series: make(map[string]*prometheusSeries)}
+		r.families[name] = family
+	}
+	if family.metricType != metricType {
+		return nil
+	}
+	series, ok := family.series[labels]
+	if !ok {
+		series = &prometheusSeries{}
+		if metricType == prometheusHistogramType {
+			series.buckets = make([]uint64, len(r.buckets))
+		}
+		family.series[labels] = series
+	}
+	return series
+}
+
+func (r *prometheusRegistry) write(w io.Writer) error {
+	r.Lock()
+	defer r.Unlock()
+	var sb strings.Builder
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		family := r.families[name]
+		sb.WriteString("# TYPE " + name + " " + family.metricType + "\n")
+		labelSets := make([]string, 0, len(family.series))
+		for labels := range family.series {
+			labelSets = append(labelSets, labels)
+		}
+		sort.Strings(labelSets)
+		for _, labels := range labelSets {
+			family.series[labels].write(&sb, name, family.metricType, labels, r.buckets)
+		}
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func (s *prometheusSeries) write(w *strings.Builder, name, metricType, labels string, buckets []float64) {
+	s.Lock()
+	defer s.Unlock()
+	if metricType != prometheusHistogramType {
+		writePrometheusSample(w, name, labels, "", s.value)
+		return
+	}
+	var cumulative uint64
+	for i, upperBound := range buckets {
+		cumulative += s.buckets[i]
+		writePrometheusSample(w, name+"_bucket", labels, `le="`+formatPrometheusValue(upperBound)+`"`, float64(cumulative))
+	}
+	writePrometheusSample(w, name+"_bucket", labels, `le="+Inf"`, float64(s.count))
+	writePrometheusSample(w, name+"_sum", labels, "", s.sum)
+	writePrometheusSample(w, name+"_count", labels, "", float64(s.count))
+}
+
+func (c prometheusCounter) Inc(delta int64) {
+	c.series.Lock()
+	defer c.series.Unlock()
+	c.series.value += float64(delta)
+}
+
+func (g prometheusGauge) Update(value float64) {
+	g.series.Lock()
+	defer g.series.Unlock()
+	g.series.value = value
+}
+
+func (t prometheusTimer) Record(value time.Duration) {
+	seconds := value.Seconds()
+	t.series.Lock()
+	defer t.series.Unlock()
+	for i, upperBound := range t.buckets {
+		if seconds <= upperBound {
+			t.series.buckets[i]++
+			break
+		}
+	}
+	t.series.sum += seconds
+	t.series.count++
+}
+
+func writePrometheusSample(w *strings.Builder, name, labels, extraLabel string, value float64) {
+	w.WriteString(name)
+	if labels != "" || extraLabel != "" {
+		w.WriteByte('{')
+		w.WriteString(labels)
+		if labels != "" && extraLabel != "" {
+			w.WriteByte(',')
+		}
+		w.WriteString(extraLabel)
+		w.WriteByte('}')
+	}
+	w.WriteByte(' ')
+	w.WriteString(formatPrometheusValue(value))
+	w.WriteByte('\n')
+}
+
+func formatPrometheusValue(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// prometheusLabels renders the tags sorted by name, for example a="1",b="2".
+func prometheusLabels(tags map[string]string) string {
+	names := make([]string, 0, len(tags))
+	for name := range tags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var sb strings.Builder
+	for i, name := range names {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(name)
+		sb.WriteString(`="`)
+		sb.WriteString(prometheusLabelValueReplacer.Replace(tags[name]))
+		sb.WriteByte('"')
+	}
+	return sb.String()
+}
+
+var prometheusLabelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// sanitizePrometheusName replaces all characters that are not allowed in metric names, or label names if allowColon
+// is false, with underscores.
+func sanitizePrometheusName(name string, allowColon bool) string {
+	var sb strings.Builder
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_', r == ':' && allowColon:
+			sb.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				sb.WriteByte('_')
+			}
+			sb.WriteRune(r)
+		default:
+			sb.WriteByte('_')
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,92 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusHandler(t *testing.T) {
+	handler := NewPrometheusHandler(PrometheusHandlerOptions{TimerBuckets: []time.Duration{time.Second, 100 * time.Millisecond}})
+	tagged := handler.WithTags(map[string]string{"namespace": "default", "task-queue": "tq\"1"})
+	tagged.Counter("temporal_request").Inc(2)
+	tagged.Counter("temporal_request").Inc(1)
+	handler.Counter("temporal_request").Inc(1)
+	tagged.Gauge("temporal.sticky_cache_size").Update(5)
+	tagged.Gauge("temporal.sticky_cache_size").Update(3)
+	tagged.Timer("temporal_request_latency").Record(50 * time.Millisecond)
+	tagged.Timer("temporal_request_latency").Record(500 * time.Millisecond)
+	tagged.Timer("temporal_request_latency").Record(2 * time.Second)
+
+	var sb strings.Builder
+	require.NoError(t, handler.Write(&sb))
+	expected := `# TYPE temporal_request counter
+temporal_request 1
+temporal_request{namespace="default",task_queue="tq\"1"} 3
+# TYPE temporal_request_latency histogram
+temporal_request_latency_bucket{namespace="default",task_queue="tq\"1",le="0.1"} 1
+temporal_request_latency_bucket{namespace="default",task_queue="tq\"1",le="1"} 2
+temporal_request_latency_bucket{namespace="default",task_queue="tq\"1",le="+Inf"} 3
+temporal_request_latency_sum{namespace="default",task_queue="tq\"1"} 2.55
+temporal_request_latency_count{namespace="default",task_queue="tq\"1"} 3
+# TYPE temporal_sticky_cache_size gauge
+temporal_sticky_cache_size{namespace="default",task_queue="tq\"1"} 3
+`
+	require.Equal(t, expected, sb.String())
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	require.Equal(t, PrometheusContentType, recorder.Header().Get("Content-Type"))
+	require.Equal(t, expected, recorder.Body.String())
+}
+
+func TestPrometheusHandlerTypeConflict(t *testing.T) {
+	handler := NewPrometheusHandler(PrometheusHandlerOptions{})
+	handler.Counter("metric").Inc(1)
+	handler.Gauge("metric").Update(5)
+	handler.Timer("metric").Record(time.Second)
+
+	var sb strings.Builder
+	require.NoError(t, handler.Write(&sb))
+	require.Equal(t, "# TYPE metric counter\nmetric 1\n", sb.String())
+}
+
+func TestPrometheusHandlerReplayAware(t *testing.T) {
+	handler := NewPrometheusHandler(PrometheusHandlerOptions{})
+	isReplay := true
+	replayAware := NewReplayAwareHandler(&isReplay, handler)
+	replayAware.Counter("metric").Inc(1)
+	isReplay = false
+	replayAware.Counter("metric").Inc(2)
+
+	var sb strings.Builder
+	require.NoError(t, handler.Write(&sb))
+	require.Equal(t, "# TYPE metric counter\nmetric 2\n", sb.String())
+}
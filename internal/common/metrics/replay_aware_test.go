@@ -34,8 +34,8 @@ import (
 
 func Test_Counter(t *testing.T) {
 	t.Parallel()
-	replayed, executed := withScope(t, func(scope tally.Scope) {
-		scope.Counter("test-name").Inc(3)
+	replayed, executed := withHandler(t, func(handler Handler) {
+		handler.Counter("test-name").Inc(3)
 	})
 	require.Equal(t, 0, len(replayed.Counts()))
 	require.Equal(t, 1, len(executed.Counts()))
@@ -44,8 +44,8 @@ func Test_Counter(t *testing.T) {
 
 func Test_Gauge(t *testing.T) {
 	t.Parallel()
-	replayed, executed := withScope(t, func(scope tally.Scope) {
-		scope.Gauge("test-name").Update(3)
+	replayed, executed := withHandler(t, func(handler Handler) {
+		handler.Gauge("test-name").Update(3)
 	})
 	require.Equal(t, 0, len(replayed.Gauges()))
 	require.Equal(t, 1, len(executed.Gauges()))
@@ -53,6 +53,16 @@ func Test_Gauge(t *testing.T) {
 }
 
 func Test_Timer(t *testing.T) {
+	t.Parallel()
+	replayed, executed := withHandler(t, func(handler Handler) {
+		handler.Timer("test-name").Record(time.Second)
+	})
+	require.Equal(t, 0, len(replayed.Timers()))
+	require.Equal(t, 1, len(executed.Timers()))
+	require.Equal(t, time.Second, executed.Timers()[0].Value())
+}
+
+func Test_ScopeTimer(t *testing.T) {
 	t.Parallel()
 	replayed, executed := withScope(t, func(scope tally.Scope) {
 		scope.Timer("test-name").Record(time.Second)
@@ -90,7 +100,8 @@ func Test_Histogram(t *testing.T) {
 
 func Test_ScopeCoverage(t *testing.T) {
 	isReplay := false
-	scope, closer, reporter := NewMetricsScope(&isReplay)
+	handler, closer, reporter := NewMetricsHandler(&isReplay)
+	scope := NewTallyScope(handler, nil)
 	caps := scope.Capabilities()
 	require.Equal(t, true, caps.Reporting())
 	require.Equal(t, true, caps.Tagging())
@@ -99,23 +110,44 @@ func Test_ScopeCoverage(t *testing.T) {
 	taggedScope.Counter("test-counter").Inc(1)
 	_ = closer.Close()
 	require.Equal(t, 1, len(reporter.Counts()))
+	require.Equal(t, "test.test-counter", reporter.Counts()[0].Name())
 }
 
-// withScope runs your callback twice, once for "during replay" and once for "after replay" / "executing".
-// stats are captured, and the results are returned for your validation.
+func Test_ReplayFlagChange(t *testing.T) {
+	isReplay := true
+	handler, closer, reporter := NewMetricsHandler(&isReplay)
+	counter := handler.WithTags(map[string]string{"tag": "value"}).Counter("test-counter")
+	counter.Inc(1)
+	isReplay = false
+	counter.Inc(2)
+	_ = closer.Close()
+	require.Equal(t, 1, len(reporter.Counts()))
+	require.Equal(t, int64(2), reporter.Counts()[0].Value())
+	require.Equal(t, "value", reporter.Counts()[0].Tags()["tag"])
+}
+
+// withScope runs your callback twice with the scope of NewTallyScope, like withHandler does.
 func withScope(t *testing.T, cb func(scope tally.Scope)) (replayed *CapturingStatsReporter, executed *CapturingStatsReporter) {
+	return withHandler(t, func(handler Handler) {
+		cb(NewTallyScope(handler, nil))
+	})
+}
+
+// withHandler runs your callback twice, once for "during replay" and once for "after replay" / "executing".
+// stats are captured, and the results are returned for your validation.
+func withHandler(t *testing.T, cb func(handler Handler)) (replayed *CapturingStatsReporter, executed *CapturingStatsReporter) {
 	replaying, executing := true, false
 
-	replayingScope, replayingCloser, replayed := NewMetricsScope(&replaying)
-	executingScope, executingCloser, executed := NewMetricsScope(&executing)
+	replayingHandler, replayingCloser, replayed := NewMetricsHandler(&replaying)
+	executingHandler, executingCloser, executed := NewMetricsHandler(&executing)
 
 	defer func() {
 		require.NoError(t, replayingCloser.Close())
 		require.NoError(t, executingCloser.Close())
 	}()
 
-	cb(replayingScope)
-	cb(executingScope)
+	cb(replayingHandler)
+	cb(executingHandler)
 
 	return replayed, executed
 }
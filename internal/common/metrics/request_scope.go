@@ -27,13 +27,11 @@ package metrics
 import (
 	"strings"
 	"time"
-
-	"github.com/uber-go/tally"
 )
 
 type (
 	requestScope struct {
-		scope                        tally.Scope
+		handler                      Handler
 		startTime                    time.Time
 		isLongPoll                   bool
 		longPollRequestCountMetric   string
@@ -47,12 +45,11 @@ type (
 
 // newRequestScope creates metric scope for a specified operation, defined by gRPC method string, isLongPoll flag and
 // metric suffix. Suffix should be an empty string for individual calls and should have non-empty value for aggregated values.
-func newRequestScope(scope tally.Scope, method string, isLongPoll bool, suffix string) *requestScope {
+func newRequestScope(handler Handler, method string, isLongPoll bool, suffix string) *requestScope {
 	operation := ConvertMethodToScope(method)
-	subScope := getMetricsScopeForOperation(scope, operation)
 
 	return &requestScope{
-		scope:                        subScope,
+		handler:                      getMetricsHandlerForOperation(handler, operation),
 		startTime:                    time.Now(),
 		isLongPoll:                   isLongPoll,
 		longPollRequestCountMetric:   TemporalLongRequest + suffix,
@@ -66,24 +63,24 @@ func newRequestScope(scope tally.Scope, method string, isLongPoll bool, suffix s
 
 func (rs *requestScope) recordStart() {
 	if rs.isLongPoll {
-		rs.scope.Counter(rs.longPollRequestCountMetric).Inc(1)
+		rs.handler.Counter(rs.longPollRequestCountMetric).Inc(1)
 	} else {
-		rs.scope.Counter(rs.requestCountMetric).Inc(1)
+		rs.handler.Counter(rs.requestCountMetric).Inc(1)
 	}
 }
 
 func (rs *requestScope) recordEnd(err error) {
 	if rs.isLongPoll {
-		rs.scope.Timer(rs.longPollRequestLatencyMetric).Record(time.Since(rs.startTime))
+		rs.handler.Timer(rs.longPollRequestLatencyMetric).Record(time.Since(rs.startTime))
 	} else {
-		rs.scope.Timer(rs.requestLatencyMetric).Record(time.Since(rs.startTime))
+		rs.handler.Timer(rs.requestLatencyMetric).Record(time.Since(rs.startTime))
 	}
 
 	if err != nil {
 		if rs.isLongPoll {
-			rs.scope.Counter(rs.longPollRequestFailureMetric).Inc(1)
+			rs.handler.Counter(rs.longPollRequestFailureMetric).Inc(1)
 		} else {
-			rs.scope.Counter(rs.requestFailureMetric).Inc(1)
+			rs.handler.Counter(rs.requestFailureMetric).Inc(1)
 		}
 	}
 }
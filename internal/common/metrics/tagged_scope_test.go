@@ -33,55 +33,56 @@ import (
 	"github.com/uber-go/tally"
 )
 
-func Test_TaggedScope(t *testing.T) {
-	scope, closer, reporter := NewTaggedMetricsScope()
-	taggedScope := TagScope(scope, "tag1", "val1")
-	taggedScope.Counter("test-name").Inc(3)
+func Test_TaggedHandler(t *testing.T) {
+	isReplay := false
+	handler, closer, reporter := NewMetricsHandler(&isReplay)
+	taggedHandler := TagHandler(handler, "tag1", "val1")
+	taggedHandler.Counter("test-name").Inc(3)
 	_ = closer.Close()
 	require.Equal(t, 1, len(reporter.Counts()))
 	require.Equal(t, int64(3), reporter.Counts()[0].Value())
 
-	scope, closer, reporter = NewTaggedMetricsScope()
-	taggedScope = TagScope(scope, "tag2", "val1")
-	taggedScope.Counter("test-name").Inc(2)
+	handler, closer, reporter = NewMetricsHandler(&isReplay)
+	taggedHandler = TagHandler(handler, "tag2", "val1")
+	taggedHandler.Counter("test-name").Inc(2)
 
-	taggedScope2 := TagScope(scope, "tag2", "val1")
-	taggedScope2.Counter("test-name").Inc(1)
+	taggedHandler2 := TagHandler(handler, "tag2", "val1")
+	taggedHandler2.Counter("test-name").Inc(1)
 	_ = closer.Close()
 	require.Equal(t, 1, len(reporter.Counts()))
 	require.Equal(t, int64(3), reporter.Counts()[0].Value())
 }
 
-func Test_TaggedScope_WithMultiTags(t *testing.T) {
-	scope, closer, reporter := newTaggedMetricsScope()
-	taggedScope := TagScope(scope, "tag1", "val1", "tag2", "val2")
-	taggedScope.Counter("test-name").Inc(3)
+func Test_TaggedHandler_WithMultiTags(t *testing.T) {
+	handler, closer, reporter := newTaggedMetricsHandler()
+	taggedHandler := TagHandler(handler, "tag1", "val1", "tag2", "val2")
+	taggedHandler.Counter("test-name").Inc(3)
 	_ = closer.Close()
 	require.Equal(t, 1, len(reporter.counts))
 	require.Equal(t, int64(3), reporter.counts[0].value)
 
-	scope, closer, reporter = newTaggedMetricsScope()
-	taggedScope = TagScope(scope, "tag2", "val1", "tag3", "val3")
-	taggedScope.Counter("test-name").Inc(2)
-	scope2 := TagScope(scope, "tag2", "val1", "tag3", "val3")
-	scope2.Counter("test-name").Inc(1)
+	handler, closer, reporter = newTaggedMetricsHandler()
+	taggedHandler = TagHandler(handler, "tag2", "val1", "tag3", "val3")
+	taggedHandler.Counter("test-name").Inc(2)
+	handler2 := TagHandler(handler, "tag2", "val1", "tag3", "val3")
+	handler2.Counter("test-name").Inc(1)
 	_ = closer.Close()
 	require.Equal(t, 1, len(reporter.counts))
 	require.Equal(t, int64(3), reporter.counts[0].value)
 
 	//lint:ignore SA5012 We test exactly for this for this
-	require.Panics(t, func() { TagScope(scope, "tag") })
+	require.Panics(t, func() { TagHandler(handler, "tag") })
 }
 
-func newMetricsScope(isReplay *bool) (tally.Scope, io.Closer, *capturingStatsReporter) {
+func newMetricsHandler(isReplay *bool) (Handler, io.Closer, *capturingStatsReporter) {
 	reporter := &capturingStatsReporter{}
 	opts := tally.ScopeOptions{Reporter: reporter}
 	scope, closer := tally.NewRootScope(opts, time.Second)
-	return WrapScope(isReplay, scope, &realClock{}), closer, reporter
+	return NewReplayAwareHandler(isReplay, NewTallyHandler(scope)), closer, reporter
 }
 
-func newTaggedMetricsScope() (tally.Scope, io.Closer, *capturingStatsReporter) {
+func newTaggedMetricsHandler() (Handler, io.Closer, *capturingStatsReporter) {
 	isReplay := false
-	scope, closer, reporter := newMetricsScope(&isReplay)
-	return scope, closer, reporter
+	handler, closer, reporter := newMetricsHandler(&isReplay)
+	return handler, closer, reporter
 }
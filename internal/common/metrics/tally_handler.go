@@ -0,0 +1,58 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package metrics
+
+import (
+	"github.com/uber-go/tally"
+)
+
+type tallyHandler struct {
+	scope tally.Scope
+}
+
+// NewTallyHandler returns a Handler that reports metrics to the given tally scope.
+func NewTallyHandler(scope tally.Scope) Handler {
+	return tallyHandler{scope: scope}
+}
+
+// WithTags implements Handler.WithTags.
+func (t tallyHandler) WithTags(tags map[string]string) Handler {
+	return tallyHandler{scope: t.scope.Tagged(tags)}
+}
+
+// Counter implements Handler.Counter.
+func (t tallyHandler) Counter(name string) Counter {
+	return t.scope.Counter(name)
+}
+
+// Gauge implements Handler.Gauge.
+func (t tallyHandler) Gauge(name string) Gauge {
+	return t.scope.Gauge(name)
+}
+
+// Timer implements Handler.Timer.
+func (t tallyHandler) Timer(name string) Timer {
+	return t.scope.Timer(name)
+}
@@ -0,0 +1,308 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package metrics
+
+import (
+	"time"
+
+	"github.com/uber-go/tally"
+)
+
+type (
+	// Clock is the time source of the stopwatches of scopes returned by NewTallyScope.
+	Clock interface {
+		Now() time.Time
+	}
+
+	systemClock struct{}
+
+	durationRecorder interface {
+		RecordDuration(duration time.Duration)
+	}
+
+	// tallyScope reports metrics of a tally.Scope to a Handler.
+	tallyScope struct {
+		handler Handler
+		prefix  string
+		clock   Clock
+	}
+
+	tallyTimer struct {
+		timer Timer
+		clock Clock
+	}
+
+	tallyHistogram struct {
+		gauge Gauge
+		timer tallyTimer
+	}
+
+	tallyCapabilities struct{}
+
+	// replayAwareScope wraps a tally.Scope and skips recording metrics when isReplay is true.
+	replayAwareScope struct {
+		isReplay *bool
+		scope    tally.Scope
+		clock    Clock
+	}
+
+	replayAwareCounter struct {
+		isReplay *bool
+		counter  tally.Counter
+	}
+
+	replayAwareGauge struct {
+		isReplay *bool
+		gauge    tally.Gauge
+	}
+
+	replayAwareTimer struct {
+		isReplay *bool
+		timer    tally.Timer
+		clock    Clock
+	}
+
+	replayAwareHistogram struct {
+		isReplay  *bool
+		histogram tally.Histogram
+		clock     Clock
+	}
+
+	replayAwareStopwatchRecorder struct {
+		isReplay *bool
+		recorder durationRecorder
+		clock    Clock
+	}
+)
+
+// SystemClock is a Clock which returns the wall clock time.
+var SystemClock Clock = systemClock{}
+
+// NewTallyScope returns a tally.Scope which reports metrics to the given handler. It lets code written against the
+// tally based API report to any Handler. Stopwatches of the scope measure time with clock, so a workflow scope must
+// be given the workflow clock to stay deterministic. A nil clock is SystemClock.
+// If handler reports to a tally.Scope, possibly through NewReplayAwareHandler, the scope is used directly, so
+// histograms keep their buckets. Otherwise histogram durations are reported as timers and histogram values, which a
+// Handler can't represent, as gauges.
+func NewTallyScope(handler Handler, clock Clock) tally.Scope {
+	if clock == nil {
+		clock = SystemClock
+	}
+	isReplay, underlying := new(bool), handler
+	if h, ok := underlying.(replayAwareHandler); ok {
+		isReplay, underlying = h.isReplay, h.handler
+	}
+	if h, ok := underlying.(tallyHandler); ok {
+		return &replayAwareScope{isReplay: isReplay, scope: h.scope, clock: clock}
+	}
+	return tallyScope{handler: handler, clock: clock}
+}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// Counter implements tally.Scope.Counter.
+func (s tallyScope) Counter(name string) tally.Counter {
+	return s.handler.Counter(s.prefix + name)
+}
+
+// Gauge implements tally.Scope.Gauge.
+func (s tallyScope) Gauge(name string) tally.Gauge {
+	return s.handler.Gauge(s.prefix + name)
+}
+
+// Timer implements tally.Scope.Timer.
+func (s tallyScope) Timer(name string) tally.Timer {
+	return tallyTimer{timer: s.handler.Timer(s.prefix + name), clock: s.clock}
+}
+
+// Histogram implements tally.Scope.Histogram. Values are reported as gauges and durations as timers.
+func (s tallyScope) Histogram(name string, _ tally.Buckets) tally.Histogram {
+	return tallyHistogram{gauge: s.handler.Gauge(s.prefix + name), timer: tallyTimer{timer: s.handler.Timer(s.prefix + name), clock: s.clock}}
+}
+
+// Tagged implements tally.Scope.Tagged.
+func (s tallyScope) Tagged(tags map[string]string) tally.Scope {
+	return tallyScope{handler: s.handler.WithTags(tags), prefix: s.prefix, clock: s.clock}
+}
+
+// SubScope implements tally.Scope.SubScope. Names of the metrics of the sub scope are prefixed the same way tally does
+// by default.
+func (s tallyScope) SubScope(name string) tally.Scope {
+	return tallyScope{handler: s.handler, prefix: s.prefix + name + tally.DefaultSeparator, clock: s.clock}
+}
+
+// Capabilities implements tally.Scope.Capabilities.
+func (s tallyScope) Capabilities() tally.Capabilities {
+	return tallyCapabilities{}
+}
+
+// Record implements tally.Timer.Record.
+func (t tallyTimer) Record(value time.Duration) {
+	t.timer.Record(value)
+}
+
+// Start implements tally.Timer.Start.
+func (t tallyTimer) Start() tally.Stopwatch {
+	return tally.NewStopwatch(t.clock.Now(), t)
+}
+
+// RecordStopwatch implements tally.StopwatchRecorder.
+func (t tallyTimer) RecordStopwatch(stopwatchStart time.Time) {
+	t.timer.Record(t.clock.Now().Sub(stopwatchStart))
+}
+
+// RecordValue implements tally.Histogram.RecordValue.
+func (h tallyHistogram) RecordValue(value float64) {
+	h.gauge.Update(value)
+}
+
+// RecordDuration implements tally.Histogram.RecordDuration.
+func (h tallyHistogram) RecordDuration(value time.Duration) {
+	h.timer.Record(value)
+}
+
+// Start implements tally.Histogram.Start.
+func (h tallyHistogram) Start() tally.Stopwatch {
+	return h.timer.Start()
+}
+
+func (tallyCapabilities) Reporting() bool {
+	return true
+}
+
+func (tallyCapabilities) Tagging() bool {
+	return true
+}
+
+// Inc increments the counter by a delta.
+func (c *replayAwareCounter) Inc(delta int64) {
+	if *c.isReplay {
+		return
+	}
+	c.counter.Inc(delta)
+}
+
+// Update sets the gauges absolute value.
+func (g *replayAwareGauge) Update(value float64) {
+	if *g.isReplay {
+		return
+	}
+	g.gauge.Update(value)
+}
+
+// Record a specific duration.
+func (t *replayAwareTimer) Record(value time.Duration) {
+	if *t.isReplay {
+		return
+	}
+	t.timer.Record(value)
+}
+
+// RecordDuration a specific duration.
+func (t *replayAwareTimer) RecordDuration(duration time.Duration) {
+	t.Record(duration)
+}
+
+// Start gives you back a specific point in time to report via Stop.
+func (t *replayAwareTimer) Start() tally.Stopwatch {
+	return tally.NewStopwatch(t.clock.Now(), &replayAwareStopwatchRecorder{t.isReplay, t, t.clock})
+}
+
+// RecordValue records a specific value directly. Will use the configured value buckets for the histogram.
+func (h *replayAwareHistogram) RecordValue(value float64) {
+	if *h.isReplay {
+		return
+	}
+	h.histogram.RecordValue(value)
+}
+
+// RecordDuration records a specific duration directly.
+// Will use the configured duration buckets for the histogram.
+func (h *replayAwareHistogram) RecordDuration(value time.Duration) {
+	if *h.isReplay {
+		return
+	}
+	h.histogram.RecordDuration(value)
+}
+
+// Start gives you a specific point in time to then record a duration.
+// Will use the configured duration buckets for the histogram.
+func (h *replayAwareHistogram) Start() tally.Stopwatch {
+	return tally.NewStopwatch(h.clock.Now(), &replayAwareStopwatchRecorder{h.isReplay, h, h.clock})
+}
+
+// RecordStopwatch is a recorder that is called when a stopwatch is stopped with Stop().
+func (r *replayAwareStopwatchRecorder) RecordStopwatch(stopwatchStart time.Time) {
+	if *r.isReplay {
+		return
+	}
+	d := r.clock.Now().Sub(stopwatchStart)
+	r.recorder.RecordDuration(d)
+}
+
+// Counter returns the Counter object corresponding to the name.
+func (s *replayAwareScope) Counter(name string) tally.Counter {
+	return &replayAwareCounter{s.isReplay, s.scope.Counter(name)}
+}
+
+// Gauge returns the Gauge object corresponding to the name.
+func (s *replayAwareScope) Gauge(name string) tally.Gauge {
+	return &replayAwareGauge{s.isReplay, s.scope.Gauge(name)}
+}
+
+// Timer returns the Timer object corresponding to the name.
+func (s *replayAwareScope) Timer(name string) tally.Timer {
+	return &replayAwareTimer{s.isReplay, s.scope.Timer(name), s.clock}
+}
+
+// Histogram returns the Histogram object corresponding to the name.
+// To use default value and duration buckets configured for the scope
+// simply pass tally.DefaultBuckets or nil.
+// You can use tally.ValueBuckets{x, y, ...} for value buckets.
+// You can use tally.DurationBuckets{x, y, ...} for duration buckets.
+// You can use tally.MustMakeLinearValueBuckets(start, width, count) for linear values.
+// You can use tally.MustMakeLinearDurationBuckets(start, width, count) for linear durations.
+// You can use tally.MustMakeExponentialValueBuckets(start, factor, count) for exponential values.
+// You can use tally.MustMakeExponentialDurationBuckets(start, factor, count) for exponential durations.
+func (s *replayAwareScope) Histogram(name string, buckets tally.Buckets) tally.Histogram {
+	return &replayAwareHistogram{s.isReplay, s.scope.Histogram(name, buckets), s.clock}
+}
+
+// Tagged returns a new child scope with the given tags and current tags.
+func (s *replayAwareScope) Tagged(tags map[string]string) tally.Scope {
+	return &replayAwareScope{s.isReplay, s.scope.Tagged(tags), s.clock}
+}
+
+// SubScope returns a new child scope appending a further name prefix.
+func (s *replayAwareScope) SubScope(name string) tally.Scope {
+	return &replayAwareScope{s.isReplay, s.scope.SubScope(name), s.clock}
+}
+
+// Capabilities returns a description of metrics reporting capabilities.
+func (s *replayAwareScope) Capabilities() tally.Capabilities {
+	return s.scope.Capabilities()
+}
@@ -0,0 +1,92 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+func TestTallyScope(t *testing.T) {
+	handler := NewPrometheusHandler(PrometheusHandlerOptions{TimerBuckets: []time.Duration{time.Second}})
+	clock := &testClock{now: time.Unix(0, 0)}
+	scope := NewTallyScope(handler, clock)
+	require.True(t, scope.Capabilities().Tagging())
+	tagged := scope.Tagged(map[string]string{"namespace": "default"})
+	tagged.Counter("temporal_request").Inc(2)
+	tagged.SubScope("temporal").Gauge("sticky_cache_size").Update(3)
+	tagged.Timer("temporal_request_latency").Record(500 * time.Millisecond)
+	stopwatch := tagged.Histogram("temporal_request_latency", tally.DefaultBuckets).Start()
+	clock.now = clock.now.Add(2 * time.Second)
+	stopwatch.Stop()
+	tagged.Histogram("temporal_value", tally.DefaultBuckets).RecordValue(7)
+
+	var sb strings.Builder
+	require.NoError(t, handler.Write(&sb))
+	require.Equal(t, `# TYPE temporal_request counter
+temporal_request{namespace="default"} 2
+# TYPE temporal_request_latency histogram
+temporal_request_latency_bucket{namespace="default",le="1"} 1
+temporal_request_latency_bucket{namespace="default",le="+Inf"} 2
+temporal_request_latency_sum{namespace="default"} 2.5
+temporal_request_latency_count{namespace="default"} 2
+# TYPE temporal_sticky_cache_size gauge
+temporal_sticky_cache_size{namespace="default"} 3
+# TYPE temporal_value gauge
+temporal_value{namespace="default"} 7
+`, sb.String())
+}
+
+func TestTallyScopeOfTallyHandler(t *testing.T) {
+	isReplay := true
+	handler, closer, reporter := NewMetricsHandler(&isReplay)
+	clock := &testClock{now: time.Unix(0, 0)}
+	scope := NewTallyScope(handler.WithTags(map[string]string{"namespace": "default"}), clock)
+	scope.Timer("temporal_replayed").Start().Stop()
+	isReplay = false
+	stopwatch := scope.Timer("temporal_request_latency").Start()
+	clock.now = clock.now.Add(time.Minute)
+	stopwatch.Stop()
+	scope.Histogram("temporal_value", tally.MustMakeLinearValueBuckets(0, 10, 10)).RecordValue(5)
+	require.NoError(t, closer.Close())
+
+	require.Len(t, reporter.Timers(), 1)
+	require.Equal(t, "temporal_request_latency", reporter.Timers()[0].Name())
+	require.Equal(t, time.Minute, reporter.Timers()[0].Value())
+	require.Equal(t, "default", reporter.Timers()[0].Tags()["namespace"])
+	require.Len(t, reporter.HistogramValueSamples(), 1)
+}
+
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
@@ -24,19 +24,15 @@
 
 package metrics
 
-import (
-	"github.com/uber-go/tally"
-)
-
-// TagScope return a scope with one or multiple tags,
-// input should be key value pairs like: tagScope(tag1, val1, tag2, val2).
-func TagScope(metricsScope tally.Scope, keyValuePairs ...string) tally.Scope {
-	if metricsScope == nil {
-		metricsScope = tally.NoopScope
+// TagHandler return a handler with one or multiple tags,
+// input should be key value pairs like: TagHandler(handler, tag1, val1, tag2, val2).
+func TagHandler(handler Handler, keyValuePairs ...string) Handler {
+	if handler == nil {
+		handler = NopHandler
 	}
 
 	if len(keyValuePairs)%2 != 0 {
-		panic("TagScope key value are not in pairs")
+		panic("TagHandler key value are not in pairs")
 	}
 
 	tagsMap := map[string]string{}
@@ -46,45 +42,45 @@ func TagScope(metricsScope tally.Scope, keyValuePairs ...string) tally.Scope {
 		tagsMap[tagName] = tagValue
 	}
 
-	return metricsScope.Tagged(tagsMap)
+	return handler.WithTags(tagsMap)
 }
 
-// GetRootScope return properly tagged tally scope with base tags included on all metric emitted by the client
-func GetRootScope(ts tally.Scope, namespace string) tally.Scope {
-	// Include all tags on the root scope which are emitted by rpc calls to Temporal
-	return TagScope(ts, NamespaceTagName, namespace, ClientTagName, ClientTagValue, WorkerTypeTagName, NoneTagValue,
+// GetRootHandler return properly tagged handler with base tags included on all metric emitted by the client
+func GetRootHandler(handler Handler, namespace string) Handler {
+	// Include all tags on the root handler which are emitted by rpc calls to Temporal
+	return TagHandler(handler, NamespaceTagName, namespace, ClientTagName, ClientTagValue, WorkerTypeTagName, NoneTagValue,
 		WorkflowTypeNameTagName, NoneTagValue, ActivityTypeNameTagName, NoneTagValue, TaskQueueTagName, NoneTagValue)
 }
 
-// GetWorkerScope return properly tagged tally scope worker type tag
-func GetWorkerScope(ts tally.Scope, workerType string) tally.Scope {
-	return TagScope(ts, WorkerTypeTagName, workerType)
+// GetWorkerHandler return properly tagged handler with worker type tag
+func GetWorkerHandler(handler Handler, workerType string) Handler {
+	return TagHandler(handler, WorkerTypeTagName, workerType)
 }
 
-// GetMetricsScopeForActivity return properly tagged tally scope for activity
-func GetMetricsScopeForActivity(ts tally.Scope, workflowType, activityType, taskQueueName string) tally.Scope {
-	return TagScope(ts, WorkflowTypeNameTagName, workflowType, ActivityTypeNameTagName,
+// GetMetricsHandlerForActivity return properly tagged handler for activity
+func GetMetricsHandlerForActivity(handler Handler, workflowType, activityType, taskQueueName string) Handler {
+	return TagHandler(handler, WorkflowTypeNameTagName, workflowType, ActivityTypeNameTagName,
 		activityType, TaskQueueTagName, taskQueueName)
 }
 
-// GetMetricsScopeForLocalActivity return properly tagged tally scope for local activity
-func GetMetricsScopeForLocalActivity(ts tally.Scope, workflowType, localActivityType string) tally.Scope {
-	return TagScope(ts, WorkflowTypeNameTagName, workflowType, ActivityTypeNameTagName,
+// GetMetricsHandlerForLocalActivity return properly tagged handler for local activity
+func GetMetricsHandlerForLocalActivity(handler Handler, workflowType, localActivityType string) Handler {
+	return TagHandler(handler, WorkflowTypeNameTagName, workflowType, ActivityTypeNameTagName,
 		localActivityType)
 }
 
-// GetMetricsScopeForWorkflow return properly tagged tally scope for workflow execution
-func GetMetricsScopeForWorkflow(ts tally.Scope, workflowType string) tally.Scope {
-	return TagScope(ts, WorkflowTypeNameTagName, workflowType)
+// GetMetricsHandlerForWorkflow return properly tagged handler for workflow execution
+func GetMetricsHandlerForWorkflow(handler Handler, workflowType string) Handler {
+	return TagHandler(handler, WorkflowTypeNameTagName, workflowType)
 }
 
-// GetMetricsScopeForRPC return properly tagged tally scope for workflow execution
-func GetMetricsScopeForRPC(ts tally.Scope, workflowType, activityType, taskQueueName string) tally.Scope {
-	return TagScope(ts, WorkflowTypeNameTagName, workflowType, ActivityTypeNameTagName,
+// GetMetricsHandlerForRPC return properly tagged handler for workflow execution
+func GetMetricsHandlerForRPC(handler Handler, workflowType, activityType, taskQueueName string) Handler {
+	return TagHandler(handler, WorkflowTypeNameTagName, workflowType, ActivityTypeNameTagName,
 		activityType, TaskQueueTagName, taskQueueName)
 }
 
-// getMetricsScopeForOperation return properly tagged tally scope for rpc operation
-func getMetricsScopeForOperation(ts tally.Scope, operation string) tally.Scope {
-	return TagScope(ts, OperationTagName, operation)
+// getMetricsHandlerForOperation return properly tagged handler for rpc operation
+func getMetricsHandlerForOperation(handler Handler, operation string) Handler {
+	return TagHandler(handler, OperationTagName, operation)
 }
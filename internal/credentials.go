@@ -0,0 +1,227 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultCertificateCheckInterval is how often certificate files are checked for changes by default.
+	defaultCertificateCheckInterval = time.Minute
+
+	// defaultTokenRefreshBefore is how long before the expiration a bearer token is refreshed by default.
+	defaultTokenRefreshBefore = time.Minute
+
+	// tokenRefreshRetryInterval is how long a bearer token which is still valid is used after a failed refresh before
+	// the refresh is retried.
+	tokenRefreshRetryInterval = 5 * time.Second
+)
+
+type (
+	// ClientCertificateProvider provides a client certificate for every new TLS handshake with the server. It allows
+	// to rotate certificates without recreating clients and workers: when the certificate changes, connections which
+	// are established after that (including reconnects) use the new certificate.
+	ClientCertificateProvider interface {
+		GetClientCertificate(info *tls.CertificateRequestInfo) (*tls.Certificate, error)
+	}
+
+	// BearerTokenSource returns a new bearer token and its expiration time. Zero expiration time means that
+	// the token never expires.
+	BearerTokenSource func(ctx context.Context) (token string, expiresAt time.Time, err error)
+
+	// fileClientCertificateProvider loads a certificate from PEM encoded files and reloads it when the files
+	// are modified.
+	fileClientCertificateProvider struct {
+		certFile      string
+		keyFile       string
+		checkInterval time.Duration
+
+		lock        sync.Mutex
+		certificate *tls.Certificate
+		certModTime time.Time
+		keyModTime  time.Time
+		lastChecked time.Time
+	}
+
+	// bearerTokenHeadersProvider caches a bearer token and refreshes it before it expires. Only one refresh runs at
+	// a time, the source is called without holding the lock.
+	bearerTokenHeadersProvider struct {
+		source        BearerTokenSource
+		refreshBefore time.Duration
+
+		lock        sync.Mutex
+		token       string
+		expiresAt   time.Time
+		refresh     *tokenRefresh // refresh in progress
+		nextRefresh time.Time     // earliest time of the next refresh after a failed one
+	}
+
+	// tokenRefresh is the result of a bearer token refresh shared by the callers waiting for it.
+	tokenRefresh struct {
+		done  chan struct{}
+		token string
+		err   error
+	}
+)
+
+// NewFileClientCertificateProvider creates ClientCertificateProvider which reads a certificate from PEM encoded
+// certFile and keyFile. The files are checked for modifications at most once per checkInterval (defaults to 1 minute)
+// and the certificate is reloaded when they change. If the modified files can't be loaded, for example because
+// only one of them is written so far, the previous certificate is used until the next check.
+func NewFileClientCertificateProvider(certFile, keyFile string, checkInterval time.Duration) (ClientCertificateProvider, error) {
+	if checkInterval == 0 {
+		checkInterval = defaultCertificateCheckInterval
+	}
+	p := &fileClientCertificateProvider{
+		certFile:      certFile,
+		keyFile:       keyFile,
+		checkInterval: checkInterval,
+	}
+	if err := p.reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *fileClientCertificateProvider) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if time.Since(p.lastChecked) >= p.checkInterval {
+		// keep using the previous certificate if the new one can't be loaded
+		_ = p.reload()
+	}
+	return p.certificate, nil
+}
+
+// reload loads the certificate if the files are modified since the last load. Must be called under lock.
+func (p *fileClientCertificateProvider) reload() error {
+	p.lastChecked = time.Now()
+	certInfo, err := os.Stat(p.certFile)
+	if err != nil {
+		return err
+	}
+	keyInfo, err := os.Stat(p.keyFile)
+	if err != nil {
+		return err
+	}
+	if p.certificate != nil && certInfo.ModTime().Equal(p.certModTime) && keyInfo.ModTime().Equal(p.keyModTime) {
+		return nil
+	}
+	certificate, err := tls.LoadX509KeyPair(p.certFile, p.keyFile)
+	if err != nil {
+		return fmt.Errorf("unable to load client certificate: %w", err)
+	}
+	p.certificate = &certificate
+	p.certModTime = certInfo.ModTime()
+	p.keyModTime = keyInfo.ModTime()
+	return nil
+}
+
+// NewBearerTokenHeadersProvider creates HeadersProvider which sets "authorization" header to the bearer token
+// returned by the source. The token is cached and refreshed refreshBefore (defaults to 1 minute) it expires.
+// Because headers are populated for every request, long polls started after the refresh use the new token.
+// Only one refresh runs at a time and callers keep using the cached token while it runs. If the refresh fails
+// the cached token is used until it expires and the refresh is retried at most once per 5 seconds.
+func NewBearerTokenHeadersProvider(source BearerTokenSource, refreshBefore time.Duration) HeadersProvider {
+	if refreshBefore == 0 {
+		refreshBefore = defaultTokenRefreshBefore
+	}
+	return &bearerTokenHeadersProvider{
+		source:        source,
+		refreshBefore: refreshBefore,
+	}
+}
+
+func (p *bearerTokenHeadersProvider) GetHeaders(ctx context.Context) (map[string]string, error) {
+	token, err := p.getToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"authorization": "Bearer " + token}, nil
+}
+
+func (p *bearerTokenHeadersProvider) getToken(ctx context.Context) (string, error) {
+	p.lock.Lock()
+	now := time.Now()
+	if p.token != "" && (p.expiresAt.IsZero() || now.Add(p.refreshBefore).Before(p.expiresAt)) {
+		token := p.token
+		p.lock.Unlock()
+		return token, nil
+	}
+	if p.validToken(now) && (p.refresh != nil || now.Before(p.nextRefresh)) {
+		// the token is being refreshed by another caller or the last refresh failed recently
+		token := p.token
+		p.lock.Unlock()
+		return token, nil
+	}
+	refresh := p.refresh
+	if refresh == nil {
+		refresh = &tokenRefresh{done: make(chan struct{})}
+		p.refresh = refresh
+		p.lock.Unlock()
+		p.refreshToken(ctx, refresh)
+	} else {
+		p.lock.Unlock()
+	}
+
+	select {
+	case <-refresh.done:
+		return refresh.token, refresh.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// refreshToken gets a new token from the source and completes the refresh. Must be called without lock.
+func (p *bearerTokenHeadersProvider) refreshToken(ctx context.Context, refresh *tokenRefresh) {
+	token, expiresAt, err := p.source(ctx)
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.refresh = nil
+	now := time.Now()
+	if err == nil {
+		p.token = token
+		p.expiresAt = expiresAt
+		refresh.token = token
+	} else if p.validToken(now) {
+		p.nextRefresh = now.Add(tokenRefreshRetryInterval)
+		refresh.token = p.token
+	} else {
+		refresh.err = fmt.Errorf("unable to refresh bearer token: %w", err)
+	}
+	close(refresh.done)
+}
+
+// validToken returns true if the cached token hasn't expired. Must be called under lock.
+func (p *bearerTokenHeadersProvider) validToken(now time.Time) bool {
+	return p.token != "" && (p.expiresAt.IsZero() || now.Before(p.expiresAt))
+}
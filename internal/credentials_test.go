@@ -0,0 +1,213 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestCertificate writes a self-signed certificate with the common name to certFile and keyFile.
+func writeTestCertificate(t *testing.T, commonName, certFile, keyFile string, modTime time.Time) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	require.NoError(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+	require.NoError(t, os.Chtimes(certFile, modTime, modTime))
+	require.NoError(t, os.Chtimes(keyFile, modTime, modTime))
+}
+
+func certificateCommonName(t *testing.T, certificate *tls.Certificate) string {
+	parsed, err := x509.ParseCertificate(certificate.Certificate[0])
+	require.NoError(t, err)
+	return parsed.Subject.CommonName
+}
+
+func TestFileClientCertificateProvider(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client.key")
+	modTime := time.Now().Add(-time.Minute)
+	writeTestCertificate(t, "first", certFile, keyFile, modTime)
+
+	provider, err := NewFileClientCertificateProvider(certFile, keyFile, time.Nanosecond)
+	require.NoError(t, err)
+	certificate, err := provider.GetClientCertificate(nil)
+	require.NoError(t, err)
+	require.Equal(t, "first", certificateCommonName(t, certificate))
+
+	writeTestCertificate(t, "second", certFile, keyFile, modTime.Add(time.Second))
+	certificate, err = provider.GetClientCertificate(nil)
+	require.NoError(t, err)
+	require.Equal(t, "second", certificateCommonName(t, certificate))
+
+	// broken files don't replace the loaded certificate
+	require.NoError(t, ioutil.WriteFile(keyFile, []byte("garbage"), 0600))
+	certificate, err = provider.GetClientCertificate(nil)
+	require.NoError(t, err)
+	require.Equal(t, "second", certificateCommonName(t, certificate))
+}
+
+func TestFileClientCertificateProvider_MissingFiles(t *testing.T) {
+	dir := t.TempDir()
+	_, err := NewFileClientCertificateProvider(filepath.Join(dir, "client.pem"), filepath.Join(dir, "client.key"), 0)
+	require.Error(t, err)
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	require.Nil(t, newTLSConfig(ConnectionOptions{}))
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client.key")
+	writeTestCertificate(t, "client", certFile, keyFile, time.Now())
+	provider, err := NewFileClientCertificateProvider(certFile, keyFile, 0)
+	require.NoError(t, err)
+
+	userConfig := &tls.Config{ServerName: "temporal"}
+	tlsConfig := newTLSConfig(ConnectionOptions{TLS: userConfig, ClientCertificateProvider: provider})
+	require.Equal(t, "temporal", tlsConfig.ServerName)
+	require.NotNil(t, tlsConfig.GetClientCertificate)
+	// user provided configuration is not modified
+	require.Nil(t, userConfig.GetClientCertificate)
+}
+
+func TestBearerTokenHeadersProvider(t *testing.T) {
+	var calls, failedCalls int
+	var sourceErr error
+	var expiresIn time.Duration
+	provider := NewBearerTokenHeadersProvider(func(ctx context.Context) (string, time.Time, error) {
+		if sourceErr != nil {
+			failedCalls++
+			return "", time.Time{}, sourceErr
+		}
+		calls++
+		return "token" + string(rune('0'+calls)), time.Now().Add(expiresIn), nil
+	}, time.Minute)
+
+	// token is cached while it is not close to expiration
+	expiresIn = time.Hour
+	headers, err := provider.GetHeaders(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "Bearer token1", headers["authorization"])
+	headers, err = provider.GetHeaders(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "Bearer token1", headers["authorization"])
+	require.Equal(t, 1, calls)
+
+	// token which expires within refreshBefore is refreshed on every call
+	expiresIn = 30 * time.Second
+	provider.(*bearerTokenHeadersProvider).expiresAt = time.Now().Add(expiresIn)
+	headers, err = provider.GetHeaders(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "Bearer token2", headers["authorization"])
+
+	// cached token is used if refresh fails and the token hasn't expired yet
+	sourceErr = errors.New("unavailable")
+	headers, err = provider.GetHeaders(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "Bearer token2", headers["authorization"])
+	require.Equal(t, 1, failedCalls)
+
+	// failed refresh is not retried until the retry interval passes
+	headers, err = provider.GetHeaders(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "Bearer token2", headers["authorization"])
+	require.Equal(t, 1, failedCalls)
+	provider.(*bearerTokenHeadersProvider).nextRefresh = time.Now()
+	_, err = provider.GetHeaders(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, failedCalls)
+
+	provider.(*bearerTokenHeadersProvider).expiresAt = time.Now().Add(-time.Second)
+	_, err = provider.GetHeaders(context.Background())
+	require.Error(t, err)
+}
+
+func TestBearerTokenHeadersProvider_ConcurrentRefresh(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	provider := NewBearerTokenHeadersProvider(func(ctx context.Context) (string, time.Time, error) {
+		call := atomic.AddInt32(&calls, 1)
+		<-release
+		return fmt.Sprintf("token%v", call), time.Now().Add(time.Hour), nil
+	}, time.Minute).(*bearerTokenHeadersProvider)
+	provider.token = "token0"
+	provider.expiresAt = time.Now().Add(30 * time.Second)
+
+	refreshed := make(chan string)
+	go func() {
+		headers, _ := provider.GetHeaders(context.Background())
+		refreshed <- headers["authorization"]
+	}()
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&calls) == 1 }, time.Second, time.Millisecond)
+
+	// callers don't wait for the refresh while the cached token is valid
+	headers, err := provider.GetHeaders(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "Bearer token0", headers["authorization"])
+
+	// callers without a valid token wait for the refresh in progress instead of starting another one
+	provider.lock.Lock()
+	provider.expiresAt = time.Now().Add(-time.Second)
+	provider.lock.Unlock()
+	waited := make(chan string)
+	go func() {
+		headers, _ := provider.GetHeaders(context.Background())
+		waited <- headers["authorization"]
+	}()
+	close(release)
+	require.Equal(t, "Bearer token1", <-refreshed)
+	require.Equal(t, "Bearer token1", <-waited)
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
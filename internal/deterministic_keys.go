@@ -0,0 +1,98 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// DeterministicKeys returns the keys of the map m sorted in ascending order.
+// Go randomizes map iteration order, so ranging over a map directly in workflow code
+// produces a different sequence of commands on replay. Range over the returned keys instead:
+//   for _, k := range workflow.DeterministicKeys(m) {
+//       v := m[k.(string)]
+//       ...
+//   }
+// Keys must be strings, integers, floats or bools, or types derived from them.
+// Use DeterministicKeysFunc for other key types. Panics if m is not a map.
+func DeterministicKeys(m interface{}) []interface{} {
+	keys, kind := mapKeys(m)
+	var less func(a, b reflect.Value) bool
+	switch kind {
+	case reflect.String:
+		less = func(a, b reflect.Value) bool { return a.String() < b.String() }
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		less = func(a, b reflect.Value) bool { return a.Int() < b.Int() }
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		less = func(a, b reflect.Value) bool { return a.Uint() < b.Uint() }
+	case reflect.Float32, reflect.Float64:
+		less = func(a, b reflect.Value) bool { return a.Float() < b.Float() }
+	case reflect.Bool:
+		less = func(a, b reflect.Value) bool { return !a.Bool() && b.Bool() }
+	default:
+		panic(fmt.Sprintf("map key type %v has no natural order, use DeterministicKeysFunc instead", reflect.TypeOf(m).Key()))
+	}
+	sort.Slice(keys, func(i, j int) bool { return less(keys[i], keys[j]) })
+	return interfaceSlice(keys)
+}
+
+// DeterministicKeysFunc returns the keys of the map m sorted by the less function.
+// less must define a strict total order over the keys, otherwise the order is not deterministic.
+// Panics if m is not a map.
+func DeterministicKeysFunc(m interface{}, less func(a, b interface{}) bool) []interface{} {
+	keys, _ := mapKeys(m)
+	result := interfaceSlice(keys)
+	sort.Slice(result, func(i, j int) bool { return less(result[i], result[j]) })
+	return result
+}
+
+// DeterministicRange calls f for each key and value of the map m in the order returned by DeterministicKeys.
+// Iteration stops when f returns false. Panics if m is not a map or its keys have no natural order.
+func DeterministicRange(m interface{}, f func(key, value interface{}) bool) {
+	v := reflect.ValueOf(m)
+	for _, k := range DeterministicKeys(m) {
+		if !f(k, v.MapIndex(reflect.ValueOf(k)).Interface()) {
+			return
+		}
+	}
+}
+
+func mapKeys(m interface{}) ([]reflect.Value, reflect.Kind) {
+	v := reflect.ValueOf(m)
+	if v.Kind() != reflect.Map {
+		panic(fmt.Sprintf("expected a map, got %T", m))
+	}
+	return v.MapKeys(), v.Type().Key().Kind()
+}
+
+func interfaceSlice(values []reflect.Value) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, v := range values {
+		result[i] = v.Interface()
+	}
+	return result
+}
@@ -0,0 +1,71 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testKeyType string
+
+func TestDeterministicKeys(t *testing.T) {
+	assert.Equal(t, []interface{}{"a", "b", "c"}, DeterministicKeys(map[string]int{"c": 3, "a": 1, "b": 2}))
+	assert.Equal(t, []interface{}{-1, 0, 10}, DeterministicKeys(map[int]bool{10: true, -1: true, 0: false}))
+	assert.Equal(t, []interface{}{uint8(1), uint8(2)}, DeterministicKeys(map[uint8]string{2: "b", 1: "a"}))
+	assert.Equal(t, []interface{}{-0.5, 1.5}, DeterministicKeys(map[float64]int{1.5: 1, -0.5: 2}))
+	assert.Equal(t, []interface{}{false, true}, DeterministicKeys(map[bool]int{true: 1, false: 0}))
+	assert.Equal(t, []interface{}{testKeyType("x"), testKeyType("y")}, DeterministicKeys(map[testKeyType]int{"y": 1, "x": 2}))
+	assert.Empty(t, DeterministicKeys(map[string]int(nil)))
+
+	assert.Panics(t, func() { DeterministicKeys([]string{"a"}) })
+	assert.Panics(t, func() { DeterministicKeys(map[struct{ a int }]int{{1}: 1}) })
+}
+
+func TestDeterministicKeysFunc(t *testing.T) {
+	type point struct{ x, y int }
+	m := map[point]string{{2, 1}: "c", {1, 2}: "b", {1, 1}: "a"}
+	keys := DeterministicKeysFunc(m, func(a, b interface{}) bool {
+		pa, pb := a.(point), b.(point)
+		if pa.x != pb.x {
+			return pa.x < pb.x
+		}
+		return pa.y < pb.y
+	})
+	assert.Equal(t, []interface{}{point{1, 1}, point{1, 2}, point{2, 1}}, keys)
+}
+
+func TestDeterministicRange(t *testing.T) {
+	m := map[string]int{"c": 3, "a": 1, "b": 2, "d": 4}
+	var values []int
+	DeterministicRange(m, func(key, value interface{}) bool {
+		require.Equal(t, m[key.(string)], value)
+		values = append(values, value.(int))
+		return key != "c"
+	})
+	assert.Equal(t, []int{1, 2, 3}, values)
+}
@@ -34,6 +34,12 @@ import (
 	"go.temporal.io/sdk/converter"
 )
 
+// rawPayloads receives the payloads of a value as they are instead of decoding them. Code which has to handle values
+// it fails to decode receives the payloads and decodes them itself.
+type rawPayloads struct {
+	payloads *commonpb.Payloads
+}
+
 // encode multiple arguments(arguments to a function).
 func encodeArgs(dc converter.DataConverter, args []interface{}) (*commonpb.Payloads, error) {
 	return dc.ToPayloads(args...)
@@ -86,6 +92,10 @@ func decodeAndAssignValue(dc converter.DataConverter, from interface{}, toValueP
 		return errors.New("value parameter provided is not a pointer")
 	}
 	if data, ok := from.(*commonpb.Payloads); ok {
+		if raw, ok := toValuePtr.(*rawPayloads); ok {
+			raw.payloads = data
+			return nil
+		}
 		if err := decodeArg(dc, data, toValuePtr); err != nil {
 			return err
 		}
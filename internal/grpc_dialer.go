@@ -26,18 +26,25 @@ package internal
 
 import (
 	"context"
+	"crypto/tls"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gogo/status"
 	grpc_retry "github.com/grpc-ecosystem/go-grpc-middleware/retry"
-	"github.com/uber-go/tally"
 	"go.temporal.io/api/serviceerror"
 	"go.temporal.io/sdk/internal/common/retry"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/backoff"
 	"google.golang.org/grpc/credentials"
+	_ "google.golang.org/grpc/health" // registers client side health checking used by round robin endpoint selection
 	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
 
 	"go.temporal.io/sdk/internal/common/metrics"
 )
@@ -49,6 +56,22 @@ type (
 		UserConnectionOptions ConnectionOptions
 		RequiredInterceptors  []grpc.UnaryClientInterceptor
 		DefaultServiceConfig  string
+		// Endpoints overrides HostPort when more than one endpoint is provided.
+		Endpoints []string
+	}
+
+	// sharedConnection is a gRPC connection shared between several clients. The connection is closed when
+	// the last client which uses it is closed.
+	sharedConnection struct {
+		conn *grpc.ClientConn
+		lock sync.Mutex
+		refs int
+	}
+
+	// sharedConnectionRef is a closer given to every client which uses a sharedConnection.
+	sharedConnectionRef struct {
+		connection *sharedConnection
+		once       sync.Once
 	}
 )
 
@@ -59,6 +82,17 @@ const (
 	// defaultServiceConfig is a default gRPC connection service config which enables DNS round-robin between IPs.
 	defaultServiceConfig = `{"loadBalancingConfig": [{"round_robin":{}}]}`
 
+	// roundRobinEndpointsServiceConfig balances calls between multiple endpoints and excludes endpoints which
+	// fail gRPC health check.
+	roundRobinEndpointsServiceConfig = `{"loadBalancingConfig": [{"round_robin":{}}], "healthCheckConfig": {"serviceName": "` +
+		healthCheckServiceName + `"}}`
+
+	// priorityFailoverEndpointsServiceConfig connects to the first available endpoint in the order they are listed.
+	priorityFailoverEndpointsServiceConfig = `{"loadBalancingConfig": [{"pick_first":{}}]}`
+
+	// endpointsResolverScheme is a prefix of the scheme of a resolver created for multiple endpoints.
+	endpointsResolverScheme = "temporal-endpoints"
+
 	// minConnectTimeout is the minimum amount of time we are willing to give a connection to complete.
 	minConnectTimeout = 20 * time.Second
 
@@ -70,11 +104,30 @@ const (
 	defaultMaxPayloadSize = 64 * mb
 )
 
+// newTLSConfig returns TLS configuration of the connection or nil if TLS is not used.
+func newTLSConfig(options ConnectionOptions) *tls.Config {
+	if options.ClientCertificateProvider == nil {
+		return options.TLS
+	}
+	var tlsConfig *tls.Config
+	if options.TLS != nil {
+		tlsConfig = options.TLS.Clone()
+	} else {
+		tlsConfig = &tls.Config{}
+	}
+	// Certificate is requested on every handshake, so reconnects pick up a rotated certificate.
+	tlsConfig.GetClientCertificate = options.ClientCertificateProvider.GetClientCertificate
+	return tlsConfig
+}
+
+// endpointsResolverCounter makes the scheme of every endpoints resolver unique.
+var endpointsResolverCounter int32
+
 func dial(params dialParameters) (*grpc.ClientConn, error) {
 	var securityOptions []grpc.DialOption
-	if params.UserConnectionOptions.TLS != nil {
+	if tlsConfig := newTLSConfig(params.UserConnectionOptions); tlsConfig != nil {
 		securityOptions = []grpc.DialOption{
-			grpc.WithTransportCredentials(credentials.NewTLS(params.UserConnectionOptions.TLS)),
+			grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)),
 		}
 	} else {
 		securityOptions = []grpc.DialOption{
@@ -121,21 +174,106 @@ func dial(params dialParameters) (*grpc.ClientConn, error) {
 		}
 		opts = append(opts, grpc.WithKeepaliveParams(kap))
 	}
-	return grpc.Dial(params.HostPort, opts...)
+
+	if len(params.Endpoints) > 1 {
+		return dialEndpoints(params, opts)
+	}
+	hostPort := params.HostPort
+	if len(params.Endpoints) == 1 {
+		hostPort = params.Endpoints[0]
+	}
+	return grpc.Dial(hostPort, opts...)
+}
+
+// dialEndpoints creates a single gRPC connection to multiple endpoints. Endpoints are provided to gRPC
+// by a resolver which is private to the connection, and the load balancing policy is chosen according to
+// ConnectionOptions.EndpointSelection.
+func dialEndpoints(params dialParameters, opts []grpc.DialOption) (*grpc.ClientConn, error) {
+	endpoints := params.Endpoints
+	serviceConfig := roundRobinEndpointsServiceConfig
+	if params.UserConnectionOptions.EndpointSelection == EndpointSelectionPriorityFailover {
+		serviceConfig = priorityFailoverEndpointsServiceConfig
+		// Start with the first healthy endpoint. The rest of the endpoints are kept in order and gRPC will fail over
+		// to them if the connection to the current endpoint is lost.
+		endpoints = prioritizeHealthyEndpoint(params, opts)
+	}
+
+	addresses := make([]resolver.Address, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		addresses = append(addresses, resolver.Address{Addr: endpoint})
+	}
+	scheme := fmt.Sprintf("%s-%d", endpointsResolverScheme, atomic.AddInt32(&endpointsResolverCounter, 1))
+	r := manual.NewBuilderWithScheme(scheme)
+	r.InitialState(resolver.State{Addresses: addresses})
+
+	// Service config provided by the endpoints selection takes precedence over the default one.
+	opts = append(opts, grpc.WithResolvers(r), grpc.WithDefaultServiceConfig(serviceConfig))
+	return grpc.Dial(fmt.Sprintf("%s:///%s", scheme, strings.Join(params.Endpoints, ",")), opts...)
+}
+
+// prioritizeHealthyEndpoint returns endpoints starting from the first one which passes checkHealth. Endpoints
+// are returned as is if none of them is healthy or health check is disabled.
+func prioritizeHealthyEndpoint(params dialParameters, opts []grpc.DialOption) []string {
+	if params.UserConnectionOptions.DisableHealthCheck {
+		return params.Endpoints
+	}
+	for i, endpoint := range params.Endpoints {
+		conn, err := grpc.Dial(endpoint, opts...)
+		if err != nil {
+			continue
+		}
+		err = checkHealth(conn, params.UserConnectionOptions)
+		_ = conn.Close()
+		if err == nil {
+			return append(append([]string{}, params.Endpoints[i:]...), params.Endpoints[:i]...)
+		}
+	}
+	return params.Endpoints
+}
+
+func newSharedConnection(conn *grpc.ClientConn) *sharedConnection {
+	return &sharedConnection{conn: conn}
+}
+
+// acquire returns a closer which must be closed when the client no longer uses the connection.
+func (c *sharedConnection) acquire() *sharedConnectionRef {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.refs++
+	return &sharedConnectionRef{connection: c}
+}
+
+func (c *sharedConnection) release() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.refs--
+	if c.refs > 0 {
+		return nil
+	}
+	return c.conn.Close()
+}
+
+// Close releases the shared connection. Subsequent calls are no-op.
+func (r *sharedConnectionRef) Close() error {
+	var err error
+	r.once.Do(func() {
+		err = r.connection.release()
+	})
+	return err
 }
 
-func requiredInterceptors(metricScope tally.Scope, headersProvider HeadersProvider, controller TrafficController) []grpc.UnaryClientInterceptor {
+func requiredInterceptors(metricsHandler metrics.Handler, headersProvider HeadersProvider, controller TrafficController) []grpc.UnaryClientInterceptor {
 	interceptors := []grpc.UnaryClientInterceptor{
 		errorInterceptor,
 		// Report aggregated metrics for the call, this is done outside of the retry loop.
-		metrics.NewGRPCMetricsInterceptor(metricScope, ""),
+		metrics.NewGRPCMetricsInterceptor(metricsHandler, ""),
 		// By default the grpc retry interceptor *is disabled*, preventing accidental use of retries.
 		// We add call options for retry configuration based on the values present in the context.
 		retry.NewRetryOptionsInterceptor(),
 		// Performs retries *IF* retry options are set for the call.
 		grpc_retry.UnaryClientInterceptor(),
 		// Report metrics for every call made to the server.
-		metrics.NewGRPCMetricsInterceptor(metricScope, attemptSuffix),
+		metrics.NewGRPCMetricsInterceptor(metricsHandler, attemptSuffix),
 	}
 	if headersProvider != nil {
 		interceptors = append(interceptors, headersProviderInterceptor(headersProvider))
@@ -27,7 +27,9 @@ package internal
 import (
 	"context"
 	"errors"
+	"net"
 	"testing"
+	"time"
 
 	"github.com/gogo/status"
 	"github.com/stretchr/testify/require"
@@ -35,6 +37,9 @@ import (
 	"go.temporal.io/api/serviceerror"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/metadata"
 )
 
@@ -115,3 +120,111 @@ func TestHeadersProvider_IncludedWithHeadersProvider(t *testing.T) {
 	interceptors := requiredInterceptors(nil, authHeadersProvider{token: "test-auth-token"}, nil)
 	require.Equal(t, 6, len(interceptors))
 }
+
+// startHealthServer starts gRPC server which serves only health check and returns its address.
+func startHealthServer(t *testing.T, status healthpb.HealthCheckResponse_ServingStatus) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(healthCheckServiceName, status)
+	healthpb.RegisterHealthServer(server, healthServer)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+	return listener.Addr().String()
+}
+
+// unusedAddress returns an address nobody listens on.
+func unusedAddress(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	require.NoError(t, listener.Close())
+	return address
+}
+
+var testEndpointsConnectionOptions = ConnectionOptions{
+	HealthCheckAttemptTimeout: 100 * time.Millisecond,
+	HealthCheckTimeout:        500 * time.Millisecond,
+}
+
+func TestDialEndpoints_RoundRobin(t *testing.T) {
+	endpoints := []string{
+		startHealthServer(t, healthpb.HealthCheckResponse_SERVING),
+		startHealthServer(t, healthpb.HealthCheckResponse_SERVING),
+	}
+	conn, err := dial(dialParameters{
+		Endpoints:             endpoints,
+		UserConnectionOptions: testEndpointsConnectionOptions,
+		DefaultServiceConfig:  defaultServiceConfig,
+	})
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+	require.NoError(t, checkHealth(conn, testEndpointsConnectionOptions))
+}
+
+func TestDialEndpoints_PriorityFailover(t *testing.T) {
+	unhealthy := startHealthServer(t, healthpb.HealthCheckResponse_NOT_SERVING)
+	healthy := startHealthServer(t, healthpb.HealthCheckResponse_SERVING)
+	down := unusedAddress(t)
+	options := testEndpointsConnectionOptions
+	options.EndpointSelection = EndpointSelectionPriorityFailover
+
+	params := dialParameters{
+		Endpoints:             []string{down, unhealthy, healthy},
+		UserConnectionOptions: options,
+		DefaultServiceConfig:  defaultServiceConfig,
+	}
+	require.Equal(t, []string{healthy, down, unhealthy}, prioritizeHealthyEndpoint(params, []grpc.DialOption{grpc.WithInsecure()}))
+
+	conn, err := dial(params)
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+	require.NoError(t, checkHealth(conn, options))
+}
+
+func TestDialEndpoints_PriorityFailover_NoHealthyEndpoints(t *testing.T) {
+	endpoints := []string{unusedAddress(t), unusedAddress(t)}
+	options := testEndpointsConnectionOptions
+	options.EndpointSelection = EndpointSelectionPriorityFailover
+	params := dialParameters{Endpoints: endpoints, UserConnectionOptions: options}
+	require.Equal(t, endpoints, prioritizeHealthyEndpoint(params, []grpc.DialOption{grpc.WithInsecure()}))
+}
+
+func TestSharedConnection(t *testing.T) {
+	conn, err := dial(dialParameters{
+		HostPort:             startHealthServer(t, healthpb.HealthCheckResponse_SERVING),
+		DefaultServiceConfig: defaultServiceConfig,
+	})
+	require.NoError(t, err)
+	shared := newSharedConnection(conn)
+	first := shared.acquire()
+	second := shared.acquire()
+
+	require.NoError(t, first.Close())
+	// closing the same reference twice doesn't release the connection for other clients
+	require.NoError(t, first.Close())
+	require.NotEqual(t, connectivity.Shutdown, conn.GetState())
+
+	require.NoError(t, second.Close())
+	require.Equal(t, connectivity.Shutdown, conn.GetState())
+}
+
+func TestNewNamespaceClientFromClient(t *testing.T) {
+	c, err := NewClient(ClientOptions{
+		HostPorts:         []string{startHealthServer(t, healthpb.HealthCheckResponse_SERVING), unusedAddress(t)},
+		ConnectionOptions: testEndpointsConnectionOptions,
+	})
+	require.NoError(t, err)
+	wc := c.(*WorkflowClient)
+
+	nc, err := NewNamespaceClientFromClient(c)
+	require.NoError(t, err)
+	c.Close()
+	require.NotEqual(t, connectivity.Shutdown, wc.connection.conn.GetState())
+	nc.Close()
+	require.Equal(t, connectivity.Shutdown, wc.connection.conn.GetState())
+
+	_, err = NewNamespaceClientFromClient(NewServiceClient(nil, nil, ClientOptions{}))
+	require.Error(t, err)
+}
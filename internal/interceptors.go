@@ -25,9 +25,13 @@
 package internal
 
 import (
+	"context"
 	"time"
 
 	"github.com/uber-go/tally"
+	commonpb "go.temporal.io/api/common/v1"
+	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/workflowservice/v1"
 
 	"go.temporal.io/sdk/converter"
 	"go.temporal.io/sdk/log"
@@ -46,6 +50,13 @@ type WorkflowInterceptor interface {
 // All code in the interceptor is executed in the workflow.Context of a workflow. So all the rules and restrictions
 // that apply to the workflow code should be obeyed by the interceptor implementation.
 // Use workflow.IsReplaying(ctx) to filter out duplicated calls.
+// Methods are added to this interface when the SDK intercepts more calls, for example HandleSignal and HandleQuery
+// were added after ExecuteWorkflow. Implementations which don't embed WorkflowInboundCallsInterceptorBase stop
+// compiling when that happens.
+// ExecuteWorkflow gets decoded arguments, because their types are known from the registered workflow function.
+// HandleSignal and HandleQuery get the encoded arguments instead: a signal is decoded only when the workflow code
+// receives it from the signal channel into a value of its choice, and query arguments are decoded by the query handler
+// which may not be registered yet.
 type WorkflowInboundCallsInterceptor interface {
 	Init(outbound WorkflowOutboundCallsInterceptor) error
 
@@ -54,7 +65,18 @@ type WorkflowInboundCallsInterceptor interface {
 	// WorkflowType argument is for information purposes only and should not be mutated.
 	ExecuteWorkflow(ctx Context, workflowType string, args ...interface{}) []interface{}
 
-	//TODO(maxim): ProcessSignal(ctx Context, signalName string, arg interface{}) error
+	// HandleSignal intercepts delivery of a signal to the workflow. It is called before the signal is placed into
+	// the channel returned by GetSignalChannel. Arg contains the encoded signal argument and can be replaced before
+	// forwarding it to the next link. Returning an error panics in the workflow task, which is then handled
+	// according to WorkerOptions.WorkflowPanicPolicy: by default the workflow task fails and is retried, so the
+	// signal is delivered again by the retry. Return an error only when the workflow can't make progress without it.
+	// It is not called from a workflow coroutine, so it must not block.
+	HandleSignal(ctx Context, signalName string, arg *commonpb.Payloads) error
+
+	// HandleQuery intercepts a query to the workflow. It is called before the query handler registered through
+	// SetQueryHandler is invoked and can be used to validate the query or to inspect its result.
+	// It is not called from a workflow coroutine, so it must not block.
+	HandleQuery(ctx Context, queryType string, args *commonpb.Payloads) (*commonpb.Payloads, error)
 }
 
 // WorkflowOutboundCallsInterceptor is an interface that can be implemented to intercept calls to the SDK APIs done
@@ -71,6 +93,8 @@ type WorkflowOutboundCallsInterceptor interface {
 	ExecuteChildWorkflow(ctx Context, childWorkflowType string, args ...interface{}) ChildWorkflowFuture
 	GetWorkflowInfo(ctx Context) *WorkflowInfo
 	GetLogger(ctx Context) log.Logger
+	GetMetricsHandler(ctx Context) MetricsHandler
+	// Deprecated: use GetMetricsHandler instead.
 	GetMetricsScope(ctx Context) tally.Scope
 	Now(ctx Context) time.Time
 	NewTimer(ctx Context, d time.Duration) Future
@@ -108,6 +132,16 @@ func (w WorkflowInboundCallsInterceptorBase) ExecuteWorkflow(ctx Context, workfl
 	return w.Next.ExecuteWorkflow(ctx, workflowType, args...)
 }
 
+// HandleSignal forwards to w.Next
+func (w WorkflowInboundCallsInterceptorBase) HandleSignal(ctx Context, signalName string, arg *commonpb.Payloads) error {
+	return w.Next.HandleSignal(ctx, signalName, arg)
+}
+
+// HandleQuery forwards to w.Next
+func (w WorkflowInboundCallsInterceptorBase) HandleQuery(ctx Context, queryType string, args *commonpb.Payloads) (*commonpb.Payloads, error) {
+	return w.Next.HandleQuery(ctx, queryType, args)
+}
+
 // WorkflowOutboundCallsInterceptorBase is a noop implementation of WorkflowOutboundCallsInterceptor that just forwards requests
 // to the next link in an interceptor chain. To be used as base implementation of interceptors.
 type WorkflowOutboundCallsInterceptorBase struct {
@@ -144,7 +178,14 @@ func (t *WorkflowOutboundCallsInterceptorBase) GetLogger(ctx Context) log.Logger
 	return t.Next.GetLogger(ctx)
 }
 
+// GetMetricsHandler forwards to t.Next
+func (t *WorkflowOutboundCallsInterceptorBase) GetMetricsHandler(ctx Context) MetricsHandler {
+	return t.Next.GetMetricsHandler(ctx)
+}
+
 // GetMetricsScope forwards to t.Next
+//
+// Deprecated: use GetMetricsHandler instead.
 func (t *WorkflowOutboundCallsInterceptorBase) GetMetricsScope(ctx Context) tally.Scope {
 	return t.Next.GetMetricsScope(ctx)
 }
@@ -223,3 +264,270 @@ func (t *WorkflowOutboundCallsInterceptorBase) GetLastCompletionResult(ctx Conte
 func (t *WorkflowOutboundCallsInterceptorBase) GetLastError(ctx Context) error {
 	return t.Next.GetLastError(ctx)
 }
+
+// ActivityInterceptor is used to create a single link in the activity interceptor chain
+type ActivityInterceptor interface {
+	// InterceptActivity creates an interceptor instance. The created instance must delegate every call to
+	// the next parameter for activity code function correctly.
+	InterceptActivity(info *ActivityInfo, next ActivityInboundCallsInterceptor) ActivityInboundCallsInterceptor
+}
+
+// ActivityInboundCallsInterceptor is an interface that can be implemented to intercept calls to the activity.
+// Use ActivityInboundCallsInterceptorBase as a base struct for implementations that do not want to implement every method.
+// Interceptor implementation must forward calls to the next in the interceptor chain.
+// Local activities are not intercepted.
+type ActivityInboundCallsInterceptor interface {
+	Init(outbound ActivityOutboundCallsInterceptor) error
+
+	// ExecuteActivity intercepts activity function invocation. Args are already decoded using the worker DataConverter.
+	// ActivityType argument is for information purposes only and should not be mutated.
+	ExecuteActivity(ctx context.Context, activityType string, args ...interface{}) (interface{}, error)
+}
+
+// ActivityOutboundCallsInterceptor is an interface that can be implemented to intercept calls to the SDK APIs done
+// by the activity code.
+// Use ActivityOutboundCallsInterceptorBase as a base struct for implementations that do not want to implement every method.
+// Interceptor implementation must forward calls to the next in the interceptor chain.
+type ActivityOutboundCallsInterceptor interface {
+	GetActivityInfo(ctx context.Context) ActivityInfo
+	GetActivityLogger(ctx context.Context) log.Logger
+	GetActivityMetricsHandler(ctx context.Context) MetricsHandler
+	// Deprecated: use GetActivityMetricsHandler instead.
+	GetActivityMetricsScope(ctx context.Context) tally.Scope
+	RecordActivityHeartbeat(ctx context.Context, details ...interface{})
+}
+
+var _ ActivityInboundCallsInterceptor = (*ActivityInboundCallsInterceptorBase)(nil)
+var _ ActivityOutboundCallsInterceptor = (*ActivityOutboundCallsInterceptorBase)(nil)
+
+// ActivityInboundCallsInterceptorBase is a noop implementation of ActivityInboundCallsInterceptor that just forwards requests
+// to the next link in an interceptor chain. To be used as base implementation of interceptors.
+type ActivityInboundCallsInterceptorBase struct {
+	Next ActivityInboundCallsInterceptor
+}
+
+// Init called before the activity function is invoked
+func (a *ActivityInboundCallsInterceptorBase) Init(outbound ActivityOutboundCallsInterceptor) error {
+	return a.Next.Init(outbound)
+}
+
+// ExecuteActivity intercepts invocation of the activity function
+func (a *ActivityInboundCallsInterceptorBase) ExecuteActivity(ctx context.Context, activityType string, args ...interface{}) (interface{}, error) {
+	return a.Next.ExecuteActivity(ctx, activityType, args...)
+}
+
+// ActivityOutboundCallsInterceptorBase is a noop implementation of ActivityOutboundCallsInterceptor that just forwards requests
+// to the next link in an interceptor chain. To be used as base implementation of interceptors.
+type ActivityOutboundCallsInterceptorBase struct {
+	Next ActivityOutboundCallsInterceptor
+}
+
+// GetActivityInfo forwards to a.Next
+func (a *ActivityOutboundCallsInterceptorBase) GetActivityInfo(ctx context.Context) ActivityInfo {
+	return a.Next.GetActivityInfo(ctx)
+}
+
+// GetActivityLogger forwards to a.Next
+func (a *ActivityOutboundCallsInterceptorBase) GetActivityLogger(ctx context.Context) log.Logger {
+	return a.Next.GetActivityLogger(ctx)
+}
+
+// GetActivityMetricsHandler forwards to a.Next
+func (a *ActivityOutboundCallsInterceptorBase) GetActivityMetricsHandler(ctx context.Context) MetricsHandler {
+	return a.Next.GetActivityMetricsHandler(ctx)
+}
+
+// GetActivityMetricsScope forwards to a.Next
+//
+// Deprecated: use GetActivityMetricsHandler instead.
+func (a *ActivityOutboundCallsInterceptorBase) GetActivityMetricsScope(ctx context.Context) tally.Scope {
+	return a.Next.GetActivityMetricsScope(ctx)
+}
+
+// RecordActivityHeartbeat forwards to a.Next
+func (a *ActivityOutboundCallsInterceptorBase) RecordActivityHeartbeat(ctx context.Context, details ...interface{}) {
+	a.Next.RecordActivityHeartbeat(ctx, details...)
+}
+
+// ClientInterceptor is used to create a single link in the client interceptor chain.
+type ClientInterceptor interface {
+	// InterceptClient creates an interceptor instance. The created instance must delegate every call to
+	// the next parameter for the client to function correctly.
+	InterceptClient(next ClientOutboundInterceptor) ClientOutboundInterceptor
+}
+
+// ClientOutboundInterceptor is an interface that can be implemented to intercept calls done through the Client.
+// Calls are intercepted before arguments are converted to payloads, so interceptors see the same values that were
+// passed to the Client.
+// Use ClientOutboundInterceptorBase as a base struct for implementations that do not want to implement every method.
+// Interceptor implementation must forward calls to the next in the interceptor chain.
+type ClientOutboundInterceptor interface {
+	ExecuteWorkflow(ctx context.Context, options StartWorkflowOptions, workflow interface{}, args ...interface{}) (WorkflowRun, error)
+	GetWorkflow(ctx context.Context, workflowID string, runID string) WorkflowRun
+	SignalWorkflow(ctx context.Context, workflowID string, runID string, signalName string, arg interface{}) error
+	SignalWithStartWorkflow(ctx context.Context, workflowID string, signalName string, signalArg interface{},
+		options StartWorkflowOptions, workflow interface{}, workflowArgs ...interface{}) (WorkflowRun, error)
+	CancelWorkflow(ctx context.Context, workflowID string, runID string) error
+	TerminateWorkflow(ctx context.Context, workflowID string, runID string, reason string, details ...interface{}) error
+	GetWorkflowHistory(ctx context.Context, workflowID string, runID string, isLongPoll bool, filterType enumspb.HistoryEventFilterType) HistoryEventIterator
+	CompleteActivity(ctx context.Context, taskToken []byte, result interface{}, err error) error
+	CompleteActivityByID(ctx context.Context, namespace, workflowID, runID, activityID string, result interface{}, err error) error
+	RecordActivityHeartbeat(ctx context.Context, taskToken []byte, details ...interface{}) error
+	RecordActivityHeartbeatByID(ctx context.Context, namespace, workflowID, runID, activityID string, details ...interface{}) error
+	ListClosedWorkflow(ctx context.Context, request *workflowservice.ListClosedWorkflowExecutionsRequest) (*workflowservice.ListClosedWorkflowExecutionsResponse, error)
+	ListOpenWorkflow(ctx context.Context, request *workflowservice.ListOpenWorkflowExecutionsRequest) (*workflowservice.ListOpenWorkflowExecutionsResponse, error)
+	ListWorkflow(ctx context.Context, request *workflowservice.ListWorkflowExecutionsRequest) (*workflowservice.ListWorkflowExecutionsResponse, error)
+	ListArchivedWorkflow(ctx context.Context, request *workflowservice.ListArchivedWorkflowExecutionsRequest) (*workflowservice.ListArchivedWorkflowExecutionsResponse, error)
+	ScanWorkflow(ctx context.Context, request *workflowservice.ScanWorkflowExecutionsRequest) (*workflowservice.ScanWorkflowExecutionsResponse, error)
+	CountWorkflow(ctx context.Context, request *workflowservice.CountWorkflowExecutionsRequest) (*workflowservice.CountWorkflowExecutionsResponse, error)
+	GetSearchAttributes(ctx context.Context) (*workflowservice.GetSearchAttributesResponse, error)
+	// QueryWorkflowWithOptions intercepts both Client.QueryWorkflow and Client.QueryWorkflowWithOptions calls.
+	QueryWorkflowWithOptions(ctx context.Context, request *QueryWorkflowWithOptionsRequest) (*QueryWorkflowWithOptionsResponse, error)
+	DescribeWorkflowExecution(ctx context.Context, workflowID, runID string) (*workflowservice.DescribeWorkflowExecutionResponse, error)
+	DescribeTaskQueue(ctx context.Context, taskQueue string, taskQueueType enumspb.TaskQueueType) (*workflowservice.DescribeTaskQueueResponse, error)
+	ResetWorkflowExecution(ctx context.Context, request *workflowservice.ResetWorkflowExecutionRequest) (*workflowservice.ResetWorkflowExecutionResponse, error)
+	ListWorkflowExecutions(ctx context.Context, options ListWorkflowOptions) WorkflowExecutionIterator
+	CountWorkflowExecutions(ctx context.Context, query string) (int64, error)
+	ExecuteBatchOperation(ctx context.Context, options BatchOperationOptions) (*BatchOperationReport, error)
+	// UpdateWorkflowWithOptions intercepts both Client.UpdateWorkflow and Client.UpdateWorkflowWithOptions calls.
+	UpdateWorkflowWithOptions(ctx context.Context, request *UpdateWorkflowWithOptionsRequest) (converter.EncodedValue, error)
+}
+
+var _ ClientOutboundInterceptor = (*ClientOutboundInterceptorBase)(nil)
+
+// ClientOutboundInterceptorBase is a noop implementation of ClientOutboundInterceptor that just forwards requests
+// to the next link in an interceptor chain. To be used as base implementation of interceptors.
+type ClientOutboundInterceptorBase struct {
+	Next ClientOutboundInterceptor
+}
+
+// ExecuteWorkflow forwards to c.Next
+func (c *ClientOutboundInterceptorBase) ExecuteWorkflow(ctx context.Context, options StartWorkflowOptions, workflow interface{}, args ...interface{}) (WorkflowRun, error) {
+	return c.Next.ExecuteWorkflow(ctx, options, workflow, args...)
+}
+
+// GetWorkflow forwards to c.Next
+func (c *ClientOutboundInterceptorBase) GetWorkflow(ctx context.Context, workflowID string, runID string) WorkflowRun {
+	return c.Next.GetWorkflow(ctx, workflowID, runID)
+}
+
+// SignalWorkflow forwards to c.Next
+func (c *ClientOutboundInterceptorBase) SignalWorkflow(ctx context.Context, workflowID string, runID string, signalName string, arg interface{}) error {
+	return c.Next.SignalWorkflow(ctx, workflowID, runID, signalName, arg)
+}
+
+// SignalWithStartWorkflow forwards to c.Next
+func (c *ClientOutboundInterceptorBase) SignalWithStartWorkflow(ctx context.Context, workflowID string, signalName string, signalArg interface{},
+	options StartWorkflowOptions, workflow interface{}, workflowArgs ...interface{}) (WorkflowRun, error) {
+	return c.Next.SignalWithStartWorkflow(ctx, workflowID, signalName, signalArg, options, workflow, workflowArgs...)
+}
+
+// CancelWorkflow forwards to c.Next
+func (c *ClientOutboundInterceptorBase) CancelWorkflow(ctx context.Context, workflowID string, runID string) error {
+	return c.Next.CancelWorkflow(ctx, workflowID, runID)
+}
+
+// TerminateWorkflow forwards to c.Next
+func (c *ClientOutboundInterceptorBase) TerminateWorkflow(ctx context.Context, workflowID string, runID string, reason string, details ...interface{}) error {
+	return c.Next.TerminateWorkflow(ctx, workflowID, runID, reason, details...)
+}
+
+// GetWorkflowHistory forwards to c.Next
+func (c *ClientOutboundInterceptorBase) GetWorkflowHistory(ctx context.Context, workflowID string, runID string, isLongPoll bool, filterType enumspb.HistoryEventFilterType) HistoryEventIterator {
+	return c.Next.GetWorkflowHistory(ctx, workflowID, runID, isLongPoll, filterType)
+}
+
+// CompleteActivity forwards to c.Next
+func (c *ClientOutboundInterceptorBase) CompleteActivity(ctx context.Context, taskToken []byte, result interface{}, err error) error {
+	return c.Next.CompleteActivity(ctx, taskToken, result, err)
+}
+
+// CompleteActivityByID forwards to c.Next
+func (c *ClientOutboundInterceptorBase) CompleteActivityByID(ctx context.Context, namespace, workflowID, runID, activityID string, result interface{}, err error) error {
+	return c.Next.CompleteActivityByID(ctx, namespace, workflowID, runID, activityID, result, err)
+}
+
+// RecordActivityHeartbeat forwards to c.Next
+func (c *ClientOutboundInterceptorBase) RecordActivityHeartbeat(ctx context.Context, taskToken []byte, details ...interface{}) error {
+	return c.Next.RecordActivityHeartbeat(ctx, taskToken, details...)
+}
+
+// RecordActivityHeartbeatByID forwards to c.Next
+func (c *ClientOutboundInterceptorBase) RecordActivityHeartbeatByID(ctx context.Context, namespace, workflowID, runID, activityID string, details ...interface{}) error {
+	return c.Next.RecordActivityHeartbeatByID(ctx, namespace, workflowID, runID, activityID, details...)
+}
+
+// ListClosedWorkflow forwards to c.Next
+func (c *ClientOutboundInterceptorBase) ListClosedWorkflow(ctx context.Context, request *workflowservice.ListClosedWorkflowExecutionsRequest) (*workflowservice.ListClosedWorkflowExecutionsResponse, error) {
+	return c.Next.ListClosedWorkflow(ctx, request)
+}
+
+// ListOpenWorkflow forwards to c.Next
+func (c *ClientOutboundInterceptorBase) ListOpenWorkflow(ctx context.Context, request *workflowservice.ListOpenWorkflowExecutionsRequest) (*workflowservice.ListOpenWorkflowExecutionsResponse, error) {
+	return c.Next.ListOpenWorkflow(ctx, request)
+}
+
+// ListWorkflow forwards to c.Next
+func (c *ClientOutboundInterceptorBase) ListWorkflow(ctx context.Context, request *workflowservice.ListWorkflowExecutionsRequest) (*workflowservice.ListWorkflowExecutionsResponse, error) {
+	return c.Next.ListWorkflow(ctx, request)
+}
+
+// ListArchivedWorkflow forwards to c.Next
+func (c *ClientOutboundInterceptorBase) ListArchivedWorkflow(ctx context.Context, request *workflowservice.ListArchivedWorkflowExecutionsRequest) (*workflowservice.ListArchivedWorkflowExecutionsResponse, error) {
+	return c.Next.ListArchivedWorkflow(ctx, request)
+}
+
+// ScanWorkflow forwards to c.Next
+func (c *ClientOutboundInterceptorBase) ScanWorkflow(ctx context.Context, request *workflowservice.ScanWorkflowExecutionsRequest) (*workflowservice.ScanWorkflowExecutionsResponse, error) {
+	return c.Next.ScanWorkflow(ctx, request)
+}
+
+// CountWorkflow forwards to c.Next
+func (c *ClientOutboundInterceptorBase) CountWorkflow(ctx context.Context, request *workflowservice.CountWorkflowExecutionsRequest) (*workflowservice.CountWorkflowExecutionsResponse, error) {
+	return c.Next.CountWorkflow(ctx, request)
+}
+
+// GetSearchAttributes forwards to c.Next
+func (c *ClientOutboundInterceptorBase) GetSearchAttributes(ctx context.Context) (*workflowservice.GetSearchAttributesResponse, error) {
+	return c.Next.GetSearchAttributes(ctx)
+}
+
+// QueryWorkflowWithOptions forwards to c.Next
+func (c *ClientOutboundInterceptorBase) QueryWorkflowWithOptions(ctx context.Context, request *QueryWorkflowWithOptionsRequest) (*QueryWorkflowWithOptionsResponse, error) {
+	return c.Next.QueryWorkflowWithOptions(ctx, request)
+}
+
+// DescribeWorkflowExecution forwards to c.Next
+func (c *ClientOutboundInterceptorBase) DescribeWorkflowExecution(ctx context.Context, workflowID, runID string) (*workflowservice.DescribeWorkflowExecutionResponse, error) {
+	return c.Next.DescribeWorkflowExecution(ctx, workflowID, runID)
+}
+
+// DescribeTaskQueue forwards to c.Next
+func (c *ClientOutboundInterceptorBase) DescribeTaskQueue(ctx context.Context, taskQueue string, taskQueueType enumspb.TaskQueueType) (*workflowservice.DescribeTaskQueueResponse, error) {
+	return c.Next.DescribeTaskQueue(ctx, taskQueue, taskQueueType)
+}
+
+// ResetWorkflowExecution forwards to c.Next
+func (c *ClientOutboundInterceptorBase) ResetWorkflowExecution(ctx context.Context, request *workflowservice.ResetWorkflowExecutionRequest) (*workflowservice.ResetWorkflowExecutionResponse, error) {
+	return c.Next.ResetWorkflowExecution(ctx, request)
+}
+
+// ListWorkflowExecutions forwards to c.Next
+func (c *ClientOutboundInterceptorBase) ListWorkflowExecutions(ctx context.Context, options ListWorkflowOptions) WorkflowExecutionIterator {
+	return c.Next.ListWorkflowExecutions(ctx, options)
+}
+
+// CountWorkflowExecutions forwards to c.Next
+func (c *ClientOutboundInterceptorBase) CountWorkflowExecutions(ctx context.Context, query string) (int64, error) {
+	return c.Next.CountWorkflowExecutions(ctx, query)
+}
+
+// ExecuteBatchOperation forwards to c.Next
+func (c *ClientOutboundInterceptorBase) ExecuteBatchOperation(ctx context.Context, options BatchOperationOptions) (*BatchOperationReport, error) {
+	return c.Next.ExecuteBatchOperation(ctx, options)
+}
+
+// UpdateWorkflowWithOptions forwards to c.Next
+func (c *ClientOutboundInterceptorBase) UpdateWorkflowWithOptions(ctx context.Context, request *UpdateWorkflowWithOptionsRequest) (converter.EncodedValue, error) {
+	return c.Next.UpdateWorkflowWithOptions(ctx, request)
+}
@@ -34,10 +34,10 @@ import (
 	"time"
 
 	"github.com/opentracing/opentracing-go"
-	"github.com/uber-go/tally"
 	commonpb "go.temporal.io/api/common/v1"
 
 	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/internal/common/metrics"
 	"go.temporal.io/sdk/log"
 )
 
@@ -128,7 +128,7 @@ type (
 		activityType       ActivityType
 		serviceInvoker     ServiceInvoker
 		logger             log.Logger
-		metricsScope       tally.Scope
+		metricsHandler     metrics.Handler
 		isLocalActivity    bool
 		heartbeatTimeout   time.Duration
 		deadline           time.Time
@@ -143,6 +143,15 @@ type (
 		workerStopChannel  <-chan struct{}
 		contextPropagators []ContextPropagator
 		tracer             opentracing.Tracer
+		interceptor        *activityEnvironmentInterceptor
+	}
+
+	// activityEnvironmentInterceptor is the last link of the activity inbound interceptor chain and the first
+	// link of the outbound one.
+	activityEnvironmentInterceptor struct {
+		fn                  interface{}
+		inboundInterceptor  ActivityInboundCallsInterceptor
+		outboundInterceptor ActivityOutboundCallsInterceptor
 	}
 
 	// context.WithValue need this type instead of basic type string to avoid lint error
@@ -183,6 +192,72 @@ func getActivityEnv(ctx context.Context) *activityEnvironment {
 	return env.(*activityEnvironment)
 }
 
+func getActivityOutboundCallsInterceptor(ctx context.Context) ActivityOutboundCallsInterceptor {
+	env := getActivityEnv(ctx)
+	if env.interceptor == nil {
+		// Activity is executed without an interceptor chain, for example a local activity.
+		return &activityEnvironmentInterceptor{}
+	}
+	return env.interceptor.outboundInterceptor
+}
+
+func getActivityEnvironmentInterceptor(ctx context.Context) *activityEnvironmentInterceptor {
+	env := getActivityEnvironmentFromCtx(ctx)
+	if env == nil || env.interceptor == nil {
+		envInterceptor := &activityEnvironmentInterceptor{}
+		envInterceptor.inboundInterceptor = envInterceptor
+		envInterceptor.outboundInterceptor = envInterceptor
+		return envInterceptor
+	}
+	return env.interceptor
+}
+
+func newActivityInterceptors(env *activityEnvironment, interceptors []ActivityInterceptor) (*activityEnvironmentInterceptor, error) {
+	envInterceptor := &activityEnvironmentInterceptor{}
+	var interceptor ActivityInboundCallsInterceptor = envInterceptor
+	info := env.getActivityInfo()
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor = interceptors[i].InterceptActivity(&info, interceptor)
+	}
+	envInterceptor.inboundInterceptor = interceptor
+	if err := interceptor.Init(envInterceptor); err != nil {
+		return nil, err
+	}
+	return envInterceptor, nil
+}
+
+func (a *activityEnvironmentInterceptor) Init(outbound ActivityOutboundCallsInterceptor) error {
+	a.outboundInterceptor = outbound
+	return nil
+}
+
+func (a *activityEnvironmentInterceptor) ExecuteActivity(ctx context.Context, activityType string, args ...interface{}) (interface{}, error) {
+	fnType := reflect.TypeOf(a.fn)
+	var reflectArgs []reflect.Value
+
+	// activities optionally might not take context.
+	argsOffset := 0
+	if fnType.NumIn() > 0 && isActivityContext(fnType.In(0)) {
+		reflectArgs = append(reflectArgs, reflect.ValueOf(ctx))
+		argsOffset = 1
+	}
+	for i, arg := range args {
+		if arg == nil {
+			reflectArgs = append(reflectArgs, reflect.New(fnType.In(i+argsOffset)).Elem())
+		} else {
+			reflectArgs = append(reflectArgs, reflect.ValueOf(arg))
+		}
+	}
+
+	retValues := reflect.ValueOf(a.fn).Call(reflectArgs)
+	var result interface{}
+	if len(retValues) > 1 {
+		result = retValues[0].Interface()
+	}
+	err, _ := retValues[len(retValues)-1].Interface().(error)
+	return result, err
+}
+
 func getActivityOptions(ctx Context) *ExecuteActivityOptions {
 	eap := ctx.Value(activityOptionsContextKey)
 	if eap == nil {
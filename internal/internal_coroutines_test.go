@@ -820,6 +820,140 @@ func TestAwaitWithTimeoutCancellation(t *testing.T) {
 	require.True(t, ok)
 }
 
+func TestMutex(t *testing.T) {
+	var history []string
+	d := createNewDispatcher(func(ctx Context) {
+		mutex := NewMutex(ctx)
+		c := NewChannel(ctx)
+		for i := 0; i < 3; i++ {
+			i := i
+			Go(ctx, func(ctx Context) {
+				require.NoError(t, mutex.Lock(ctx))
+				history = append(history, fmt.Sprintf("lock-%v", i))
+				c.Receive(ctx, nil)
+				history = append(history, fmt.Sprintf("unlock-%v", i))
+				mutex.Unlock()
+			})
+		}
+		for i := 0; i < 3; i++ {
+			c.Send(ctx, nil)
+		}
+		require.NoError(t, mutex.Lock(ctx))
+		require.True(t, mutex.IsLocked())
+		require.False(t, mutex.TryLock(ctx))
+		mutex.Unlock()
+		require.False(t, mutex.IsLocked())
+	})
+	defer d.Close()
+	requireNoExecuteErr(t, d.ExecuteUntilAllBlocked(defaultDeadlockDetectionTimeout))
+	require.True(t, d.IsDone(), d.StackTrace())
+	expected := []string{"lock-0", "unlock-0", "lock-1", "unlock-1", "lock-2", "unlock-2"}
+	require.EqualValues(t, expected, history)
+}
+
+func TestMutexStackTrace(t *testing.T) {
+	d := createNewDispatcher(func(ctx Context) {
+		mutex := NewMutex(ctx)
+		require.True(t, mutex.TryLock(ctx))
+		_ = mutex.Lock(ctx)
+	})
+	defer d.Close()
+	requireNoExecuteErr(t, d.ExecuteUntilAllBlocked(defaultDeadlockDetectionTimeout))
+	require.False(t, d.IsDone())
+	require.Contains(t, d.StackTrace(), "[blocked on mutex-1.Lock]")
+}
+
+func TestMutexLockCancellation(t *testing.T) {
+	var lockError error
+	var mutex Mutex
+	interceptor, ctx := createRootTestContext()
+	ctx, cancelHandler := WithCancel(ctx)
+	d, _ := newDispatcher(ctx, interceptor, func(ctx Context) {
+		mutex = NewMutex(ctx)
+		require.True(t, mutex.TryLock(ctx))
+		lockError = mutex.Lock(ctx)
+	})
+	defer d.Close()
+	requireNoExecuteErr(t, d.ExecuteUntilAllBlocked(defaultDeadlockDetectionTimeout))
+	require.False(t, d.IsDone())
+	cancelHandler()
+	requireNoExecuteErr(t, d.ExecuteUntilAllBlocked(defaultDeadlockDetectionTimeout))
+	require.True(t, d.IsDone())
+	_, ok := lockError.(*CanceledError)
+	require.True(t, ok)
+	require.True(t, mutex.IsLocked())
+	require.Empty(t, mutex.(*mutexImpl).semaphore.waiters)
+}
+
+func TestMutexUnlockOfUnlockedPanics(t *testing.T) {
+	d := createNewDispatcher(func(ctx Context) {
+		NewMutex(ctx).Unlock()
+	})
+	defer d.Close()
+	err := d.ExecuteUntilAllBlocked(defaultDeadlockDetectionTimeout)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unlock of unlocked mutex-1")
+}
+
+func TestSemaphore(t *testing.T) {
+	var history []string
+	d := createNewDispatcher(func(ctx Context) {
+		sem := NewSemaphore(ctx, 3)
+		c := NewChannel(ctx)
+		acquired := NewChannel(ctx)
+		Go(ctx, func(ctx Context) {
+			require.NoError(t, sem.Acquire(ctx, 2))
+			history = append(history, "acquire-2")
+			acquired.Send(ctx, nil)
+			c.Receive(ctx, nil)
+			sem.Release(2)
+		})
+		Go(ctx, func(ctx Context) {
+			// Must wait for the first coroutine as only one unit is left
+			require.NoError(t, sem.Acquire(ctx, 2))
+			history = append(history, "acquire-2-second")
+			sem.Release(2)
+		})
+		Go(ctx, func(ctx Context) {
+			// Must not overtake the second coroutine even though one unit is available
+			require.NoError(t, sem.Acquire(ctx, 1))
+			history = append(history, "acquire-1")
+			sem.Release(1)
+		})
+		acquired.Receive(ctx, nil)
+		// One unit is available, but the second coroutine is already waiting
+		require.False(t, sem.TryAcquire(ctx, 1))
+		history = append(history, "release")
+		c.Send(ctx, nil)
+	})
+	defer d.Close()
+	requireNoExecuteErr(t, d.ExecuteUntilAllBlocked(defaultDeadlockDetectionTimeout))
+	require.True(t, d.IsDone(), d.StackTrace())
+	expected := []string{"acquire-2", "release", "acquire-2-second", "acquire-1"}
+	require.EqualValues(t, expected, history)
+}
+
+func TestSemaphoreAcquireWithTimeoutCancellation(t *testing.T) {
+	var acquireError error
+	var acquireOk bool
+	interceptor, ctx := createRootTestContext()
+	ctx, cancelHandler := WithCancel(ctx)
+	d, _ := newDispatcher(ctx, interceptor, func(ctx Context) {
+		sem := NewSemaphore(ctx, 1)
+		require.True(t, sem.TryAcquire(ctx, 1))
+		acquireOk, acquireError = sem.AcquireWithTimeout(ctx, time.Hour, 1)
+	})
+	defer d.Close()
+	requireNoExecuteErr(t, d.ExecuteUntilAllBlocked(defaultDeadlockDetectionTimeout))
+	require.False(t, d.IsDone())
+	cancelHandler()
+	requireNoExecuteErr(t, d.ExecuteUntilAllBlocked(defaultDeadlockDetectionTimeout))
+	require.True(t, d.IsDone())
+	require.False(t, acquireOk)
+	_, ok := acquireError.(*CanceledError)
+	require.True(t, ok)
+}
+
 func TestFutureSetValue(t *testing.T) {
 	var history []string
 	var f Future
@@ -0,0 +1,1339 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import (
+	"container/heap"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pborman/uuid"
+	commonpb "go.temporal.io/api/common/v1"
+	enumspb "go.temporal.io/api/enums/v1"
+	filterpb "go.temporal.io/api/filter/v1"
+	historypb "go.temporal.io/api/history/v1"
+	namespacepb "go.temporal.io/api/namespace/v1"
+	querypb "go.temporal.io/api/query/v1"
+	"go.temporal.io/api/serviceerror"
+	taskqueuepb "go.temporal.io/api/taskqueue/v1"
+	workflowpb "go.temporal.io/api/workflow/v1"
+	"go.temporal.io/api/workflowservice/v1"
+	"google.golang.org/grpc"
+
+	"go.temporal.io/sdk/internal/common"
+	"go.temporal.io/sdk/internal/common/metrics"
+	ilog "go.temporal.io/sdk/internal/log"
+)
+
+// Assert that DevServer does indeed implement the interface
+var _ workflowservice.WorkflowServiceClient = (*DevServer)(nil)
+
+const (
+	// devServerLongPollTimeout is how long polls and history long polls wait before returning an empty response.
+	devServerLongPollTimeout = 20 * time.Second
+
+	// devServerDefaultWorkflowTaskTimeout is used when a workflow is started without workflow task timeout.
+	devServerDefaultWorkflowTaskTimeout = 10 * time.Second
+
+	// devServerDefaultRetryInitialInterval and devServerDefaultRetryBackoffCoefficient are used to retry activities
+	// which are scheduled without retry policy, the same way the server does.
+	devServerDefaultRetryInitialInterval      = time.Second
+	devServerDefaultRetryBackoffCoefficient   = 2.0
+	devServerDefaultRetryMaximumIntervalRatio = 100
+)
+
+var errDevServerLongPollTimeout = errors.New("long poll timeout")
+
+type (
+	// DevServerOptions are optional parameters of DevServer.
+	DevServerOptions struct {
+		// Optional: Initial time of the dev server clock.
+		// default: current time
+		StartTime time.Time
+
+		// Optional: By default the dev server clock advances with real time and skips to the next workflow timer or
+		// activity retry as soon as there is no workflow or activity task to process, so workflows which sleep for days
+		// complete instantly. Workflow and activity timeouts never cause time skipping by themselves. If ManualTime is
+		// set, the clock advances only when DevServer.AdvanceTime is called.
+		ManualTime bool
+
+		// Optional: Custom search attributes in addition to the ones registered by default (CustomStringField,
+		// CustomKeywordField, CustomIntField, CustomDoubleField, CustomBoolField and CustomDatetimeField).
+		SearchAttributes map[string]enumspb.IndexedValueType
+	}
+
+	// DevServer is an in-memory implementation of the Temporal service which is meant for tests. Clients created by
+	// DevServer.NewClient and workers created from them run end-to-end without network: workflow and activity tasks
+	// are dispatched to real pollers, signals, queries and cancellations are delivered through workflow tasks, and
+	// timers fire according to the dev server clock.
+	//
+	// Workflow retries and cron schedules, reset, archival and advanced visibility queries are not supported. List
+	// and count support queries combined of WorkflowId, RunId, WorkflowType and ExecutionStatus equality conditions
+	// joined with "and".
+	DevServer struct {
+		lock    sync.Mutex
+		changed chan struct{}
+		closed  bool
+
+		now         time.Time
+		clockSynced time.Time
+		alarm       *time.Timer
+		manualTime  bool
+		timers      devTimerQueue
+		timerSeq    int64
+		wakeups     int
+
+		namespaces       map[string]*devNamespace
+		searchAttributes map[string]enumspb.IndexedValueType
+		executions       map[string]*devExecution
+		executionOrder   []*devExecution
+		currentRuns      map[string]*devExecution
+		workflowTasks    map[string][]devWorkflowTaskEntry
+		activityTasks    map[string][]devActivityTaskEntry
+		pollers          map[string]map[string]time.Time
+		queries          map[string]*devQuery
+	}
+
+	devNamespace struct {
+		info   *namespacepb.NamespaceInfo
+		config *namespacepb.NamespaceConfig
+	}
+
+	// devTaskToken is encoded as a task token of workflow, activity and query tasks.
+	devTaskToken struct {
+		RunID            string `json:"runId"`
+		ScheduledEventID int64  `json:"scheduledEventId,omitempty"`
+		Attempt          int32  `json:"attempt,omitempty"`
+		QueryID          string `json:"queryId,omitempty"`
+	}
+
+	devWorkflowTaskEntry struct {
+		execution        *devExecution
+		scheduledEventID int64
+		sticky           bool
+		query            *devQuery
+	}
+
+	devActivityTaskEntry struct {
+		activity *devActivity
+		attempt  int32
+	}
+
+	devQuery struct {
+		id         string
+		execution  *devExecution
+		query      *querypb.WorkflowQuery
+		dispatched bool
+		done       bool
+		result     *commonpb.Payloads
+		err        error
+	}
+
+	devTimer struct {
+		server   *DevServer
+		fireTime time.Time
+		seq      int64
+		fn       func()
+		wakeup   bool
+		done     bool
+	}
+
+	devTimerQueue []*devTimer
+)
+
+// NewDevServer creates an in-memory Temporal service with "default" namespace registered.
+func NewDevServer(options DevServerOptions) *DevServer {
+	now := options.StartTime
+	if now.IsZero() {
+		now = time.Now()
+	}
+	s := &DevServer{
+		changed:       make(chan struct{}),
+		now:           now,
+		clockSynced:   time.Now(),
+		manualTime:    options.ManualTime,
+		namespaces:    make(map[string]*devNamespace),
+		executions:    make(map[string]*devExecution),
+		currentRuns:   make(map[string]*devExecution),
+		workflowTasks: make(map[string][]devWorkflowTaskEntry),
+		activityTasks: make(map[string][]devActivityTaskEntry),
+		pollers:       make(map[string]map[string]time.Time),
+		queries:       make(map[string]*devQuery),
+		searchAttributes: map[string]enumspb.IndexedValueType{
+			"WorkflowId":          enumspb.INDEXED_VALUE_TYPE_KEYWORD,
+			"RunId":               enumspb.INDEXED_VALUE_TYPE_KEYWORD,
+			"WorkflowType":        enumspb.INDEXED_VALUE_TYPE_KEYWORD,
+			"ExecutionStatus":     enumspb.INDEXED_VALUE_TYPE_KEYWORD,
+			"TaskQueue":           enumspb.INDEXED_VALUE_TYPE_KEYWORD,
+			"StartTime":           enumspb.INDEXED_VALUE_TYPE_DATETIME,
+			"CloseTime":           enumspb.INDEXED_VALUE_TYPE_DATETIME,
+			"ExecutionTime":       enumspb.INDEXED_VALUE_TYPE_DATETIME,
+			"CustomStringField":   enumspb.INDEXED_VALUE_TYPE_STRING,
+			"CustomKeywordField":  enumspb.INDEXED_VALUE_TYPE_KEYWORD,
+			"CustomIntField":      enumspb.INDEXED_VALUE_TYPE_INT,
+			"CustomDoubleField":   enumspb.INDEXED_VALUE_TYPE_DOUBLE,
+			"CustomBoolField":     enumspb.INDEXED_VALUE_TYPE_BOOL,
+			"CustomDatetimeField": enumspb.INDEXED_VALUE_TYPE_DATETIME,
+		},
+	}
+	for name, valueType := range options.SearchAttributes {
+		s.searchAttributes[name] = valueType
+	}
+	s.registerNamespace(&workflowservice.RegisterNamespaceRequest{
+		Namespace:                        DefaultNamespace,
+		WorkflowExecutionRetentionPeriod: common.DurationPtr(24 * time.Hour),
+	})
+	return s
+}
+
+// NewClient creates a client which talks to the dev server. Workers created from the client poll the dev server.
+func (s *DevServer) NewClient(options ClientOptions) Client {
+	if options.Namespace == "" {
+		options.Namespace = DefaultNamespace
+	}
+	options.MetricsHandler = metrics.GetRootHandler(options.metricsHandler(), options.Namespace)
+	if options.Logger == nil {
+		options.Logger = ilog.NewDefaultLogger()
+	}
+	return NewServiceClient(s, nil, options)
+}
+
+// Now returns the current time of the dev server clock.
+func (s *DevServer) Now() time.Time {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.clock()
+}
+
+// AdvanceTime moves the dev server clock forward and fires all the timers and timeouts which are due.
+func (s *DevServer) AdvanceTime(d time.Duration) {
+	s.lock.Lock()
+	defer s.unlockAndNotify()
+	target := s.clock().Add(d)
+	for len(s.timers) > 0 && !s.timers[0].fireTime.After(target) {
+		s.fireNextTimer()
+	}
+	s.now = target
+}
+
+// Close fails all the pending and subsequent long polls, so workers which use the dev server can stop.
+func (s *DevServer) Close() {
+	s.lock.Lock()
+	defer s.unlockAndNotify()
+	s.closed = true
+	if s.alarm != nil {
+		s.alarm.Stop()
+	}
+}
+
+// unlockAndNotify must be called instead of Unlock after the state is changed. It skips time if there is no work to do
+// and wakes up all long polls.
+func (s *DevServer) unlockAndNotify() {
+	if !s.manualTime {
+		for len(s.timers) > 0 && !s.timers[0].fireTime.After(s.clock()) {
+			s.fireNextTimer()
+		}
+		for s.wakeups > 0 && s.isIdle() {
+			s.fireNextTimer()
+		}
+		s.setAlarm()
+	}
+	close(s.changed)
+	s.changed = make(chan struct{})
+	s.lock.Unlock()
+}
+
+// clock returns the current time of the dev server clock, which advances with real time unless ManualTime is set.
+// Must be called under lock.
+func (s *DevServer) clock() time.Time {
+	if !s.manualTime {
+		realNow := time.Now()
+		s.now = s.now.Add(realNow.Sub(s.clockSynced))
+		s.clockSynced = realNow
+	}
+	return s.now
+}
+
+// setAlarm arranges the next timer to fire in real time if nothing else happens on the dev server before it is due.
+// Must be called under lock.
+func (s *DevServer) setAlarm() {
+	if s.alarm != nil {
+		s.alarm.Stop()
+		s.alarm = nil
+	}
+	if s.closed || len(s.timers) == 0 {
+		return
+	}
+	s.alarm = time.AfterFunc(s.timers[0].fireTime.Sub(s.now), func() {
+		s.lock.Lock()
+		s.unlockAndNotify()
+	})
+}
+
+// wait releases the lock until the state is changed. Must be called under lock.
+func (s *DevServer) wait(ctx context.Context, timeout <-chan time.Time) error {
+	if s.closed {
+		return serviceerror.NewUnavailable("dev server is closed")
+	}
+	changed := s.changed
+	s.lock.Unlock()
+	defer s.lock.Lock()
+	select {
+	case <-changed:
+		return nil
+	case <-timeout:
+		return errDevServerLongPollTimeout
+	case <-ctx.Done():
+		if ctx.Err() == context.DeadlineExceeded {
+			return serviceerror.NewDeadlineExceeded(ctx.Err().Error())
+		}
+		return serviceerror.NewCanceled(ctx.Err().Error())
+	}
+}
+
+// isIdle returns true if there are no tasks to be processed by workers.
+func (s *DevServer) isIdle() bool {
+	for _, e := range s.executions {
+		if e.isRunning() && (e.wtScheduledID != 0 || len(e.pendingActivities()) > 0) {
+			return false
+		}
+	}
+	for _, q := range s.queries {
+		if !q.done {
+			return false
+		}
+	}
+	return true
+}
+
+// startTimer schedules fn to be called when the dev server clock reaches now + d. The clock is skipped forward to
+// the timer when the server is idle. Must be called under lock.
+func (s *DevServer) startTimer(d time.Duration, fn func()) *devTimer {
+	s.wakeups++
+	return s.addTimer(d, fn, true)
+}
+
+// startDeadline schedules fn to be called when the dev server clock reaches now + d. Unlike startTimer, the clock is
+// not skipped forward to the deadline. Must be called under lock.
+func (s *DevServer) startDeadline(d time.Duration, fn func()) *devTimer {
+	return s.addTimer(d, fn, false)
+}
+
+func (s *DevServer) addTimer(d time.Duration, fn func(), wakeup bool) *devTimer {
+	s.timerSeq++
+	t := &devTimer{server: s, fireTime: s.clock().Add(d), seq: s.timerSeq, fn: fn, wakeup: wakeup}
+	heap.Push(&s.timers, t)
+	return t
+}
+
+func (s *DevServer) fireNextTimer() {
+	t := heap.Pop(&s.timers).(*devTimer)
+	if t.done {
+		return
+	}
+	t.finish()
+	if t.fireTime.After(s.now) {
+		s.now = t.fireTime
+	}
+	t.fn()
+}
+
+func (t *devTimer) finish() {
+	if !t.done && t.wakeup {
+		t.server.wakeups--
+	}
+	t.done = true
+}
+
+func (t *devTimer) cancel() {
+	if t != nil {
+		t.finish()
+	}
+}
+
+func (q devTimerQueue) Len() int { return len(q) }
+
+func (q devTimerQueue) Less(i, j int) bool {
+	if q[i].fireTime.Equal(q[j].fireTime) {
+		return q[i].seq < q[j].seq
+	}
+	return q[i].fireTime.Before(q[j].fireTime)
+}
+
+func (q devTimerQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *devTimerQueue) Push(x interface{}) { *q = append(*q, x.(*devTimer)) }
+
+func (q *devTimerQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	t := old[n-1]
+	*q = old[:n-1]
+	return t
+}
+
+func devTaskQueueKey(namespace, taskQueue string) string {
+	return namespace + "/" + taskQueue
+}
+
+func devWorkflowKey(namespace, workflowID string) string {
+	return namespace + "/" + workflowID
+}
+
+func encodeDevTaskToken(token devTaskToken) []byte {
+	data, _ := json.Marshal(token)
+	return data
+}
+
+func decodeDevTaskToken(data []byte) (devTaskToken, error) {
+	var token devTaskToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		return token, serviceerror.NewInvalidArgument("invalid task token")
+	}
+	return token, nil
+}
+
+func (s *DevServer) recordPoller(kind, namespace, taskQueue, identity string) {
+	key := kind + "/" + devTaskQueueKey(namespace, taskQueue)
+	if s.pollers[key] == nil {
+		s.pollers[key] = make(map[string]time.Time)
+	}
+	s.pollers[key][identity] = time.Now()
+}
+
+// getExecution returns the run with runID or the current run of the workflow if runID is empty. Must be called under lock.
+func (s *DevServer) getExecution(namespace string, execution *commonpb.WorkflowExecution) (*devExecution, error) {
+	if execution.GetRunId() != "" {
+		e, ok := s.executions[execution.GetRunId()]
+		if !ok || e.namespace != namespace || e.execution.WorkflowId != execution.GetWorkflowId() {
+			return nil, serviceerror.NewNotFound(fmt.Sprintf("workflow execution %v/%v not found", execution.GetWorkflowId(), execution.GetRunId()))
+		}
+		return e, nil
+	}
+	e, ok := s.currentRuns[devWorkflowKey(namespace, execution.GetWorkflowId())]
+	if !ok {
+		return nil, serviceerror.NewNotFound(fmt.Sprintf("workflow execution %v not found", execution.GetWorkflowId()))
+	}
+	return e, nil
+}
+
+func (s *DevServer) getRunningExecution(namespace string, execution *commonpb.WorkflowExecution) (*devExecution, error) {
+	e, err := s.getExecution(namespace, execution)
+	if err != nil {
+		return nil, err
+	}
+	if !e.isRunning() {
+		return nil, serviceerror.NewNotFound("workflow execution already completed")
+	}
+	return e, nil
+}
+
+// RegisterNamespace registers a new namespace.
+func (s *DevServer) RegisterNamespace(_ context.Context, request *workflowservice.RegisterNamespaceRequest, _ ...grpc.CallOption) (*workflowservice.RegisterNamespaceResponse, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, ok := s.namespaces[request.GetNamespace()]; ok {
+		return nil, serviceerror.NewNamespaceAlreadyExists(fmt.Sprintf("namespace %v already exists", request.GetNamespace()))
+	}
+	s.registerNamespace(request)
+	return &workflowservice.RegisterNamespaceResponse{}, nil
+}
+
+func (s *DevServer) registerNamespace(request *workflowservice.RegisterNamespaceRequest) {
+	s.namespaces[request.GetNamespace()] = &devNamespace{
+		info: &namespacepb.NamespaceInfo{
+			Name:        request.GetNamespace(),
+			State:       enumspb.NAMESPACE_STATE_REGISTERED,
+			Description: request.GetDescription(),
+			OwnerEmail:  request.GetOwnerEmail(),
+			Data:        request.GetData(),
+			Id:          uuid.New(),
+		},
+		config: &namespacepb.NamespaceConfig{
+			WorkflowExecutionRetentionTtl: request.GetWorkflowExecutionRetentionPeriod(),
+		},
+	}
+}
+
+// DescribeNamespace returns information about the namespace.
+func (s *DevServer) DescribeNamespace(_ context.Context, request *workflowservice.DescribeNamespaceRequest, _ ...grpc.CallOption) (*workflowservice.DescribeNamespaceResponse, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for _, ns := range s.namespaces {
+		if ns.info.Name == request.GetNamespace() || (request.GetId() != "" && ns.info.Id == request.GetId()) {
+			return &workflowservice.DescribeNamespaceResponse{NamespaceInfo: ns.info, Config: ns.config}, nil
+		}
+	}
+	return nil, serviceerror.NewNotFound(fmt.Sprintf("namespace %v not found", request.GetNamespace()))
+}
+
+// ListNamespaces returns all the registered namespaces.
+func (s *DevServer) ListNamespaces(_ context.Context, _ *workflowservice.ListNamespacesRequest, _ ...grpc.CallOption) (*workflowservice.ListNamespacesResponse, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	response := &workflowservice.ListNamespacesResponse{}
+	for _, ns := range s.namespaces {
+		response.Namespaces = append(response.Namespaces, &workflowservice.DescribeNamespaceResponse{NamespaceInfo: ns.info, Config: ns.config})
+	}
+	sort.Slice(response.Namespaces, func(i, j int) bool {
+		return response.Namespaces[i].NamespaceInfo.Name < response.Namespaces[j].NamespaceInfo.Name
+	})
+	return response, nil
+}
+
+// UpdateNamespace updates information and configuration of the namespace.
+func (s *DevServer) UpdateNamespace(_ context.Context, request *workflowservice.UpdateNamespaceRequest, _ ...grpc.CallOption) (*workflowservice.UpdateNamespaceResponse, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	ns, ok := s.namespaces[request.GetNamespace()]
+	if !ok {
+		return nil, serviceerror.NewNotFound(fmt.Sprintf("namespace %v not found", request.GetNamespace()))
+	}
+	if info := request.GetUpdateInfo(); info != nil {
+		ns.info.Description = info.GetDescription()
+		ns.info.OwnerEmail = info.GetOwnerEmail()
+		ns.info.Data = info.GetData()
+	}
+	if request.GetConfig() != nil {
+		ns.config = request.GetConfig()
+	}
+	return &workflowservice.UpdateNamespaceResponse{NamespaceInfo: ns.info, Config: ns.config}, nil
+}
+
+// DeprecateNamespace marks the namespace as deprecated.
+func (s *DevServer) DeprecateNamespace(_ context.Context, request *workflowservice.DeprecateNamespaceRequest, _ ...grpc.CallOption) (*workflowservice.DeprecateNamespaceResponse, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	ns, ok := s.namespaces[request.GetNamespace()]
+	if !ok {
+		return nil, serviceerror.NewNotFound(fmt.Sprintf("namespace %v not found", request.GetNamespace()))
+	}
+	ns.info.State = enumspb.NAMESPACE_STATE_DEPRECATED
+	return &workflowservice.DeprecateNamespaceResponse{}, nil
+}
+
+// StartWorkflowExecution starts a new workflow execution.
+func (s *DevServer) StartWorkflowExecution(_ context.Context, request *workflowservice.StartWorkflowExecutionRequest, _ ...grpc.CallOption) (*workflowservice.StartWorkflowExecutionResponse, error) {
+	s.lock.Lock()
+	defer s.unlockAndNotify()
+	e, err := s.startWorkflow(request, nil)
+	if err != nil {
+		return nil, err
+	}
+	e.scheduleWorkflowTask()
+	return &workflowservice.StartWorkflowExecutionResponse{RunId: e.execution.RunId}, nil
+}
+
+// startWorkflow creates a new run of the workflow without scheduling a workflow task. Must be called under lock.
+func (s *DevServer) startWorkflow(request *workflowservice.StartWorkflowExecutionRequest, parent *devChild) (*devExecution, error) {
+	if request.GetWorkflowId() == "" || request.GetWorkflowType().GetName() == "" || request.GetTaskQueue().GetName() == "" {
+		return nil, serviceerror.NewInvalidArgument("workflow id, workflow type and task queue are required")
+	}
+	key := devWorkflowKey(request.GetNamespace(), request.GetWorkflowId())
+	if current, ok := s.currentRuns[key]; ok {
+		if current.isRunning() {
+			if request.GetRequestId() != "" && current.startRequestID == request.GetRequestId() {
+				return current, nil
+			}
+			return nil, serviceerror.NewWorkflowExecutionAlreadyStarted(fmt.Sprintf("Workflow execution is already running. WorkflowId: %v, RunId: %v.", current.execution.WorkflowId, current.execution.RunId), current.startRequestID, current.execution.RunId)
+		}
+		switch request.GetWorkflowIdReusePolicy() {
+		case enumspb.WORKFLOW_ID_REUSE_POLICY_REJECT_DUPLICATE:
+			return nil, serviceerror.NewWorkflowExecutionAlreadyStarted("Workflow execution already finished. Workflow Id reuse policy: reject duplicate.", current.startRequestID, current.execution.RunId)
+		case enumspb.WORKFLOW_ID_REUSE_POLICY_ALLOW_DUPLICATE_FAILED_ONLY:
+			if current.status == enumspb.WORKFLOW_EXECUTION_STATUS_COMPLETED {
+				return nil, serviceerror.NewWorkflowExecutionAlreadyStarted("Workflow execution already finished successfully. Workflow Id reuse policy: allow duplicate workflow Id if last run failed.", current.startRequestID, current.execution.RunId)
+			}
+		}
+	}
+
+	runID := uuid.New()
+	attributes := &historypb.WorkflowExecutionStartedEventAttributes{
+		WorkflowType:             request.GetWorkflowType(),
+		TaskQueue:                &taskqueuepb.TaskQueue{Name: request.GetTaskQueue().GetName(), Kind: enumspb.TASK_QUEUE_KIND_NORMAL},
+		Input:                    request.GetInput(),
+		WorkflowExecutionTimeout: request.GetWorkflowExecutionTimeout(),
+		WorkflowRunTimeout:       request.GetWorkflowRunTimeout(),
+		WorkflowTaskTimeout:      request.GetWorkflowTaskTimeout(),
+		OriginalExecutionRunId:   runID,
+		Identity:                 request.GetIdentity(),
+		FirstExecutionRunId:      runID,
+		RetryPolicy:              request.GetRetryPolicy(),
+		Attempt:                  1,
+		CronSchedule:             request.GetCronSchedule(),
+		Memo:                     request.GetMemo(),
+		SearchAttributes:         request.GetSearchAttributes(),
+		Header:                   request.GetHeader(),
+	}
+	if common.DurationValue(attributes.WorkflowTaskTimeout) == 0 {
+		attributes.WorkflowTaskTimeout = common.DurationPtr(devServerDefaultWorkflowTaskTimeout)
+	}
+	if common.DurationValue(attributes.WorkflowRunTimeout) == 0 {
+		attributes.WorkflowRunTimeout = attributes.WorkflowExecutionTimeout
+	}
+	if timeout := common.DurationValue(attributes.WorkflowExecutionTimeout); timeout > 0 {
+		expirationTime := s.clock().Add(timeout)
+		attributes.WorkflowExecutionExpirationTime = &expirationTime
+	}
+	if parent != nil {
+		attributes.ParentWorkflowNamespace = parent.parent.namespace
+		attributes.ParentWorkflowExecution = parent.parent.execution
+		attributes.ParentInitiatedEventId = parent.initiatedEventID
+	}
+	return s.newExecution(request.GetNamespace(), request.GetWorkflowId(), runID, request.GetRequestId(), attributes, parent), nil
+}
+
+func (s *DevServer) newExecution(namespace, workflowID, runID, requestID string, attributes *historypb.WorkflowExecutionStartedEventAttributes, parent *devChild) *devExecution {
+	e := &devExecution{
+		server:           s,
+		namespace:        namespace,
+		execution:        &commonpb.WorkflowExecution{WorkflowId: workflowID, RunId: runID},
+		startRequestID:   requestID,
+		started:          attributes,
+		status:           enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING,
+		startTime:        s.clock(),
+		memo:             attributes.GetMemo(),
+		searchAttributes: attributes.GetSearchAttributes(),
+		parent:           parent,
+		activities:       make(map[int64]*devActivity),
+		timers:           make(map[string]*devWorkflowTimer),
+		children:         make(map[int64]*devChild),
+	}
+	s.executions[runID] = e
+	s.executionOrder = append(s.executionOrder, e)
+	s.currentRuns[devWorkflowKey(namespace, workflowID)] = e
+
+	e.addEvent(enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_STARTED, &historypb.HistoryEvent_WorkflowExecutionStartedEventAttributes{
+		WorkflowExecutionStartedEventAttributes: attributes,
+	})
+	if timeout := common.DurationValue(attributes.GetWorkflowRunTimeout()); timeout > 0 {
+		if expiration := common.TimeValue(attributes.GetWorkflowExecutionExpirationTime()); !expiration.IsZero() {
+			if remaining := expiration.Sub(s.clock()); remaining < timeout {
+				timeout = remaining
+			}
+		}
+		e.timeoutTimer = s.startDeadline(timeout, e.timeout)
+	}
+	return e
+}
+
+// GetWorkflowExecutionHistory returns history of the workflow execution. Long poll requests wait for new events.
+func (s *DevServer) GetWorkflowExecutionHistory(ctx context.Context, request *workflowservice.GetWorkflowExecutionHistoryRequest, _ ...grpc.CallOption) (*workflowservice.GetWorkflowExecutionHistoryResponse, error) {
+	timeout := time.NewTimer(devServerLongPollTimeout)
+	defer timeout.Stop()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	e, err := s.getExecution(request.GetNamespace(), request.GetExecution())
+	if err != nil {
+		return nil, err
+	}
+	var from int
+	if len(request.GetNextPageToken()) > 0 {
+		if from, err = strconv.Atoi(string(request.GetNextPageToken())); err != nil {
+			return nil, serviceerror.NewInvalidArgument("invalid next page token")
+		}
+	}
+
+	for {
+		closeEventOnly := request.GetHistoryEventFilterType() == enumspb.HISTORY_EVENT_FILTER_TYPE_CLOSE_EVENT
+		ready := !e.isRunning() || (!closeEventOnly && len(e.history) > from)
+		if ready || !request.GetWaitNewEvent() {
+			break
+		}
+		if err := s.wait(ctx, timeout.C); err != nil {
+			if err == errDevServerLongPollTimeout {
+				return &workflowservice.GetWorkflowExecutionHistoryResponse{
+					History:       &historypb.History{},
+					NextPageToken: []byte(strconv.Itoa(from)),
+				}, nil
+			}
+			return nil, err
+		}
+	}
+
+	response := &workflowservice.GetWorkflowExecutionHistoryResponse{History: &historypb.History{}}
+	if request.GetHistoryEventFilterType() == enumspb.HISTORY_EVENT_FILTER_TYPE_CLOSE_EVENT {
+		if !e.isRunning() {
+			response.History.Events = e.history[len(e.history)-1:]
+		}
+		return response, nil
+	}
+	to := len(e.history)
+	if pageSize := int(request.GetMaximumPageSize()); pageSize > 0 && from+pageSize < to {
+		to = from + pageSize
+	}
+	if from < to {
+		response.History.Events = append([]*historypb.HistoryEvent{}, e.history[from:to]...)
+	}
+	if to < len(e.history) || (request.GetWaitNewEvent() && e.isRunning()) {
+		response.NextPageToken = []byte(strconv.Itoa(to))
+	}
+	return response, nil
+}
+
+// PollWorkflowTaskQueue returns the next workflow or query task from the task queue.
+func (s *DevServer) PollWorkflowTaskQueue(ctx context.Context, request *workflowservice.PollWorkflowTaskQueueRequest, _ ...grpc.CallOption) (*workflowservice.PollWorkflowTaskQueueResponse, error) {
+	timeout := time.NewTimer(devServerLongPollTimeout)
+	defer timeout.Stop()
+	s.lock.Lock()
+	s.recordPoller("workflow", request.GetNamespace(), request.GetTaskQueue().GetName(), request.GetIdentity())
+	key := devTaskQueueKey(request.GetNamespace(), request.GetTaskQueue().GetName())
+	for {
+		if task := s.takeWorkflowTask(key, request.GetIdentity()); task != nil {
+			s.unlockAndNotify()
+			return task, nil
+		}
+		if err := s.wait(ctx, timeout.C); err != nil {
+			s.lock.Unlock()
+			if err == errDevServerLongPollTimeout {
+				return &workflowservice.PollWorkflowTaskQueueResponse{}, nil
+			}
+			return nil, err
+		}
+	}
+}
+
+// takeWorkflowTask removes the first task which can be dispatched from the task queue. Must be called under lock.
+func (s *DevServer) takeWorkflowTask(key string, identity string) *workflowservice.PollWorkflowTaskQueueResponse {
+	entries := s.workflowTasks[key]
+	for i, entry := range entries {
+		if entry.query != nil {
+			if entry.query.done {
+				s.workflowTasks[key] = append(entries[:i:i], entries[i+1:]...)
+				return s.takeWorkflowTask(key, identity)
+			}
+			// queries are dispatched when there is no workflow task in progress
+			if entry.execution.wtStartedID != 0 {
+				continue
+			}
+			s.workflowTasks[key] = append(entries[:i:i], entries[i+1:]...)
+			entry.query.dispatched = true
+			return entry.execution.queryTask(entry.query)
+		}
+		e := entry.execution
+		if !e.isRunning() || e.wtScheduledID != entry.scheduledEventID || e.wtStartedID != 0 || e.wtSticky != entry.sticky {
+			// stale entry
+			s.workflowTasks[key] = append(entries[:i:i], entries[i+1:]...)
+			return s.takeWorkflowTask(key, identity)
+		}
+		s.workflowTasks[key] = append(entries[:i:i], entries[i+1:]...)
+		return e.startWorkflowTask(identity)
+	}
+	return nil
+}
+
+// RespondWorkflowTaskCompleted applies the commands produced by the workflow task.
+func (s *DevServer) RespondWorkflowTaskCompleted(_ context.Context, request *workflowservice.RespondWorkflowTaskCompletedRequest, _ ...grpc.CallOption) (*workflowservice.RespondWorkflowTaskCompletedResponse, error) {
+	s.lock.Lock()
+	defer s.unlockAndNotify()
+	e, err := s.getWorkflowTaskExecution(request.GetTaskToken())
+	if err != nil {
+		return nil, err
+	}
+	e.completeWorkflowTask(request)
+	response := &workflowservice.RespondWorkflowTaskCompletedResponse{}
+	if request.GetReturnNewWorkflowTask() && e.isRunning() && e.wtScheduledID != 0 && e.wtStartedID == 0 {
+		response.WorkflowTask = e.startWorkflowTask(request.GetIdentity())
+	}
+	return response, nil
+}
+
+// RespondWorkflowTaskFailed records the failure of the workflow task and schedules a new one.
+func (s *DevServer) RespondWorkflowTaskFailed(_ context.Context, request *workflowservice.RespondWorkflowTaskFailedRequest, _ ...grpc.CallOption) (*workflowservice.RespondWorkflowTaskFailedResponse, error) {
+	s.lock.Lock()
+	defer s.unlockAndNotify()
+	e, err := s.getWorkflowTaskExecution(request.GetTaskToken())
+	if err != nil {
+		return nil, err
+	}
+	e.failWorkflowTask(request.GetCause(), request.GetFailure(), request.GetIdentity())
+	return &workflowservice.RespondWorkflowTaskFailedResponse{}, nil
+}
+
+func (s *DevServer) getWorkflowTaskExecution(taskToken []byte) (*devExecution, error) {
+	token, err := decodeDevTaskToken(taskToken)
+	if err != nil {
+		return nil, err
+	}
+	e, ok := s.executions[token.RunID]
+	if !ok || !e.isRunning() || e.wtStartedID == 0 || e.wtScheduledID != token.ScheduledEventID || e.wtAttempt != token.Attempt {
+		return nil, serviceerror.NewNotFound("workflow task not found")
+	}
+	return e, nil
+}
+
+// PollActivityTaskQueue returns the next activity task from the task queue.
+func (s *DevServer) PollActivityTaskQueue(ctx context.Context, request *workflowservice.PollActivityTaskQueueRequest, _ ...grpc.CallOption) (*workflowservice.PollActivityTaskQueueResponse, error) {
+	timeout := time.NewTimer(devServerLongPollTimeout)
+	defer timeout.Stop()
+	s.lock.Lock()
+	s.recordPoller("activity", request.GetNamespace(), request.GetTaskQueue().GetName(), request.GetIdentity())
+	key := devTaskQueueKey(request.GetNamespace(), request.GetTaskQueue().GetName())
+	for {
+		if task := s.takeActivityTask(key, request.GetIdentity()); task != nil {
+			s.unlockAndNotify()
+			return task, nil
+		}
+		if err := s.wait(ctx, timeout.C); err != nil {
+			s.lock.Unlock()
+			if err == errDevServerLongPollTimeout {
+				return &workflowservice.PollActivityTaskQueueResponse{}, nil
+			}
+			return nil, err
+		}
+	}
+}
+
+func (s *DevServer) takeActivityTask(key string, identity string) *workflowservice.PollActivityTaskQueueResponse {
+	entries := s.activityTasks[key]
+	for len(entries) > 0 {
+		entry := entries[0]
+		entries = entries[1:]
+		a := entry.activity
+		if a.execution.isRunning() && a.execution.activities[a.scheduledEventID] == a && !a.started && a.attempt == entry.attempt {
+			s.activityTasks[key] = entries
+			return a.start(identity)
+		}
+	}
+	s.activityTasks[key] = entries
+	return nil
+}
+
+// getActivity returns the started activity identified by the task token or by the workflow execution and activity id.
+// Must be called under lock.
+func (s *DevServer) getActivity(taskToken []byte, namespace, workflowID, runID, activityID string) (*devActivity, error) {
+	var e *devExecution
+	var a *devActivity
+	if len(taskToken) > 0 {
+		token, err := decodeDevTaskToken(taskToken)
+		if err != nil {
+			return nil, err
+		}
+		if e = s.executions[token.RunID]; e != nil {
+			if a = e.activities[token.ScheduledEventID]; a != nil && a.attempt != token.Attempt {
+				a = nil
+			}
+		}
+	} else {
+		var err error
+		if e, err = s.getExecution(namespace, &commonpb.WorkflowExecution{WorkflowId: workflowID, RunId: runID}); err != nil {
+			return nil, err
+		}
+		if scheduledEventID, ok := e.activityIDs[activityID]; ok {
+			a = e.activities[scheduledEventID]
+		}
+	}
+	if e == nil || !e.isRunning() || a == nil || !a.started {
+		return nil, serviceerror.NewNotFound("activity task not found")
+	}
+	return a, nil
+}
+
+// RecordActivityTaskHeartbeat records activity progress and returns true if cancellation of the activity is requested.
+func (s *DevServer) RecordActivityTaskHeartbeat(_ context.Context, request *workflowservice.RecordActivityTaskHeartbeatRequest, _ ...grpc.CallOption) (*workflowservice.RecordActivityTaskHeartbeatResponse, error) {
+	s.lock.Lock()
+	defer s.unlockAndNotify()
+	a, err := s.getActivity(request.GetTaskToken(), "", "", "", "")
+	if err != nil {
+		return nil, err
+	}
+	return &workflowservice.RecordActivityTaskHeartbeatResponse{CancelRequested: a.heartbeat(request.GetDetails())}, nil
+}
+
+// RecordActivityTaskHeartbeatById records activity progress and returns true if cancellation of the activity is requested.
+func (s *DevServer) RecordActivityTaskHeartbeatById(_ context.Context, request *workflowservice.RecordActivityTaskHeartbeatByIdRequest, _ ...grpc.CallOption) (*workflowservice.RecordActivityTaskHeartbeatByIdResponse, error) {
+	s.lock.Lock()
+	defer s.unlockAndNotify()
+	a, err := s.getActivity(nil, request.GetNamespace(), request.GetWorkflowId(), request.GetRunId(), request.GetActivityId())
+	if err != nil {
+		return nil, err
+	}
+	return &workflowservice.RecordActivityTaskHeartbeatByIdResponse{CancelRequested: a.heartbeat(request.GetDetails())}, nil
+}
+
+// RespondActivityTaskCompleted completes the activity.
+func (s *DevServer) RespondActivityTaskCompleted(_ context.Context, request *workflowservice.RespondActivityTaskCompletedRequest, _ ...grpc.CallOption) (*workflowservice.RespondActivityTaskCompletedResponse, error) {
+	s.lock.Lock()
+	defer s.unlockAndNotify()
+	a, err := s.getActivity(request.GetTaskToken(), "", "", "", "")
+	if err != nil {
+		return nil, err
+	}
+	a.complete(request.GetResult(), request.GetIdentity())
+	return &workflowservice.RespondActivityTaskCompletedResponse{}, nil
+}
+
+// RespondActivityTaskCompletedById completes the activity.
+func (s *DevServer) RespondActivityTaskCompletedById(_ context.Context, request *workflowservice.RespondActivityTaskCompletedByIdRequest, _ ...grpc.CallOption) (*workflowservice.RespondActivityTaskCompletedByIdResponse, error) {
+	s.lock.Lock()
+	defer s.unlockAndNotify()
+	a, err := s.getActivity(nil, request.GetNamespace(), request.GetWorkflowId(), request.GetRunId(), request.GetActivityId())
+	if err != nil {
+		return nil, err
+	}
+	a.complete(request.GetResult(), request.GetIdentity())
+	return &workflowservice.RespondActivityTaskCompletedByIdResponse{}, nil
+}
+
+// RespondActivityTaskFailed fails the activity. The activity is retried according to its retry policy.
+func (s *DevServer) RespondActivityTaskFailed(_ context.Context, request *workflowservice.RespondActivityTaskFailedRequest, _ ...grpc.CallOption) (*workflowservice.RespondActivityTaskFailedResponse, error) {
+	s.lock.Lock()
+	defer s.unlockAndNotify()
+	a, err := s.getActivity(request.GetTaskToken(), "", "", "", "")
+	if err != nil {
+		return nil, err
+	}
+	a.fail(request.GetFailure(), request.GetIdentity())
+	return &workflowservice.RespondActivityTaskFailedResponse{}, nil
+}
+
+// RespondActivityTaskFailedById fails the activity. The activity is retried according to its retry policy.
+func (s *DevServer) RespondActivityTaskFailedById(_ context.Context, request *workflowservice.RespondActivityTaskFailedByIdRequest, _ ...grpc.CallOption) (*workflowservice.RespondActivityTaskFailedByIdResponse, error) {
+	s.lock.Lock()
+	defer s.unlockAndNotify()
+	a, err := s.getActivity(nil, request.GetNamespace(), request.GetWorkflowId(), request.GetRunId(), request.GetActivityId())
+	if err != nil {
+		return nil, err
+	}
+	a.fail(request.GetFailure(), request.GetIdentity())
+	return &workflowservice.RespondActivityTaskFailedByIdResponse{}, nil
+}
+
+// RespondActivityTaskCanceled reports that the activity is canceled.
+func (s *DevServer) RespondActivityTaskCanceled(_ context.Context, request *workflowservice.RespondActivityTaskCanceledRequest, _ ...grpc.CallOption) (*workflowservice.RespondActivityTaskCanceledResponse, error) {
+	s.lock.Lock()
+	defer s.unlockAndNotify()
+	a, err := s.getActivity(request.GetTaskToken(), "", "", "", "")
+	if err != nil {
+		return nil, err
+	}
+	a.canceled(request.GetDetails(), request.GetIdentity())
+	return &workflowservice.RespondActivityTaskCanceledResponse{}, nil
+}
+
+// RespondActivityTaskCanceledById reports that the activity is canceled.
+func (s *DevServer) RespondActivityTaskCanceledById(_ context.Context, request *workflowservice.RespondActivityTaskCanceledByIdRequest, _ ...grpc.CallOption) (*workflowservice.RespondActivityTaskCanceledByIdResponse, error) {
+	s.lock.Lock()
+	defer s.unlockAndNotify()
+	a, err := s.getActivity(nil, request.GetNamespace(), request.GetWorkflowId(), request.GetRunId(), request.GetActivityId())
+	if err != nil {
+		return nil, err
+	}
+	a.canceled(request.GetDetails(), request.GetIdentity())
+	return &workflowservice.RespondActivityTaskCanceledByIdResponse{}, nil
+}
+
+// RequestCancelWorkflowExecution requests cancellation of the workflow execution.
+func (s *DevServer) RequestCancelWorkflowExecution(_ context.Context, request *workflowservice.RequestCancelWorkflowExecutionRequest, _ ...grpc.CallOption) (*workflowservice.RequestCancelWorkflowExecutionResponse, error) {
+	s.lock.Lock()
+	defer s.unlockAndNotify()
+	e, err := s.getRunningExecution(request.GetNamespace(), request.GetWorkflowExecution())
+	if err != nil {
+		return nil, err
+	}
+	e.requestCancel(&historypb.WorkflowExecutionCancelRequestedEventAttributes{Identity: request.GetIdentity()})
+	return &workflowservice.RequestCancelWorkflowExecutionResponse{}, nil
+}
+
+// SignalWorkflowExecution sends a signal to the running workflow execution.
+func (s *DevServer) SignalWorkflowExecution(_ context.Context, request *workflowservice.SignalWorkflowExecutionRequest, _ ...grpc.CallOption) (*workflowservice.SignalWorkflowExecutionResponse, error) {
+	s.lock.Lock()
+	defer s.unlockAndNotify()
+	e, err := s.getRunningExecution(request.GetNamespace(), request.GetWorkflowExecution())
+	if err != nil {
+		return nil, err
+	}
+	e.signal(request.GetSignalName(), request.GetInput(), request.GetIdentity())
+	return &workflowservice.SignalWorkflowExecutionResponse{}, nil
+}
+
+// SignalWithStartWorkflowExecution sends a signal to the running workflow execution or starts a new one with the signal.
+func (s *DevServer) SignalWithStartWorkflowExecution(_ context.Context, request *workflowservice.SignalWithStartWorkflowExecutionRequest, _ ...grpc.CallOption) (*workflowservice.SignalWithStartWorkflowExecutionResponse, error) {
+	s.lock.Lock()
+	defer s.unlockAndNotify()
+	if e, err := s.getRunningExecution(request.GetNamespace(), &commonpb.WorkflowExecution{WorkflowId: request.GetWorkflowId()}); err == nil {
+		e.signal(request.GetSignalName(), request.GetSignalInput(), request.GetIdentity())
+		return &workflowservice.SignalWithStartWorkflowExecutionResponse{RunId: e.execution.RunId}, nil
+	}
+	e, err := s.startWorkflow(&workflowservice.StartWorkflowExecutionRequest{
+		Namespace:                request.GetNamespace(),
+		WorkflowId:               request.GetWorkflowId(),
+		WorkflowType:             request.GetWorkflowType(),
+		TaskQueue:                request.GetTaskQueue(),
+		Input:                    request.GetInput(),
+		WorkflowExecutionTimeout: request.GetWorkflowExecutionTimeout(),
+		WorkflowRunTimeout:       request.GetWorkflowRunTimeout(),
+		WorkflowTaskTimeout:      request.GetWorkflowTaskTimeout(),
+		Identity:                 request.GetIdentity(),
+		RequestId:                request.GetRequestId(),
+		WorkflowIdReusePolicy:    request.GetWorkflowIdReusePolicy(),
+		RetryPolicy:              request.GetRetryPolicy(),
+		CronSchedule:             request.GetCronSchedule(),
+		Memo:                     request.GetMemo(),
+		SearchAttributes:         request.GetSearchAttributes(),
+		Header:                   request.GetHeader(),
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	e.signal(request.GetSignalName(), request.GetSignalInput(), request.GetIdentity())
+	return &workflowservice.SignalWithStartWorkflowExecutionResponse{RunId: e.execution.RunId}, nil
+}
+
+// ResetWorkflowExecution is not supported by the dev server, because it doesn't keep the state needed to replay a
+// workflow from an earlier workflow task. It always returns serviceerror.Unimplemented.
+func (s *DevServer) ResetWorkflowExecution(context.Context, *workflowservice.ResetWorkflowExecutionRequest, ...grpc.CallOption) (*workflowservice.ResetWorkflowExecutionResponse, error) {
+	return nil, serviceerror.NewUnimplemented("reset is not supported by the dev server")
+}
+
+// TerminateWorkflowExecution terminates the running workflow execution.
+func (s *DevServer) TerminateWorkflowExecution(_ context.Context, request *workflowservice.TerminateWorkflowExecutionRequest, _ ...grpc.CallOption) (*workflowservice.TerminateWorkflowExecutionResponse, error) {
+	s.lock.Lock()
+	defer s.unlockAndNotify()
+	e, err := s.getRunningExecution(request.GetNamespace(), request.GetWorkflowExecution())
+	if err != nil {
+		return nil, err
+	}
+	e.terminate(request.GetReason(), request.GetDetails(), request.GetIdentity())
+	return &workflowservice.TerminateWorkflowExecutionResponse{}, nil
+}
+
+// ListOpenWorkflowExecutions lists running workflow executions.
+func (s *DevServer) ListOpenWorkflowExecutions(_ context.Context, request *workflowservice.ListOpenWorkflowExecutionsRequest, _ ...grpc.CallOption) (*workflowservice.ListOpenWorkflowExecutionsResponse, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	filter := func(e *devExecution) bool {
+		return e.isRunning() && e.matchesStartTimeFilter(request.GetStartTimeFilter()) &&
+			e.matchesFilters(request.GetExecutionFilter(), request.GetTypeFilter(), nil)
+	}
+	executions, nextPageToken, err := s.listExecutions(request.GetNamespace(), filter, request.GetMaximumPageSize(), request.GetNextPageToken())
+	if err != nil {
+		return nil, err
+	}
+	return &workflowservice.ListOpenWorkflowExecutionsResponse{Executions: executions, NextPageToken: nextPageToken}, nil
+}
+
+// ListClosedWorkflowExecutions lists closed workflow executions.
+func (s *DevServer) ListClosedWorkflowExecutions(_ context.Context, request *workflowservice.ListClosedWorkflowExecutionsRequest, _ ...grpc.CallOption) (*workflowservice.ListClosedWorkflowExecutionsResponse, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	filter := func(e *devExecution) bool {
+		return !e.isRunning() && e.matchesStartTimeFilter(request.GetStartTimeFilter()) &&
+			e.matchesFilters(request.GetExecutionFilter(), request.GetTypeFilter(), request.GetStatusFilter())
+	}
+	executions, nextPageToken, err := s.listExecutions(request.GetNamespace(), filter, request.GetMaximumPageSize(), request.GetNextPageToken())
+	if err != nil {
+		return nil, err
+	}
+	return &workflowservice.ListClosedWorkflowExecutionsResponse{Executions: executions, NextPageToken: nextPageToken}, nil
+}
+
+// ListWorkflowExecutions lists workflow executions which match the query.
+func (s *DevServer) ListWorkflowExecutions(_ context.Context, request *workflowservice.ListWorkflowExecutionsRequest, _ ...grpc.CallOption) (*workflowservice.ListWorkflowExecutionsResponse, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	filter, err := newDevQueryFilter(request.GetQuery())
+	if err != nil {
+		return nil, err
+	}
+	executions, nextPageToken, err := s.listExecutions(request.GetNamespace(), filter, request.GetPageSize(), request.GetNextPageToken())
+	if err != nil {
+		return nil, err
+	}
+	return &workflowservice.ListWorkflowExecutionsResponse{Executions: executions, NextPageToken: nextPageToken}, nil
+}
+
+// ListArchivedWorkflowExecutions is not supported by the dev server.
+func (s *DevServer) ListArchivedWorkflowExecutions(context.Context, *workflowservice.ListArchivedWorkflowExecutionsRequest, ...grpc.CallOption) (*workflowservice.ListArchivedWorkflowExecutionsResponse, error) {
+	return nil, serviceerror.NewUnimplemented("archival is not supported by the dev server")
+}
+
+// ScanWorkflowExecutions lists workflow executions which match the query.
+func (s *DevServer) ScanWorkflowExecutions(_ context.Context, request *workflowservice.ScanWorkflowExecutionsRequest, _ ...grpc.CallOption) (*workflowservice.ScanWorkflowExecutionsResponse, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	filter, err := newDevQueryFilter(request.GetQuery())
+	if err != nil {
+		return nil, err
+	}
+	executions, nextPageToken, err := s.listExecutions(request.GetNamespace(), filter, request.GetPageSize(), request.GetNextPageToken())
+	if err != nil {
+		return nil, err
+	}
+	return &workflowservice.ScanWorkflowExecutionsResponse{Executions: executions, NextPageToken: nextPageToken}, nil
+}
+
+// CountWorkflowExecutions counts workflow executions which match the query.
+func (s *DevServer) CountWorkflowExecutions(_ context.Context, request *workflowservice.CountWorkflowExecutionsRequest, _ ...grpc.CallOption) (*workflowservice.CountWorkflowExecutionsResponse, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	filter, err := newDevQueryFilter(request.GetQuery())
+	if err != nil {
+		return nil, err
+	}
+	executions, _, err := s.listExecutions(request.GetNamespace(), filter, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &workflowservice.CountWorkflowExecutionsResponse{Count: int64(len(executions))}, nil
+}
+
+// listExecutions returns executions which match the filter, most recently started first. Must be called under lock.
+func (s *DevServer) listExecutions(namespace string, filter func(e *devExecution) bool, pageSize int32, nextPageToken []byte) ([]*workflowpb.WorkflowExecutionInfo, []byte, error) {
+	var from int
+	if len(nextPageToken) > 0 {
+		var err error
+		if from, err = strconv.Atoi(string(nextPageToken)); err != nil {
+			return nil, nil, serviceerror.NewInvalidArgument("invalid next page token")
+		}
+	}
+	var result []*workflowpb.WorkflowExecutionInfo
+	var index int
+	for i := len(s.executionOrder) - 1; i >= 0; i-- {
+		e := s.executionOrder[i]
+		if e.namespace != namespace || !filter(e) {
+			continue
+		}
+		if index >= from {
+			if pageSize > 0 && len(result) == int(pageSize) {
+				return result, []byte(strconv.Itoa(index)), nil
+			}
+			result = append(result, e.info())
+		}
+		index++
+	}
+	return result, nil, nil
+}
+
+// newDevQueryFilter supports queries which consist of equality conditions on WorkflowId, RunId, WorkflowType and
+// ExecutionStatus joined with "and", for example: WorkflowType = 'orderWorkflow' and ExecutionStatus = 'Running'.
+func newDevQueryFilter(query string) (func(e *devExecution) bool, error) {
+	type condition struct {
+		field string
+		value string
+	}
+	var conditions []condition
+	query = strings.TrimSpace(query)
+	if query != "" {
+		for _, clause := range strings.Split(query, " and ") {
+			parts := strings.SplitN(clause, "=", 2)
+			if len(parts) != 2 {
+				return nil, serviceerror.NewInvalidArgument(fmt.Sprintf("query condition %q is not supported by the dev server", clause))
+			}
+			field := strings.TrimSpace(parts[0])
+			value := strings.Trim(strings.TrimSpace(parts[1]), `'"`)
+			switch field {
+			case "WorkflowId", "RunId", "WorkflowType", "ExecutionStatus":
+			default:
+				return nil, serviceerror.NewInvalidArgument(fmt.Sprintf("query field %q is not supported by the dev server", field))
+			}
+			conditions = append(conditions, condition{field: field, value: value})
+		}
+	}
+	return func(e *devExecution) bool {
+		for _, c := range conditions {
+			var actual string
+			switch c.field {
+			case "WorkflowId":
+				actual = e.execution.WorkflowId
+			case "RunId":
+				actual = e.execution.RunId
+			case "WorkflowType":
+				actual = e.started.WorkflowType.GetName()
+			case "ExecutionStatus":
+				actual = e.status.String()
+			}
+			if actual != c.value {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+// GetSearchAttributes returns search attributes supported by the dev server.
+func (s *DevServer) GetSearchAttributes(context.Context, *workflowservice.GetSearchAttributesRequest, ...grpc.CallOption) (*workflowservice.GetSearchAttributesResponse, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	keys := make(map[string]enumspb.IndexedValueType, len(s.searchAttributes))
+	for name, valueType := range s.searchAttributes {
+		keys[name] = valueType
+	}
+	return &workflowservice.GetSearchAttributesResponse{Keys: keys}, nil
+}
+
+// RespondQueryTaskCompleted delivers the result of the query task to the waiting QueryWorkflow call.
+func (s *DevServer) RespondQueryTaskCompleted(_ context.Context, request *workflowservice.RespondQueryTaskCompletedRequest, _ ...grpc.CallOption) (*workflowservice.RespondQueryTaskCompletedResponse, error) {
+	s.lock.Lock()
+	defer s.unlockAndNotify()
+	token, err := decodeDevTaskToken(request.GetTaskToken())
+	if err != nil {
+		return nil, err
+	}
+	q, ok := s.queries[token.QueryID]
+	if !ok || q.done {
+		return nil, serviceerror.NewNotFound("query task not found")
+	}
+	q.done = true
+	if request.GetCompletedType() == enumspb.QUERY_RESULT_TYPE_ANSWERED {
+		q.result = request.GetQueryResult()
+	} else {
+		q.err = serviceerror.NewQueryFailed(request.GetErrorMessage())
+	}
+	return &workflowservice.RespondQueryTaskCompletedResponse{}, nil
+}
+
+// ResetStickyTaskQueue makes the next workflow task of the execution go to the normal task queue.
+func (s *DevServer) ResetStickyTaskQueue(_ context.Context, request *workflowservice.ResetStickyTaskQueueRequest, _ ...grpc.CallOption) (*workflowservice.ResetStickyTaskQueueResponse, error) {
+	s.lock.Lock()
+	defer s.unlockAndNotify()
+	e, err := s.getExecution(request.GetNamespace(), request.GetExecution())
+	if err != nil {
+		return nil, err
+	}
+	e.resetStickiness()
+	return &workflowservice.ResetStickyTaskQueueResponse{}, nil
+}
+
+// QueryWorkflow dispatches a query task to a worker and waits for the result.
+func (s *DevServer) QueryWorkflow(ctx context.Context, request *workflowservice.QueryWorkflowRequest, _ ...grpc.CallOption) (*workflowservice.QueryWorkflowResponse, error) {
+	s.lock.Lock()
+	e, err := s.getExecution(request.GetNamespace(), request.GetExecution())
+	if err != nil {
+		s.lock.Unlock()
+		return nil, err
+	}
+	switch request.GetQueryRejectCondition() {
+	case enumspb.QUERY_REJECT_CONDITION_NOT_OPEN:
+		if !e.isRunning() {
+			s.lock.Unlock()
+			return &workflowservice.QueryWorkflowResponse{QueryRejected: &querypb.QueryRejected{Status: e.status}}, nil
+		}
+	case enumspb.QUERY_REJECT_CONDITION_NOT_COMPLETED_CLEANLY:
+		if !e.isRunning() && e.status != enumspb.WORKFLOW_EXECUTION_STATUS_COMPLETED {
+			s.lock.Unlock()
+			return &workflowservice.QueryWorkflowResponse{QueryRejected: &querypb.QueryRejected{Status: e.status}}, nil
+		}
+	}
+
+	q := &devQuery{id: uuid.New(), execution: e, query: request.GetQuery()}
+	s.queries[q.id] = q
+	key := devTaskQueueKey(e.namespace, e.started.TaskQueue.GetName())
+	s.workflowTasks[key] = append(s.workflowTasks[key], devWorkflowTaskEntry{execution: e, query: q})
+	s.unlockAndNotify()
+
+	s.lock.Lock()
+	defer s.unlockAndNotify()
+	defer delete(s.queries, q.id)
+	for !q.done {
+		if err := s.wait(ctx, nil); err != nil {
+			q.done = true
+			return nil, err
+		}
+	}
+	if q.err != nil {
+		return nil, q.err
+	}
+	return &workflowservice.QueryWorkflowResponse{QueryResult: q.result}, nil
+}
+
+// DescribeWorkflowExecution returns information about the workflow execution including pending activities and children.
+func (s *DevServer) DescribeWorkflowExecution(_ context.Context, request *workflowservice.DescribeWorkflowExecutionRequest, _ ...grpc.CallOption) (*workflowservice.DescribeWorkflowExecutionResponse, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	e, err := s.getExecution(request.GetNamespace(), request.GetExecution())
+	if err != nil {
+		return nil, err
+	}
+	return e.describe(), nil
+}
+
+// DescribeTaskQueue returns pollers of the task queue.
+func (s *DevServer) DescribeTaskQueue(_ context.Context, request *workflowservice.DescribeTaskQueueRequest, _ ...grpc.CallOption) (*workflowservice.DescribeTaskQueueResponse, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	kind := "workflow"
+	if request.GetTaskQueueType() == enumspb.TASK_QUEUE_TYPE_ACTIVITY {
+		kind = "activity"
+	}
+	response := &workflowservice.DescribeTaskQueueResponse{}
+	for identity, lastAccessTime := range s.pollers[kind+"/"+devTaskQueueKey(request.GetNamespace(), request.GetTaskQueue().GetName())] {
+		lastAccessTime := lastAccessTime
+		response.Pollers = append(response.Pollers, &taskqueuepb.PollerInfo{Identity: identity, LastAccessTime: &lastAccessTime})
+	}
+	sort.Slice(response.Pollers, func(i, j int) bool {
+		return response.Pollers[i].Identity < response.Pollers[j].Identity
+	})
+	return response, nil
+}
+
+// GetClusterInfo returns information about the dev server.
+func (s *DevServer) GetClusterInfo(context.Context, *workflowservice.GetClusterInfoRequest, ...grpc.CallOption) (*workflowservice.GetClusterInfoResponse, error) {
+	return &workflowservice.GetClusterInfoResponse{ClusterName: "dev", ServerVersion: "dev", HistoryShardCount: 1}, nil
+}
+
+// ListTaskQueuePartitions returns the single partition of the task queue.
+func (s *DevServer) ListTaskQueuePartitions(_ context.Context, request *workflowservice.ListTaskQueuePartitionsRequest, _ ...grpc.CallOption) (*workflowservice.ListTaskQueuePartitionsResponse, error) {
+	partition := []*taskqueuepb.TaskQueuePartitionMetadata{{Key: request.GetTaskQueue().GetName(), OwnerHostName: "dev"}}
+	return &workflowservice.ListTaskQueuePartitionsResponse{
+		ActivityTaskQueuePartitions: partition,
+		WorkflowTaskQueuePartitions: partition,
+	}, nil
+}
+
+// matchesStartTimeFilter is used by ListOpenWorkflowExecutions and ListClosedWorkflowExecutions.
+func (e *devExecution) matchesStartTimeFilter(filter *filterpb.StartTimeFilter) bool {
+	if filter == nil {
+		return true
+	}
+	if earliest := common.TimeValue(filter.EarliestTime); !earliest.IsZero() && e.startTime.Before(earliest) {
+		return false
+	}
+	if latest := common.TimeValue(filter.LatestTime); !latest.IsZero() && e.startTime.After(latest) {
+		return false
+	}
+	return true
+}
+
+func (e *devExecution) matchesFilters(executionFilter *filterpb.WorkflowExecutionFilter, typeFilter *filterpb.WorkflowTypeFilter, statusFilter *filterpb.StatusFilter) bool {
+	if executionFilter != nil && (e.execution.WorkflowId != executionFilter.GetWorkflowId() ||
+		(executionFilter.GetRunId() != "" && e.execution.RunId != executionFilter.GetRunId())) {
+		return false
+	}
+	if typeFilter != nil && e.started.WorkflowType.GetName() != typeFilter.GetName() {
+		return false
+	}
+	if statusFilter != nil && e.status != statusFilter.GetStatus() {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,1434 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import (
+	"math"
+	"time"
+
+	"github.com/pborman/uuid"
+	commandpb "go.temporal.io/api/command/v1"
+	commonpb "go.temporal.io/api/common/v1"
+	enumspb "go.temporal.io/api/enums/v1"
+	failurepb "go.temporal.io/api/failure/v1"
+	historypb "go.temporal.io/api/history/v1"
+	taskqueuepb "go.temporal.io/api/taskqueue/v1"
+	workflowpb "go.temporal.io/api/workflow/v1"
+	"go.temporal.io/api/workflowservice/v1"
+
+	"go.temporal.io/sdk/internal/common"
+)
+
+type (
+	// devExecution is a single run of a workflow hosted by the dev server. All the methods must be called under the
+	// dev server lock.
+	devExecution struct {
+		server           *DevServer
+		namespace        string
+		execution        *commonpb.WorkflowExecution
+		startRequestID   string
+		started          *historypb.WorkflowExecutionStartedEventAttributes
+		status           enumspb.WorkflowExecutionStatus
+		startTime        time.Time
+		closeTime        time.Time
+		memo             *commonpb.Memo
+		searchAttributes *commonpb.SearchAttributes
+		parent           *devChild
+		history          []*historypb.HistoryEvent
+		timeoutTimer     *devTimer
+		cancelRequested  bool
+
+		wtScheduledID    int64
+		wtStartedID      int64
+		wtAttempt        int32
+		wtScheduledTime  time.Time
+		wtTimeout        *time.Timer
+		wtSticky         bool
+		lastWTStartedID  int64
+		stickyAttributes *taskqueuepb.StickyExecutionAttributes
+		bufferedEvents   []devBufferedEvent
+		activities       map[int64]*devActivity
+		activityIDs      map[string]int64
+		timers           map[string]*devWorkflowTimer
+		children         map[int64]*devChild
+	}
+
+	// devBufferedEvent is an event which arrived while a workflow task was in progress. Buffered events are added to
+	// history after the workflow task is completed.
+	devBufferedEvent struct {
+		apply func()
+		// timerID is set for TimerFired events, which are discarded if the timer is canceled by the workflow task.
+		timerID string
+		// activityScheduledID is set for the events which close the activity.
+		activityScheduledID int64
+	}
+
+	devWorkflowTimer struct {
+		startedEventID int64
+		timer          *devTimer
+	}
+
+	// devActivity is a scheduled activity and the state of its current attempt.
+	devActivity struct {
+		execution        *devExecution
+		scheduledEventID int64
+		attributes       *commandpb.ScheduleActivityTaskCommandAttributes
+		scheduledTime    time.Time
+		attemptTime      time.Time
+		attempt          int32
+		queued           bool
+		started          bool
+		startedTime      time.Time
+		identity         string
+		requestID        string
+		heartbeatDetails *commonpb.Payloads
+		lastHeartbeat    time.Time
+		lastFailure      *failurepb.Failure
+		cancelRequested  bool
+		cancelEventID    int64
+		attemptTimeout   *time.Timer
+		heartbeatTimeout *time.Timer
+		closeTimer       *devTimer
+	}
+
+	// devChild links a child workflow to its parent. It is shared by all the runs of the child.
+	devChild struct {
+		parent            *devExecution
+		initiatedEventID  int64
+		startedEventID    int64
+		namespace         string
+		execution         *commonpb.WorkflowExecution
+		workflowType      *commonpb.WorkflowType
+		parentClosePolicy enumspb.ParentClosePolicy
+	}
+)
+
+func (e *devExecution) isRunning() bool {
+	return e.status == enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING
+}
+
+// pendingActivities returns activities which are either waiting in the task queue or being executed by a worker.
+func (e *devExecution) pendingActivities() []*devActivity {
+	var result []*devActivity
+	for _, a := range e.activities {
+		if a.queued || a.started {
+			result = append(result, a)
+		}
+	}
+	return result
+}
+
+func (e *devExecution) addEvent(eventType enumspb.EventType, attributes interface{}) *historypb.HistoryEvent {
+	now := e.server.clock()
+	event := &historypb.HistoryEvent{
+		EventId:   int64(len(e.history) + 1),
+		EventTime: &now,
+		EventType: eventType,
+	}
+	switch a := attributes.(type) {
+	case *historypb.HistoryEvent_WorkflowExecutionStartedEventAttributes:
+		event.Attributes = a
+	case *historypb.HistoryEvent_WorkflowExecutionCompletedEventAttributes:
+		event.Attributes = a
+	case *historypb.HistoryEvent_WorkflowExecutionFailedEventAttributes:
+		event.Attributes = a
+	case *historypb.HistoryEvent_WorkflowExecutionTimedOutEventAttributes:
+		event.Attributes = a
+	case *historypb.HistoryEvent_WorkflowTaskScheduledEventAttributes:
+		event.Attributes = a
+	case *historypb.HistoryEvent_WorkflowTaskStartedEventAttributes:
+		event.Attributes = a
+	case *historypb.HistoryEvent_WorkflowTaskCompletedEventAttributes:
+		event.Attributes = a
+	case *historypb.HistoryEvent_WorkflowTaskTimedOutEventAttributes:
+		event.Attributes = a
+	case *historypb.HistoryEvent_WorkflowTaskFailedEventAttributes:
+		event.Attributes = a
+	case *historypb.HistoryEvent_ActivityTaskScheduledEventAttributes:
+		event.Attributes = a
+	case *historypb.HistoryEvent_ActivityTaskStartedEventAttributes:
+		event.Attributes = a
+	case *historypb.HistoryEvent_ActivityTaskCompletedEventAttributes:
+		event.Attributes = a
+	case *historypb.HistoryEvent_ActivityTaskFailedEventAttributes:
+		event.Attributes = a
+	case *historypb.HistoryEvent_ActivityTaskTimedOutEventAttributes:
+		event.Attributes = a
+	case *historypb.HistoryEvent_ActivityTaskCancelRequestedEventAttributes:
+		event.Attributes = a
+	case *historypb.HistoryEvent_ActivityTaskCanceledEventAttributes:
+		event.Attributes = a
+	case *historypb.HistoryEvent_TimerStartedEventAttributes:
+		event.Attributes = a
+	case *historypb.HistoryEvent_TimerFiredEventAttributes:
+		event.Attributes = a
+	case *historypb.HistoryEvent_TimerCanceledEventAttributes:
+		event.Attributes = a
+	case *historypb.HistoryEvent_MarkerRecordedEventAttributes:
+		event.Attributes = a
+	case *historypb.HistoryEvent_WorkflowExecutionSignaledEventAttributes:
+		event.Attributes = a
+	case *historypb.HistoryEvent_WorkflowExecutionTerminatedEventAttributes:
+		event.Attributes = a
+	case *historypb.HistoryEvent_WorkflowExecutionCancelRequestedEventAttributes:
+		event.Attributes = a
+	case *historypb.HistoryEvent_WorkflowExecutionCanceledEventAttributes:
+		event.Attributes = a
+	case *historypb.HistoryEvent_RequestCancelExternalWorkflowExecutionInitiatedEventAttributes:
+		event.Attributes = a
+	case *historypb.HistoryEvent_RequestCancelExternalWorkflowExecutionFailedEventAttributes:
+		event.Attributes = a
+	case *historypb.HistoryEvent_ExternalWorkflowExecutionCancelRequestedEventAttributes:
+		event.Attributes = a
+	case *historypb.HistoryEvent_WorkflowExecutionContinuedAsNewEventAttributes:
+		event.Attributes = a
+	case *historypb.HistoryEvent_StartChildWorkflowExecutionInitiatedEventAttributes:
+		event.Attributes = a
+	case *historypb.HistoryEvent_StartChildWorkflowExecutionFailedEventAttributes:
+		event.Attributes = a
+	case *historypb.HistoryEvent_ChildWorkflowExecutionStartedEventAttributes:
+		event.Attributes = a
+	case *historypb.HistoryEvent_ChildWorkflowExecutionCompletedEventAttributes:
+		event.Attributes = a
+	case *historypb.HistoryEvent_ChildWorkflowExecutionFailedEventAttributes:
+		event.Attributes = a
+	case *historypb.HistoryEvent_ChildWorkflowExecutionCanceledEventAttributes:
+		event.Attributes = a
+	case *historypb.HistoryEvent_ChildWorkflowExecutionTimedOutEventAttributes:
+		event.Attributes = a
+	case *historypb.HistoryEvent_ChildWorkflowExecutionTerminatedEventAttributes:
+		event.Attributes = a
+	case *historypb.HistoryEvent_SignalExternalWorkflowExecutionInitiatedEventAttributes:
+		event.Attributes = a
+	case *historypb.HistoryEvent_SignalExternalWorkflowExecutionFailedEventAttributes:
+		event.Attributes = a
+	case *historypb.HistoryEvent_ExternalWorkflowExecutionSignaledEventAttributes:
+		event.Attributes = a
+	case *historypb.HistoryEvent_UpsertWorkflowSearchAttributesEventAttributes:
+		event.Attributes = a
+	default:
+		panic("unknown history event attributes")
+	}
+	e.history = append(e.history, event)
+	return event
+}
+
+// deliver adds events produced outside of workflow task to history and schedules a workflow task to process them.
+// If a workflow task is in progress, the events are buffered until it is completed.
+func (e *devExecution) deliver(event devBufferedEvent) {
+	if !e.isRunning() {
+		return
+	}
+	if e.wtStartedID != 0 {
+		e.bufferedEvents = append(e.bufferedEvents, event)
+		return
+	}
+	event.apply()
+	e.scheduleWorkflowTask()
+}
+
+func (e *devExecution) flushBufferedEvents() {
+	buffered := e.bufferedEvents
+	e.bufferedEvents = nil
+	for _, event := range buffered {
+		e.deliver(event)
+	}
+}
+
+func (e *devExecution) scheduleWorkflowTask() {
+	if !e.isRunning() || e.wtScheduledID != 0 {
+		return
+	}
+	if e.wtAttempt == 0 {
+		e.wtAttempt = 1
+	}
+	event := e.addEvent(enumspb.EVENT_TYPE_WORKFLOW_TASK_SCHEDULED, &historypb.HistoryEvent_WorkflowTaskScheduledEventAttributes{
+		WorkflowTaskScheduledEventAttributes: &historypb.WorkflowTaskScheduledEventAttributes{
+			TaskQueue:           e.started.TaskQueue,
+			StartToCloseTimeout: e.started.WorkflowTaskTimeout,
+			Attempt:             e.wtAttempt,
+		},
+	})
+	e.wtScheduledID = event.EventId
+	e.wtScheduledTime = e.server.clock()
+	e.wtSticky = false
+	if e.stickyAttributes != nil {
+		e.wtSticky = true
+		// The task is moved to the normal task queue if the worker which has the workflow cached doesn't pick it up.
+		s, scheduledID := e.server, e.wtScheduledID
+		time.AfterFunc(common.DurationValue(e.stickyAttributes.GetScheduleToStartTimeout()), func() {
+			s.lock.Lock()
+			defer s.unlockAndNotify()
+			if e.isRunning() && e.wtScheduledID == scheduledID && e.wtStartedID == 0 && e.wtSticky {
+				e.resetStickiness()
+			}
+		})
+	}
+	e.enqueueWorkflowTask()
+}
+
+func (e *devExecution) enqueueWorkflowTask() {
+	taskQueue := e.started.TaskQueue.GetName()
+	if e.wtSticky {
+		taskQueue = e.stickyAttributes.GetWorkerTaskQueue().GetName()
+	}
+	key := devTaskQueueKey(e.namespace, taskQueue)
+	e.server.workflowTasks[key] = append(e.server.workflowTasks[key], devWorkflowTaskEntry{
+		execution:        e,
+		scheduledEventID: e.wtScheduledID,
+		sticky:           e.wtSticky,
+	})
+}
+
+// resetStickiness stops dispatching workflow tasks of the execution to the sticky task queue. The workflow task which
+// is already scheduled is moved to the normal task queue.
+func (e *devExecution) resetStickiness() {
+	e.stickyAttributes = nil
+	if e.wtSticky && e.wtScheduledID != 0 && e.wtStartedID == 0 {
+		e.wtSticky = false
+		e.enqueueWorkflowTask()
+	}
+}
+
+func (e *devExecution) startWorkflowTask(identity string) *workflowservice.PollWorkflowTaskQueueResponse {
+	event := e.addEvent(enumspb.EVENT_TYPE_WORKFLOW_TASK_STARTED, &historypb.HistoryEvent_WorkflowTaskStartedEventAttributes{
+		WorkflowTaskStartedEventAttributes: &historypb.WorkflowTaskStartedEventAttributes{
+			ScheduledEventId: e.wtScheduledID,
+			Identity:         identity,
+			RequestId:        uuid.New(),
+		},
+	})
+	e.wtStartedID = event.EventId
+
+	// Workflow task timeout is measured in real time, because it limits processing time of the worker.
+	s := e.server
+	scheduledID, attempt := e.wtScheduledID, e.wtAttempt
+	e.wtTimeout = time.AfterFunc(common.DurationValue(e.started.WorkflowTaskTimeout), func() {
+		s.lock.Lock()
+		defer s.unlockAndNotify()
+		if e.isRunning() && e.wtScheduledID == scheduledID && e.wtAttempt == attempt && e.wtStartedID != 0 {
+			e.timeoutWorkflowTask()
+		}
+	})
+
+	// Workers which have the workflow cached get only the new events.
+	history := e.history
+	if e.wtSticky {
+		history = e.history[e.lastWTStartedID:]
+	}
+	scheduledTime, startedTime := e.wtScheduledTime, s.clock()
+	return &workflowservice.PollWorkflowTaskQueueResponse{
+		TaskToken:                  encodeDevTaskToken(devTaskToken{RunID: e.execution.RunId, ScheduledEventID: scheduledID, Attempt: attempt}),
+		WorkflowExecution:          e.execution,
+		WorkflowType:               e.started.WorkflowType,
+		PreviousStartedEventId:     e.lastWTStartedID,
+		StartedEventId:             e.wtStartedID,
+		Attempt:                    attempt,
+		History:                    &historypb.History{Events: append([]*historypb.HistoryEvent{}, history...)},
+		WorkflowExecutionTaskQueue: e.started.TaskQueue,
+		ScheduledTime:              &scheduledTime,
+		StartedTime:                &startedTime,
+	}
+}
+
+func (e *devExecution) queryTask(q *devQuery) *workflowservice.PollWorkflowTaskQueueResponse {
+	return &workflowservice.PollWorkflowTaskQueueResponse{
+		TaskToken:                  encodeDevTaskToken(devTaskToken{RunID: e.execution.RunId, QueryID: q.id}),
+		WorkflowExecution:          e.execution,
+		WorkflowType:               e.started.WorkflowType,
+		PreviousStartedEventId:     e.lastWTStartedID,
+		History:                    &historypb.History{Events: append([]*historypb.HistoryEvent{}, e.history...)},
+		Query:                      q.query,
+		WorkflowExecutionTaskQueue: e.started.TaskQueue,
+	}
+}
+
+// endWorkflowTask resets the state of the workflow task which is in progress.
+func (e *devExecution) endWorkflowTask(completed bool) {
+	if e.wtTimeout != nil {
+		e.wtTimeout.Stop()
+		e.wtTimeout = nil
+	}
+	if completed {
+		e.lastWTStartedID = e.wtStartedID
+		e.wtAttempt = 1
+	} else {
+		e.wtAttempt++
+		e.stickyAttributes = nil
+	}
+	e.wtScheduledID = 0
+	e.wtStartedID = 0
+}
+
+func (e *devExecution) timeoutWorkflowTask() {
+	e.addEvent(enumspb.EVENT_TYPE_WORKFLOW_TASK_TIMED_OUT, &historypb.HistoryEvent_WorkflowTaskTimedOutEventAttributes{
+		WorkflowTaskTimedOutEventAttributes: &historypb.WorkflowTaskTimedOutEventAttributes{
+			ScheduledEventId: e.wtScheduledID,
+			StartedEventId:   e.wtStartedID,
+			TimeoutType:      enumspb.TIMEOUT_TYPE_START_TO_CLOSE,
+		},
+	})
+	e.endWorkflowTask(false)
+	e.flushBufferedEvents()
+	e.scheduleWorkflowTask()
+}
+
+func (e *devExecution) failWorkflowTask(cause enumspb.WorkflowTaskFailedCause, failure *failurepb.Failure, identity string) {
+	e.addEvent(enumspb.EVENT_TYPE_WORKFLOW_TASK_FAILED, &historypb.HistoryEvent_WorkflowTaskFailedEventAttributes{
+		WorkflowTaskFailedEventAttributes: &historypb.WorkflowTaskFailedEventAttributes{
+			ScheduledEventId: e.wtScheduledID,
+			StartedEventId:   e.wtStartedID,
+			Cause:            cause,
+			Failure:          failure,
+			Identity:         identity,
+		},
+	})
+	e.endWorkflowTask(false)
+	e.flushBufferedEvents()
+	e.scheduleWorkflowTask()
+}
+
+func isDevCloseCommand(commandType enumspb.CommandType) bool {
+	switch commandType {
+	case enumspb.COMMAND_TYPE_COMPLETE_WORKFLOW_EXECUTION,
+		enumspb.COMMAND_TYPE_FAIL_WORKFLOW_EXECUTION,
+		enumspb.COMMAND_TYPE_CANCEL_WORKFLOW_EXECUTION,
+		enumspb.COMMAND_TYPE_CONTINUE_AS_NEW_WORKFLOW_EXECUTION:
+		return true
+	}
+	return false
+}
+
+// completeWorkflowTask applies the commands of the completed workflow task.
+func (e *devExecution) completeWorkflowTask(request *workflowservice.RespondWorkflowTaskCompletedRequest) {
+	if len(e.bufferedEvents) > 0 {
+		for _, command := range request.GetCommands() {
+			if isDevCloseCommand(command.GetCommandType()) {
+				// The workflow must see the new events before it is closed.
+				e.failWorkflowTask(enumspb.WORKFLOW_TASK_FAILED_CAUSE_UNHANDLED_COMMAND, nil, request.GetIdentity())
+				return
+			}
+		}
+	}
+
+	completedEvent := e.addEvent(enumspb.EVENT_TYPE_WORKFLOW_TASK_COMPLETED, &historypb.HistoryEvent_WorkflowTaskCompletedEventAttributes{
+		WorkflowTaskCompletedEventAttributes: &historypb.WorkflowTaskCompletedEventAttributes{
+			ScheduledEventId: e.wtScheduledID,
+			StartedEventId:   e.wtStartedID,
+			Identity:         request.GetIdentity(),
+			BinaryChecksum:   request.GetBinaryChecksum(),
+		},
+	})
+	e.endWorkflowTask(true)
+	if request.GetStickyAttributes().GetWorkerTaskQueue().GetName() != "" {
+		e.stickyAttributes = request.GetStickyAttributes()
+	}
+
+	// Results of the commands which affect other executions are delivered as new events after the command events.
+	var followUps []func()
+	for _, command := range request.GetCommands() {
+		if !e.isRunning() {
+			break
+		}
+		if followUp := e.handleCommand(command, completedEvent.EventId); followUp != nil {
+			followUps = append(followUps, followUp)
+		}
+	}
+	e.flushBufferedEvents()
+	for _, followUp := range followUps {
+		followUp()
+	}
+	if request.GetForceCreateNewWorkflowTask() {
+		e.scheduleWorkflowTask()
+	}
+}
+
+func (e *devExecution) handleCommand(command *commandpb.Command, completedEventID int64) func() {
+	switch command.GetCommandType() {
+	case enumspb.COMMAND_TYPE_SCHEDULE_ACTIVITY_TASK:
+		e.scheduleActivity(command.GetScheduleActivityTaskCommandAttributes(), completedEventID)
+	case enumspb.COMMAND_TYPE_REQUEST_CANCEL_ACTIVITY_TASK:
+		return e.requestCancelActivity(command.GetRequestCancelActivityTaskCommandAttributes().GetScheduledEventId(), completedEventID)
+	case enumspb.COMMAND_TYPE_START_TIMER:
+		e.startTimer(command.GetStartTimerCommandAttributes(), completedEventID)
+	case enumspb.COMMAND_TYPE_CANCEL_TIMER:
+		e.cancelTimer(command.GetCancelTimerCommandAttributes().GetTimerId(), completedEventID)
+	case enumspb.COMMAND_TYPE_COMPLETE_WORKFLOW_EXECUTION:
+		result := command.GetCompleteWorkflowExecutionCommandAttributes().GetResult()
+		e.addEvent(enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_COMPLETED, &historypb.HistoryEvent_WorkflowExecutionCompletedEventAttributes{
+			WorkflowExecutionCompletedEventAttributes: &historypb.WorkflowExecutionCompletedEventAttributes{
+				Result:                       result,
+				WorkflowTaskCompletedEventId: completedEventID,
+			},
+		})
+		e.close(enumspb.WORKFLOW_EXECUTION_STATUS_COMPLETED)
+		e.notifyParent(func(c *devChild) {
+			c.parent.addEvent(enumspb.EVENT_TYPE_CHILD_WORKFLOW_EXECUTION_COMPLETED, &historypb.HistoryEvent_ChildWorkflowExecutionCompletedEventAttributes{
+				ChildWorkflowExecutionCompletedEventAttributes: &historypb.ChildWorkflowExecutionCompletedEventAttributes{
+					Result:            result,
+					Namespace:         c.namespace,
+					WorkflowExecution: c.execution,
+					WorkflowType:      c.workflowType,
+					InitiatedEventId:  c.initiatedEventID,
+					StartedEventId:    c.startedEventID,
+				},
+			})
+		})
+	case enumspb.COMMAND_TYPE_FAIL_WORKFLOW_EXECUTION:
+		failure := command.GetFailWorkflowExecutionCommandAttributes().GetFailure()
+		e.addEvent(enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_FAILED, &historypb.HistoryEvent_WorkflowExecutionFailedEventAttributes{
+			WorkflowExecutionFailedEventAttributes: &historypb.WorkflowExecutionFailedEventAttributes{
+				Failure:                      failure,
+				RetryState:                   enumspb.RETRY_STATE_RETRY_POLICY_NOT_SET,
+				WorkflowTaskCompletedEventId: completedEventID,
+			},
+		})
+		e.close(enumspb.WORKFLOW_EXECUTION_STATUS_FAILED)
+		e.notifyParent(func(c *devChild) {
+			c.parent.addEvent(enumspb.EVENT_TYPE_CHILD_WORKFLOW_EXECUTION_FAILED, &historypb.HistoryEvent_ChildWorkflowExecutionFailedEventAttributes{
+				ChildWorkflowExecutionFailedEventAttributes: &historypb.ChildWorkflowExecutionFailedEventAttributes{
+					Failure:           failure,
+					Namespace:         c.namespace,
+					WorkflowExecution: c.execution,
+					WorkflowType:      c.workflowType,
+					InitiatedEventId:  c.initiatedEventID,
+					StartedEventId:    c.startedEventID,
+					RetryState:        enumspb.RETRY_STATE_RETRY_POLICY_NOT_SET,
+				},
+			})
+		})
+	case enumspb.COMMAND_TYPE_CANCEL_WORKFLOW_EXECUTION:
+		details := command.GetCancelWorkflowExecutionCommandAttributes().GetDetails()
+		e.addEvent(enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_CANCELED, &historypb.HistoryEvent_WorkflowExecutionCanceledEventAttributes{
+			WorkflowExecutionCanceledEventAttributes: &historypb.WorkflowExecutionCanceledEventAttributes{
+				WorkflowTaskCompletedEventId: completedEventID,
+				Details:                      details,
+			},
+		})
+		e.close(enumspb.WORKFLOW_EXECUTION_STATUS_CANCELED)
+		e.notifyParent(func(c *devChild) {
+			c.parent.addEvent(enumspb.EVENT_TYPE_CHILD_WORKFLOW_EXECUTION_CANCELED, &historypb.HistoryEvent_ChildWorkflowExecutionCanceledEventAttributes{
+				ChildWorkflowExecutionCanceledEventAttributes: &historypb.ChildWorkflowExecutionCanceledEventAttributes{
+					Details:           details,
+					Namespace:         c.namespace,
+					WorkflowExecution: c.execution,
+					WorkflowType:      c.workflowType,
+					InitiatedEventId:  c.initiatedEventID,
+					StartedEventId:    c.startedEventID,
+				},
+			})
+		})
+	case enumspb.COMMAND_TYPE_CONTINUE_AS_NEW_WORKFLOW_EXECUTION:
+		e.continueAsNew(command.GetContinueAsNewWorkflowExecutionCommandAttributes(), completedEventID)
+	case enumspb.COMMAND_TYPE_RECORD_MARKER:
+		attributes := command.GetRecordMarkerCommandAttributes()
+		e.addEvent(enumspb.EVENT_TYPE_MARKER_RECORDED, &historypb.HistoryEvent_MarkerRecordedEventAttributes{
+			MarkerRecordedEventAttributes: &historypb.MarkerRecordedEventAttributes{
+				MarkerName:                   attributes.GetMarkerName(),
+				Details:                      attributes.GetDetails(),
+				WorkflowTaskCompletedEventId: completedEventID,
+				Header:                       attributes.GetHeader(),
+				Failure:                      attributes.GetFailure(),
+			},
+		})
+	case enumspb.COMMAND_TYPE_UPSERT_WORKFLOW_SEARCH_ATTRIBUTES:
+		searchAttributes := command.GetUpsertWorkflowSearchAttributesCommandAttributes().GetSearchAttributes()
+		e.addEvent(enumspb.EVENT_TYPE_UPSERT_WORKFLOW_SEARCH_ATTRIBUTES, &historypb.HistoryEvent_UpsertWorkflowSearchAttributesEventAttributes{
+			UpsertWorkflowSearchAttributesEventAttributes: &historypb.UpsertWorkflowSearchAttributesEventAttributes{
+				WorkflowTaskCompletedEventId: completedEventID,
+				SearchAttributes:             searchAttributes,
+			},
+		})
+		merged := &commonpb.SearchAttributes{IndexedFields: make(map[string]*commonpb.Payload)}
+		for name, value := range e.searchAttributes.GetIndexedFields() {
+			merged.IndexedFields[name] = value
+		}
+		for name, value := range searchAttributes.GetIndexedFields() {
+			merged.IndexedFields[name] = value
+		}
+		e.searchAttributes = merged
+	case enumspb.COMMAND_TYPE_START_CHILD_WORKFLOW_EXECUTION:
+		return e.startChild(command.GetStartChildWorkflowExecutionCommandAttributes(), completedEventID)
+	case enumspb.COMMAND_TYPE_SIGNAL_EXTERNAL_WORKFLOW_EXECUTION:
+		return e.signalExternal(command.GetSignalExternalWorkflowExecutionCommandAttributes(), completedEventID)
+	case enumspb.COMMAND_TYPE_REQUEST_CANCEL_EXTERNAL_WORKFLOW_EXECUTION:
+		return e.requestCancelExternal(command.GetRequestCancelExternalWorkflowExecutionCommandAttributes(), completedEventID)
+	}
+	return nil
+}
+
+// close moves the execution to the closed status and applies parent close policy to the running children.
+func (e *devExecution) close(status enumspb.WorkflowExecutionStatus) {
+	e.status = status
+	e.closeTime = e.server.clock()
+	if e.wtTimeout != nil {
+		e.wtTimeout.Stop()
+		e.wtTimeout = nil
+	}
+	e.wtScheduledID = 0
+	e.wtStartedID = 0
+	e.bufferedEvents = nil
+	e.timeoutTimer.cancel()
+	for _, t := range e.timers {
+		t.timer.cancel()
+	}
+	for _, a := range e.activities {
+		a.stopTimers()
+		a.closeTimer.cancel()
+	}
+	for _, c := range e.children {
+		child, ok := e.server.currentRuns[devWorkflowKey(c.namespace, c.execution.WorkflowId)]
+		if !ok || !child.isRunning() || child.parent != c {
+			continue
+		}
+		switch c.parentClosePolicy {
+		case enumspb.PARENT_CLOSE_POLICY_REQUEST_CANCEL:
+			child.requestCancel(&historypb.WorkflowExecutionCancelRequestedEventAttributes{Cause: "by parent close policy"})
+		case enumspb.PARENT_CLOSE_POLICY_ABANDON:
+		default:
+			child.terminate("by parent close policy", nil, "")
+		}
+	}
+}
+
+// notifyParent delivers the event which closes the child workflow to its parent.
+func (e *devExecution) notifyParent(addEvent func(c *devChild)) {
+	c := e.parent
+	if c == nil || !c.parent.isRunning() {
+		return
+	}
+	c.parent.deliver(devBufferedEvent{apply: func() {
+		delete(c.parent.children, c.initiatedEventID)
+		addEvent(c)
+	}})
+}
+
+func (e *devExecution) timeout() {
+	if !e.isRunning() {
+		return
+	}
+	e.wtStartedID = 0
+	e.flushBufferedEvents()
+	e.addEvent(enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_TIMED_OUT, &historypb.HistoryEvent_WorkflowExecutionTimedOutEventAttributes{
+		WorkflowExecutionTimedOutEventAttributes: &historypb.WorkflowExecutionTimedOutEventAttributes{
+			RetryState: enumspb.RETRY_STATE_TIMEOUT,
+		},
+	})
+	e.close(enumspb.WORKFLOW_EXECUTION_STATUS_TIMED_OUT)
+	e.notifyParent(func(c *devChild) {
+		c.parent.addEvent(enumspb.EVENT_TYPE_CHILD_WORKFLOW_EXECUTION_TIMED_OUT, &historypb.HistoryEvent_ChildWorkflowExecutionTimedOutEventAttributes{
+			ChildWorkflowExecutionTimedOutEventAttributes: &historypb.ChildWorkflowExecutionTimedOutEventAttributes{
+				Namespace:         c.namespace,
+				WorkflowExecution: c.execution,
+				WorkflowType:      c.workflowType,
+				InitiatedEventId:  c.initiatedEventID,
+				StartedEventId:    c.startedEventID,
+				RetryState:        enumspb.RETRY_STATE_TIMEOUT,
+			},
+		})
+	})
+}
+
+func (e *devExecution) terminate(reason string, details *commonpb.Payloads, identity string) {
+	// Terminate discards the workflow task in progress, but keeps the events which were delivered to the workflow.
+	e.wtStartedID = 0
+	e.flushBufferedEvents()
+	e.addEvent(enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_TERMINATED, &historypb.HistoryEvent_WorkflowExecutionTerminatedEventAttributes{
+		WorkflowExecutionTerminatedEventAttributes: &historypb.WorkflowExecutionTerminatedEventAttributes{
+			Reason:   reason,
+			Details:  details,
+			Identity: identity,
+		},
+	})
+	e.close(enumspb.WORKFLOW_EXECUTION_STATUS_TERMINATED)
+	e.notifyParent(func(c *devChild) {
+		c.parent.addEvent(enumspb.EVENT_TYPE_CHILD_WORKFLOW_EXECUTION_TERMINATED, &historypb.HistoryEvent_ChildWorkflowExecutionTerminatedEventAttributes{
+			ChildWorkflowExecutionTerminatedEventAttributes: &historypb.ChildWorkflowExecutionTerminatedEventAttributes{
+				Namespace:         c.namespace,
+				WorkflowExecution: c.execution,
+				WorkflowType:      c.workflowType,
+				InitiatedEventId:  c.initiatedEventID,
+				StartedEventId:    c.startedEventID,
+			},
+		})
+	})
+}
+
+func (e *devExecution) signal(name string, input *commonpb.Payloads, identity string) {
+	e.deliver(devBufferedEvent{apply: func() {
+		e.addEvent(enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_SIGNALED, &historypb.HistoryEvent_WorkflowExecutionSignaledEventAttributes{
+			WorkflowExecutionSignaledEventAttributes: &historypb.WorkflowExecutionSignaledEventAttributes{
+				SignalName: name,
+				Input:      input,
+				Identity:   identity,
+			},
+		})
+	}})
+}
+
+func (e *devExecution) requestCancel(attributes *historypb.WorkflowExecutionCancelRequestedEventAttributes) {
+	if e.cancelRequested {
+		return
+	}
+	e.cancelRequested = true
+	e.deliver(devBufferedEvent{apply: func() {
+		e.addEvent(enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_CANCEL_REQUESTED, &historypb.HistoryEvent_WorkflowExecutionCancelRequestedEventAttributes{
+			WorkflowExecutionCancelRequestedEventAttributes: attributes,
+		})
+	}})
+}
+
+func (e *devExecution) continueAsNew(attributes *commandpb.ContinueAsNewWorkflowExecutionCommandAttributes, completedEventID int64) {
+	runID := uuid.New()
+	taskQueue := e.started.TaskQueue
+	if attributes.GetTaskQueue().GetName() != "" {
+		taskQueue = &taskqueuepb.TaskQueue{Name: attributes.GetTaskQueue().GetName(), Kind: enumspb.TASK_QUEUE_KIND_NORMAL}
+	}
+	workflowTaskTimeout := attributes.GetWorkflowTaskTimeout()
+	if common.DurationValue(workflowTaskTimeout) == 0 {
+		workflowTaskTimeout = e.started.WorkflowTaskTimeout
+	}
+	workflowRunTimeout := attributes.GetWorkflowRunTimeout()
+	if common.DurationValue(workflowRunTimeout) == 0 {
+		workflowRunTimeout = e.started.WorkflowRunTimeout
+	}
+	e.addEvent(enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_CONTINUED_AS_NEW, &historypb.HistoryEvent_WorkflowExecutionContinuedAsNewEventAttributes{
+		WorkflowExecutionContinuedAsNewEventAttributes: &historypb.WorkflowExecutionContinuedAsNewEventAttributes{
+			NewExecutionRunId:            runID,
+			WorkflowType:                 attributes.GetWorkflowType(),
+			TaskQueue:                    taskQueue,
+			Input:                        attributes.GetInput(),
+			WorkflowRunTimeout:           workflowRunTimeout,
+			WorkflowTaskTimeout:          workflowTaskTimeout,
+			WorkflowTaskCompletedEventId: completedEventID,
+			BackoffStartInterval:         attributes.GetBackoffStartInterval(),
+			Initiator:                    attributes.GetInitiator(),
+			Failure:                      attributes.GetFailure(),
+			LastCompletionResult:         attributes.GetLastCompletionResult(),
+			Header:                       attributes.GetHeader(),
+			Memo:                         attributes.GetMemo(),
+			SearchAttributes:             attributes.GetSearchAttributes(),
+		},
+	})
+	parent := e.parent
+	// The children are not affected by the parent close policy when the parent continues as new.
+	e.children = nil
+	e.close(enumspb.WORKFLOW_EXECUTION_STATUS_CONTINUED_AS_NEW)
+
+	next := e.server.newExecution(e.namespace, e.execution.WorkflowId, runID, "", &historypb.WorkflowExecutionStartedEventAttributes{
+		WorkflowType:                    attributes.GetWorkflowType(),
+		ParentWorkflowNamespace:         e.started.ParentWorkflowNamespace,
+		ParentWorkflowExecution:         e.started.ParentWorkflowExecution,
+		ParentInitiatedEventId:          e.started.ParentInitiatedEventId,
+		TaskQueue:                       taskQueue,
+		Input:                           attributes.GetInput(),
+		WorkflowExecutionTimeout:        e.started.WorkflowExecutionTimeout,
+		WorkflowRunTimeout:              workflowRunTimeout,
+		WorkflowTaskTimeout:             workflowTaskTimeout,
+		ContinuedExecutionRunId:         e.execution.RunId,
+		Initiator:                       attributes.GetInitiator(),
+		ContinuedFailure:                attributes.GetFailure(),
+		LastCompletionResult:            attributes.GetLastCompletionResult(),
+		OriginalExecutionRunId:          runID,
+		FirstExecutionRunId:             e.started.FirstExecutionRunId,
+		RetryPolicy:                     attributes.GetRetryPolicy(),
+		Attempt:                         1,
+		WorkflowExecutionExpirationTime: e.started.WorkflowExecutionExpirationTime,
+		CronSchedule:                    attributes.GetCronSchedule(),
+		Memo:                            attributes.GetMemo(),
+		SearchAttributes:                attributes.GetSearchAttributes(),
+		Header:                          attributes.GetHeader(),
+	}, parent)
+	next.scheduleWorkflowTask()
+}
+
+func (e *devExecution) startTimer(attributes *commandpb.StartTimerCommandAttributes, completedEventID int64) {
+	event := e.addEvent(enumspb.EVENT_TYPE_TIMER_STARTED, &historypb.HistoryEvent_TimerStartedEventAttributes{
+		TimerStartedEventAttributes: &historypb.TimerStartedEventAttributes{
+			TimerId:                      attributes.GetTimerId(),
+			StartToFireTimeout:           attributes.GetStartToFireTimeout(),
+			WorkflowTaskCompletedEventId: completedEventID,
+		},
+	})
+	timerID := attributes.GetTimerId()
+	t := &devWorkflowTimer{startedEventID: event.EventId}
+	t.timer = e.server.startTimer(common.DurationValue(attributes.GetStartToFireTimeout()), func() {
+		delete(e.timers, timerID)
+		e.deliver(devBufferedEvent{timerID: timerID, apply: func() {
+			e.addEvent(enumspb.EVENT_TYPE_TIMER_FIRED, &historypb.HistoryEvent_TimerFiredEventAttributes{
+				TimerFiredEventAttributes: &historypb.TimerFiredEventAttributes{
+					TimerId:        timerID,
+					StartedEventId: t.startedEventID,
+				},
+			})
+		}})
+	})
+	e.timers[timerID] = t
+}
+
+func (e *devExecution) cancelTimer(timerID string, completedEventID int64) {
+	t, ok := e.timers[timerID]
+	if ok {
+		t.timer.cancel()
+		delete(e.timers, timerID)
+	} else {
+		// The timer may have fired while the workflow task was in progress, in which case the fired event is dropped.
+		for i, event := range e.bufferedEvents {
+			if event.timerID == timerID {
+				e.bufferedEvents = append(e.bufferedEvents[:i:i], e.bufferedEvents[i+1:]...)
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return
+		}
+	}
+	var startedEventID int64
+	for _, event := range e.history {
+		if attributes := event.GetTimerStartedEventAttributes(); attributes != nil && attributes.GetTimerId() == timerID {
+			startedEventID = event.EventId
+		}
+	}
+	e.addEvent(enumspb.EVENT_TYPE_TIMER_CANCELED, &historypb.HistoryEvent_TimerCanceledEventAttributes{
+		TimerCanceledEventAttributes: &historypb.TimerCanceledEventAttributes{
+			TimerId:                      timerID,
+			StartedEventId:               startedEventID,
+			WorkflowTaskCompletedEventId: completedEventID,
+		},
+	})
+}
+
+func (e *devExecution) startChild(attributes *commandpb.StartChildWorkflowExecutionCommandAttributes, completedEventID int64) func() {
+	namespace := attributes.GetNamespace()
+	if namespace == "" {
+		namespace = e.namespace
+	}
+	event := e.addEvent(enumspb.EVENT_TYPE_START_CHILD_WORKFLOW_EXECUTION_INITIATED, &historypb.HistoryEvent_StartChildWorkflowExecutionInitiatedEventAttributes{
+		StartChildWorkflowExecutionInitiatedEventAttributes: &historypb.StartChildWorkflowExecutionInitiatedEventAttributes{
+			Namespace:                    namespace,
+			WorkflowId:                   attributes.GetWorkflowId(),
+			WorkflowType:                 attributes.GetWorkflowType(),
+			TaskQueue:                    attributes.GetTaskQueue(),
+			Input:                        attributes.GetInput(),
+			WorkflowExecutionTimeout:     attributes.GetWorkflowExecutionTimeout(),
+			WorkflowRunTimeout:           attributes.GetWorkflowRunTimeout(),
+			WorkflowTaskTimeout:          attributes.GetWorkflowTaskTimeout(),
+			ParentClosePolicy:            attributes.GetParentClosePolicy(),
+			Control:                      attributes.GetControl(),
+			WorkflowTaskCompletedEventId: completedEventID,
+			WorkflowIdReusePolicy:        attributes.GetWorkflowIdReusePolicy(),
+			RetryPolicy:                  attributes.GetRetryPolicy(),
+			CronSchedule:                 attributes.GetCronSchedule(),
+			Header:                       attributes.GetHeader(),
+			Memo:                         attributes.GetMemo(),
+			SearchAttributes:             attributes.GetSearchAttributes(),
+		},
+	})
+	c := &devChild{
+		parent:            e,
+		initiatedEventID:  event.EventId,
+		namespace:         namespace,
+		workflowType:      attributes.GetWorkflowType(),
+		parentClosePolicy: attributes.GetParentClosePolicy(),
+	}
+	taskQueue := attributes.GetTaskQueue()
+	if taskQueue.GetName() == "" {
+		taskQueue = e.started.TaskQueue
+	}
+	return func() {
+		child, err := e.server.startWorkflow(&workflowservice.StartWorkflowExecutionRequest{
+			Namespace:                namespace,
+			WorkflowId:               attributes.GetWorkflowId(),
+			WorkflowType:             attributes.GetWorkflowType(),
+			TaskQueue:                taskQueue,
+			Input:                    attributes.GetInput(),
+			WorkflowExecutionTimeout: attributes.GetWorkflowExecutionTimeout(),
+			WorkflowRunTimeout:       attributes.GetWorkflowRunTimeout(),
+			WorkflowTaskTimeout:      attributes.GetWorkflowTaskTimeout(),
+			RequestId:                uuid.New(),
+			WorkflowIdReusePolicy:    attributes.GetWorkflowIdReusePolicy(),
+			RetryPolicy:              attributes.GetRetryPolicy(),
+			CronSchedule:             attributes.GetCronSchedule(),
+			Memo:                     attributes.GetMemo(),
+			SearchAttributes:         attributes.GetSearchAttributes(),
+			Header:                   attributes.GetHeader(),
+		}, c)
+		if err != nil {
+			e.deliver(devBufferedEvent{apply: func() {
+				e.addEvent(enumspb.EVENT_TYPE_START_CHILD_WORKFLOW_EXECUTION_FAILED, &historypb.HistoryEvent_StartChildWorkflowExecutionFailedEventAttributes{
+					StartChildWorkflowExecutionFailedEventAttributes: &historypb.StartChildWorkflowExecutionFailedEventAttributes{
+						Namespace:                    namespace,
+						WorkflowId:                   attributes.GetWorkflowId(),
+						WorkflowType:                 attributes.GetWorkflowType(),
+						Cause:                        enumspb.START_CHILD_WORKFLOW_EXECUTION_FAILED_CAUSE_WORKFLOW_ALREADY_EXISTS,
+						Control:                      attributes.GetControl(),
+						InitiatedEventId:             c.initiatedEventID,
+						WorkflowTaskCompletedEventId: completedEventID,
+					},
+				})
+			}})
+			return
+		}
+		c.execution = child.execution
+		e.children[c.initiatedEventID] = c
+		e.deliver(devBufferedEvent{apply: func() {
+			started := e.addEvent(enumspb.EVENT_TYPE_CHILD_WORKFLOW_EXECUTION_STARTED, &historypb.HistoryEvent_ChildWorkflowExecutionStartedEventAttributes{
+				ChildWorkflowExecutionStartedEventAttributes: &historypb.ChildWorkflowExecutionStartedEventAttributes{
+					Namespace:         namespace,
+					InitiatedEventId:  c.initiatedEventID,
+					WorkflowExecution: c.execution,
+					WorkflowType:      c.workflowType,
+					Header:            attributes.GetHeader(),
+				},
+			})
+			c.startedEventID = started.EventId
+		}})
+		child.scheduleWorkflowTask()
+	}
+}
+
+func (e *devExecution) signalExternal(attributes *commandpb.SignalExternalWorkflowExecutionCommandAttributes, completedEventID int64) func() {
+	namespace := attributes.GetNamespace()
+	if namespace == "" {
+		namespace = e.namespace
+	}
+	event := e.addEvent(enumspb.EVENT_TYPE_SIGNAL_EXTERNAL_WORKFLOW_EXECUTION_INITIATED, &historypb.HistoryEvent_SignalExternalWorkflowExecutionInitiatedEventAttributes{
+		SignalExternalWorkflowExecutionInitiatedEventAttributes: &historypb.SignalExternalWorkflowExecutionInitiatedEventAttributes{
+			WorkflowTaskCompletedEventId: completedEventID,
+			Namespace:                    namespace,
+			WorkflowExecution:            attributes.GetExecution(),
+			SignalName:                   attributes.GetSignalName(),
+			Input:                        attributes.GetInput(),
+			Control:                      attributes.GetControl(),
+			ChildWorkflowOnly:            attributes.GetChildWorkflowOnly(),
+		},
+	})
+	initiatedEventID := event.EventId
+	return func() {
+		target, err := e.server.getRunningExecution(namespace, attributes.GetExecution())
+		if err != nil {
+			e.deliver(devBufferedEvent{apply: func() {
+				e.addEvent(enumspb.EVENT_TYPE_SIGNAL_EXTERNAL_WORKFLOW_EXECUTION_FAILED, &historypb.HistoryEvent_SignalExternalWorkflowExecutionFailedEventAttributes{
+					SignalExternalWorkflowExecutionFailedEventAttributes: &historypb.SignalExternalWorkflowExecutionFailedEventAttributes{
+						Cause:                        enumspb.SIGNAL_EXTERNAL_WORKFLOW_EXECUTION_FAILED_CAUSE_EXTERNAL_WORKFLOW_EXECUTION_NOT_FOUND,
+						WorkflowTaskCompletedEventId: completedEventID,
+						Namespace:                    namespace,
+						WorkflowExecution:            attributes.GetExecution(),
+						InitiatedEventId:             initiatedEventID,
+						Control:                      attributes.GetControl(),
+					},
+				})
+			}})
+			return
+		}
+		target.signal(attributes.GetSignalName(), attributes.GetInput(), "")
+		e.deliver(devBufferedEvent{apply: func() {
+			e.addEvent(enumspb.EVENT_TYPE_EXTERNAL_WORKFLOW_EXECUTION_SIGNALED, &historypb.HistoryEvent_ExternalWorkflowExecutionSignaledEventAttributes{
+				ExternalWorkflowExecutionSignaledEventAttributes: &historypb.ExternalWorkflowExecutionSignaledEventAttributes{
+					InitiatedEventId:  initiatedEventID,
+					Namespace:         namespace,
+					WorkflowExecution: attributes.GetExecution(),
+					Control:           attributes.GetControl(),
+				},
+			})
+		}})
+	}
+}
+
+func (e *devExecution) requestCancelExternal(attributes *commandpb.RequestCancelExternalWorkflowExecutionCommandAttributes, completedEventID int64) func() {
+	namespace := attributes.GetNamespace()
+	if namespace == "" {
+		namespace = e.namespace
+	}
+	execution := &commonpb.WorkflowExecution{WorkflowId: attributes.GetWorkflowId(), RunId: attributes.GetRunId()}
+	event := e.addEvent(enumspb.EVENT_TYPE_REQUEST_CANCEL_EXTERNAL_WORKFLOW_EXECUTION_INITIATED, &historypb.HistoryEvent_RequestCancelExternalWorkflowExecutionInitiatedEventAttributes{
+		RequestCancelExternalWorkflowExecutionInitiatedEventAttributes: &historypb.RequestCancelExternalWorkflowExecutionInitiatedEventAttributes{
+			WorkflowTaskCompletedEventId: completedEventID,
+			Namespace:                    namespace,
+			WorkflowExecution:            execution,
+			Control:                      attributes.GetControl(),
+			ChildWorkflowOnly:            attributes.GetChildWorkflowOnly(),
+		},
+	})
+	initiatedEventID := event.EventId
+	return func() {
+		target, err := e.server.getRunningExecution(namespace, execution)
+		if err != nil {
+			e.deliver(devBufferedEvent{apply: func() {
+				e.addEvent(enumspb.EVENT_TYPE_REQUEST_CANCEL_EXTERNAL_WORKFLOW_EXECUTION_FAILED, &historypb.HistoryEvent_RequestCancelExternalWorkflowExecutionFailedEventAttributes{
+					RequestCancelExternalWorkflowExecutionFailedEventAttributes: &historypb.RequestCancelExternalWorkflowExecutionFailedEventAttributes{
+						Cause:                        enumspb.CANCEL_EXTERNAL_WORKFLOW_EXECUTION_FAILED_CAUSE_EXTERNAL_WORKFLOW_EXECUTION_NOT_FOUND,
+						WorkflowTaskCompletedEventId: completedEventID,
+						Namespace:                    namespace,
+						WorkflowExecution:            execution,
+						InitiatedEventId:             initiatedEventID,
+						Control:                      attributes.GetControl(),
+					},
+				})
+			}})
+			return
+		}
+		target.requestCancel(&historypb.WorkflowExecutionCancelRequestedEventAttributes{
+			ExternalInitiatedEventId:  initiatedEventID,
+			ExternalWorkflowExecution: e.execution,
+		})
+		e.deliver(devBufferedEvent{apply: func() {
+			e.addEvent(enumspb.EVENT_TYPE_EXTERNAL_WORKFLOW_EXECUTION_CANCEL_REQUESTED, &historypb.HistoryEvent_ExternalWorkflowExecutionCancelRequestedEventAttributes{
+				ExternalWorkflowExecutionCancelRequestedEventAttributes: &historypb.ExternalWorkflowExecutionCancelRequestedEventAttributes{
+					InitiatedEventId:  initiatedEventID,
+					Namespace:         namespace,
+					WorkflowExecution: execution,
+				},
+			})
+		}})
+	}
+}
+
+func (e *devExecution) scheduleActivity(attributes *commandpb.ScheduleActivityTaskCommandAttributes, completedEventID int64) {
+	if attributes.GetNamespace() == "" {
+		attributes.Namespace = e.namespace
+	}
+	if attributes.GetTaskQueue().GetName() == "" {
+		attributes.TaskQueue = e.started.TaskQueue
+	}
+	event := e.addEvent(enumspb.EVENT_TYPE_ACTIVITY_TASK_SCHEDULED, &historypb.HistoryEvent_ActivityTaskScheduledEventAttributes{
+		ActivityTaskScheduledEventAttributes: &historypb.ActivityTaskScheduledEventAttributes{
+			ActivityId:                   attributes.GetActivityId(),
+			ActivityType:                 attributes.GetActivityType(),
+			Namespace:                    attributes.GetNamespace(),
+			TaskQueue:                    attributes.GetTaskQueue(),
+			Header:                       attributes.GetHeader(),
+			Input:                        attributes.GetInput(),
+			ScheduleToCloseTimeout:       attributes.GetScheduleToCloseTimeout(),
+			ScheduleToStartTimeout:       attributes.GetScheduleToStartTimeout(),
+			StartToCloseTimeout:          attributes.GetStartToCloseTimeout(),
+			HeartbeatTimeout:             attributes.GetHeartbeatTimeout(),
+			WorkflowTaskCompletedEventId: completedEventID,
+			RetryPolicy:                  attributes.GetRetryPolicy(),
+		},
+	})
+	a := &devActivity{
+		execution:        e,
+		scheduledEventID: event.EventId,
+		attributes:       attributes,
+		scheduledTime:    e.server.clock(),
+	}
+	e.activities[a.scheduledEventID] = a
+	if e.activityIDs == nil {
+		e.activityIDs = make(map[string]int64)
+	}
+	e.activityIDs[attributes.GetActivityId()] = a.scheduledEventID
+	if timeout := common.DurationValue(attributes.GetScheduleToCloseTimeout()); timeout > 0 {
+		a.closeTimer = e.server.startDeadline(timeout, func() {
+			a.timeout(enumspb.TIMEOUT_TYPE_SCHEDULE_TO_CLOSE, false)
+		})
+	}
+	a.schedule()
+}
+
+func (e *devExecution) requestCancelActivity(scheduledEventID int64, completedEventID int64) func() {
+	a, ok := e.activities[scheduledEventID]
+	if !ok {
+		// The activity may have been closed while the workflow task was in progress.
+		for _, event := range e.bufferedEvents {
+			if event.activityScheduledID == scheduledEventID {
+				ok = true
+			}
+		}
+		if ok {
+			e.addEvent(enumspb.EVENT_TYPE_ACTIVITY_TASK_CANCEL_REQUESTED, &historypb.HistoryEvent_ActivityTaskCancelRequestedEventAttributes{
+				ActivityTaskCancelRequestedEventAttributes: &historypb.ActivityTaskCancelRequestedEventAttributes{
+					ScheduledEventId:             scheduledEventID,
+					WorkflowTaskCompletedEventId: completedEventID,
+				},
+			})
+		}
+		return nil
+	}
+	event := e.addEvent(enumspb.EVENT_TYPE_ACTIVITY_TASK_CANCEL_REQUESTED, &historypb.HistoryEvent_ActivityTaskCancelRequestedEventAttributes{
+		ActivityTaskCancelRequestedEventAttributes: &historypb.ActivityTaskCancelRequestedEventAttributes{
+			ScheduledEventId:             scheduledEventID,
+			WorkflowTaskCompletedEventId: completedEventID,
+		},
+	})
+	a.cancelRequested = true
+	a.cancelEventID = event.EventId
+	if a.started {
+		return nil
+	}
+	// The activity which is not being executed is canceled immediately. The canceled event must follow
+	// the events of all commands of the workflow task, as the SDK predicts the IDs of command events.
+	a.remove()
+	return func() {
+		e.deliver(devBufferedEvent{activityScheduledID: a.scheduledEventID, apply: func() {
+			e.addEvent(enumspb.EVENT_TYPE_ACTIVITY_TASK_CANCELED, &historypb.HistoryEvent_ActivityTaskCanceledEventAttributes{
+				ActivityTaskCanceledEventAttributes: &historypb.ActivityTaskCanceledEventAttributes{
+					LatestCancelRequestedEventId: a.cancelEventID,
+					ScheduledEventId:             a.scheduledEventID,
+				},
+			})
+		}})
+	}
+}
+
+func (e *devExecution) info() *workflowpb.WorkflowExecutionInfo {
+	startTime := e.startTime
+	info := &workflowpb.WorkflowExecutionInfo{
+		Execution:        e.execution,
+		Type:             e.started.WorkflowType,
+		StartTime:        &startTime,
+		ExecutionTime:    &startTime,
+		Status:           e.status,
+		HistoryLength:    int64(len(e.history)),
+		ParentExecution:  e.started.ParentWorkflowExecution,
+		Memo:             e.memo,
+		SearchAttributes: e.searchAttributes,
+		TaskQueue:        e.started.TaskQueue.GetName(),
+	}
+	if !e.isRunning() {
+		closeTime := e.closeTime
+		info.CloseTime = &closeTime
+	}
+	return info
+}
+
+func (e *devExecution) describe() *workflowservice.DescribeWorkflowExecutionResponse {
+	response := &workflowservice.DescribeWorkflowExecutionResponse{
+		ExecutionConfig: &workflowpb.WorkflowExecutionConfig{
+			TaskQueue:                  e.started.TaskQueue,
+			WorkflowExecutionTimeout:   e.started.WorkflowExecutionTimeout,
+			WorkflowRunTimeout:         e.started.WorkflowRunTimeout,
+			DefaultWorkflowTaskTimeout: e.started.WorkflowTaskTimeout,
+		},
+		WorkflowExecutionInfo: e.info(),
+	}
+	if !e.isRunning() {
+		return response
+	}
+	for _, a := range e.activities {
+		response.PendingActivities = append(response.PendingActivities, a.info())
+	}
+	for _, c := range e.children {
+		response.PendingChildren = append(response.PendingChildren, &workflowpb.PendingChildExecutionInfo{
+			WorkflowId:        c.execution.GetWorkflowId(),
+			RunId:             c.execution.GetRunId(),
+			WorkflowTypeName:  c.workflowType.GetName(),
+			InitiatedId:       c.initiatedEventID,
+			ParentClosePolicy: c.parentClosePolicy,
+		})
+	}
+	return response
+}
+
+// schedule adds the current attempt of the activity to the task queue.
+func (a *devActivity) schedule() {
+	s := a.execution.server
+	a.attempt++
+	a.attemptTime = s.clock()
+	a.queued = true
+	key := devTaskQueueKey(a.attributes.GetNamespace(), a.attributes.GetTaskQueue().GetName())
+	s.activityTasks[key] = append(s.activityTasks[key], devActivityTaskEntry{activity: a, attempt: a.attempt})
+}
+
+func (a *devActivity) start(identity string) *workflowservice.PollActivityTaskQueueResponse {
+	s := a.execution.server
+	a.queued = false
+	a.started = true
+	a.startedTime = s.clock()
+	a.lastHeartbeat = a.startedTime
+	a.identity = identity
+	a.requestID = uuid.New()
+
+	// Start-to-close and heartbeat timeouts are measured in real time, because they limit processing time of the worker.
+	attempt := a.attempt
+	if timeout := common.DurationValue(a.attributes.GetStartToCloseTimeout()); timeout > 0 {
+		a.attemptTimeout = time.AfterFunc(timeout, func() {
+			s.lock.Lock()
+			defer s.unlockAndNotify()
+			if a.isCurrentAttempt(attempt) {
+				a.timeout(enumspb.TIMEOUT_TYPE_START_TO_CLOSE, true)
+			}
+		})
+	}
+	a.resetHeartbeatTimeout()
+
+	scheduledTime, attemptTime, startedTime := a.scheduledTime, a.attemptTime, a.startedTime
+	e := a.execution
+	return &workflowservice.PollActivityTaskQueueResponse{
+		TaskToken:                   encodeDevTaskToken(devTaskToken{RunID: e.execution.RunId, ScheduledEventID: a.scheduledEventID, Attempt: a.attempt}),
+		WorkflowNamespace:           e.namespace,
+		WorkflowType:                e.started.WorkflowType,
+		WorkflowExecution:           e.execution,
+		ActivityType:                a.attributes.GetActivityType(),
+		ActivityId:                  a.attributes.GetActivityId(),
+		Header:                      a.attributes.GetHeader(),
+		Input:                       a.attributes.GetInput(),
+		HeartbeatDetails:            a.heartbeatDetails,
+		ScheduledTime:               &scheduledTime,
+		CurrentAttemptScheduledTime: &attemptTime,
+		StartedTime:                 &startedTime,
+		Attempt:                     a.attempt,
+		ScheduleToCloseTimeout:      a.attributes.GetScheduleToCloseTimeout(),
+		StartToCloseTimeout:         a.attributes.GetStartToCloseTimeout(),
+		HeartbeatTimeout:            a.attributes.GetHeartbeatTimeout(),
+		RetryPolicy:                 a.attributes.GetRetryPolicy(),
+	}
+}
+
+func (a *devActivity) isCurrentAttempt(attempt int32) bool {
+	return a.execution.isRunning() && a.execution.activities[a.scheduledEventID] == a && a.started && a.attempt == attempt
+}
+
+func (a *devActivity) resetHeartbeatTimeout() {
+	if a.heartbeatTimeout != nil {
+		a.heartbeatTimeout.Stop()
+		a.heartbeatTimeout = nil
+	}
+	timeout := common.DurationValue(a.attributes.GetHeartbeatTimeout())
+	if timeout <= 0 {
+		return
+	}
+	s, attempt := a.execution.server, a.attempt
+	a.heartbeatTimeout = time.AfterFunc(timeout, func() {
+		s.lock.Lock()
+		defer s.unlockAndNotify()
+		if a.isCurrentAttempt(attempt) {
+			a.timeout(enumspb.TIMEOUT_TYPE_HEARTBEAT, true)
+		}
+	})
+}
+
+func (a *devActivity) stopTimers() {
+	if a.attemptTimeout != nil {
+		a.attemptTimeout.Stop()
+		a.attemptTimeout = nil
+	}
+	if a.heartbeatTimeout != nil {
+		a.heartbeatTimeout.Stop()
+		a.heartbeatTimeout = nil
+	}
+}
+
+// remove deletes the activity from the pending activities of the workflow.
+func (a *devActivity) remove() {
+	a.stopTimers()
+	a.closeTimer.cancel()
+	a.queued = false
+	a.started = false
+	delete(a.execution.activities, a.scheduledEventID)
+}
+
+func (a *devActivity) heartbeat(details *commonpb.Payloads) bool {
+	a.heartbeatDetails = details
+	a.lastHeartbeat = a.execution.server.clock()
+	a.resetHeartbeatTimeout()
+	return a.cancelRequested
+}
+
+// close removes the activity and delivers its started event followed by the close event to the workflow.
+func (a *devActivity) close(addEvent func(startedEventID int64)) {
+	identity, requestID, attempt, lastFailure, started := a.identity, a.requestID, a.attempt, a.lastFailure, a.started
+	a.remove()
+	e := a.execution
+	e.deliver(devBufferedEvent{activityScheduledID: a.scheduledEventID, apply: func() {
+		var startedEventID int64
+		if started {
+			event := e.addEvent(enumspb.EVENT_TYPE_ACTIVITY_TASK_STARTED, &historypb.HistoryEvent_ActivityTaskStartedEventAttributes{
+				ActivityTaskStartedEventAttributes: &historypb.ActivityTaskStartedEventAttributes{
+					ScheduledEventId: a.scheduledEventID,
+					Identity:         identity,
+					RequestId:        requestID,
+					Attempt:          attempt,
+					LastFailure:      lastFailure,
+				},
+			})
+			startedEventID = event.EventId
+		}
+		addEvent(startedEventID)
+	}})
+}
+
+func (a *devActivity) complete(result *commonpb.Payloads, identity string) {
+	a.identity = identity
+	a.close(func(startedEventID int64) {
+		a.execution.addEvent(enumspb.EVENT_TYPE_ACTIVITY_TASK_COMPLETED, &historypb.HistoryEvent_ActivityTaskCompletedEventAttributes{
+			ActivityTaskCompletedEventAttributes: &historypb.ActivityTaskCompletedEventAttributes{
+				Result:           result,
+				ScheduledEventId: a.scheduledEventID,
+				StartedEventId:   startedEventID,
+				Identity:         identity,
+			},
+		})
+	})
+}
+
+func (a *devActivity) canceled(details *commonpb.Payloads, identity string) {
+	a.identity = identity
+	a.close(func(startedEventID int64) {
+		a.execution.addEvent(enumspb.EVENT_TYPE_ACTIVITY_TASK_CANCELED, &historypb.HistoryEvent_ActivityTaskCanceledEventAttributes{
+			ActivityTaskCanceledEventAttributes: &historypb.ActivityTaskCanceledEventAttributes{
+				Details:                      details,
+				LatestCancelRequestedEventId: a.cancelEventID,
+				ScheduledEventId:             a.scheduledEventID,
+				StartedEventId:               startedEventID,
+				Identity:                     identity,
+			},
+		})
+	})
+}
+
+func (a *devActivity) fail(failure *failurepb.Failure, identity string) {
+	a.identity = identity
+	retryState := a.retry(failure)
+	if retryState == enumspb.RETRY_STATE_IN_PROGRESS {
+		return
+	}
+	a.close(func(startedEventID int64) {
+		a.execution.addEvent(enumspb.EVENT_TYPE_ACTIVITY_TASK_FAILED, &historypb.HistoryEvent_ActivityTaskFailedEventAttributes{
+			ActivityTaskFailedEventAttributes: &historypb.ActivityTaskFailedEventAttributes{
+				Failure:          failure,
+				ScheduledEventId: a.scheduledEventID,
+				StartedEventId:   startedEventID,
+				Identity:         identity,
+				RetryState:       retryState,
+			},
+		})
+	})
+}
+
+func (a *devActivity) timeout(timeoutType enumspb.TimeoutType, retryable bool) {
+	if a.execution.activities[a.scheduledEventID] != a {
+		return
+	}
+	failure := &failurepb.Failure{
+		Message: "activity " + timeoutType.String() + " timeout",
+		FailureInfo: &failurepb.Failure_TimeoutFailureInfo{TimeoutFailureInfo: &failurepb.TimeoutFailureInfo{
+			TimeoutType:          timeoutType,
+			LastHeartbeatDetails: a.heartbeatDetails,
+		}},
+	}
+	retryState := enumspb.RETRY_STATE_TIMEOUT
+	if retryable {
+		if retryState = a.retry(failure); retryState == enumspb.RETRY_STATE_IN_PROGRESS {
+			return
+		}
+	}
+	a.close(func(startedEventID int64) {
+		a.execution.addEvent(enumspb.EVENT_TYPE_ACTIVITY_TASK_TIMED_OUT, &historypb.HistoryEvent_ActivityTaskTimedOutEventAttributes{
+			ActivityTaskTimedOutEventAttributes: &historypb.ActivityTaskTimedOutEventAttributes{
+				Failure:          failure,
+				ScheduledEventId: a.scheduledEventID,
+				StartedEventId:   startedEventID,
+				RetryState:       retryState,
+			},
+		})
+	})
+}
+
+// retry schedules the next attempt of the failed activity according to its retry policy. It returns
+// RETRY_STATE_IN_PROGRESS if the activity is going to be retried or the reason why it is not.
+func (a *devActivity) retry(failure *failurepb.Failure) enumspb.RetryState {
+	policy := a.attributes.GetRetryPolicy()
+	if info := failure.GetApplicationFailureInfo(); info != nil {
+		if info.GetNonRetryable() {
+			return enumspb.RETRY_STATE_NON_RETRYABLE_FAILURE
+		}
+		for _, errorType := range policy.GetNonRetryableErrorTypes() {
+			if errorType == info.GetType() {
+				return enumspb.RETRY_STATE_NON_RETRYABLE_FAILURE
+			}
+		}
+	}
+	if failure.GetCanceledFailureInfo() != nil || a.cancelRequested {
+		return enumspb.RETRY_STATE_CANCEL_REQUESTED
+	}
+	if policy.GetMaximumAttempts() > 0 && a.attempt >= policy.GetMaximumAttempts() {
+		return enumspb.RETRY_STATE_MAXIMUM_ATTEMPTS_REACHED
+	}
+
+	initialInterval := common.DurationValue(policy.GetInitialInterval())
+	if initialInterval <= 0 {
+		initialInterval = devServerDefaultRetryInitialInterval
+	}
+	coefficient := policy.GetBackoffCoefficient()
+	if coefficient < 1 {
+		coefficient = devServerDefaultRetryBackoffCoefficient
+	}
+	maximumInterval := common.DurationValue(policy.GetMaximumInterval())
+	if maximumInterval <= 0 {
+		maximumInterval = initialInterval * devServerDefaultRetryMaximumIntervalRatio
+	}
+	backoff := time.Duration(float64(initialInterval) * math.Pow(coefficient, float64(a.attempt-1)))
+	if backoff > maximumInterval || backoff <= 0 {
+		backoff = maximumInterval
+	}
+	s := a.execution.server
+	if timeout := common.DurationValue(a.attributes.GetScheduleToCloseTimeout()); timeout > 0 && s.clock().Add(backoff).After(a.scheduledTime.Add(timeout)) {
+		return enumspb.RETRY_STATE_TIMEOUT
+	}
+
+	a.stopTimers()
+	a.started = false
+	a.lastFailure = failure
+	a.heartbeatDetails = nil
+	s.startTimer(backoff, func() {
+		if a.execution.isRunning() && a.execution.activities[a.scheduledEventID] == a && !a.started && !a.queued {
+			a.schedule()
+		}
+	})
+	return enumspb.RETRY_STATE_IN_PROGRESS
+}
+
+func (a *devActivity) info() *workflowpb.PendingActivityInfo {
+	state := enumspb.PENDING_ACTIVITY_STATE_SCHEDULED
+	if a.cancelRequested {
+		state = enumspb.PENDING_ACTIVITY_STATE_CANCEL_REQUESTED
+	} else if a.started {
+		state = enumspb.PENDING_ACTIVITY_STATE_STARTED
+	}
+	scheduledTime := a.attemptTime
+	info := &workflowpb.PendingActivityInfo{
+		ActivityId:         a.attributes.GetActivityId(),
+		ActivityType:       a.attributes.GetActivityType(),
+		State:              state,
+		HeartbeatDetails:   a.heartbeatDetails,
+		Attempt:            a.attempt,
+		MaximumAttempts:    a.attributes.GetRetryPolicy().GetMaximumAttempts(),
+		ScheduledTime:      &scheduledTime,
+		LastFailure:        a.lastFailure,
+		LastWorkerIdentity: a.identity,
+	}
+	if a.started {
+		startedTime, lastHeartbeat := a.startedTime, a.lastHeartbeat
+		info.LastStartedTime = &startedTime
+		info.LastHeartbeatTime = &lastHeartbeat
+	}
+	if timeout := common.DurationValue(a.attributes.GetScheduleToCloseTimeout()); timeout > 0 {
+		expirationTime := a.scheduledTime.Add(timeout)
+		info.ExpirationTime = &expirationTime
+	}
+	return info
+}
@@ -0,0 +1,93 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"go.temporal.io/api/serviceerror"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// NewGRPCServer returns a gRPC server which serves the dev server as Temporal frontend together with gRPC health check.
+// It allows clients created with NewClient to use the dev server over a local listener, including gRPC interceptors,
+// metrics and retries which are bypassed by DevServer.NewClient:
+//   server := devServer.NewGRPCServer()
+//   listener, _ := net.Listen("tcp", "127.0.0.1:0")
+//   go func() { _ = server.Serve(listener) }()
+//   c, _ := client.NewClient(client.Options{HostPort: listener.Addr().String()})
+func (s *DevServer) NewGRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts, grpc.UnknownServiceHandler(s.handleGRPCStream))
+	server := grpc.NewServer(opts...)
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(healthCheckServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+	return server
+}
+
+// handleGRPCStream dispatches unary WorkflowService calls to the method of the dev server with the same name.
+func (s *DevServer) handleGRPCStream(_ interface{}, stream grpc.ServerStream) error {
+	fullMethod, ok := grpc.MethodFromServerStream(stream)
+	if !ok {
+		return status.Error(codes.Internal, "unable to get method name from stream")
+	}
+	prefix := "/" + healthCheckServiceName + "/"
+	if !strings.HasPrefix(fullMethod, prefix) {
+		return status.Errorf(codes.Unimplemented, "unknown method %v", fullMethod)
+	}
+	method := reflect.ValueOf(s).MethodByName(strings.TrimPrefix(fullMethod, prefix))
+	if !method.IsValid() || !isServiceClientMethod(method.Type()) {
+		return status.Errorf(codes.Unimplemented, "unknown method %v", fullMethod)
+	}
+
+	request := reflect.New(method.Type().In(1).Elem())
+	if err := stream.RecvMsg(request.Interface()); err != nil {
+		return err
+	}
+	results := method.Call([]reflect.Value{reflect.ValueOf(stream.Context()), request})
+	if err, _ := results[1].Interface().(error); err != nil {
+		return serviceerror.ToStatus(err).Err()
+	}
+	return stream.SendMsg(results[0].Interface())
+}
+
+// isServiceClientMethod checks that methodType is
+// func(context.Context, *Request, ...grpc.CallOption) (*Response, error).
+func isServiceClientMethod(methodType reflect.Type) bool {
+	return methodType.NumIn() == 3 && methodType.IsVariadic() &&
+		methodType.In(0) == contextType && methodType.In(1).Kind() == reflect.Ptr &&
+		methodType.NumOut() == 2 && methodType.Out(1) == errorType
+}
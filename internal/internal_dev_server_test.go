@@ -0,0 +1,397 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/api/workflowservice/v1"
+)
+
+const devServerTestTaskQueue = "dev-server-test-tq"
+
+type DevServerTestSuite struct {
+	suite.Suite
+	server *DevServer
+	client *WorkflowClient
+	worker *AggregatedWorker
+
+	activityAttempts int32
+}
+
+func TestDevServerTestSuite(t *testing.T) {
+	suite.Run(t, new(DevServerTestSuite))
+}
+
+func (s *DevServerTestSuite) SetupTest() {
+	s.server = NewDevServer(DevServerOptions{})
+	s.client = s.server.NewClient(ClientOptions{}).(*WorkflowClient)
+	atomic.StoreInt32(&s.activityAttempts, 0)
+}
+
+func (s *DevServerTestSuite) TearDownTest() {
+	if s.worker != nil {
+		s.worker.Stop()
+		s.worker = nil
+	}
+	s.server.Close()
+}
+
+func (s *DevServerTestSuite) startWorker() {
+	s.worker = NewAggregatedWorker(s.client, devServerTestTaskQueue, WorkerOptions{})
+	s.worker.RegisterWorkflow(devServerGreetingWorkflow)
+	s.worker.RegisterWorkflow(devServerSleepWorkflow)
+	s.worker.RegisterWorkflow(devServerParentWorkflow)
+	s.worker.RegisterWorkflow(devServerRetryWorkflow)
+	s.worker.RegisterWorkflow(devServerCancelActivityWorkflow)
+	s.worker.RegisterWorkflow(updateTestWorkflow)
+	s.worker.RegisterActivityWithOptions(devServerGreetActivity, RegisterActivityOptions{Name: "devServerGreetActivity"})
+	s.worker.RegisterActivityWithOptions(func(ctx context.Context) (int32, error) {
+		attempt := atomic.AddInt32(&s.activityAttempts, 1)
+		if attempt < 3 {
+			return 0, fmt.Errorf("attempt %v failed", attempt)
+		}
+		return attempt, nil
+	}, RegisterActivityOptions{Name: "devServerFlakyActivity"})
+	s.Require().NoError(s.worker.Start())
+}
+
+func (s *DevServerTestSuite) startWorkflow(ctx context.Context, id string, workflow interface{}, args ...interface{}) WorkflowRun {
+	run, err := s.client.ExecuteWorkflow(ctx, StartWorkflowOptions{ID: id, TaskQueue: devServerTestTaskQueue}, workflow, args...)
+	s.Require().NoError(err)
+	return run
+}
+
+func devServerGreetActivity(_ context.Context, name string) (string, error) {
+	return "Hello " + name, nil
+}
+
+func devServerGreetingWorkflow(ctx Context) (string, error) {
+	state := "waiting for name"
+	if err := SetQueryHandler(ctx, "state", func() (string, error) {
+		return state, nil
+	}); err != nil {
+		return "", err
+	}
+	var name string
+	GetSignalChannel(ctx, "name").Receive(ctx, &name)
+
+	state = "sleeping"
+	if err := Sleep(ctx, 24*time.Hour); err != nil {
+		return "", err
+	}
+
+	state = "greeting"
+	ctx = WithActivityOptions(ctx, ActivityOptions{StartToCloseTimeout: 10 * time.Second})
+	var greeting string
+	err := ExecuteActivity(ctx, "devServerGreetActivity", name).Get(ctx, &greeting)
+	return greeting, err
+}
+
+func devServerSleepWorkflow(ctx Context, d time.Duration) (time.Time, error) {
+	if err := Sleep(ctx, d); err != nil {
+		return time.Time{}, err
+	}
+	return Now(ctx), nil
+}
+
+func devServerParentWorkflow(ctx Context) (string, error) {
+	ctx = WithChildWorkflowOptions(ctx, ChildWorkflowOptions{WorkflowID: "dev-server-child"})
+	var greeting string
+	err := ExecuteChildWorkflow(ctx, devServerRetryWorkflow).Get(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	ctx = WithActivityOptions(ctx, ActivityOptions{StartToCloseTimeout: 10 * time.Second})
+	err = ExecuteActivity(ctx, "devServerGreetActivity", "child").Get(ctx, &greeting)
+	return greeting, err
+}
+
+func devServerRetryWorkflow(ctx Context) (int32, error) {
+	ctx = WithActivityOptions(ctx, ActivityOptions{
+		StartToCloseTimeout: 10 * time.Second,
+		RetryPolicy:         &RetryPolicy{InitialInterval: time.Minute, BackoffCoefficient: 2, MaximumAttempts: 5},
+	})
+	var attempt int32
+	err := ExecuteActivity(ctx, "devServerFlakyActivity").Get(ctx, &attempt)
+	return attempt, err
+}
+
+func devServerCancelActivityWorkflow(ctx Context) (string, error) {
+	ctx = WithActivityOptions(ctx, ActivityOptions{StartToCloseTimeout: 10 * time.Second})
+	canceledCtx, cancel := WithCancel(ctx)
+	_ = ExecuteActivity(canceledCtx, "devServerGreetActivity", "canceled")
+	cancel()
+	var greeting string
+	err := ExecuteActivity(ctx, "devServerGreetActivity", "again").Get(ctx, &greeting)
+	return greeting, err
+}
+
+func (s *DevServerTestSuite) TestSignalQueryTimerAndActivity() {
+	s.startWorker()
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	start := s.server.Now()
+	run := s.startWorkflow(ctx, "greeting", devServerGreetingWorkflow)
+
+	value, err := s.client.QueryWorkflow(ctx, "greeting", "", "state")
+	s.NoError(err)
+	var state string
+	s.NoError(value.Get(&state))
+	s.Equal("waiting for name", state)
+
+	s.NoError(s.client.SignalWorkflow(ctx, "greeting", "", "name", "Temporal"))
+	var greeting string
+	s.NoError(run.Get(ctx, &greeting))
+	s.Equal("Hello Temporal", greeting)
+	s.True(s.server.Now().Sub(start) >= 24*time.Hour)
+
+	description, err := s.client.DescribeWorkflowExecution(ctx, "greeting", "")
+	s.NoError(err)
+	s.Equal(enumspb.WORKFLOW_EXECUTION_STATUS_COMPLETED, description.WorkflowExecutionInfo.Status)
+
+	_, err = s.client.QueryWorkflowWithOptions(ctx, &QueryWorkflowWithOptionsRequest{
+		WorkflowID:           "greeting",
+		QueryType:            "state",
+		QueryRejectCondition: enumspb.QUERY_REJECT_CONDITION_NOT_OPEN,
+	})
+	s.NoError(err)
+}
+
+func (s *DevServerTestSuite) TestActivityRetryAndChildWorkflow() {
+	s.startWorker()
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	run := s.startWorkflow(ctx, "parent", devServerParentWorkflow)
+	var greeting string
+	s.NoError(run.Get(ctx, &greeting))
+	s.Equal("Hello child", greeting)
+	s.Equal(int32(3), atomic.LoadInt32(&s.activityAttempts))
+
+	var attempt int32
+	s.NoError(s.client.GetWorkflow(ctx, "dev-server-child", "").Get(ctx, &attempt))
+	s.Equal(int32(3), attempt)
+}
+
+func (s *DevServerTestSuite) TestManualTime() {
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.server = NewDevServer(DevServerOptions{StartTime: start, ManualTime: true})
+	s.client = s.server.NewClient(ClientOptions{}).(*WorkflowClient)
+	s.startWorker()
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	run := s.startWorkflow(ctx, "sleep", devServerSleepWorkflow, time.Hour)
+
+	// wait until the timer is started
+	for {
+		description, err := s.client.DescribeWorkflowExecution(ctx, "sleep", "")
+		s.Require().NoError(err)
+		if description.WorkflowExecutionInfo.HistoryLength >= 5 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	s.server.AdvanceTime(30 * time.Minute)
+	description, err := s.client.DescribeWorkflowExecution(ctx, "sleep", "")
+	s.NoError(err)
+	s.Equal(enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING, description.WorkflowExecutionInfo.Status)
+
+	s.server.AdvanceTime(30 * time.Minute)
+	var completedAt time.Time
+	s.NoError(run.Get(ctx, &completedAt))
+	s.Equal(start.Add(time.Hour), completedAt.UTC())
+}
+
+func (s *DevServerTestSuite) TestClockAdvancesWithRealTime() {
+	start := s.server.Now()
+	time.Sleep(10 * time.Millisecond)
+	s.True(s.server.Now().Sub(start) >= 10*time.Millisecond)
+}
+
+func (s *DevServerTestSuite) TestCancelActivityBeforeStarted() {
+	s.startWorker()
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	run := s.startWorkflow(ctx, "cancel-activity", devServerCancelActivityWorkflow)
+	var greeting string
+	s.NoError(run.Get(ctx, &greeting))
+	s.Equal("Hello again", greeting)
+
+	// The canceled event follows the events of all the commands of the workflow task.
+	var eventTypes []enumspb.EventType
+	iter := s.client.GetWorkflowHistory(ctx, "cancel-activity", run.GetRunID(), false, enumspb.HISTORY_EVENT_FILTER_TYPE_ALL_EVENT)
+	for iter.HasNext() {
+		event, err := iter.Next()
+		s.Require().NoError(err)
+		eventTypes = append(eventTypes, event.GetEventType())
+	}
+	s.Equal([]enumspb.EventType{
+		enumspb.EVENT_TYPE_ACTIVITY_TASK_SCHEDULED,
+		enumspb.EVENT_TYPE_ACTIVITY_TASK_CANCEL_REQUESTED,
+		enumspb.EVENT_TYPE_ACTIVITY_TASK_SCHEDULED,
+		enumspb.EVENT_TYPE_ACTIVITY_TASK_CANCELED,
+	}, eventTypes[4:8])
+}
+
+func (s *DevServerTestSuite) TestGRPCServer() {
+	grpcServer := s.server.NewGRPCServer()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	s.Require().NoError(err)
+	go func() { _ = grpcServer.Serve(listener) }()
+	defer grpcServer.Stop()
+
+	c, err := NewClient(ClientOptions{HostPort: listener.Addr().String()})
+	s.Require().NoError(err)
+	defer c.Close()
+	s.client = c.(*WorkflowClient)
+	s.startWorker()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	start := s.server.Now()
+	run := s.startWorkflow(ctx, "grpc-sleep", devServerSleepWorkflow, time.Hour)
+	var completedAt time.Time
+	s.NoError(run.Get(ctx, &completedAt))
+	s.False(completedAt.Before(start.Add(time.Hour)))
+
+	// Service errors keep their type over gRPC.
+	_, err = c.DescribeWorkflowExecution(ctx, "unknown", "")
+	var notFound *serviceerror.NotFound
+	s.True(errors.As(err, &notFound))
+}
+
+func (s *DevServerTestSuite) TestCancelAndTerminate() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	run := s.startWorkflow(ctx, "canceled", devServerSleepWorkflow, time.Hour)
+	s.NoError(s.client.CancelWorkflow(ctx, "canceled", ""))
+	s.startWorker()
+	err := run.Get(ctx, nil)
+	var canceledErr *CanceledError
+	s.True(errors.As(err, &canceledErr))
+
+	run = s.startWorkflow(ctx, "terminated", devServerGreetingWorkflow)
+	s.NoError(s.client.TerminateWorkflow(ctx, "terminated", "", "test", nil))
+	err = run.Get(ctx, nil)
+	var terminatedErr *TerminatedError
+	s.True(errors.As(err, &terminatedErr))
+}
+
+func (s *DevServerTestSuite) TestStartWorkflow_AlreadyStarted() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	s.startWorkflow(ctx, "duplicate", devServerGreetingWorkflow)
+	_, err := s.client.ExecuteWorkflow(ctx, StartWorkflowOptions{
+		ID:                                       "duplicate",
+		TaskQueue:                                devServerTestTaskQueue,
+		WorkflowExecutionErrorWhenAlreadyStarted: true,
+	}, devServerGreetingWorkflow)
+	var alreadyStarted *serviceerror.WorkflowExecutionAlreadyStarted
+	s.True(errors.As(err, &alreadyStarted))
+}
+
+func (s *DevServerTestSuite) TestListAndCountWorkflows() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	s.startWorkflow(ctx, "list-1", devServerGreetingWorkflow)
+	s.startWorkflow(ctx, "list-2", devServerSleepWorkflow, time.Hour)
+	s.NoError(s.client.TerminateWorkflow(ctx, "list-2", "", "test", nil))
+
+	response, err := s.client.ListWorkflow(ctx, &workflowservice.ListWorkflowExecutionsRequest{
+		Namespace: DefaultNamespace,
+		Query:     "WorkflowType = 'devServerGreetingWorkflow' and ExecutionStatus = 'Running'",
+	})
+	s.NoError(err)
+	s.Len(response.Executions, 1)
+	s.Equal("list-1", response.Executions[0].Execution.WorkflowId)
+
+	count, err := s.client.CountWorkflow(ctx, &workflowservice.CountWorkflowExecutionsRequest{Namespace: DefaultNamespace})
+	s.NoError(err)
+	s.Equal(int64(2), count.Count)
+
+	_, err = s.client.CountWorkflow(ctx, &workflowservice.CountWorkflowExecutionsRequest{
+		Namespace: DefaultNamespace,
+		Query:     "StartTime > '2021-01-01'",
+	})
+	var invalidArgument *serviceerror.InvalidArgument
+	s.True(errors.As(err, &invalidArgument))
+}
+
+func (s *DevServerTestSuite) TestGetHistoryLongPoll() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	run := s.startWorkflow(ctx, "history", devServerGreetingWorkflow)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = s.client.TerminateWorkflow(ctx, "history", "", "test", nil)
+	}()
+	iter := s.client.GetWorkflowHistory(ctx, "history", run.GetRunID(), true, enumspb.HISTORY_EVENT_FILTER_TYPE_CLOSE_EVENT)
+	s.True(iter.HasNext())
+	event, err := iter.Next()
+	s.NoError(err)
+	s.Equal(enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_TERMINATED, event.EventType)
+}
+
+func (s *DevServerTestSuite) TestUpdateWorkflow() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	s.startWorker()
+	run := s.startWorkflow(ctx, "update", updateTestWorkflow)
+
+	request := &UpdateWorkflowWithOptionsRequest{UpdateID: "first", WorkflowID: "update", UpdateName: "add", Args: []interface{}{4}}
+	value, err := s.client.UpdateWorkflowWithOptions(ctx, request)
+	s.NoError(err)
+	var total int
+	s.NoError(value.Get(&total))
+	s.Equal(4, total)
+
+	// A retried update returns the result of the first call without running the handler again.
+	value, err = s.client.UpdateWorkflowWithOptions(ctx, request)
+	s.NoError(err)
+	s.NoError(value.Get(&total))
+	s.Equal(4, total)
+
+	_, err = s.client.UpdateWorkflow(ctx, "update", run.GetRunID(), "add", -1)
+	var applicationErr *ApplicationError
+	s.True(errors.As(err, &applicationErr))
+	s.Equal("InvalidAmount", applicationErr.Type())
+
+	value, err = s.client.UpdateWorkflow(ctx, "update", "", "add", 6)
+	s.NoError(err)
+	s.NoError(value.Get(&total))
+	s.Equal(10, total)
+	s.NoError(run.Get(ctx, &total))
+	s.Equal(10, total)
+}
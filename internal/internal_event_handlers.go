@@ -35,7 +35,6 @@ import (
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/opentracing/opentracing-go"
-	"github.com/uber-go/tally"
 	commandpb "go.temporal.io/api/command/v1"
 	commonpb "go.temporal.io/api/common/v1"
 	enumspb "go.temporal.io/api/enums/v1"
@@ -128,12 +127,13 @@ type (
 		isReplay              bool // flag to indicate if workflow is in replay mode
 		enableLoggingInReplay bool // flag to indicate if workflow should enable logging in replay mode
 
-		metricsScope             tally.Scope
-		registry                 *registry
-		dataConverter            converter.DataConverter
-		contextPropagators       []ContextPropagator
-		tracer                   opentracing.Tracer
-		deadlockDetectionTimeout time.Duration
+		metricsHandler             metrics.Handler
+		registry                   *registry
+		dataConverter              converter.DataConverter
+		contextPropagators         []ContextPropagator
+		tracer                     opentracing.Tracer
+		deadlockDetectionTimeout   time.Duration
+		nativeConcurrencyDetection bool
 	}
 
 	localActivityTask struct {
@@ -174,29 +174,31 @@ func newWorkflowExecutionEventHandler(
 	completeHandler completionHandler,
 	logger log.Logger,
 	enableLoggingInReplay bool,
-	scope tally.Scope,
+	metricsHandler metrics.Handler,
 	registry *registry,
 	dataConverter converter.DataConverter,
 	contextPropagators []ContextPropagator,
 	tracer opentracing.Tracer,
 	deadlockDetectionTimeout time.Duration,
+	nativeConcurrencyDetection bool,
 ) workflowExecutionEventHandler {
 	context := &workflowEnvironmentImpl{
-		workflowInfo:             workflowInfo,
-		commandsHelper:           newCommandsHelper(),
-		sideEffectResult:         make(map[int64]*commonpb.Payloads),
-		mutableSideEffect:        make(map[string]*commonpb.Payloads),
-		changeVersions:           make(map[string]Version),
-		pendingLaTasks:           make(map[string]*localActivityTask),
-		unstartedLaTasks:         make(map[string]struct{}),
-		openSessions:             make(map[string]*SessionInfo),
-		completeHandler:          completeHandler,
-		enableLoggingInReplay:    enableLoggingInReplay,
-		registry:                 registry,
-		dataConverter:            dataConverter,
-		contextPropagators:       contextPropagators,
-		tracer:                   tracer,
-		deadlockDetectionTimeout: deadlockDetectionTimeout,
+		workflowInfo:               workflowInfo,
+		commandsHelper:             newCommandsHelper(),
+		sideEffectResult:           make(map[int64]*commonpb.Payloads),
+		mutableSideEffect:          make(map[string]*commonpb.Payloads),
+		changeVersions:             make(map[string]Version),
+		pendingLaTasks:             make(map[string]*localActivityTask),
+		unstartedLaTasks:           make(map[string]struct{}),
+		openSessions:               make(map[string]*SessionInfo),
+		completeHandler:            completeHandler,
+		enableLoggingInReplay:      enableLoggingInReplay,
+		registry:                   registry,
+		dataConverter:              dataConverter,
+		contextPropagators:         contextPropagators,
+		tracer:                     tracer,
+		deadlockDetectionTimeout:   deadlockDetectionTimeout,
+		nativeConcurrencyDetection: nativeConcurrencyDetection,
 	}
 	context.logger = ilog.NewReplayLogger(
 		log.With(logger,
@@ -208,9 +210,9 @@ func newWorkflowExecutionEventHandler(
 		&context.isReplay,
 		&context.enableLoggingInReplay)
 
-	if scope != nil {
-		replayAwareScope := metrics.WrapScope(&context.isReplay, scope, context)
-		context.metricsScope = metrics.GetMetricsScopeForWorkflow(replayAwareScope, workflowInfo.WorkflowType.Name)
+	if metricsHandler != nil {
+		context.metricsHandler = metrics.GetMetricsHandlerForWorkflow(
+			metrics.NewReplayAwareHandler(&context.isReplay, metricsHandler), workflowInfo.WorkflowType.Name)
 	}
 
 	return &workflowExecutionEventHandlerImpl{context, nil}
@@ -432,8 +434,8 @@ func (wc *workflowEnvironmentImpl) GetLogger() log.Logger {
 	return wc.logger
 }
 
-func (wc *workflowEnvironmentImpl) GetMetricsScope() tally.Scope {
-	return wc.metricsScope
+func (wc *workflowEnvironmentImpl) GetMetricsHandler() metrics.Handler {
+	return wc.metricsHandler
 }
 
 func (wc *workflowEnvironmentImpl) GetDataConverter() converter.DataConverter {
@@ -763,6 +765,10 @@ func (wc *workflowEnvironmentImpl) GetRegistry() *registry {
 	return wc.registry
 }
 
+func (wc *workflowEnvironmentImpl) IsNativeConcurrencyDetectionEnabled() bool {
+	return wc.nativeConcurrencyDetection
+}
+
 func (weh *workflowExecutionEventHandlerImpl) ProcessEvent(
 	event *historypb.HistoryEvent,
 	isReplay bool,
@@ -773,7 +779,7 @@ func (weh *workflowExecutionEventHandlerImpl) ProcessEvent(
 	}
 	defer func() {
 		if p := recover(); p != nil {
-			weh.metricsScope.Counter(metrics.WorkflowTaskExecutionFailureCounter).Inc(1)
+			weh.metricsHandler.Counter(metrics.WorkflowTaskExecutionFailureCounter).Inc(1)
 			topLine := fmt.Sprintf("process event for %s [panic]:", weh.workflowInfo.TaskQueueName)
 			st := getStackTraceRaw(topLine, 7, 0)
 			weh.Complete(nil, newWorkflowPanicError(p, st))
@@ -0,0 +1,150 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+// All code in this file is private to the package.
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// nativeConcurrencyCheckInterval is how often the dispatcher inspects a running coroutine
+// when WorkerOptions.EnableNativeConcurrencyDetection is set.
+const nativeConcurrencyCheckInterval = 10 * time.Millisecond
+
+var (
+	// coroutineYieldFunction is the frame every coroutine blocked through the workflow API has on its stack.
+	coroutineYieldFunction = runtime.FuncForPC(reflect.ValueOf((*coroutineState).initialYield).Pointer()).Name()
+	// sdkMethodPrefix matches methods of types of this package, which start goroutines on behalf of coroutines.
+	sdkMethodPrefix = coroutineYieldFunction[:strings.Index(coroutineYieldFunction, "(*")+2]
+)
+
+// goroutineInfo is a single goroutine parsed from runtime.Stack output.
+type goroutineInfo struct {
+	id        int64
+	state     string // wait reason, like "chan receive" or "select"
+	createdBy string // function which executed the go statement
+	parentID  int64  // id of the goroutine which executed the go statement, 0 if runtime doesn't report it
+	stack     string
+}
+
+// nativeBlockingStates are goroutine wait reasons caused by native go concurrency primitives.
+var nativeBlockingStates = []string{"chan send", "chan receive", "select", "sleep", "sync.WaitGroup.Wait", "sync.Cond.Wait"}
+
+// checkNativeBlocking panics if the coroutine goroutine is blocked on a native channel, select or sleep.
+// Such coroutine never yields, so without the check it is reported only after the deadlock detection timeout.
+func (s *coroutineState) checkNativeBlocking() {
+	for _, g := range dumpGoroutines() {
+		if g.id != s.goroutineID {
+			continue
+		}
+		if strings.Contains(g.stack, coroutineYieldFunction) || !isNativeBlockingState(g.state) {
+			return
+		}
+		s.closed.Store(true)
+		panic(fmt.Sprintf("Potential non-determinism detected: workflow goroutine %q is blocked on native %q. "+
+			"Use workflow.Channel, workflow.Selector and workflow.Sleep instead.\n%s", s.name, g.state, g.stack))
+	}
+}
+
+// checkNativeGoroutines panics if the coroutine started a goroutine with the go statement instead of workflow.Go.
+// It relies on the parent goroutine id, which is reported by the runtime since Go 1.21.
+func (s *coroutineState) checkNativeGoroutines() {
+	for _, g := range dumpGoroutines() {
+		if g.parentID != s.goroutineID || strings.HasPrefix(g.createdBy, sdkMethodPrefix) {
+			continue
+		}
+		panic(fmt.Sprintf("Potential non-determinism detected: workflow goroutine %q started goroutine %d "+
+			"with the go statement in %s. Use workflow.Go instead.\n%s", s.name, g.id, g.createdBy, g.stack))
+	}
+}
+
+func isNativeBlockingState(state string) bool {
+	for _, prefix := range nativeBlockingStates {
+		if strings.HasPrefix(state, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func currentGoroutineID() int64 {
+	var buf [64]byte
+	header := string(buf[:runtime.Stack(buf[:], false)])
+	return parseGoroutineID(header)
+}
+
+func dumpGoroutines() []goroutineInfo {
+	buf := make([]byte, 64*1024)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+	var result []goroutineInfo
+	for _, stack := range strings.Split(string(buf), "\n\n") {
+		lines := strings.Split(strings.TrimSpace(stack), "\n")
+		if len(lines) == 0 || !strings.HasPrefix(lines[0], "goroutine ") {
+			continue
+		}
+		g := goroutineInfo{id: parseGoroutineID(lines[0]), stack: stack}
+		// "goroutine 18 [chan receive, 2 minutes]:"
+		if start, end := strings.Index(lines[0], "["), strings.LastIndex(lines[0], "]"); start >= 0 && end > start {
+			g.state = strings.SplitN(lines[0][start+1:end], ",", 2)[0]
+		}
+		// "created by main.workflow in goroutine 7"
+		for _, line := range lines {
+			if !strings.HasPrefix(line, "created by ") {
+				continue
+			}
+			createdBy := strings.TrimPrefix(line, "created by ")
+			if i := strings.Index(createdBy, " in goroutine "); i >= 0 {
+				g.parentID, _ = strconv.ParseInt(createdBy[i+len(" in goroutine "):], 10, 64)
+				createdBy = createdBy[:i]
+			}
+			g.createdBy = createdBy
+		}
+		result = append(result, g)
+	}
+	return result
+}
+
+// parseGoroutineID parses id from the "goroutine 18 [running]:" header line.
+func parseGoroutineID(header string) int64 {
+	fields := strings.Fields(header)
+	if len(fields) < 2 {
+		return 0
+	}
+	id, _ := strconv.ParseInt(fields[1], 10, 64)
+	return id
+}
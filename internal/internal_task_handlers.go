@@ -39,7 +39,6 @@ import (
 	"github.com/gogo/protobuf/proto"
 	"github.com/gogo/status"
 	"github.com/opentracing/opentracing-go"
-	"github.com/uber-go/tally"
 	commandpb "go.temporal.io/api/command/v1"
 	commonpb "go.temporal.io/api/common/v1"
 	enumspb "go.temporal.io/api/enums/v1"
@@ -121,20 +120,21 @@ type (
 
 	// workflowTaskHandlerImpl is the implementation of WorkflowTaskHandler
 	workflowTaskHandlerImpl struct {
-		namespace                string
-		metricsScope             tally.Scope
-		ppMgr                    pressurePointMgr
-		logger                   log.Logger
-		identity                 string
-		enableLoggingInReplay    bool
-		registry                 *registry
-		laTunnel                 *localActivityTunnel
-		workflowPanicPolicy      WorkflowPanicPolicy
-		dataConverter            converter.DataConverter
-		contextPropagators       []ContextPropagator
-		tracer                   opentracing.Tracer
-		cache                    *WorkerCache
-		deadlockDetectionTimeout time.Duration
+		namespace                  string
+		metricsHandler             metrics.Handler
+		ppMgr                      pressurePointMgr
+		logger                     log.Logger
+		identity                   string
+		enableLoggingInReplay      bool
+		registry                   *registry
+		laTunnel                   *localActivityTunnel
+		workflowPanicPolicy        WorkflowPanicPolicy
+		dataConverter              converter.DataConverter
+		contextPropagators         []ContextPropagator
+		tracer                     opentracing.Tracer
+		cache                      *WorkerCache
+		deadlockDetectionTimeout   time.Duration
+		nativeConcurrencyDetection bool
 	}
 
 	activityProvider func(name string) activity
@@ -144,7 +144,7 @@ type (
 		taskQueueName      string
 		identity           string
 		service            workflowservice.WorkflowServiceClient
-		metricsScope       tally.Scope
+		metricsHandler     metrics.Handler
 		logger             log.Logger
 		userContext        context.Context
 		registry           *registry
@@ -386,19 +386,20 @@ func isPreloadMarkerEvent(event *historypb.HistoryEvent) bool {
 func newWorkflowTaskHandler(params workerExecutionParameters, ppMgr pressurePointMgr, registry *registry) WorkflowTaskHandler {
 	ensureRequiredParams(&params)
 	return &workflowTaskHandlerImpl{
-		namespace:                params.Namespace,
-		logger:                   params.Logger,
-		ppMgr:                    ppMgr,
-		metricsScope:             params.MetricsScope,
-		identity:                 params.Identity,
-		enableLoggingInReplay:    params.EnableLoggingInReplay,
-		registry:                 registry,
-		workflowPanicPolicy:      params.WorkflowPanicPolicy,
-		dataConverter:            params.DataConverter,
-		contextPropagators:       params.ContextPropagators,
-		tracer:                   params.Tracer,
-		cache:                    params.cache,
-		deadlockDetectionTimeout: params.DeadlockDetectionTimeout,
+		namespace:                  params.Namespace,
+		logger:                     params.Logger,
+		ppMgr:                      ppMgr,
+		metricsHandler:             params.MetricsHandler,
+		identity:                   params.Identity,
+		enableLoggingInReplay:      params.EnableLoggingInReplay,
+		registry:                   registry,
+		workflowPanicPolicy:        params.WorkflowPanicPolicy,
+		dataConverter:              params.DataConverter,
+		contextPropagators:         params.ContextPropagators,
+		tracer:                     params.Tracer,
+		cache:                      params.cache,
+		deadlockDetectionTimeout:   params.DeadlockDetectionTimeout,
+		nativeConcurrencyDetection: params.EnableNativeConcurrencyDetection,
 	}
 }
 
@@ -517,12 +518,13 @@ func (w *workflowExecutionContextImpl) createEventHandler() {
 		w.completeWorkflow,
 		w.wth.logger,
 		w.wth.enableLoggingInReplay,
-		w.wth.metricsScope,
+		w.wth.metricsHandler,
 		w.wth.registry,
 		w.wth.dataConverter,
 		w.wth.contextPropagators,
 		w.wth.tracer,
 		w.wth.deadlockDetectionTimeout,
+		w.wth.nativeConcurrencyDetection,
 	)
 
 	w.eventHandler = &eventHandler
@@ -591,12 +593,12 @@ func (wth *workflowTaskHandlerImpl) getOrCreateWorkflowContext(
 	task *workflowservice.PollWorkflowTaskQueueResponse,
 	historyIterator HistoryIterator,
 ) (workflowContext *workflowExecutionContextImpl, err error) {
-	workflowMetricsScope := metrics.GetMetricsScopeForWorkflow(wth.metricsScope, task.WorkflowType.GetName())
+	workflowMetricsHandler := metrics.GetMetricsHandlerForWorkflow(wth.metricsHandler, task.WorkflowType.GetName())
 	defer func() {
 		if err == nil && workflowContext != nil && workflowContext.laTunnel == nil {
 			workflowContext.laTunnel = wth.laTunnel
 		}
-		workflowMetricsScope.Gauge(metrics.StickyCacheSize).Update(float64(wth.cache.getWorkflowCache().Size()))
+		workflowMetricsHandler.Gauge(metrics.StickyCacheSize).Update(float64(wth.cache.getWorkflowCache().Size()))
 	}()
 
 	runID := task.WorkflowExecution.GetRunId()
@@ -613,10 +615,10 @@ func (wth *workflowTaskHandlerImpl) getOrCreateWorkflowContext(
 		workflowContext.Lock()
 		if task.Query != nil && !isFullHistory {
 			// query task and we have a valid cached state
-			workflowMetricsScope.Counter(metrics.StickyCacheHit).Inc(1)
+			workflowMetricsHandler.Counter(metrics.StickyCacheHit).Inc(1)
 		} else if history.Events[0].GetEventId() == workflowContext.previousStartedEventID+1 {
 			// non query task and we have a valid cached state
-			workflowMetricsScope.Counter(metrics.StickyCacheHit).Inc(1)
+			workflowMetricsHandler.Counter(metrics.StickyCacheHit).Inc(1)
 		} else {
 			// non query task and cached state is missing events, we need to discard the cached state and rebuild one.
 			_ = workflowContext.ResetIfStale(task, historyIterator)
@@ -625,7 +627,7 @@ func (wth *workflowTaskHandlerImpl) getOrCreateWorkflowContext(
 		if !isFullHistory {
 			// we are getting partial history task, but cached state was already evicted.
 			// we need to reset history so we get events from beginning to replay/rebuild the state
-			workflowMetricsScope.Counter(metrics.StickyCacheMiss).Inc(1)
+			workflowMetricsHandler.Counter(metrics.StickyCacheMiss).Inc(1)
 			if _, err = resetHistory(task, historyIterator); err != nil {
 				return
 			}
@@ -818,9 +820,9 @@ func (w *workflowExecutionContextImpl) ProcessWorkflowTask(workflowTask *workflo
 	var respondEvents []*historypb.HistoryEvent
 
 	skipReplayCheck := w.skipReplayCheck()
-	workflowMetricsScope := metrics.GetMetricsScopeForWorkflow(w.wth.metricsScope, task.WorkflowType.GetName())
-	replayStopWatch := workflowMetricsScope.Timer(metrics.WorkflowTaskReplayLatency).Start()
-	replayStopWatchStopped := false
+	workflowMetricsHandler := metrics.GetMetricsHandlerForWorkflow(w.wth.metricsHandler, task.WorkflowType.GetName())
+	replayStartTime := time.Now()
+	replayRecorded := false
 
 	// Process events
 ProcessEvents:
@@ -854,9 +856,9 @@ ProcessEvents:
 
 		for i, event := range reorderedEvents {
 			isInReplay := reorderedHistory.IsReplayEvent(event)
-			if !isInReplay && !replayStopWatchStopped {
-				replayStopWatch.Stop()
-				replayStopWatchStopped = true
+			if !isInReplay && !replayRecorded {
+				workflowMetricsHandler.Timer(metrics.WorkflowTaskReplayLatency).Record(time.Since(replayStartTime))
+				replayRecorded = true
 			}
 
 			isLast := !isInReplay && i == len(reorderedEvents)-1
@@ -905,8 +907,8 @@ ProcessEvents:
 		}
 	}
 
-	if !replayStopWatchStopped {
-		replayStopWatch.Stop()
+	if !replayRecorded {
+		workflowMetricsHandler.Timer(metrics.WorkflowTaskReplayLatency).Record(time.Since(replayStartTime))
 	}
 
 	// Non-deterministic error could happen in 2 different places:
@@ -1446,7 +1448,7 @@ func (wth *workflowTaskHandlerImpl) completeWorkflow(
 		return queryCompletedRequest
 	}
 
-	metricsScope := metrics.GetMetricsScopeForWorkflow(wth.metricsScope, eventHandler.workflowEnvironmentImpl.workflowInfo.WorkflowType.Name)
+	metricsHandler := metrics.GetMetricsHandlerForWorkflow(wth.metricsHandler, eventHandler.workflowEnvironmentImpl.workflowInfo.WorkflowType.Name)
 
 	// complete workflow task
 	var closeCommand *commandpb.Command
@@ -1455,14 +1457,14 @@ func (wth *workflowTaskHandlerImpl) completeWorkflow(
 
 	if errors.As(workflowContext.err, &canceledErr) {
 		// Workflow canceled
-		metricsScope.Counter(metrics.WorkflowCanceledCounter).Inc(1)
+		metricsHandler.Counter(metrics.WorkflowCanceledCounter).Inc(1)
 		closeCommand = createNewCommand(enumspb.COMMAND_TYPE_CANCEL_WORKFLOW_EXECUTION)
 		closeCommand.Attributes = &commandpb.Command_CancelWorkflowExecutionCommandAttributes{CancelWorkflowExecutionCommandAttributes: &commandpb.CancelWorkflowExecutionCommandAttributes{
 			Details: convertErrDetailsToPayloads(canceledErr.details, wth.dataConverter),
 		}}
 	} else if errors.As(workflowContext.err, &contErr) {
 		// Continue as new error.
-		metricsScope.Counter(metrics.WorkflowContinueAsNewCounter).Inc(1)
+		metricsHandler.Counter(metrics.WorkflowContinueAsNewCounter).Inc(1)
 		closeCommand = createNewCommand(enumspb.COMMAND_TYPE_CONTINUE_AS_NEW_WORKFLOW_EXECUTION)
 		closeCommand.Attributes = &commandpb.Command_ContinueAsNewWorkflowExecutionCommandAttributes{ContinueAsNewWorkflowExecutionCommandAttributes: &commandpb.ContinueAsNewWorkflowExecutionCommandAttributes{
 			WorkflowType:        &commonpb.WorkflowType{Name: contErr.WorkflowType.Name},
@@ -1476,7 +1478,7 @@ func (wth *workflowTaskHandlerImpl) completeWorkflow(
 		}}
 	} else if workflowContext.err != nil {
 		// Workflow failures
-		metricsScope.Counter(metrics.WorkflowFailedCounter).Inc(1)
+		metricsHandler.Counter(metrics.WorkflowFailedCounter).Inc(1)
 		closeCommand = createNewCommand(enumspb.COMMAND_TYPE_FAIL_WORKFLOW_EXECUTION)
 		failure := ConvertErrorToFailure(workflowContext.err, wth.dataConverter)
 		closeCommand.Attributes = &commandpb.Command_FailWorkflowExecutionCommandAttributes{FailWorkflowExecutionCommandAttributes: &commandpb.FailWorkflowExecutionCommandAttributes{
@@ -1484,7 +1486,7 @@ func (wth *workflowTaskHandlerImpl) completeWorkflow(
 		}}
 	} else if workflowContext.isWorkflowCompleted {
 		// Workflow completion
-		metricsScope.Counter(metrics.WorkflowCompletedCounter).Inc(1)
+		metricsHandler.Counter(metrics.WorkflowCompletedCounter).Inc(1)
 		closeCommand = createNewCommand(enumspb.COMMAND_TYPE_COMPLETE_WORKFLOW_EXECUTION)
 		closeCommand.Attributes = &commandpb.Command_CompleteWorkflowExecutionCommandAttributes{CompleteWorkflowExecutionCommandAttributes: &commandpb.CompleteWorkflowExecutionCommandAttributes{
 			Result: workflowContext.result,
@@ -1494,7 +1496,7 @@ func (wth *workflowTaskHandlerImpl) completeWorkflow(
 	if closeCommand != nil {
 		commands = append(commands, closeCommand)
 		elapsed := time.Since(workflowContext.workflowInfo.WorkflowStartTime)
-		metricsScope.Timer(metrics.WorkflowEndToEndLatency).Record(elapsed)
+		metricsHandler.Timer(metrics.WorkflowEndToEndLatency).Record(elapsed)
 		forceNewWorkflowTask = false
 	}
 
@@ -1576,7 +1578,7 @@ func newActivityTaskHandlerWithCustomProvider(
 		identity:           params.Identity,
 		service:            service,
 		logger:             params.Logger,
-		metricsScope:       params.MetricsScope,
+		metricsHandler:     params.MetricsHandler,
 		userContext:        params.UserContext,
 		registry:           registry,
 		activityProvider:   activityProvider,
@@ -1592,7 +1594,7 @@ type temporalInvoker struct {
 	sync.Mutex
 	identity            string
 	service             workflowservice.WorkflowServiceClient
-	metricsScope        tally.Scope
+	metricsHandler      metrics.Handler
 	taskToken           []byte
 	cancelHandler       func()
 	heartBeatTimeout    time.Duration // The heart beat interval configured for this activity.
@@ -1674,7 +1676,7 @@ func (i *temporalInvoker) internalHeartBeat(ctx context.Context, details *common
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	err := recordActivityHeartbeat(ctx, i.service, i.metricsScope, i.identity, i.taskToken, details)
+	err := recordActivityHeartbeat(ctx, i.service, i.metricsHandler, i.identity, i.taskToken, details)
 
 	switch err.(type) {
 	case *CanceledError:
@@ -1725,7 +1727,7 @@ func newServiceInvoker(
 	taskToken []byte,
 	identity string,
 	service workflowservice.WorkflowServiceClient,
-	metricsScope tally.Scope,
+	metricsHandler metrics.Handler,
 	cancelHandler func(),
 	heartBeatTimeout time.Duration,
 	workerStopChannel <-chan struct{},
@@ -1735,7 +1737,7 @@ func newServiceInvoker(
 		taskToken:         taskToken,
 		identity:          identity,
 		service:           service,
-		metricsScope:      metricsScope,
+		metricsHandler:    metricsHandler,
 		cancelHandler:     cancelHandler,
 		heartBeatTimeout:  heartBeatTimeout,
 		closeCh:           make(chan struct{}),
@@ -1763,13 +1765,13 @@ func (ath *activityTaskHandlerImpl) Execute(taskQueue string, t *workflowservice
 	defer cancel()
 
 	invoker := newServiceInvoker(
-		t.TaskToken, ath.identity, ath.service, ath.metricsScope, cancel, common.DurationValue(t.GetHeartbeatTimeout()),
+		t.TaskToken, ath.identity, ath.service, ath.metricsHandler, cancel, common.DurationValue(t.GetHeartbeatTimeout()),
 		ath.workerStopCh, ath.namespace)
 
 	workflowType := t.WorkflowType.GetName()
 	activityType := t.ActivityType.GetName()
-	activityMetricsScope := metrics.GetMetricsScopeForActivity(ath.metricsScope, workflowType, activityType, ath.taskQueueName)
-	ctx := WithActivityTask(canCtx, t, taskQueue, invoker, ath.logger, activityMetricsScope, ath.dataConverter, ath.workerStopCh, ath.contextPropagators, ath.tracer)
+	activityMetricsHandler := metrics.GetMetricsHandlerForActivity(ath.metricsHandler, workflowType, activityType, ath.taskQueueName)
+	ctx := WithActivityTask(canCtx, t, taskQueue, invoker, ath.logger, activityMetricsHandler, ath.dataConverter, ath.workerStopCh, ath.contextPropagators, ath.tracer)
 
 	defer func() {
 		_, activityCompleted := result.(*workflowservice.RespondActivityTaskCompletedRequest)
@@ -1780,7 +1782,7 @@ func (ath *activityTaskHandlerImpl) Execute(taskQueue string, t *workflowservice
 	if activityImplementation == nil {
 		// In case if activity is not registered we should report a failure to the server to allow activity retry
 		// instead of making it stuck on the same attempt.
-		activityMetricsScope.Counter(metrics.UnregisteredActivityInvocationCounter).Inc(1)
+		activityMetricsHandler.Counter(metrics.UnregisteredActivityInvocationCounter).Inc(1)
 		return convertActivityResultToRespondRequest(ath.identity, t.TaskToken, nil,
 			NewActivityNotRegisteredError(activityType, ath.getRegisteredActivityNames()),
 			ath.dataConverter, ath.namespace), nil
@@ -1798,7 +1800,7 @@ func (ath *activityTaskHandlerImpl) Execute(taskQueue string, t *workflowservice
 				tagAttempt, t.Attempt,
 				tagPanicError, fmt.Sprintf("%v", p),
 				tagPanicStack, st)
-			activityMetricsScope.Counter(metrics.ActivityTaskErrorCounter).Inc(1)
+			activityMetricsHandler.Counter(metrics.ActivityTaskErrorCounter).Inc(1)
 			panicErr := newPanicError(p, st)
 			result = convertActivityResultToRespondRequest(ath.identity, t.TaskToken, nil, panicErr,
 				ath.dataConverter, ath.namespace)
@@ -1817,6 +1819,13 @@ func (ath *activityTaskHandlerImpl) Execute(taskQueue string, t *workflowservice
 	ctx, dlCancelFunc := context.WithDeadline(ctx, info.deadline)
 	defer dlCancelFunc()
 
+	envInterceptor, err := newActivityInterceptors(info, ath.registry.ActivityInterceptors())
+	if err != nil {
+		return convertActivityResultToRespondRequest(ath.identity, t.TaskToken, nil, err,
+			ath.dataConverter, ath.namespace), nil
+	}
+	info.interceptor = envInterceptor
+
 	ctx, span := createOpenTracingActivitySpan(ctx, ath.tracer, time.Now(), activityType, t.WorkflowExecution.GetWorkflowId(), t.WorkflowExecution.GetRunId())
 	defer span.Finish()
 	output, err := activityImplementation.Execute(ctx, t.Input)
@@ -1871,7 +1880,7 @@ func createNewCommand(commandType enumspb.CommandType) *commandpb.Command {
 	}
 }
 
-func recordActivityHeartbeat(ctx context.Context, service workflowservice.WorkflowServiceClient, metricsScope tally.Scope,
+func recordActivityHeartbeat(ctx context.Context, service workflowservice.WorkflowServiceClient, metricsHandler metrics.Handler,
 	identity string, taskToken []byte, details *commonpb.Payloads) error {
 	namespace := getNamespaceFromActivityCtx(ctx)
 	request := &workflowservice.RecordActivityTaskHeartbeatRequest{
@@ -1883,7 +1892,7 @@ func recordActivityHeartbeat(ctx context.Context, service workflowservice.Workfl
 
 	var heartbeatResponse *workflowservice.RecordActivityTaskHeartbeatResponse
 	grpcCtx, cancel := newGRPCContext(ctx,
-		grpcMetricsScope(metricsScope),
+		grpcMetricsHandler(metricsHandler),
 		defaultGrpcRetryParameters(ctx))
 	defer cancel()
 
@@ -1894,7 +1903,7 @@ func recordActivityHeartbeat(ctx context.Context, service workflowservice.Workfl
 	return err
 }
 
-func recordActivityHeartbeatByID(ctx context.Context, service workflowservice.WorkflowServiceClient, metricsScope tally.Scope,
+func recordActivityHeartbeatByID(ctx context.Context, service workflowservice.WorkflowServiceClient, metricsHandler metrics.Handler,
 	identity, namespace, workflowID, runID, activityID string, details *commonpb.Payloads) error {
 	request := &workflowservice.RecordActivityTaskHeartbeatByIdRequest{
 		Namespace:  namespace,
@@ -1906,7 +1915,7 @@ func recordActivityHeartbeatByID(ctx context.Context, service workflowservice.Wo
 
 	var heartbeatResponse *workflowservice.RecordActivityTaskHeartbeatByIdResponse
 	grpcCtx, cancel := newGRPCContext(ctx,
-		grpcMetricsScope(metricsScope),
+		grpcMetricsHandler(metricsHandler),
 		defaultGrpcRetryParameters(ctx))
 	defer cancel()
 
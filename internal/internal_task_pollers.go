@@ -36,7 +36,6 @@ import (
 	"github.com/gogo/protobuf/types"
 	"github.com/opentracing/opentracing-go"
 	"github.com/pborman/uuid"
-	"github.com/uber-go/tally"
 	commonpb "go.temporal.io/api/common/v1"
 	enumspb "go.temporal.io/api/enums/v1"
 	historypb "go.temporal.io/api/history/v1"
@@ -71,8 +70,8 @@ type (
 
 	// basePoller is the base class for all poller implementations
 	basePoller struct {
-		metricsScope tally.Scope // base metric scope used for rpc calls
-		stopC        <-chan struct{}
+		metricsHandler metrics.Handler // base metrics handler used for rpc calls
+		stopC          <-chan struct{}
 	}
 
 	// workflowTaskPoller implements polling/processing a workflow task
@@ -109,14 +108,14 @@ type (
 	}
 
 	historyIteratorImpl struct {
-		iteratorFunc  func(nextPageToken []byte) (*historypb.History, []byte, error)
-		execution     *commonpb.WorkflowExecution
-		nextPageToken []byte
-		namespace     string
-		service       workflowservice.WorkflowServiceClient
-		maxEventID    int64
-		metricsScope  tally.Scope
-		taskQueue     string
+		iteratorFunc   func(nextPageToken []byte) (*historypb.History, []byte, error)
+		execution      *commonpb.WorkflowExecution
+		nextPageToken  []byte
+		namespace      string
+		service        workflowservice.WorkflowServiceClient
+		maxEventID     int64
+		metricsHandler metrics.Handler
+		taskQueue      string
 	}
 
 	localActivityTaskPoller struct {
@@ -128,7 +127,7 @@ type (
 
 	localActivityTaskHandler struct {
 		userContext        context.Context
-		metricsScope       tally.Scope
+		metricsHandler     metrics.Handler
 		logger             log.Logger
 		dataConverter      converter.DataConverter
 		contextPropagators []ContextPropagator
@@ -221,7 +220,7 @@ func (bp *basePoller) doPoll(pollFunc func(ctx context.Context) (interface{}, er
 // newWorkflowTaskPoller creates a new workflow task poller which must have a one to one relationship to workflow worker
 func newWorkflowTaskPoller(taskHandler WorkflowTaskHandler, service workflowservice.WorkflowServiceClient, params workerExecutionParameters) *workflowTaskPoller {
 	return &workflowTaskPoller{
-		basePoller:                   basePoller{metricsScope: params.MetricsScope, stopC: params.WorkerStopChannel},
+		basePoller:                   basePoller{metricsHandler: params.MetricsHandler, stopC: params.WorkerStopChannel},
 		service:                      service,
 		namespace:                    params.Namespace,
 		taskQueueName:                params.TaskQueue,
@@ -325,7 +324,7 @@ func (wtp *workflowTaskPoller) processResetStickinessTask(rst *resetStickinessTa
 	grpcCtx, cancel := newGRPCContext(context.Background())
 	defer cancel()
 	// WorkflowType information is not available on reset sticky task.  Emit using base scope.
-	wtp.metricsScope.Counter(metrics.StickyCacheTotalForcedEviction).Inc(1)
+	wtp.metricsHandler.Counter(metrics.StickyCacheTotalForcedEviction).Inc(1)
 	if _, err := wtp.service.ResetStickyTaskQueue(grpcCtx, rst.task); err != nil {
 		wtp.logger.Warn("ResetStickyTaskQueue failed",
 			tagWorkflowID, rst.task.Execution.GetWorkflowId(),
@@ -344,9 +343,9 @@ func (wtp *workflowTaskPoller) RespondTaskCompletedWithMetrics(
 	startTime time.Time,
 ) (response *workflowservice.RespondWorkflowTaskCompletedResponse, err error) {
 
-	workflowMetricsScope := metrics.GetMetricsScopeForWorkflow(wtp.metricsScope, task.WorkflowType.GetName())
+	workflowMetricsHandler := metrics.GetMetricsHandlerForWorkflow(wtp.metricsHandler, task.WorkflowType.GetName())
 	if taskErr != nil {
-		workflowMetricsScope.Counter(metrics.WorkflowTaskExecutionFailureCounter).Inc(1)
+		workflowMetricsHandler.Counter(metrics.WorkflowTaskExecutionFailureCounter).Inc(1)
 		wtp.logger.Warn("Failed to process workflow task.",
 			tagWorkflowType, task.WorkflowType.GetName(),
 			tagWorkflowID, task.WorkflowExecution.GetWorkflowId(),
@@ -357,7 +356,7 @@ func (wtp *workflowTaskPoller) RespondTaskCompletedWithMetrics(
 		completedRequest = errorToFailWorkflowTask(task.TaskToken, taskErr, wtp.identity, wtp.dataConverter, wtp.namespace)
 	}
 
-	workflowMetricsScope.Timer(metrics.WorkflowTaskExecutionLatency).Record(time.Since(startTime))
+	workflowMetricsHandler.Timer(metrics.WorkflowTaskExecutionLatency).Record(time.Since(startTime))
 
 	response, err = wtp.RespondTaskCompleted(completedRequest, task)
 	return
@@ -366,8 +365,8 @@ func (wtp *workflowTaskPoller) RespondTaskCompletedWithMetrics(
 func (wtp *workflowTaskPoller) RespondTaskCompleted(completedRequest interface{}, task *workflowservice.PollWorkflowTaskQueueResponse) (response *workflowservice.RespondWorkflowTaskCompletedResponse, err error) {
 	ctx := context.Background()
 	// Respond task completion.
-	grpcCtx, cancel := newGRPCContext(ctx, grpcMetricsScope(
-		metrics.GetMetricsScopeForRPC(wtp.metricsScope, task.GetWorkflowType().GetName(),
+	grpcCtx, cancel := newGRPCContext(ctx, grpcMetricsHandler(
+		metrics.GetMetricsHandlerForRPC(wtp.metricsHandler, task.GetWorkflowType().GetName(),
 			metrics.NoneTagValue, metrics.NoneTagValue)),
 		defaultGrpcRetryParameters(ctx))
 	defer cancel()
@@ -418,14 +417,14 @@ func (wtp *workflowTaskPoller) RespondTaskCompleted(completedRequest interface{}
 func newLocalActivityPoller(params workerExecutionParameters, laTunnel *localActivityTunnel) *localActivityTaskPoller {
 	handler := &localActivityTaskHandler{
 		userContext:        params.UserContext,
-		metricsScope:       params.MetricsScope,
+		metricsHandler:     params.MetricsHandler,
 		logger:             params.Logger,
 		dataConverter:      params.DataConverter,
 		contextPropagators: params.ContextPropagators,
 		tracer:             params.Tracer,
 	}
 	return &localActivityTaskPoller{
-		basePoller: basePoller{metricsScope: params.MetricsScope, stopC: params.WorkerStopChannel},
+		basePoller: basePoller{metricsHandler: params.MetricsHandler, stopC: params.WorkerStopChannel},
 		handler:    handler,
 		logger:     params.Logger,
 		laTunnel:   laTunnel,
@@ -458,9 +457,9 @@ func (latp *localActivityTaskPoller) ProcessTask(task interface{}) error {
 func (lath *localActivityTaskHandler) executeLocalActivityTask(task *localActivityTask) (result *localActivityResult) {
 	workflowType := task.params.WorkflowInfo.WorkflowType.Name
 	activityType := task.params.ActivityType
-	activityMetricsScope := metrics.GetMetricsScopeForLocalActivity(lath.metricsScope, workflowType, activityType)
+	activityMetricsHandler := metrics.GetMetricsHandlerForLocalActivity(lath.metricsHandler, workflowType, activityType)
 
-	activityMetricsScope.Counter(metrics.LocalActivityTotalCounter).Inc(1)
+	activityMetricsHandler.Counter(metrics.LocalActivityTotalCounter).Inc(1)
 
 	ae := activityExecutor{name: activityType, fn: task.params.ActivityFn}
 
@@ -470,6 +469,14 @@ func (lath *localActivityTaskHandler) executeLocalActivityTask(task *localActivi
 	}
 
 	workflowTypeLocal := task.params.WorkflowInfo.WorkflowType
+	logger := log.With(lath.logger,
+		tagActivityID, task.activityID,
+		tagActivityType, activityType,
+		tagAttempt, task.attempt,
+		tagWorkflowType, workflowType,
+		tagWorkflowID, task.params.WorkflowInfo.WorkflowExecution.ID,
+		tagRunID, task.params.WorkflowInfo.WorkflowExecution.RunID,
+	)
 
 	ctx := context.WithValue(rootCtx, activityEnvContextKey, &activityEnvironment{
 		workflowType:      &workflowTypeLocal,
@@ -478,8 +485,8 @@ func (lath *localActivityTaskHandler) executeLocalActivityTask(task *localActivi
 		activityType:      ActivityType{Name: activityType},
 		activityID:        fmt.Sprintf("%v", task.activityID),
 		workflowExecution: task.params.WorkflowInfo.WorkflowExecution,
-		logger:            lath.logger,
-		metricsScope:      lath.metricsScope, // Use base scope to make sure down stream callers does not have unexpected tags
+		logger:            logger,
+		metricsHandler:    lath.metricsHandler, // Use base handler to make sure down stream callers does not have unexpected tags
 		isLocalActivity:   true,
 		dataConverter:     lath.dataConverter,
 		attempt:           task.attempt,
@@ -510,7 +517,7 @@ func (lath *localActivityTaskHandler) executeLocalActivityTask(task *localActivi
 				tagAttempt, task.attempt,
 				tagPanicError, fmt.Sprintf("%v", p),
 				tagPanicStack, st)
-			activityMetricsScope.Counter(metrics.LocalActivityErrorCounter).Inc(1)
+			activityMetricsHandler.Counter(metrics.LocalActivityErrorCounter).Inc(1)
 			panicErr := newPanicError(p, st)
 			result = &localActivityResult{
 				task:   task,
@@ -519,7 +526,7 @@ func (lath *localActivityTaskHandler) executeLocalActivityTask(task *localActivi
 			}
 		}
 		if result.err != nil {
-			activityMetricsScope.Counter(metrics.LocalActivityFailedCounter).Inc(1)
+			activityMetricsHandler.Counter(metrics.LocalActivityFailedCounter).Inc(1)
 		}
 	}()
 
@@ -555,7 +562,7 @@ func (lath *localActivityTaskHandler) executeLocalActivityTask(task *localActivi
 		laResult, err = ae.ExecuteWithActualArgs(ctx, task.params.InputArgs)
 		executionLatency := time.Since(laStartTime)
 		close(ch)
-		activityMetricsScope.Timer(metrics.LocalActivityExecutionLatency).Record(executionLatency)
+		activityMetricsHandler.Timer(metrics.LocalActivityExecutionLatency).Record(executionLatency)
 		if executionLatency > timeoutDuration {
 			// If local activity takes longer than expected timeout, the context would already be DeadlineExceeded and
 			// the result would be discarded. Print a warning in this case.
@@ -579,7 +586,7 @@ WaitResult:
 
 		// context is done
 		if ctx.Err() == context.Canceled {
-			activityMetricsScope.Counter(metrics.LocalActivityCanceledCounter).Inc(1)
+			activityMetricsHandler.Counter(metrics.LocalActivityCanceledCounter).Inc(1)
 			return &localActivityResult{err: ErrCanceled, task: task}
 		} else if ctx.Err() == context.DeadlineExceeded {
 			return &localActivityResult{err: ErrDeadlineExceeded, task: task}
@@ -669,7 +676,7 @@ func (wtp *workflowTaskPoller) poll(ctx context.Context) (interface{}, error) {
 
 	if response == nil || len(response.TaskToken) == 0 {
 		// Emit using base scope as no workflow type information is available in the case of empty poll
-		wtp.metricsScope.Counter(metrics.WorkflowTaskQueuePollEmptyCounter).Inc(1)
+		wtp.metricsHandler.Counter(metrics.WorkflowTaskQueuePollEmptyCounter).Inc(1)
 		wtp.updateBacklog(request.TaskQueue.GetKind(), 0)
 		return &workflowTask{}, nil
 	}
@@ -689,23 +696,23 @@ func (wtp *workflowTaskPoller) poll(ctx context.Context) (interface{}, error) {
 			"IsQueryTask", response.Query != nil)
 	})
 
-	workflowMetricsScope := metrics.GetMetricsScopeForWorkflow(wtp.metricsScope, response.WorkflowType.GetName())
-	workflowMetricsScope.Counter(metrics.WorkflowTaskQueuePollSucceedCounter).Inc(1)
+	workflowMetricsHandler := metrics.GetMetricsHandlerForWorkflow(wtp.metricsHandler, response.WorkflowType.GetName())
+	workflowMetricsHandler.Counter(metrics.WorkflowTaskQueuePollSucceedCounter).Inc(1)
 
 	scheduleToStartLatency := common.TimeValue(response.GetStartedTime()).Sub(common.TimeValue(response.GetScheduledTime()))
-	workflowMetricsScope.Timer(metrics.WorkflowTaskScheduleToStartLatency).Record(scheduleToStartLatency)
+	workflowMetricsHandler.Timer(metrics.WorkflowTaskScheduleToStartLatency).Record(scheduleToStartLatency)
 	return task, nil
 }
 
 func (wtp *workflowTaskPoller) toWorkflowTask(response *workflowservice.PollWorkflowTaskQueueResponse) *workflowTask {
 	historyIterator := &historyIteratorImpl{
-		execution:     response.WorkflowExecution,
-		nextPageToken: response.NextPageToken,
-		namespace:     wtp.namespace,
-		service:       wtp.service,
-		maxEventID:    response.GetStartedEventId(),
-		metricsScope:  wtp.metricsScope,
-		taskQueue:     wtp.taskQueueName,
+		execution:      response.WorkflowExecution,
+		nextPageToken:  response.NextPageToken,
+		namespace:      wtp.namespace,
+		service:        wtp.service,
+		maxEventID:     response.GetStartedEventId(),
+		metricsHandler: wtp.metricsHandler,
+		taskQueue:      wtp.taskQueueName,
 	}
 	task := &workflowTask{
 		task:            response,
@@ -722,7 +729,7 @@ func (h *historyIteratorImpl) GetNextPage() (*historypb.History, error) {
 			h.namespace,
 			h.execution,
 			h.maxEventID,
-			h.metricsScope,
+			h.metricsHandler,
 			h.taskQueue,
 		)
 	}
@@ -749,13 +756,13 @@ func newGetHistoryPageFunc(
 	namespace string,
 	execution *commonpb.WorkflowExecution,
 	atWorkflowTaskCompletedEventID int64,
-	metricsScope tally.Scope,
+	metricsHandler metrics.Handler,
 	taskQueue string,
 ) func(nextPageToken []byte) (*historypb.History, []byte, error) {
 	return func(nextPageToken []byte) (*historypb.History, []byte, error) {
 		var resp *workflowservice.GetWorkflowExecutionHistoryResponse
-		grpcCtx, cancel := newGRPCContext(ctx, grpcMetricsScope(
-			metrics.GetMetricsScopeForRPC(metricsScope, metrics.NoneTagValue, metrics.NoneTagValue, taskQueue)),
+		grpcCtx, cancel := newGRPCContext(ctx, grpcMetricsHandler(
+			metrics.GetMetricsHandlerForRPC(metricsHandler, metrics.NoneTagValue, metrics.NoneTagValue, taskQueue)),
 			defaultGrpcRetryParameters(ctx))
 		defer cancel()
 
@@ -796,7 +803,7 @@ func newGetHistoryPageFunc(
 
 func newActivityTaskPoller(taskHandler ActivityTaskHandler, service workflowservice.WorkflowServiceClient, params workerExecutionParameters) *activityTaskPoller {
 	return &activityTaskPoller{
-		basePoller:          basePoller{metricsScope: params.MetricsScope, stopC: params.WorkerStopChannel},
+		basePoller:          basePoller{metricsHandler: params.MetricsHandler, stopC: params.WorkerStopChannel},
 		taskHandler:         taskHandler,
 		service:             service,
 		namespace:           params.Namespace,
@@ -827,16 +834,16 @@ func (atp *activityTaskPoller) poll(ctx context.Context) (interface{}, error) {
 	}
 	if response == nil || len(response.TaskToken) == 0 {
 		// No activity info is available on empty poll.  Emit using base scope.
-		atp.metricsScope.Counter(metrics.ActivityPollNoTaskCounter).Inc(1)
+		atp.metricsHandler.Counter(metrics.ActivityPollNoTaskCounter).Inc(1)
 		return &activityTask{}, nil
 	}
 
 	workflowType := response.WorkflowType.GetName()
 	activityType := response.ActivityType.GetName()
-	activityMetricsScope := metrics.GetMetricsScopeForActivity(atp.metricsScope, workflowType, activityType, atp.taskQueueName)
+	activityMetricsHandler := metrics.GetMetricsHandlerForActivity(atp.metricsHandler, workflowType, activityType, atp.taskQueueName)
 
 	scheduleToStartLatency := common.TimeValue(response.GetStartedTime()).Sub(common.TimeValue(response.GetCurrentAttemptScheduledTime()))
-	activityMetricsScope.Timer(metrics.ActivityScheduleToStartLatency).Record(scheduleToStartLatency)
+	activityMetricsHandler.Timer(metrics.ActivityScheduleToStartLatency).Record(scheduleToStartLatency)
 
 	return &activityTask{task: response, pollStartTime: startTime}, nil
 }
@@ -868,21 +875,21 @@ func (atp *activityTaskPoller) ProcessTask(task interface{}) error {
 
 	workflowType := activityTask.task.WorkflowType.GetName()
 	activityType := activityTask.task.ActivityType.GetName()
-	activityMetricsScope := metrics.GetMetricsScopeForActivity(atp.metricsScope, workflowType, activityType, atp.taskQueueName)
+	activityMetricsHandler := metrics.GetMetricsHandlerForActivity(atp.metricsHandler, workflowType, activityType, atp.taskQueueName)
 
 	executionStartTime := time.Now()
 	// Process the activity task.
 	request, err := atp.taskHandler.Execute(atp.taskQueueName, activityTask.task)
 	// err is returned in case of internal failure, such as unable to propagate context or context timeout.
 	if err != nil {
-		activityMetricsScope.Counter(metrics.ActivityExecutionFailedCounter).Inc(1)
+		activityMetricsHandler.Counter(metrics.ActivityExecutionFailedCounter).Inc(1)
 		return err
 	}
 	// in case if activity execution failed, request should be of type RespondActivityTaskFailedRequest
 	if _, ok := request.(*workflowservice.RespondActivityTaskFailedRequest); ok {
-		activityMetricsScope.Counter(metrics.ActivityExecutionFailedCounter).Inc(1)
+		activityMetricsHandler.Counter(metrics.ActivityExecutionFailedCounter).Inc(1)
 	}
-	activityMetricsScope.Timer(metrics.ActivityExecutionLatency).Record(time.Since(executionStartTime))
+	activityMetricsHandler.Timer(metrics.ActivityExecutionLatency).Record(time.Since(executionStartTime))
 
 	if request == ErrActivityResultPending {
 		return nil
@@ -893,8 +900,8 @@ func (atp *activityTaskPoller) ProcessTask(task interface{}) error {
 		return errStop
 	}
 
-	rpcScope := metrics.GetMetricsScopeForRPC(atp.metricsScope, workflowType, activityType, metrics.NoneTagValue)
-	reportErr := reportActivityComplete(context.Background(), atp.service, request, rpcScope)
+	rpcMetricsHandler := metrics.GetMetricsHandlerForRPC(atp.metricsHandler, workflowType, activityType, metrics.NoneTagValue)
+	reportErr := reportActivityComplete(context.Background(), atp.service, request, rpcMetricsHandler)
 	if reportErr != nil {
 		traceLog(func() {
 			atp.logger.Debug("reportActivityComplete failed", tagError, reportErr)
@@ -902,13 +909,13 @@ func (atp *activityTaskPoller) ProcessTask(task interface{}) error {
 		return reportErr
 	}
 
-	activityMetricsScope.
+	activityMetricsHandler.
 		Timer(metrics.ActivityEndToEndLatency).
 		Record(time.Since(common.TimeValue(activityTask.task.GetStartedTime())))
 	return nil
 }
 
-func reportActivityComplete(ctx context.Context, service workflowservice.WorkflowServiceClient, request interface{}, rpcScope tally.Scope) error {
+func reportActivityComplete(ctx context.Context, service workflowservice.WorkflowServiceClient, request interface{}, rpcMetricsHandler metrics.Handler) error {
 	if request == nil {
 		// nothing to report
 		return nil
@@ -917,18 +924,18 @@ func reportActivityComplete(ctx context.Context, service workflowservice.Workflo
 	var reportErr error
 	switch request := request.(type) {
 	case *workflowservice.RespondActivityTaskCanceledRequest:
-		grpcCtx, cancel := newGRPCContext(ctx, grpcMetricsScope(rpcScope),
+		grpcCtx, cancel := newGRPCContext(ctx, grpcMetricsHandler(rpcMetricsHandler),
 			defaultGrpcRetryParameters(ctx))
 		defer cancel()
 		_, err := service.RespondActivityTaskCanceled(grpcCtx, request)
 		reportErr = err
 	case *workflowservice.RespondActivityTaskFailedRequest:
-		grpcCtx, cancel := newGRPCContext(ctx, grpcMetricsScope(rpcScope), defaultGrpcRetryParameters(ctx))
+		grpcCtx, cancel := newGRPCContext(ctx, grpcMetricsHandler(rpcMetricsHandler), defaultGrpcRetryParameters(ctx))
 		defer cancel()
 		_, err := service.RespondActivityTaskFailed(grpcCtx, request)
 		reportErr = err
 	case *workflowservice.RespondActivityTaskCompletedRequest:
-		grpcCtx, cancel := newGRPCContext(ctx, grpcMetricsScope(rpcScope),
+		grpcCtx, cancel := newGRPCContext(ctx, grpcMetricsHandler(rpcMetricsHandler),
 			defaultGrpcRetryParameters(ctx))
 		defer cancel()
 		_, err := service.RespondActivityTaskCompleted(grpcCtx, request)
@@ -937,7 +944,7 @@ func reportActivityComplete(ctx context.Context, service workflowservice.Workflo
 	return reportErr
 }
 
-func reportActivityCompleteByID(ctx context.Context, service workflowservice.WorkflowServiceClient, request interface{}, rpcScope tally.Scope) error {
+func reportActivityCompleteByID(ctx context.Context, service workflowservice.WorkflowServiceClient, request interface{}, rpcMetricsHandler metrics.Handler) error {
 	if request == nil {
 		// nothing to report
 		return nil
@@ -946,19 +953,19 @@ func reportActivityCompleteByID(ctx context.Context, service workflowservice.Wor
 	var reportErr error
 	switch request := request.(type) {
 	case *workflowservice.RespondActivityTaskCanceledByIdRequest:
-		grpcCtx, cancel := newGRPCContext(ctx, grpcMetricsScope(rpcScope),
+		grpcCtx, cancel := newGRPCContext(ctx, grpcMetricsHandler(rpcMetricsHandler),
 			defaultGrpcRetryParameters(ctx))
 		defer cancel()
 		_, err := service.RespondActivityTaskCanceledById(grpcCtx, request)
 		reportErr = err
 	case *workflowservice.RespondActivityTaskFailedByIdRequest:
-		grpcCtx, cancel := newGRPCContext(ctx, grpcMetricsScope(rpcScope),
+		grpcCtx, cancel := newGRPCContext(ctx, grpcMetricsHandler(rpcMetricsHandler),
 			defaultGrpcRetryParameters(ctx))
 		defer cancel()
 		_, err := service.RespondActivityTaskFailedById(grpcCtx, request)
 		reportErr = err
 	case *workflowservice.RespondActivityTaskCompletedByIdRequest:
-		grpcCtx, cancel := newGRPCContext(ctx, grpcMetricsScope(rpcScope),
+		grpcCtx, cancel := newGRPCContext(ctx, grpcMetricsHandler(rpcMetricsHandler),
 			defaultGrpcRetryParameters(ctx))
 		defer cancel()
 		_, err := service.RespondActivityTaskCompletedById(grpcCtx, request)
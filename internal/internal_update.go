@@ -0,0 +1,368 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/pborman/uuid"
+	commonpb "go.temporal.io/api/common/v1"
+	enumspb "go.temporal.io/api/enums/v1"
+	failurepb "go.temporal.io/api/failure/v1"
+
+	"go.temporal.io/sdk/converter"
+)
+
+const (
+	// updateSignalName is the signal which carries update requests sent by Client.UpdateWorkflow.
+	updateSignalName = "__update_request"
+
+	// updateResultQueryType is the built in query Client.UpdateWorkflow uses to get the update result.
+	updateResultQueryType = "__update_result"
+
+	// updateResultsLimit is the number of results of completed updates the workflow keeps. The results of older updates
+	// are dropped, so a long running workflow which handles many updates doesn't keep all of them in memory.
+	updateResultsLimit = 1000
+
+	updatePollInitialInterval = 50 * time.Millisecond
+	updatePollMaximumInterval = time.Second
+)
+
+type (
+	// updateRequest is the argument of the update signal.
+	updateRequest struct {
+		ID   string
+		Name string
+		Args *commonpb.Payloads
+	}
+
+	// updateResult is the result of the update result query. Failure is the serialized failurepb.Failure as the
+	// failure proto can't be encoded with JSON.
+	updateResult struct {
+		Completed bool
+		Result    *commonpb.Payloads
+		Failure   []byte
+	}
+
+	// updateRegistry keeps update handlers and results of the updates received by the workflow. Only the last
+	// updateResultsLimit results of completed updates are kept, completed holds their IDs in completion order.
+	updateRegistry struct {
+		handlers  map[string]interface{}
+		results   map[string]*updateResult
+		completed []string
+	}
+
+	// UpdateWorkflowWithOptionsRequest is the request to UpdateWorkflowWithOptions
+	UpdateWorkflowWithOptionsRequest struct {
+		// UpdateID is an optional field identifying the update. The workflow applies an update with the same ID only
+		// once, so a call which is retried with the same UpdateID returns the result of the first one instead of
+		// running the update handler again. The ID is only remembered while the result of the update is kept, see
+		// Client.UpdateWorkflow.
+		// If UpdateID is not provided a random one is used.
+		UpdateID string
+
+		// WorkflowID is a required field indicating the workflow which should be updated.
+		WorkflowID string
+
+		// RunID is an optional field used to identify a specific run of the updated workflow.
+		// If RunID is not provided the latest run will be used.
+		RunID string
+
+		// UpdateName is a required field which specifies the name the update handler is registered with.
+		UpdateName string
+
+		// Args is an optional field used to identify the arguments passed to the update handler.
+		Args []interface{}
+	}
+)
+
+func newUpdateRegistry() *updateRegistry {
+	return &updateRegistry{
+		handlers: make(map[string]interface{}),
+		results:  make(map[string]*updateResult),
+	}
+}
+
+// SetUpdateHandler registers the handler of updates with the given name sent by Client.UpdateWorkflow. See
+// workflow.SetUpdateHandler for the details.
+func SetUpdateHandler(ctx Context, updateName string, handler interface{}) error {
+	if err := validateUpdateHandlerFn(handler); err != nil {
+		return err
+	}
+	getWorkflowEnvOptions(ctx).updates.handlers[updateName] = handler
+	return nil
+}
+
+func validateUpdateHandlerFn(handler interface{}) error {
+	fnType := reflect.TypeOf(handler)
+	if fnType == nil || fnType.Kind() != reflect.Func {
+		return fmt.Errorf("update handler must be function but was %v", fnType)
+	}
+	if fnType.NumIn() == 0 || !isWorkflowContext(fnType.In(0)) {
+		return fmt.Errorf("first argument of update handler must be workflow.Context")
+	}
+	switch fnType.NumOut() {
+	case 1:
+	case 2:
+		if !isValidResultType(fnType.Out(0)) {
+			return fmt.Errorf("first return value of update handler must be serializable but found: %v", fnType.Out(0).Kind())
+		}
+	default:
+		return fmt.Errorf(
+			"update handler must return error or serializable result and error, but found %d return values", fnType.NumOut(),
+		)
+	}
+	if !isError(fnType.Out(fnType.NumOut() - 1)) {
+		return fmt.Errorf("last return value of update handler must be error but found %v", fnType.Out(fnType.NumOut()-1).Kind())
+	}
+	return nil
+}
+
+// dispatch receives update requests and runs the handler of each request in its own coroutine, so a long running
+// update doesn't block the other ones. It is started with the workflow and runs after the workflow code blocks for the
+// first time, so an update which has no handler registered by then is completed with an error instead of waiting for
+// a handler which might never be registered.
+func (r *updateRegistry) dispatch(ctx Context) {
+	ch := GetSignalChannel(ctx, updateSignalName)
+	for {
+		// The request is decoded here instead of by Receive, which drops a request it can't decode. The update of
+		// such request is completed with an error, so the caller doesn't wait for it forever.
+		var input rawPayloads
+		if more := ch.Receive(ctx, &input); !more {
+			return
+		}
+		request, err := r.decodeRequest(ctx, input.payloads)
+		if request.ID == "" {
+			GetLogger(ctx).Warn("Dropped malformed update request.", tagError, err)
+			continue
+		}
+		if _, ok := r.results[request.ID]; ok {
+			// The request was delivered again by a retried signal.
+			continue
+		}
+		result := &updateResult{}
+		r.results[request.ID] = result
+		if err != nil {
+			r.complete(ctx, request.ID, result, nil, err)
+			continue
+		}
+		handler, ok := r.handlers[request.Name]
+		if !ok {
+			r.complete(ctx, request.ID, result, nil, fmt.Errorf("unknown update %v. KnownUpdates=%v", request.Name, r.handlerNames()))
+			continue
+		}
+		GoNamed(ctx, fmt.Sprintf("update-%v", request.ID), func(ctx Context) {
+			value, err := r.execute(ctx, handler, request.Args)
+			r.complete(ctx, request.ID, result, value, err)
+		})
+	}
+}
+
+// decodeRequest decodes the update request. If the request can't be decoded, its ID is still decoded if possible,
+// so the update can be completed with the error.
+func (r *updateRegistry) decodeRequest(ctx Context, input *commonpb.Payloads) (updateRequest, error) {
+	dc := getDataConverterFromWorkflowContext(ctx)
+	var request updateRequest
+	err := decodeArg(dc, input, &request)
+	if err == nil {
+		if request.ID == "" {
+			err = errors.New("update request has no ID")
+		}
+		return request, err
+	}
+	var id struct{ ID string }
+	if decodeArg(dc, input, &id) == nil {
+		request = updateRequest{ID: id.ID}
+	}
+	return request, fmt.Errorf("unable to decode the update request: %w", err)
+}
+
+func (r *updateRegistry) execute(ctx Context, handler interface{}, input *commonpb.Payloads) (interface{}, error) {
+	fnType := reflect.TypeOf(handler)
+	decoded, err := decodeArgs(getDataConverterFromWorkflowContext(ctx), fnType, input)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode the update input: %w", err)
+	}
+	args := append([]reflect.Value{reflect.ValueOf(ctx)}, decoded...)
+	retValues := reflect.ValueOf(handler).Call(args)
+
+	var value interface{}
+	if len(retValues) == 2 {
+		value = retValues[0].Interface()
+	}
+	if errValue := retValues[len(retValues)-1]; !errValue.IsNil() {
+		return nil, errValue.Interface().(error)
+	}
+	return value, nil
+}
+
+func (r *updateRegistry) complete(ctx Context, id string, result *updateResult, value interface{}, err error) {
+	dc := getDataConverterFromWorkflowContext(ctx)
+	if err == nil && value != nil {
+		result.Result, err = encodeArg(dc, value)
+	}
+	if err != nil {
+		result.Result = nil
+		result.Failure, err = ConvertErrorToFailure(err, dc).Marshal()
+		if err != nil {
+			panic(err)
+		}
+	}
+	result.Completed = true
+
+	r.completed = append(r.completed, id)
+	if len(r.completed) > updateResultsLimit {
+		delete(r.results, r.completed[0])
+		r.completed = r.completed[1:]
+	}
+}
+
+func (r *updateRegistry) handlerNames() []string {
+	var names []string
+	for name := range r.handlers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// queryResult handles the update result query. The result of an unknown update is reported as not completed as its
+// request might not be processed yet.
+func (r *updateRegistry) queryResult(ctx Context, args *commonpb.Payloads) (*commonpb.Payloads, error) {
+	dc := getDataConverterFromWorkflowContext(ctx)
+	var id string
+	if err := decodeArg(dc, args, &id); err != nil {
+		return nil, fmt.Errorf("unable to decode the update id: %w", err)
+	}
+	result, ok := r.results[id]
+	if !ok {
+		result = &updateResult{}
+	}
+	return encodeArg(dc, result)
+}
+
+// UpdateWorkflow sends the update request to the workflow execution and waits for its result.
+func (wc *WorkflowClient) UpdateWorkflow(ctx context.Context, workflowID string, runID string, updateName string, args ...interface{}) (converter.EncodedValue, error) {
+	return wc.UpdateWorkflowWithOptions(ctx, &UpdateWorkflowWithOptionsRequest{
+		WorkflowID: workflowID,
+		RunID:      runID,
+		UpdateName: updateName,
+		Args:       args,
+	})
+}
+
+// UpdateWorkflowWithOptions sends the update request to the workflow execution and waits for its result.
+func (wc *WorkflowClient) UpdateWorkflowWithOptions(ctx context.Context, request *UpdateWorkflowWithOptionsRequest) (converter.EncodedValue, error) {
+	return wc.getInterceptor().UpdateWorkflowWithOptions(ctx, request)
+}
+
+func (w *workflowClientInterceptor) UpdateWorkflowWithOptions(ctx context.Context, request *UpdateWorkflowWithOptionsRequest) (converter.EncodedValue, error) {
+	input, err := encodeArgs(w.client.dataConverter, request.Args)
+	if err != nil {
+		return nil, err
+	}
+	workflowID, runID := request.WorkflowID, request.RunID
+	if runID == "" {
+		// The result has to be queried from the run which receives the request.
+		response, err := w.client.describeWorkflowExecution(ctx, workflowID, "")
+		if err != nil {
+			return nil, err
+		}
+		runID = response.GetWorkflowExecutionInfo().GetExecution().GetRunId()
+	}
+	updateID := request.UpdateID
+	if updateID == "" {
+		updateID = uuid.New()
+	}
+	signalArg := updateRequest{ID: updateID, Name: request.UpdateName, Args: input}
+	if err := w.SignalWorkflow(ctx, workflowID, runID, updateSignalName, signalArg); err != nil {
+		return nil, err
+	}
+	return w.pollUpdateResult(ctx, workflowID, runID, updateID)
+}
+
+func (w *workflowClientInterceptor) pollUpdateResult(ctx context.Context, workflowID string, runID string, requestID string) (converter.EncodedValue, error) {
+	interval := updatePollInitialInterval
+	for {
+		result, closed, err := w.queryUpdateResult(ctx, workflowID, runID, requestID, enumspb.QUERY_REJECT_CONDITION_NOT_OPEN)
+		if err != nil {
+			return nil, err
+		}
+		if closed {
+			// The final state of the workflow tells if the update completed before the workflow was closed.
+			if result, _, err = w.queryUpdateResult(ctx, workflowID, runID, requestID, enumspb.QUERY_REJECT_CONDITION_NONE); err != nil {
+				return nil, err
+			}
+			if !result.Completed {
+				return nil, fmt.Errorf("workflow execution %v closed before update %v completed", workflowID, requestID)
+			}
+		}
+		if result.Completed {
+			if result.Failure != nil {
+				failure := &failurepb.Failure{}
+				if err := failure.Unmarshal(result.Failure); err != nil {
+					return nil, err
+				}
+				return nil, ConvertFailureToError(failure, w.client.dataConverter)
+			}
+			return newEncodedValue(result.Result, w.client.dataConverter), nil
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		if interval *= 2; interval > updatePollMaximumInterval {
+			interval = updatePollMaximumInterval
+		}
+	}
+}
+
+func (w *workflowClientInterceptor) queryUpdateResult(ctx context.Context, workflowID string, runID string, requestID string,
+	rejectCondition enumspb.QueryRejectCondition) (result updateResult, closed bool, err error) {
+	response, err := w.QueryWorkflowWithOptions(ctx, &QueryWorkflowWithOptionsRequest{
+		WorkflowID:           workflowID,
+		RunID:                runID,
+		QueryType:            updateResultQueryType,
+		Args:                 []interface{}{requestID},
+		QueryRejectCondition: rejectCondition,
+	})
+	if err != nil {
+		return result, false, err
+	}
+	if response.QueryRejected != nil {
+		return result, true, nil
+	}
+	err = response.QueryResult.Get(&result)
+	return result, false, err
+}
@@ -0,0 +1,196 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	failurepb "go.temporal.io/api/failure/v1"
+
+	"go.temporal.io/sdk/converter"
+)
+
+func updateTestWorkflow(ctx Context) (int, error) {
+	total := 0
+	err := SetUpdateHandler(ctx, "add", func(ctx Context, n int) (int, error) {
+		if n < 0 {
+			return 0, NewApplicationError("negative amount", "InvalidAmount", false, nil)
+		}
+		if err := Sleep(ctx, time.Minute); err != nil {
+			return 0, err
+		}
+		total += n
+		return total, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	err = Await(ctx, func() bool { return total >= 10 })
+	return total, err
+}
+
+func queryUpdateResult(t *testing.T, env *TestWorkflowEnvironment, id string) updateResult {
+	value, err := env.QueryWorkflow(updateResultQueryType, id)
+	require.NoError(t, err)
+	var result updateResult
+	require.NoError(t, value.Get(&result))
+	return result
+}
+
+func TestUpdateHandler(t *testing.T) {
+	var s WorkflowTestSuite
+	env := s.NewTestWorkflowEnvironment()
+	dc := converter.GetDefaultDataConverter()
+	encode := func(args ...interface{}) updateRequest {
+		input, err := encodeArgs(dc, args)
+		require.NoError(t, err)
+		return updateRequest{Name: "add", Args: input}
+	}
+
+	env.RegisterDelayedCallback(func() {
+		request := encode(4)
+		request.ID = "first"
+		env.SignalWorkflow(updateSignalName, request)
+		// A retried request is not applied twice.
+		env.SignalWorkflow(updateSignalName, request)
+		require.False(t, queryUpdateResult(t, env, "first").Completed)
+
+		request = encode(-1)
+		request.ID = "invalid"
+		env.SignalWorkflow(updateSignalName, request)
+		env.SignalWorkflow(updateSignalName, updateRequest{ID: "unknown", Name: "subtract"})
+	}, time.Minute)
+	env.RegisterDelayedCallback(func() {
+		result := queryUpdateResult(t, env, "first")
+		require.True(t, result.Completed)
+		var total int
+		require.NoError(t, dc.FromPayloads(result.Result, &total))
+		require.Equal(t, 4, total)
+
+		result = queryUpdateResult(t, env, "invalid")
+		require.True(t, result.Completed)
+		failure := &failurepb.Failure{}
+		require.NoError(t, failure.Unmarshal(result.Failure))
+		var applicationErr *ApplicationError
+		require.True(t, errors.As(ConvertFailureToError(failure, dc), &applicationErr))
+		require.Equal(t, "InvalidAmount", applicationErr.Type())
+
+		result = queryUpdateResult(t, env, "unknown")
+		require.True(t, result.Completed)
+		require.NoError(t, failure.Unmarshal(result.Failure))
+		require.Contains(t, failure.GetMessage(), "unknown update subtract")
+
+		request := encode(6)
+		request.ID = "second"
+		env.SignalWorkflow(updateSignalName, request)
+	}, 3*time.Minute)
+	env.ExecuteWorkflow(updateTestWorkflow)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var total int
+	require.NoError(t, env.GetWorkflowResult(&total))
+	require.Equal(t, 10, total)
+	require.False(t, queryUpdateResult(t, env, "missing").Completed)
+}
+
+func TestUpdateHandlerMalformedRequest(t *testing.T) {
+	var s WorkflowTestSuite
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(updateSignalName, "garbage")
+		env.SignalWorkflow(updateSignalName, map[string]interface{}{"ID": "malformed", "Name": 5})
+	}, time.Minute)
+	env.RegisterDelayedCallback(func() {
+		result := queryUpdateResult(t, env, "malformed")
+		require.True(t, result.Completed)
+		failure := &failurepb.Failure{}
+		require.NoError(t, failure.Unmarshal(result.Failure))
+		require.Contains(t, failure.GetMessage(), "unable to decode the update request")
+
+		env.SignalWorkflow(updateSignalName, updateRequest{ID: "valid", Name: "subtract"})
+	}, 2*time.Minute)
+	env.RegisterDelayedCallback(func() {
+		require.True(t, queryUpdateResult(t, env, "valid").Completed)
+		env.CancelWorkflow()
+	}, 3*time.Minute)
+	env.ExecuteWorkflow(updateTestWorkflow)
+	require.True(t, env.IsWorkflowCompleted())
+}
+
+func TestUpdateWithoutHandler(t *testing.T) {
+	var s WorkflowTestSuite
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(updateSignalName, updateRequest{ID: "update", Name: "add"})
+	}, time.Minute)
+	env.RegisterDelayedCallback(func() {
+		result := queryUpdateResult(t, env, "update")
+		require.True(t, result.Completed)
+		failure := &failurepb.Failure{}
+		require.NoError(t, failure.Unmarshal(result.Failure))
+		require.Contains(t, failure.GetMessage(), "unknown update add")
+	}, 2*time.Minute)
+	env.ExecuteWorkflow(func(ctx Context) error {
+		return Sleep(ctx, time.Hour)
+	})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+}
+
+func TestUpdateResultsLimit(t *testing.T) {
+	var s WorkflowTestSuite
+	env := s.NewTestWorkflowEnvironment()
+	// The test environment doesn't accept many signals sent by a single callback, so they are sent in batches.
+	const batchSize = 100
+	for batch := 0; batch*batchSize <= updateResultsLimit; batch++ {
+		start := batch * batchSize
+		env.RegisterDelayedCallback(func() {
+			for i := start; i < start+batchSize && i <= updateResultsLimit; i++ {
+				env.SignalWorkflow(updateSignalName, updateRequest{ID: fmt.Sprintf("update-%v", i), Name: "unknown"})
+			}
+		}, time.Duration(batch+1)*time.Minute)
+	}
+	env.RegisterDelayedCallback(func() {
+		require.False(t, queryUpdateResult(t, env, "update-0").Completed)
+		require.True(t, queryUpdateResult(t, env, "update-1").Completed)
+		require.True(t, queryUpdateResult(t, env, fmt.Sprintf("update-%v", updateResultsLimit)).Completed)
+		env.CancelWorkflow()
+	}, time.Hour)
+	env.ExecuteWorkflow(updateTestWorkflow)
+	require.True(t, env.IsWorkflowCompleted())
+}
+
+func TestUpdateHandlerValidation(t *testing.T) {
+	require.NoError(t, validateUpdateHandlerFn(func(ctx Context) error { return nil }))
+	require.NoError(t, validateUpdateHandlerFn(func(ctx Context, s string) (string, error) { return s, nil }))
+	require.Error(t, validateUpdateHandlerFn(func(s string) error { return nil }))
+	require.Error(t, validateUpdateHandlerFn(func(ctx Context) string { return "" }))
+	require.Error(t, validateUpdateHandlerFn("add"))
+}
@@ -35,7 +35,6 @@ import (
 	"syscall"
 	"time"
 
-	"github.com/uber-go/tally"
 	"go.temporal.io/sdk/internal/common/retry"
 	"google.golang.org/grpc/metadata"
 
@@ -66,7 +65,7 @@ type grpcContextBuilder struct {
 	//   - context fields, accessible via `ctx.Value(key)`
 	ParentContext context.Context
 
-	MetricsScope tally.Scope
+	MetricsHandler metrics.Handler
 
 	Headers metadata.MD
 
@@ -81,8 +80,8 @@ func (cb *grpcContextBuilder) Build() (context.Context, context.CancelFunc) {
 	if cb.Headers != nil {
 		ctx = metadata.NewOutgoingContext(ctx, cb.Headers)
 	}
-	if cb.MetricsScope != nil {
-		ctx = context.WithValue(ctx, metrics.ScopeContextKey, cb.MetricsScope)
+	if cb.MetricsHandler != nil {
+		ctx = context.WithValue(ctx, metrics.HandlerContextKey, cb.MetricsHandler)
 	}
 	ctx = context.WithValue(ctx, metrics.LongPollContextKey, cb.IsLongPoll)
 	var cancel context.CancelFunc
@@ -99,9 +98,9 @@ func grpcTimeout(timeout time.Duration) func(builder *grpcContextBuilder) {
 	}
 }
 
-func grpcMetricsScope(metricsScope tally.Scope) func(builder *grpcContextBuilder) {
+func grpcMetricsHandler(metricsHandler metrics.Handler) func(builder *grpcContextBuilder) {
 	return func(b *grpcContextBuilder) {
-		b.MetricsScope = metricsScope
+		b.MetricsHandler = metricsHandler
 	}
 }
 
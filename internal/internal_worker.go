@@ -34,6 +34,7 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"net/http"
 	"os"
 	"reflect"
 	"runtime"
@@ -47,7 +48,6 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/opentracing/opentracing-go"
 	"github.com/pborman/uuid"
-	"github.com/uber-go/tally"
 	commonpb "go.temporal.io/api/common/v1"
 	enumspb "go.temporal.io/api/enums/v1"
 	historypb "go.temporal.io/api/history/v1"
@@ -55,6 +55,7 @@ import (
 	"go.temporal.io/api/workflowservicemock/v1"
 
 	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/internal/common/metrics"
 	"go.temporal.io/sdk/internal/common/serializer"
 	"go.temporal.io/sdk/internal/common/util"
 	ilog "go.temporal.io/sdk/internal/log"
@@ -164,7 +165,7 @@ type (
 		// a default option.
 		Identity string
 
-		MetricsScope tally.Scope
+		MetricsHandler metrics.Handler
 
 		Logger log.Logger
 
@@ -203,6 +204,9 @@ type (
 		// DeadlockDetectionTimeout specifies workflow task timeout.
 		DeadlockDetectionTimeout time.Duration
 
+		// EnableNativeConcurrencyDetection enables detection of native go concurrency in workflow code.
+		EnableNativeConcurrencyDetection bool
+
 		// Pointer to the shared worker cache
 		cache *WorkerCache
 	}
@@ -224,9 +228,9 @@ func ensureRequiredParams(params *workerExecutionParameters) {
 		params.Logger = ilog.NewDefaultLogger()
 		params.Logger.Info("No logger configured for temporal worker. Created default one.")
 	}
-	if params.MetricsScope == nil {
-		params.MetricsScope = tally.NoopScope
-		params.Logger.Info("No metrics scope configured for temporal worker. Use NoopScope as default.")
+	if params.MetricsHandler == nil {
+		params.MetricsHandler = metrics.NopHandler
+		params.Logger.Info("No metrics handler configured for temporal worker. Use NopHandler as default.")
 	}
 	if params.DataConverter == nil {
 		params.DataConverter = converter.GetDefaultDataConverter()
@@ -235,12 +239,12 @@ func ensureRequiredParams(params *workerExecutionParameters) {
 }
 
 // verifyNamespaceExist does a DescribeNamespace operation on the specified namespace with backoff/retry
-func verifyNamespaceExist(client workflowservice.WorkflowServiceClient, metricsScope tally.Scope, namespace string, logger log.Logger) error {
+func verifyNamespaceExist(client workflowservice.WorkflowServiceClient, metricsHandler metrics.Handler, namespace string, logger log.Logger) error {
 	ctx := context.Background()
 	if namespace == "" {
 		return errors.New("namespace cannot be empty")
 	}
-	grpcCtx, cancel := newGRPCContext(ctx, grpcMetricsScope(metricsScope), defaultGrpcRetryParameters(ctx))
+	grpcCtx, cancel := newGRPCContext(ctx, grpcMetricsHandler(metricsHandler), defaultGrpcRetryParameters(ctx))
 	defer cancel()
 	_, err := client.DescribeNamespace(grpcCtx, &workflowservice.DescribeNamespaceRequest{Namespace: namespace})
 	return err
@@ -273,7 +277,7 @@ func newWorkflowTaskWorkerInternal(taskHandler WorkflowTaskHandler, service work
 		workerType:        "WorkflowWorker",
 		stopTimeout:       params.WorkerStopTimeout},
 		params.Logger,
-		params.MetricsScope,
+		params.MetricsHandler,
 		nil,
 	)
 
@@ -296,7 +300,7 @@ func newWorkflowTaskWorkerInternal(taskHandler WorkflowTaskHandler, service work
 		workerType:        "LocalActivityWorker",
 		stopTimeout:       params.WorkerStopTimeout},
 		params.Logger,
-		params.MetricsScope,
+		params.MetricsHandler,
 		nil,
 	)
 
@@ -316,7 +320,7 @@ func newWorkflowTaskWorkerInternal(taskHandler WorkflowTaskHandler, service work
 
 // Start the worker.
 func (ww *workflowWorker) Start() error {
-	err := verifyNamespaceExist(ww.workflowService, ww.executionParameters.MetricsScope, ww.executionParameters.Namespace, ww.worker.logger)
+	err := verifyNamespaceExist(ww.workflowService, ww.executionParameters.MetricsHandler, ww.executionParameters.Namespace, ww.worker.logger)
 	if err != nil {
 		return err
 	}
@@ -409,7 +413,7 @@ func newActivityTaskWorker(taskHandler ActivityTaskHandler, service workflowserv
 			stopTimeout:       workerParams.WorkerStopTimeout,
 			userContextCancel: workerParams.UserContextCancel},
 		workerParams.Logger,
-		workerParams.MetricsScope,
+		workerParams.MetricsHandler,
 		sessionTokenBucket,
 	)
 
@@ -425,7 +429,7 @@ func newActivityTaskWorker(taskHandler ActivityTaskHandler, service workflowserv
 
 // Start the worker.
 func (aw *activityWorker) Start() error {
-	err := verifyNamespaceExist(aw.workflowService, aw.executionParameters.MetricsScope, aw.executionParameters.Namespace, aw.worker.logger)
+	err := verifyNamespaceExist(aw.workflowService, aw.executionParameters.MetricsHandler, aw.executionParameters.Namespace, aw.worker.logger)
 	if err != nil {
 		return err
 	}
@@ -446,6 +450,9 @@ type registry struct {
 	activityFuncMap      map[string]activity
 	activityAliasMap     map[string]string
 	workflowInterceptors []WorkflowInterceptor
+	activityInterceptors []ActivityInterceptor
+	// searchAttributesSchema is set when search attributes validation is enabled.
+	searchAttributesSchema map[string]enumspb.IndexedValueType
 }
 
 func (r *registry) WorkflowInterceptors() []WorkflowInterceptor {
@@ -456,6 +463,26 @@ func (r *registry) SetWorkflowInterceptors(workflowInterceptors []WorkflowInterc
 	r.workflowInterceptors = workflowInterceptors
 }
 
+func (r *registry) ActivityInterceptors() []ActivityInterceptor {
+	return r.activityInterceptors
+}
+
+func (r *registry) SetActivityInterceptors(activityInterceptors []ActivityInterceptor) {
+	r.activityInterceptors = activityInterceptors
+}
+
+func (r *registry) SearchAttributesSchema() map[string]enumspb.IndexedValueType {
+	r.Lock()
+	defer r.Unlock()
+	return r.searchAttributesSchema
+}
+
+func (r *registry) SetSearchAttributesSchema(schema map[string]enumspb.IndexedValueType) {
+	r.Lock()
+	defer r.Unlock()
+	r.searchAttributesSchema = schema
+}
+
 func (r *registry) RegisterWorkflow(af interface{}) {
 	r.RegisterWorkflowWithOptions(af, RegisterWorkflowOptions{})
 }
@@ -767,24 +794,32 @@ func (ae *activityExecutor) GetFunction() interface{} {
 
 func (ae *activityExecutor) Execute(ctx context.Context, input *commonpb.Payloads) (*commonpb.Payloads, error) {
 	fnType := reflect.TypeOf(ae.fn)
-	var args []reflect.Value
 	dataConverter := getDataConverterFromActivityCtx(ctx)
 
-	// activities optionally might not take context.
-	if fnType.NumIn() > 0 && isActivityContext(fnType.In(0)) {
-		args = append(args, reflect.ValueOf(ctx))
-	}
-
 	decoded, err := decodeArgs(dataConverter, fnType, input)
 	if err != nil {
 		return nil, fmt.Errorf(
 			"unable to decode the activity function input payload with error: %w for function name: %v",
 			err, ae.name)
 	}
-	args = append(args, decoded...)
+	args := make([]interface{}, 0, len(decoded))
+	for _, arg := range decoded {
+		args = append(args, arg.Interface())
+	}
 
-	fnValue := reflect.ValueOf(ae.fn)
-	retValues := fnValue.Call(args)
+	envInterceptor := getActivityEnvironmentInterceptor(ctx)
+	envInterceptor.fn = ae.fn
+	result, err := envInterceptor.inboundInterceptor.ExecuteActivity(ctx, ae.name, args...)
+
+	var retValues []reflect.Value
+	if fnType.NumOut() > 1 {
+		if result == nil {
+			retValues = append(retValues, reflect.New(fnType.Out(0)).Elem())
+		} else {
+			retValues = append(retValues, reflect.ValueOf(result))
+		}
+	}
+	retValues = append(retValues, reflect.ValueOf(&err).Elem())
 	return validateFunctionAndGetResults(ae.fn, retValues, dataConverter)
 }
 
@@ -853,6 +888,8 @@ type AggregatedWorker struct {
 	sessionWorker  *sessionWorker
 	logger         log.Logger
 	registry       *registry
+	client         *WorkflowClient
+	httpServer     *workerHTTPServer
 	stopC          chan struct{}
 }
 
@@ -877,16 +914,36 @@ func (aw *AggregatedWorker) RegisterActivityWithOptions(a interface{}, options R
 }
 
 // Start the worker in a non-blocking fashion.
-func (aw *AggregatedWorker) Start() error {
+func (aw *AggregatedWorker) Start() (err error) {
 	aw.assertNotStopped()
 	if err := initBinaryChecksum(); err != nil {
 		return fmt.Errorf("failed to get executable checksum: %v", err)
 	}
 
+	if aw.httpServer != nil {
+		if err := aw.httpServer.start(); err != nil {
+			return err
+		}
+		defer func() {
+			if err != nil {
+				aw.httpServer.stop()
+			}
+		}()
+	}
+
 	if !util.IsInterfaceNil(aw.workflowWorker) {
 		if len(aw.registry.getRegisteredWorkflowTypes()) == 0 {
 			aw.logger.Debug("No workflows registered. Skipping workflow worker start")
 		} else {
+			if aw.client != nil && aw.client.searchAttributesValidation {
+				// Workflow code search attributes are only logged, so the worker can run without the schema.
+				schema, err := aw.client.loadSearchAttributesSchema(context.Background())
+				if err != nil {
+					aw.logger.Warn("Failed to load search attributes. Search attributes set from workflow code are not validated.", tagError, err)
+				} else {
+					aw.registry.SetSearchAttributesSchema(schema)
+				}
+			}
 			if err := aw.workflowWorker.Start(); err != nil {
 				return err
 			}
@@ -1024,6 +1081,10 @@ func (aw *AggregatedWorker) Run(interruptCh <-chan interface{}) error {
 func (aw *AggregatedWorker) Stop() {
 	close(aw.stopC)
 
+	if aw.httpServer != nil {
+		aw.httpServer.stop()
+	}
+
 	if !util.IsInterfaceNil(aw.workflowWorker) {
 		aw.workflowWorker.Stop()
 	}
@@ -1037,6 +1098,23 @@ func (aw *AggregatedWorker) Stop() {
 	aw.logger.Info("Stopped Worker")
 }
 
+// baseWorkers returns the pollers of all the workers, started or not.
+func (aw *AggregatedWorker) baseWorkers() []namedBaseWorker {
+	var result []namedBaseWorker
+	if !util.IsInterfaceNil(aw.workflowWorker) {
+		result = append(result, namedBaseWorker{name: "workflow", worker: aw.workflowWorker.worker})
+	}
+	if !util.IsInterfaceNil(aw.activityWorker) {
+		result = append(result, namedBaseWorker{name: "activity", worker: aw.activityWorker.worker})
+	}
+	if !util.IsInterfaceNil(aw.sessionWorker) {
+		result = append(result,
+			namedBaseWorker{name: "session creation", worker: aw.sessionWorker.creationWorker.worker},
+			namedBaseWorker{name: "session activity", worker: aw.sessionWorker.activityWorker.worker})
+	}
+	return result
+}
+
 // WorkflowReplayer is used to replay workflow code from an event history
 type WorkflowReplayer struct {
 	registry *registry
@@ -1168,13 +1246,13 @@ func (aw *WorkflowReplayer) replayWorkflowHistory(loger log.Logger, service work
 	}
 
 	iterator := &historyIteratorImpl{
-		nextPageToken: task.NextPageToken,
-		execution:     task.WorkflowExecution,
-		namespace:     ReplayNamespace,
-		service:       service,
-		maxEventID:    task.GetStartedEventId(),
-		metricsScope:  nil,
-		taskQueue:     taskQueue,
+		nextPageToken:  task.NextPageToken,
+		execution:      task.WorkflowExecution,
+		namespace:      ReplayNamespace,
+		service:        service,
+		maxEventID:     task.GetStartedEventId(),
+		metricsHandler: nil,
+		taskQueue:      taskQueue,
 	}
 	cache := NewWorkerCache()
 	params := workerExecutionParameters{
@@ -1279,7 +1357,7 @@ func NewAggregatedWorker(client *WorkflowClient, taskQueue string, options Worke
 		ConcurrentWorkflowTaskExecutionSize:   options.MaxConcurrentWorkflowTaskExecutionSize,
 		MaxConcurrentWorkflowTaskQueuePollers: options.MaxConcurrentWorkflowTaskPollers,
 		Identity:                              client.identity,
-		MetricsScope:                          client.metricsScope,
+		MetricsHandler:                        client.metricsHandler,
 		Logger:                                client.logger,
 		EnableLoggingInReplay:                 options.EnableLoggingInReplay,
 		UserContext:                           backgroundActivityContext,
@@ -1292,6 +1370,7 @@ func NewAggregatedWorker(client *WorkflowClient, taskQueue string, options Worke
 		ContextPropagators:                    client.contextPropagators,
 		Tracer:                                client.tracer,
 		DeadlockDetectionTimeout:              options.DeadlockDetectionTimeout,
+		EnableNativeConcurrencyDetection:      options.EnableNativeConcurrencyDetection,
 		cache:                                 cache,
 	}
 
@@ -1299,6 +1378,11 @@ func NewAggregatedWorker(client *WorkflowClient, taskQueue string, options Worke
 		workerParams.Identity = options.Identity
 	}
 
+	var metricsHTTPHandler http.Handler
+	if options.HTTPServerAddress != "" {
+		metricsHTTPHandler, workerParams.MetricsHandler = newWorkerPrometheusHandler(client)
+	}
+
 	ensureRequiredParams(&workerParams)
 	workerParams.Logger = log.With(workerParams.Logger,
 		tagNamespace, client.namespace,
@@ -1311,6 +1395,7 @@ func NewAggregatedWorker(client *WorkflowClient, taskQueue string, options Worke
 	// worker specific registry
 	registry := newRegistry()
 	registry.SetWorkflowInterceptors(options.WorkflowInterceptorChainFactories)
+	registry.SetActivityInterceptors(options.ActivityInterceptorChainFactories)
 
 	// workflow factory.
 	var workflowWorker *workflowWorker
@@ -1338,14 +1423,19 @@ func NewAggregatedWorker(client *WorkflowClient, taskQueue string, options Worke
 		})
 	}
 
-	return &AggregatedWorker{
+	aw := &AggregatedWorker{
 		workflowWorker: workflowWorker,
 		activityWorker: activityWorker,
 		sessionWorker:  sessionWorker,
 		logger:         workerParams.Logger,
 		registry:       registry,
+		client:         client,
 		stopC:          make(chan struct{}),
 	}
+	if options.HTTPServerAddress != "" {
+		aw.httpServer = newWorkerHTTPServer(options.HTTPServerAddress, aw, metricsHTTPHandler)
+	}
+	return aw
 }
 
 func processTestTags(wOptions *WorkerOptions, ep *workerExecutionParameters) {
@@ -1486,8 +1576,8 @@ func setClientDefaults(client *WorkflowClient) {
 	if client.tracer == nil {
 		client.tracer = opentracing.NoopTracer{}
 	}
-	if client.metricsScope == nil {
-		client.metricsScope = tally.NoopScope
+	if client.metricsHandler == nil {
+		client.metricsHandler = metrics.NopHandler
 	}
 }
 
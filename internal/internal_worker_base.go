@@ -32,9 +32,9 @@ import (
 	"fmt"
 	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/uber-go/tally"
 	commonpb "go.temporal.io/api/common/v1"
 	"go.temporal.io/api/serviceerror"
 	"go.temporal.io/sdk/internal/common/retry"
@@ -86,7 +86,7 @@ type (
 		RequestCancelExternalWorkflow(namespace, workflowID, runID string, callback ResultHandler)
 		ExecuteChildWorkflow(params ExecuteWorkflowParams, callback ResultHandler, startedHandler func(r WorkflowExecution, e error))
 		GetLogger() log.Logger
-		GetMetricsScope() tally.Scope
+		GetMetricsHandler() metrics.Handler
 		// Must be called before WorkflowDefinition.Execute returns
 		RegisterSignalHandler(handler func(name string, input *commonpb.Payloads))
 		SignalExternalWorkflow(namespace, workflowID, runID, signalName string, input *commonpb.Payloads, arg interface{}, childWorkflowOnly bool, callback ResultHandler)
@@ -99,6 +99,7 @@ type (
 		GetContextPropagators() []ContextPropagator
 		UpsertSearchAttributes(attributes map[string]interface{}) error
 		GetRegistry() *registry
+		IsNativeConcurrencyDetectionEnabled() bool
 	}
 
 	// WorkflowDefinitionFactory factory for creating WorkflowDefinition instances.
@@ -147,11 +148,15 @@ type (
 		limiterContextCancel func()
 		retrier              *backoff.ConcurrentRetrier // Service errors back off retrier
 		logger               log.Logger
-		metricsScope         tally.Scope
+		metricsHandler       metrics.Handler
 
 		pollerRequestCh    chan struct{}
 		taskQueueCh        chan interface{}
 		sessionTokenBucket *sessionTokenBucket
+
+		// pollFailures is the number of successive failed polls of each poller, accessed atomically. Each poller
+		// counts its own failures, so a successful poll doesn't hide the failures of the other pollers.
+		pollFailures []int32
 	}
 
 	polledTask struct {
@@ -171,7 +176,7 @@ func createPollRetryPolicy() backoff.RetryPolicy {
 	return policy
 }
 
-func newBaseWorker(options baseWorkerOptions, logger log.Logger, metricsScope tally.Scope, sessionTokenBucket *sessionTokenBucket) *baseWorker {
+func newBaseWorker(options baseWorkerOptions, logger log.Logger, metricsHandler metrics.Handler, sessionTokenBucket *sessionTokenBucket) *baseWorker {
 	ctx, cancel := context.WithCancel(context.Background())
 	bw := &baseWorker{
 		options:         options,
@@ -179,13 +184,14 @@ func newBaseWorker(options baseWorkerOptions, logger log.Logger, metricsScope ta
 		taskLimiter:     rate.NewLimiter(rate.Limit(options.maxTaskPerSecond), 1),
 		retrier:         backoff.NewConcurrentRetrier(pollOperationRetryPolicy),
 		logger:          log.With(logger, tagWorkerType, options.workerType),
-		metricsScope:    metrics.GetWorkerScope(metricsScope, options.workerType),
+		metricsHandler:  metrics.GetWorkerHandler(metricsHandler, options.workerType),
 		pollerRequestCh: make(chan struct{}, options.maxConcurrentTask),
 		taskQueueCh:     make(chan interface{}), // no buffer, so poller only able to poll new task after previous is dispatched.
 
 		limiterContext:       ctx,
 		limiterContextCancel: cancel,
 		sessionTokenBucket:   sessionTokenBucket,
+		pollFailures:         make([]int32, options.pollerCount),
 	}
 	if options.pollerRate > 0 {
 		bw.pollLimiter = rate.NewLimiter(rate.Limit(options.pollerRate), 1)
@@ -200,11 +206,11 @@ func (bw *baseWorker) Start() {
 		return
 	}
 
-	bw.metricsScope.Counter(metrics.WorkerStartCounter).Inc(1)
+	bw.metricsHandler.Counter(metrics.WorkerStartCounter).Inc(1)
 
 	for i := 0; i < bw.options.pollerCount; i++ {
 		bw.stopWG.Add(1)
-		go bw.runPoller()
+		go bw.runPoller(&bw.pollFailures[i])
 	}
 
 	bw.stopWG.Add(1)
@@ -229,9 +235,9 @@ func (bw *baseWorker) isStop() bool {
 	}
 }
 
-func (bw *baseWorker) runPoller() {
+func (bw *baseWorker) runPoller(pollFailures *int32) {
 	defer bw.stopWG.Done()
-	bw.metricsScope.Counter(metrics.PollerStartCounter).Inc(1)
+	bw.metricsHandler.Counter(metrics.PollerStartCounter).Inc(1)
 
 	for {
 		select {
@@ -241,7 +247,7 @@ func (bw *baseWorker) runPoller() {
 			if bw.sessionTokenBucket != nil {
 				bw.sessionTokenBucket.waitForAvailableToken()
 			}
-			bw.pollTask()
+			bw.pollTask(pollFailures)
 		}
 	}
 }
@@ -272,7 +278,7 @@ func (bw *baseWorker) runTaskDispatcher() {
 	}
 }
 
-func (bw *baseWorker) pollTask() {
+func (bw *baseWorker) pollTask(pollFailures *int32) {
 	var err error
 	var task interface{}
 	bw.retrier.Throttle()
@@ -291,8 +297,10 @@ func (bw *baseWorker) pollTask() {
 				}
 				return
 			}
+			atomic.AddInt32(pollFailures, 1)
 			bw.retrier.Failed()
 		} else {
+			atomic.StoreInt32(pollFailures, 0)
 			bw.retrier.Succeeded()
 		}
 	}
@@ -307,6 +315,17 @@ func (bw *baseWorker) pollTask() {
 	}
 }
 
+// successivePollFailures returns the largest number of polls a poller failed since its last successful one.
+func (bw *baseWorker) successivePollFailures() int {
+	var result int32
+	for i := range bw.pollFailures {
+		if failures := atomic.LoadInt32(&bw.pollFailures[i]); failures > result {
+			result = failures
+		}
+	}
+	return int(result)
+}
+
 func isNonRetriableError(err error) bool {
 	if err == nil {
 		return false
@@ -0,0 +1,154 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+// All code in this file is private to the package.
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.temporal.io/sdk/internal/common/metrics"
+	"go.temporal.io/sdk/log"
+)
+
+const (
+	// unhealthyPollFailures is the number of successive poll failures after which a poller is reported unhealthy.
+	unhealthyPollFailures = 5
+
+	// readinessHealthCheckTimeout is how long /readyz waits for the Temporal server health check.
+	readinessHealthCheckTimeout = time.Second
+
+	workerHTTPServerShutdownTimeout = 5 * time.Second
+)
+
+type (
+	// workerHTTPServer serves metrics, liveness and readiness of an AggregatedWorker.
+	workerHTTPServer struct {
+		worker   *AggregatedWorker
+		server   *http.Server
+		listener net.Listener
+		logger   log.Logger
+	}
+
+	// namedBaseWorker is a baseWorker with the name it is reported under by /healthz.
+	namedBaseWorker struct {
+		name   string
+		worker *baseWorker
+	}
+)
+
+// newWorkerPrometheusHandler returns the handler serving /metrics and the metrics handler of the worker. The client
+// metrics handler is served as is if it is a PrometheusHandler, otherwise worker metrics are reported to both the
+// client metrics handler and a new PrometheusHandler. Metrics the client reports itself, like the ones of gRPC
+// requests, don't reach the new PrometheusHandler, as the client handler is fixed when the client is created.
+func newWorkerPrometheusHandler(client *WorkflowClient) (http.Handler, metrics.Handler) {
+	if prometheusHandler, ok := client.metricsHandler.(*metrics.PrometheusHandler); ok {
+		return prometheusHandler, client.metricsHandler
+	}
+	prometheusHandler := metrics.NewPrometheusHandler(metrics.PrometheusHandlerOptions{})
+	rootHandler := metrics.GetRootHandler(prometheusHandler, client.namespace)
+	return prometheusHandler, metrics.NewMultiHandler(client.metricsHandler, rootHandler)
+}
+
+func newWorkerHTTPServer(address string, worker *AggregatedWorker, metricsHandler http.Handler) *workerHTTPServer {
+	s := &workerHTTPServer{
+		worker: worker,
+		logger: worker.logger,
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metricsHandler)
+	mux.HandleFunc("/healthz", s.healthz)
+	mux.HandleFunc("/readyz", s.readyz)
+	s.server = &http.Server{Addr: address, Handler: mux}
+	return s
+}
+
+func (s *workerHTTPServer) start() error {
+	listener, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to start worker HTTP server: %w", err)
+	}
+	s.listener = listener
+	go func() {
+		if err := s.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			s.logger.Error("Worker HTTP server failed.", tagError, err)
+		}
+	}()
+	s.logger.Info("Started worker HTTP server", "Address", listener.Addr().String())
+	return nil
+}
+
+func (s *workerHTTPServer) stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), workerHTTPServerShutdownTimeout)
+	defer cancel()
+	if err := s.server.Shutdown(ctx); err != nil {
+		s.logger.Warn("Unable to stop worker HTTP server.", tagError, err)
+	}
+}
+
+// healthz reports the worker unhealthy if any of its pollers failed to poll unhealthyPollFailures times in a row.
+func (s *workerHTTPServer) healthz(w http.ResponseWriter, _ *http.Request) {
+	var problems []string
+	for _, bw := range s.worker.baseWorkers() {
+		if failures := bw.worker.successivePollFailures(); failures >= unhealthyPollFailures {
+			problems = append(problems, fmt.Sprintf("%s poller failed %d times in a row", bw.name, failures))
+		}
+	}
+	writeProbeResult(w, problems)
+}
+
+// readyz reports the worker ready if it has anything registered and the Temporal server is reachable.
+func (s *workerHTTPServer) readyz(w http.ResponseWriter, _ *http.Request) {
+	var problems []string
+	registry := s.worker.registry
+	if len(registry.getRegisteredWorkflowTypes()) == 0 && len(registry.getRegisteredActivities()) == 0 {
+		problems = append(problems, "no workflows or activities registered")
+	}
+	if client := s.worker.client; client != nil && client.connection != nil {
+		options := ConnectionOptions{
+			HealthCheckAttemptTimeout: readinessHealthCheckTimeout,
+			HealthCheckTimeout:        readinessHealthCheckTimeout,
+		}
+		if err := checkHealth(client.connection.conn, options); err != nil {
+			problems = append(problems, fmt.Sprintf("temporal server is not reachable: %v", err))
+		}
+	}
+	writeProbeResult(w, problems)
+}
+
+func writeProbeResult(w http.ResponseWriter, problems []string) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if len(problems) > 0 {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = fmt.Fprintln(w, strings.Join(problems, "\n"))
+		return
+	}
+	_, _ = fmt.Fprintln(w, "ok")
+}
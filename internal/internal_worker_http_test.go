@@ -0,0 +1,118 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/api/workflowservicemock/v1"
+
+	"go.temporal.io/sdk/internal/common/metrics"
+	ilog "go.temporal.io/sdk/internal/log"
+)
+
+func newHTTPServerTestWorker(t *testing.T, options ClientOptions) *AggregatedWorker {
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+	service := workflowservicemock.NewMockWorkflowServiceClient(mockCtrl)
+	options.Logger = ilog.NewNopLogger()
+	client := NewServiceClient(service, nil, options)
+	return NewAggregatedWorker(client, "http-server-test", WorkerOptions{HTTPServerAddress: "127.0.0.1:0"})
+}
+
+func serveWorkerHTTP(aw *AggregatedWorker, path string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	aw.httpServer.server.Handler.ServeHTTP(recorder, httptest.NewRequest("GET", path, nil))
+	return recorder
+}
+
+func TestWorkerHTTPServerHealthz(t *testing.T) {
+	aw := newHTTPServerTestWorker(t, ClientOptions{})
+
+	recorder := serveWorkerHTTP(aw, "/healthz")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "ok\n", recorder.Body.String())
+
+	atomic.StoreInt32(&aw.activityWorker.worker.pollFailures[0], unhealthyPollFailures)
+	recorder = serveWorkerHTTP(aw, "/healthz")
+	require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	require.Equal(t, "activity poller failed 5 times in a row\n", recorder.Body.String())
+}
+
+func TestWorkerHTTPServerReadyz(t *testing.T) {
+	aw := newHTTPServerTestWorker(t, ClientOptions{})
+
+	recorder := serveWorkerHTTP(aw, "/readyz")
+	require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	require.Equal(t, "no workflows or activities registered\n", recorder.Body.String())
+
+	aw.RegisterActivity(testActivityHello)
+	recorder = serveWorkerHTTP(aw, "/readyz")
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestWorkerHTTPServerMetrics(t *testing.T) {
+	aw := newHTTPServerTestWorker(t, ClientOptions{})
+	aw.activityWorker.worker.metricsHandler.Counter(metrics.WorkerStartCounter).Inc(1)
+
+	recorder := serveWorkerHTTP(aw, "/metrics")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, metrics.PrometheusContentType, recorder.Header().Get("Content-Type"))
+	require.Contains(t, recorder.Body.String(), `temporal_worker_start{`)
+	require.Contains(t, recorder.Body.String(), `worker_type="ActivityWorker"`)
+}
+
+func TestWorkerHTTPServerClientPrometheusHandler(t *testing.T) {
+	prometheusHandler := metrics.NewPrometheusHandler(metrics.PrometheusHandlerOptions{})
+	aw := newHTTPServerTestWorker(t, ClientOptions{MetricsHandler: prometheusHandler})
+	require.Equal(t, prometheusHandler, aw.activityWorker.executionParameters.MetricsHandler)
+
+	prometheusHandler.Counter("client_metric").Inc(1)
+	recorder := serveWorkerHTTP(aw, "/metrics")
+	require.Contains(t, recorder.Body.String(), "client_metric 1")
+}
+
+func TestWorkerHTTPServerStartStop(t *testing.T) {
+	aw := newHTTPServerTestWorker(t, ClientOptions{})
+	require.NoError(t, aw.httpServer.start())
+
+	resp, err := http.Get("http://" + aw.httpServer.listener.Addr().String() + "/healthz")
+	require.NoError(t, err)
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "ok\n", string(body))
+
+	aw.httpServer.stop()
+	_, err = http.Get("http://" + aw.httpServer.listener.Addr().String() + "/healthz")
+	require.Error(t, err)
+}
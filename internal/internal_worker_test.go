@@ -1461,6 +1461,18 @@ func (s *internalWorkerTestSuite) TestCreateWorker() {
 	assert.False(s.T(), worker.workflowWorker.worker.isWorkerStarted)
 }
 
+func (s *internalWorkerTestSuite) TestCreateWorkerWithoutSearchAttributes() {
+	worker := createWorker(s.service)
+	worker.client.searchAttributesValidation = true
+	s.service.EXPECT().GetSearchAttributes(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, serviceerror.NewInternal("")).Times(1)
+	worker.RegisterWorkflow(testWorkflowReturnStruct)
+	err := worker.Start()
+	require.NoError(s.T(), err)
+	assert.True(s.T(), worker.workflowWorker.worker.isWorkerStarted)
+	assert.Nil(s.T(), worker.registry.SearchAttributesSchema())
+	worker.Stop()
+}
+
 func (s *internalWorkerTestSuite) TestCreateWorkerWithDataConverter() {
 	worker := createWorkerWithDataConverter(s.service)
 	worker.RegisterActivity(testActivityNoResult)
@@ -2259,7 +2271,7 @@ func TestWorkerOptionDefaults(t *testing.T) {
 	workflowWorker := aggWorker.workflowWorker
 	require.True(t, workflowWorker.executionParameters.Identity != "")
 	require.NotNil(t, workflowWorker.executionParameters.Logger)
-	require.NotNil(t, workflowWorker.executionParameters.MetricsScope)
+	require.NotNil(t, workflowWorker.executionParameters.MetricsHandler)
 	require.Nil(t, workflowWorker.executionParameters.ContextPropagators)
 
 	expected := workerExecutionParameters{
@@ -2277,7 +2289,7 @@ func TestWorkerOptionDefaults(t *testing.T) {
 		DataConverter:                         converter.GetDefaultDataConverter(),
 		Tracer:                                opentracing.NoopTracer{},
 		Logger:                                workflowWorker.executionParameters.Logger,
-		MetricsScope:                          workflowWorker.executionParameters.MetricsScope,
+		MetricsHandler:                        workflowWorker.executionParameters.MetricsHandler,
 		Identity:                              workflowWorker.executionParameters.Identity,
 		UserContext:                           workflowWorker.executionParameters.UserContext,
 	}
@@ -2287,7 +2299,7 @@ func TestWorkerOptionDefaults(t *testing.T) {
 	activityWorker := aggWorker.activityWorker
 	require.True(t, activityWorker.executionParameters.Identity != "")
 	require.NotNil(t, activityWorker.executionParameters.Logger)
-	require.NotNil(t, activityWorker.executionParameters.MetricsScope)
+	require.NotNil(t, activityWorker.executionParameters.MetricsHandler)
 	require.Nil(t, activityWorker.executionParameters.ContextPropagators)
 	assertWorkerExecutionParamsEqual(t, expected, activityWorker.executionParameters)
 }
@@ -2340,7 +2352,7 @@ func TestWorkerOptionNonDefaults(t *testing.T) {
 		DataConverter:                         client.dataConverter,
 		Tracer:                                client.tracer,
 		Logger:                                client.logger,
-		MetricsScope:                          client.metricsScope,
+		MetricsHandler:                        client.metricsHandler,
 		Identity:                              client.identity,
 	}
 
@@ -2359,7 +2371,7 @@ func TestLocalActivityWorkerOnly(t *testing.T) {
 	workflowWorker := aggWorker.workflowWorker
 	require.True(t, workflowWorker.executionParameters.Identity != "")
 	require.NotNil(t, workflowWorker.executionParameters.Logger)
-	require.NotNil(t, workflowWorker.executionParameters.MetricsScope)
+	require.NotNil(t, workflowWorker.executionParameters.MetricsHandler)
 	require.Nil(t, workflowWorker.executionParameters.ContextPropagators)
 
 	expected := workerExecutionParameters{
@@ -2377,7 +2389,7 @@ func TestLocalActivityWorkerOnly(t *testing.T) {
 		DataConverter:                         converter.GetDefaultDataConverter(),
 		Tracer:                                opentracing.NoopTracer{},
 		Logger:                                workflowWorker.executionParameters.Logger,
-		MetricsScope:                          workflowWorker.executionParameters.MetricsScope,
+		MetricsHandler:                        workflowWorker.executionParameters.MetricsHandler,
 		Identity:                              workflowWorker.executionParameters.Identity,
 		UserContext:                           workflowWorker.executionParameters.UserContext,
 	}
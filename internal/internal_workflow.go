@@ -79,6 +79,24 @@ type (
 		settable Settable // used to unblock the future when all coroutines have completed
 	}
 
+	// Implements Semaphore interface
+	semaphoreImpl struct {
+		name    string             // human readable name which appears in stack traces of blocked coroutines
+		size    int64              // the total number of units
+		cur     int64              // the number of acquired units
+		waiters []*semaphoreWaiter // coroutines blocked on Acquire in the order of arrival
+	}
+
+	semaphoreWaiter struct {
+		n        int64
+		acquired bool
+	}
+
+	// Implements Mutex interface
+	mutexImpl struct {
+		semaphore *semaphoreImpl
+	}
+
 	// Dispatcher is a container of a set of coroutines.
 	dispatcher interface {
 		// ExecuteUntilAllBlocked executes coroutines one by one in deterministic order
@@ -135,6 +153,9 @@ type (
 		sendValue  *interface{}    // value to send to the channel. Used only for send case.
 		future     asyncFuture     // Used for future case
 		futureFunc *func(f Future) // function to call when Future is ready
+
+		timeout     time.Duration // timer duration for timeout case
+		timeoutFunc *func()       // function to call when the timer fires. nil for non timeout case.
 	}
 
 	// Implements Selector interface
@@ -158,17 +179,22 @@ type (
 		closed       atomic.Bool      // indicates that owning coroutine has finished execution
 		blocked      atomic.Bool
 		panicError   error // non nil if coroutine had unhandled panic
+		goroutineID  int64 // id of the goroutine running the coroutine, set only if native concurrency detection is enabled
 	}
 
 	dispatcherImpl struct {
-		sequence         int
-		channelSequence  int // used to name channels
-		selectorSequence int // used to name channels
-		coroutines       []*coroutineState
-		executing        bool       // currently running ExecuteUntilAllBlocked. Used to avoid recursive calls to it.
-		mutex            sync.Mutex // used to synchronize executing
-		closed           bool
-		interceptor      WorkflowOutboundCallsInterceptor
+		sequence          int
+		channelSequence   int // used to name channels
+		selectorSequence  int // used to name channels
+		mutexSequence     int // used to name mutexes
+		semaphoreSequence int // used to name semaphores
+		coroutines        []*coroutineState
+		executing         bool       // currently running ExecuteUntilAllBlocked. Used to avoid recursive calls to it.
+		mutex             sync.Mutex // used to synchronize executing
+		closed            bool
+		interceptor       WorkflowOutboundCallsInterceptor
+
+		detectNativeConcurrency bool // inspect coroutines for native go concurrency, see WorkerOptions.EnableNativeConcurrencyDetection
 	}
 
 	// WorkflowOptions options passed to the workflow function
@@ -192,6 +218,7 @@ type (
 		ParentClosePolicy        enumspb.ParentClosePolicy
 		signalChannels           map[string]Channel
 		queryHandlers            map[string]func(*commonpb.Payloads) (*commonpb.Payloads, error)
+		updates                  *updateRegistry
 	}
 
 	// ExecuteWorkflowParams parameters of the workflow invocation
@@ -257,6 +284,8 @@ const (
 var _ Channel = (*channelImpl)(nil)
 var _ Selector = (*selectorImpl)(nil)
 var _ WaitGroup = (*waitGroupImpl)(nil)
+var _ Mutex = (*mutexImpl)(nil)
+var _ Semaphore = (*semaphoreImpl)(nil)
 var _ dispatcher = (*dispatcherImpl)(nil)
 
 var stackBuf [100000]byte
@@ -487,6 +516,9 @@ func (d *syncWorkflowDefinition) Execute(env WorkflowEnvironment, header *common
 			state := getState(d.rootCtx)
 			state.yield("yield before executing to setup state")
 
+			// Update requests are dispatched from the start of the workflow, see updateRegistry.dispatch.
+			d.dispatcher.NewCoroutine(d.rootCtx, "update-dispatcher", getWorkflowEnvOptions(d.rootCtx).updates.dispatch)
+
 			// TODO: @shreyassrivatsan - add workflow trace span here
 			r.workflowResult, r.error = d.workflow.Execute(d.rootCtx, input)
 			rpp := getWorkflowResultPointerPointer(ctx)
@@ -512,26 +544,13 @@ func (d *syncWorkflowDefinition) Execute(env WorkflowEnvironment, header *common
 	})
 
 	getWorkflowEnvironment(d.rootCtx).RegisterSignalHandler(func(name string, result *commonpb.Payloads) {
-		eo := getWorkflowEnvOptions(d.rootCtx)
-		// We don't want this code to be blocked ever, using sendAsync().
-		ch := eo.getSignalChannel(d.rootCtx, name).(*channelImpl)
-		ok := ch.SendAsync(result)
-		if !ok {
-			panic(fmt.Sprintf("Exceeded channel buffer size for signal: %v", name))
+		if err := envInterceptor.inboundInterceptor.HandleSignal(d.rootCtx, name, result); err != nil {
+			panic(fmt.Sprintf("Unable to handle signal %v: %v", name, err))
 		}
 	})
 
 	getWorkflowEnvironment(d.rootCtx).RegisterQueryHandler(func(queryType string, queryArgs *commonpb.Payloads) (*commonpb.Payloads, error) {
-		eo := getWorkflowEnvOptions(d.rootCtx)
-		handler, ok := eo.queryHandlers[queryType]
-		if !ok {
-			keys := []string{QueryTypeStackTrace, QueryTypeOpenSessions}
-			for k := range eo.queryHandlers {
-				keys = append(keys, k)
-			}
-			return nil, fmt.Errorf("unknown queryType %v. KnownQueryTypes=%v", queryType, keys)
-		}
-		return handler(queryArgs)
+		return envInterceptor.inboundInterceptor.HandleQuery(d.rootCtx, queryType, queryArgs)
 	})
 }
 
@@ -553,7 +572,10 @@ func (d *syncWorkflowDefinition) Close() {
 // Context passed to the root function is child of the passed rootCtx.
 // This way rootCtx can be used to pass values to the coroutine code.
 func newDispatcher(rootCtx Context, interceptor *workflowEnvironmentInterceptor, root func(ctx Context)) (*dispatcherImpl, Context) {
-	result := &dispatcherImpl{interceptor: interceptor.outboundInterceptor}
+	result := &dispatcherImpl{
+		interceptor:             interceptor.outboundInterceptor,
+		detectNativeConcurrency: getWorkflowEnvironment(rootCtx).IsNativeConcurrencyDetectionEnabled(),
+	}
 	interceptor.dispatcher = result
 	ctxWithState := result.interceptor.Go(rootCtx, "root", root)
 	return result, ctxWithState
@@ -656,6 +678,22 @@ func (c *channelImpl) ReceiveAsync(valuePtr interface{}) (ok bool) {
 	return ok
 }
 
+func (c *channelImpl) ReceiveWithTimeout(ctx Context, timeout time.Duration, valuePtr interface{}) (ok bool, more bool) {
+	if ok, more = c.ReceiveAsyncWithMoreFlag(valuePtr); ok || !more {
+		return ok, more
+	}
+	NewNamedSelector(ctx, fmt.Sprintf("%s.ReceiveWithTimeout", c.name)).
+		AddReceive(c, func(c ReceiveChannel, m bool) {
+			more = c.Receive(ctx, valuePtr)
+			ok = more
+		}).
+		AddTimeout(timeout, func() {
+			ok, more = false, true
+		}).
+		Select(ctx)
+	return ok, more
+}
+
 func (c *channelImpl) ReceiveAsyncWithMoreFlag(valuePtr interface{}) (ok bool, more bool) {
 	for {
 		v, ok, more := c.receiveAsyncImpl(nil)
@@ -805,7 +843,7 @@ func (c *channelImpl) assignValue(from interface{}, to interface{}) error {
 	// add to metrics
 	if err != nil {
 		c.env.GetLogger().Error(fmt.Sprintf("Deserialization error. Corrupted signal received on channel %s.", c.name), tagError, err)
-		c.env.GetMetricsScope().Counter(metrics.CorruptedSignalsCounter).Inc(1)
+		c.env.GetMetricsHandler().Counter(metrics.CorruptedSignalsCounter).Inc(1)
 	}
 	return err
 }
@@ -876,12 +914,28 @@ func (s *coroutineState) call(timeout time.Duration) {
 	deadlockTimer := time.NewTimer(timeout)
 	defer func() { deadlockTimer.Stop() }()
 
-	select {
-	case <-s.aboutToBlock:
-	case <-deadlockTimer.C:
-		s.closed.Store(true)
-		panic(fmt.Sprintf("Potential deadlock detected: "+
-			"workflow goroutine %q didn't yield for over a second", s.name))
+	// nil channel is never ready, so the check case is disabled unless detection is enabled.
+	var checkNativeConcurrency <-chan time.Time
+	if s.dispatcher.detectNativeConcurrency {
+		ticker := time.NewTicker(nativeConcurrencyCheckInterval)
+		defer ticker.Stop()
+		checkNativeConcurrency = ticker.C
+	}
+
+	for {
+		select {
+		case <-s.aboutToBlock:
+			if s.dispatcher.detectNativeConcurrency {
+				s.checkNativeGoroutines()
+			}
+			return
+		case <-deadlockTimer.C:
+			s.closed.Store(true)
+			panic(fmt.Sprintf("Potential deadlock detected: "+
+				"workflow goroutine %q didn't yield for over a second", s.name))
+		case <-checkNativeConcurrency:
+			s.checkNativeBlocking()
+		}
 	}
 }
 
@@ -918,6 +972,9 @@ func (d *dispatcherImpl) NewCoroutine(ctx Context, name string, f func(ctx Conte
 	state := d.newState(name)
 	spawned := WithValue(ctx, coroutinesContextKey, state)
 	go func(crt *coroutineState) {
+		if crt.dispatcher.detectNativeConcurrency {
+			crt.goroutineID = currentGoroutineID()
+		}
 		defer crt.close()
 		defer func() {
 			if r := recover(); r != nil {
@@ -1048,6 +1105,11 @@ func (s *selectorImpl) AddFuture(future Future, f func(future Future)) Selector
 	return s
 }
 
+func (s *selectorImpl) AddTimeout(timeout time.Duration, f func()) Selector {
+	s.cases = append(s.cases, &selectCase{timeout: timeout, timeoutFunc: &f})
+	return s
+}
+
 func (s *selectorImpl) AddDefault(f func()) {
 	s.defaultFunc = &f
 }
@@ -1179,6 +1241,40 @@ func (s *selectorImpl) Select(ctx Context) {
 		f()
 		return
 	}
+	// Timers are started only when Select is about to block, so no timer command is generated when any other
+	// branch is ready right away.
+	for _, pair := range s.cases {
+		if pair.timeoutFunc == nil {
+			continue
+		}
+		f := *pair.timeoutFunc
+		timerCtx, cancelTimer := WithCancel(ctx)
+		timer, isAsync := NewTimer(timerCtx, pair.timeout).(asyncFuture)
+		if !isAsync {
+			panic("cannot use timer Future that wasn't created with workflow.NewFuture")
+		}
+		callback := &receiveCallback{
+			fn: func(v interface{}, more bool) bool {
+				if readyBranch != nil {
+					return false
+				}
+				readyBranch = f
+				return true
+			},
+		}
+		if _, ok, _ := timer.GetAsync(callback); ok {
+			cancelTimer()
+			readyBranch = func() {
+			}
+			f()
+			return
+		}
+		// cancel the timer when another branch is picked to remove its pending timer command
+		cleanups = append(cleanups, func() {
+			timer.RemoveReceiveCallback(callback)
+			cancelTimer()
+		})
+	}
 	for {
 		if readyBranch != nil {
 			readyBranch()
@@ -1230,6 +1326,7 @@ func setWorkflowEnvOptionsIfNotExist(ctx Context) Context {
 	} else {
 		newOptions.signalChannels = make(map[string]Channel)
 		newOptions.queryHandlers = make(map[string]func(*commonpb.Payloads) (*commonpb.Payloads, error))
+		newOptions.updates = newUpdateRegistry()
 	}
 	if newOptions.DataConverter == nil {
 		newOptions.DataConverter = converter.GetDefaultDataConverter()
@@ -1464,3 +1561,130 @@ func (wg *waitGroupImpl) Wait(ctx Context) {
 	}
 	wg.future, wg.settable = NewFuture(ctx)
 }
+
+// Acquire blocks until n units of the semaphore are acquired or ctx is canceled.
+func (s *semaphoreImpl) Acquire(ctx Context, n int64) error {
+	_, err := s.acquire(ctx, n, nil, "Acquire")
+	return err
+}
+
+// AcquireWithTimeout blocks until n units of the semaphore are acquired, the timeout expires or ctx is canceled.
+func (s *semaphoreImpl) AcquireWithTimeout(ctx Context, timeout time.Duration, n int64) (ok bool, err error) {
+	if s.TryAcquire(ctx, n) {
+		return true, nil
+	}
+	timerCtx, cancelTimer := WithCancel(ctx)
+	defer cancelTimer()
+	return s.acquire(ctx, n, NewTimer(timerCtx, timeout), "AcquireWithTimeout")
+}
+
+// TryAcquire acquires n units of the semaphore if they are available and no other coroutine is waiting.
+func (s *semaphoreImpl) TryAcquire(_ Context, n int64) bool {
+	s.validateAcquire(n)
+	if len(s.waiters) > 0 || s.cur+n > s.size {
+		return false
+	}
+	s.cur += n
+	return true
+}
+
+// Release releases n units of the semaphore and passes them to the waiting coroutines in the order of arrival.
+func (s *semaphoreImpl) Release(n int64) {
+	if n <= 0 || n > s.cur {
+		panic(fmt.Sprintf("%s released more than held", s.name))
+	}
+	s.cur -= n
+	s.notifyWaiters()
+}
+
+func (s *semaphoreImpl) validateAcquire(n int64) {
+	if n <= 0 || n > s.size {
+		panic(fmt.Sprintf("%s acquired %v units which is out of range (0, %v]", s.name, n, s.size))
+	}
+}
+
+// acquire blocks the coroutine until the units are acquired. Returns false if timer is ready before that.
+func (s *semaphoreImpl) acquire(ctx Context, n int64, timer Future, op string) (bool, error) {
+	if s.TryAcquire(ctx, n) {
+		return true, nil
+	}
+	state := getState(ctx)
+	defer state.unblocked()
+
+	w := &semaphoreWaiter{n: n}
+	s.waiters = append(s.waiters, w)
+	for !w.acquired {
+		doneCh := ctx.Done()
+		if doneCh != nil {
+			if _, more := doneCh.ReceiveAsyncWithMoreFlag(nil); !more {
+				s.removeWaiter(w)
+				return false, NewCanceledError(fmt.Sprintf("%s.%s context canceled", s.name, op))
+			}
+		}
+		if timer != nil && timer.IsReady() {
+			s.removeWaiter(w)
+			return false, nil
+		}
+		state.yield(fmt.Sprintf("blocked on %s.%s", s.name, op))
+	}
+	return true, nil
+}
+
+// notifyWaiters hands available units to the waiters from the head of the queue. A waiter which needs more units
+// than available blocks the ones behind it, so large acquisitions are not starved.
+func (s *semaphoreImpl) notifyWaiters() {
+	for len(s.waiters) > 0 {
+		w := s.waiters[0]
+		if s.cur+w.n > s.size {
+			return
+		}
+		s.cur += w.n
+		w.acquired = true
+		s.waiters = s.waiters[1:]
+	}
+}
+
+func (s *semaphoreImpl) removeWaiter(w *semaphoreWaiter) {
+	for i, waiter := range s.waiters {
+		if waiter == w {
+			s.waiters = append(s.waiters[:i], s.waiters[i+1:]...)
+			break
+		}
+	}
+	// Waiters behind the removed one might be able to proceed now.
+	s.notifyWaiters()
+}
+
+// Lock blocks until the mutex is acquired or ctx is canceled.
+func (m *mutexImpl) Lock(ctx Context) error {
+	_, err := m.semaphore.acquire(ctx, 1, nil, "Lock")
+	return err
+}
+
+// LockWithTimeout blocks until the mutex is acquired, the timeout expires or ctx is canceled.
+func (m *mutexImpl) LockWithTimeout(ctx Context, timeout time.Duration) (ok bool, err error) {
+	if m.TryLock(ctx) {
+		return true, nil
+	}
+	timerCtx, cancelTimer := WithCancel(ctx)
+	defer cancelTimer()
+	return m.semaphore.acquire(ctx, 1, NewTimer(timerCtx, timeout), "LockWithTimeout")
+}
+
+// TryLock acquires the mutex if it is not held and no other coroutine is waiting for it.
+func (m *mutexImpl) TryLock(ctx Context) bool {
+	return m.semaphore.TryAcquire(ctx, 1)
+}
+
+// Unlock releases the mutex and passes it to the first waiting coroutine.
+func (m *mutexImpl) Unlock() {
+	if m.semaphore.cur == 0 {
+		panic(fmt.Sprintf("unlock of unlocked %s", m.semaphore.name))
+	}
+	m.semaphore.Release(1)
+}
+
+// IsLocked returns true if the mutex is held.
+func (m *mutexImpl) IsLocked() bool {
+	return m.semaphore.cur > 0
+}
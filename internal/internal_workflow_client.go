@@ -30,20 +30,22 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"sync"
 	"time"
 
 	"github.com/opentracing/opentracing-go"
 	"github.com/pborman/uuid"
-	"github.com/uber-go/tally"
 	commonpb "go.temporal.io/api/common/v1"
 	enumspb "go.temporal.io/api/enums/v1"
 	historypb "go.temporal.io/api/history/v1"
 	querypb "go.temporal.io/api/query/v1"
 	"go.temporal.io/api/serviceerror"
 	taskqueuepb "go.temporal.io/api/taskqueue/v1"
+	workflowpb "go.temporal.io/api/workflow/v1"
 	"go.temporal.io/api/workflowservice/v1"
 
 	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/internal/common"
 	"go.temporal.io/sdk/internal/common/metrics"
 	"go.temporal.io/sdk/internal/common/serializer"
 	"go.temporal.io/sdk/internal/common/util"
@@ -53,6 +55,7 @@ import (
 // Assert that structs do indeed implement the interfaces
 var _ Client = (*WorkflowClient)(nil)
 var _ NamespaceClient = (*namespaceClient)(nil)
+var _ ClientOutboundInterceptor = (*workflowClientInterceptor)(nil)
 
 const (
 	defaultGetHistoryTimeout = 65 * time.Second
@@ -67,21 +70,34 @@ type (
 	WorkflowClient struct {
 		workflowService    workflowservice.WorkflowServiceClient
 		connectionCloser   io.Closer
+		connection         *sharedConnection
 		namespace          string
 		registry           *registry
 		logger             log.Logger
-		metricsScope       tally.Scope
+		metricsHandler     metrics.Handler
 		identity           string
 		dataConverter      converter.DataConverter
 		contextPropagators []ContextPropagator
 		tracer             opentracing.Tracer
+		interceptor        ClientOutboundInterceptor
+		interceptorOnce    sync.Once
+
+		searchAttributesValidation bool
+		searchAttributesLock       sync.Mutex
+		searchAttributesSchema     map[string]enumspb.IndexedValueType
+	}
+
+	// workflowClientInterceptor is the last link in the client interceptor chain. It performs the actual calls to the
+	// Temporal service on behalf of the WorkflowClient.
+	workflowClientInterceptor struct {
+		client *WorkflowClient
 	}
 
 	// namespaceClient is the client for managing namespaces.
 	namespaceClient struct {
 		workflowService  workflowservice.WorkflowServiceClient
 		connectionCloser io.Closer
-		metricsScope     tally.Scope
+		metricsHandler   metrics.Handler
 		logger           log.Logger
 		identity         string
 	}
@@ -99,6 +115,34 @@ type (
 		// error. This is a blocking API.
 		Get(ctx context.Context, valuePtr interface{}) error
 
+		// GetWithTimeout is the same as Get but gives up waiting for the workflow execution result after the timeout.
+		// context.DeadlineExceeded is returned if the workflow execution hasn't completed in time.
+		GetWithTimeout(ctx context.Context, timeout time.Duration, valuePtr interface{}) error
+
+		// Signal sends a signal to the workflow execution. See Client.SignalWorkflow.
+		Signal(ctx context.Context, signalName string, arg interface{}) error
+
+		// Query queries the workflow execution. See Client.QueryWorkflow.
+		Query(ctx context.Context, queryType string, args ...interface{}) (converter.EncodedValue, error)
+
+		// Cancel requests cancellation of the workflow execution. See Client.CancelWorkflow.
+		Cancel(ctx context.Context) error
+
+		// Terminate terminates the workflow execution. See Client.TerminateWorkflow.
+		Terminate(ctx context.Context, reason string, details ...interface{}) error
+
+		// Describe returns information about the workflow execution, its pending activities and child workflows.
+		Describe(ctx context.Context) (*WorkflowExecutionDescription, error)
+
+		// GetHistory returns history events of the workflow execution. See Client.GetWorkflowHistory.
+		GetHistory(ctx context.Context, isLongPoll bool, filterType enumspb.HistoryEventFilterType) HistoryEventIterator
+
+		// FollowContinueAsNew returns a WorkflowRun for the same workflow execution which targets the last run of its
+		// continue-as-new chain. Signal, Query, Cancel, Terminate, Describe and GetHistory called on the returned
+		// WorkflowRun first follow ContinuedAsNew close events starting from this run and then target the run which
+		// didn't continue as new. Without it all of them target this run.
+		FollowContinueAsNew() WorkflowRun
+
 		// NOTE: if the started workflow return ContinueAsNewError during the workflow execution, the
 		// return result of GetRunID() will be the started workflow run ID, not the new run ID caused by ContinueAsNewError,
 		// however, Get(ctx context.Context, valuePtr interface{}) will return result from the run which did not return ContinueAsNewError.
@@ -117,6 +161,54 @@ type (
 		iterFn        func(ctx context.Context, runID string) HistoryEventIterator
 		dataConverter converter.DataConverter
 		registry      *registry
+		client        *WorkflowClient
+		// followRuns is true if calls target the last run of continue-as-new chain instead of firstRunID
+		followRuns bool
+		// lastRunID is the last run of continue-as-new chain resolved by targetRunID
+		lastRunID *lastRunIDCache
+	}
+
+	lastRunIDCache struct {
+		lock  sync.Mutex
+		runID string
+	}
+
+	// WorkflowExecutionDescription is a workflow execution returned by WorkflowRun.Describe.
+	WorkflowExecutionDescription struct {
+		WorkflowExecutionInfo
+		WorkflowExecutionTimeout   time.Duration
+		WorkflowRunTimeout         time.Duration
+		DefaultWorkflowTaskTimeout time.Duration
+		PendingActivities          []*PendingActivityInfo
+		PendingChildren            []*PendingChildExecutionInfo
+	}
+
+	// PendingActivityInfo is an activity scheduled by the workflow execution which hasn't completed yet.
+	PendingActivityInfo struct {
+		ActivityID   string
+		ActivityType string
+		State        enumspb.PendingActivityState
+		// HeartbeatDetails are details passed to the last activity.RecordHeartbeat call
+		HeartbeatDetails  converter.EncodedValues
+		LastHeartbeatTime time.Time
+		LastStartedTime   time.Time
+		Attempt           int32
+		MaximumAttempts   int32
+		ScheduledTime     time.Time
+		ExpirationTime    time.Time
+		// LastFailure is the error of the last failed attempt
+		LastFailure        error
+		LastWorkerIdentity string
+	}
+
+	// PendingChildExecutionInfo is a child workflow execution started by the workflow execution which hasn't
+	// completed yet.
+	PendingChildExecutionInfo struct {
+		WorkflowID        string
+		RunID             string
+		WorkflowType      string
+		InitiatedID       int64
+		ParentClosePolicy enumspb.ParentClosePolicy
 	}
 
 	// HistoryEventIterator represents the interface for
@@ -146,6 +238,56 @@ type (
 		// func which use a next token to get next page of history events
 		paginate func(nexttoken []byte) (*workflowservice.GetWorkflowExecutionHistoryResponse, error)
 	}
+
+	// WorkflowExecutionIterator represents the interface for
+	// workflow execution iterator returned by Client.ListWorkflowExecutions
+	WorkflowExecutionIterator interface {
+		// HasNext return whether this iterator has next value
+		HasNext() bool
+		// Next returns the next workflow execution and error
+		// The errors it can return:
+		//	- BadRequestError
+		//	- InternalServiceError
+		//	- error returned by the DataConverter when memo or search attributes can't be decoded
+		Next() (*WorkflowExecutionInfo, error)
+	}
+
+	// WorkflowExecutionInfo is a workflow execution returned by Client.ListWorkflowExecutions.
+	// Memo is decoded using the client DataConverter, search attributes are decoded using the default DataConverter
+	// the same way they are encoded by StartWorkflowOptions and workflow.UpsertSearchAttributes.
+	WorkflowExecutionInfo struct {
+		WorkflowID    string
+		RunID         string
+		WorkflowType  string
+		TaskQueue     string
+		Status        enumspb.WorkflowExecutionStatus
+		StartTime     time.Time
+		ExecutionTime time.Time
+		// CloseTime is zero for open workflow executions
+		CloseTime         time.Time
+		HistoryLength     int64
+		ParentNamespaceID string
+		// ParentExecution is nil if workflow execution doesn't have a parent
+		ParentExecution  *WorkflowExecution
+		Memo             map[string]interface{}
+		SearchAttributes map[string]interface{}
+	}
+
+	// workflowExecutionIteratorImpl is the implementation of WorkflowExecutionIterator
+	workflowExecutionIteratorImpl struct {
+		// whether this iterator is initialized
+		initialized bool
+		// local cached workflow executions and corresponding consuming index
+		nextIndex  int
+		executions []*workflowpb.WorkflowExecutionInfo
+		// token to get next page of workflow executions
+		nexttoken []byte
+		// err when getting next page of workflow executions
+		err error
+		// func which use a next token to get next page of workflow executions
+		paginate      func(nexttoken []byte) ([]*workflowpb.WorkflowExecutionInfo, []byte, error)
+		dataConverter converter.DataConverter
+	}
 )
 
 // StartWorkflow starts a workflow execution
@@ -183,6 +325,10 @@ func (wc *WorkflowClient) StartWorkflow(
 		return nil, err
 	}
 
+	if err := wc.validateSearchAttributes(ctx, options.SearchAttributes); err != nil {
+		return nil, err
+	}
+
 	searchAttr, err := serializeSearchAttributes(options.SearchAttributes)
 	if err != nil {
 		return nil, err
@@ -223,8 +369,8 @@ func (wc *WorkflowClient) StartWorkflow(
 
 	var response *workflowservice.StartWorkflowExecutionResponse
 
-	grpcCtx, cancel := newGRPCContext(ctx, grpcMetricsScope(
-		metrics.GetMetricsScopeForRPC(wc.metricsScope, workflowType.Name, metrics.NoneTagValue, options.TaskQueue)),
+	grpcCtx, cancel := newGRPCContext(ctx, grpcMetricsHandler(
+		metrics.GetMetricsHandlerForRPC(wc.metricsHandler, workflowType.Name, metrics.NoneTagValue, options.TaskQueue)),
 		defaultGrpcRetryParameters(ctx))
 	defer cancel()
 
@@ -240,6 +386,17 @@ func (wc *WorkflowClient) StartWorkflow(
 	return executionInfo, nil
 }
 
+// getInterceptor returns the head of the client interceptor chain. A WorkflowClient which wasn't created by
+// NewServiceClient has no interceptors and calls the service directly.
+func (wc *WorkflowClient) getInterceptor() ClientOutboundInterceptor {
+	wc.interceptorOnce.Do(func() {
+		if wc.interceptor == nil {
+			wc.interceptor = &workflowClientInterceptor{client: wc}
+		}
+	})
+	return wc.interceptor
+}
+
 // ExecuteWorkflow starts a workflow execution and returns a WorkflowRun that will allow you to wait until this workflow
 // reaches the end state, such as workflow finished successfully or timeout.
 // The user can use this to start using a functor like below and get the workflow execution result, as EncodedValue
@@ -251,10 +408,14 @@ func (wc *WorkflowClient) StartWorkflow(
 // subjected to change in the future.
 // NOTE: the context.Context should have a fairly large timeout, since workflow execution may take a while to be finished
 func (wc *WorkflowClient) ExecuteWorkflow(ctx context.Context, options StartWorkflowOptions, workflow interface{}, args ...interface{}) (WorkflowRun, error) {
+	return wc.getInterceptor().ExecuteWorkflow(ctx, options, workflow, args...)
+}
+
+func (w *workflowClientInterceptor) ExecuteWorkflow(ctx context.Context, options StartWorkflowOptions, workflow interface{}, args ...interface{}) (WorkflowRun, error) {
 	// start the workflow execution
 	var runID string
 	var workflowID string
-	executionInfo, err := wc.StartWorkflow(ctx, options, workflow, args...)
+	executionInfo, err := w.client.StartWorkflow(ctx, options, workflow, args...)
 	if err != nil {
 		if e, ok := err.(*serviceerror.WorkflowExecutionAlreadyStarted); ok {
 			if options.WorkflowExecutionErrorWhenAlreadyStarted {
@@ -271,9 +432,9 @@ func (wc *WorkflowClient) ExecuteWorkflow(ctx context.Context, options StartWork
 	}
 
 	iterFn := func(fnCtx context.Context, fnRunID string) HistoryEventIterator {
-		fnName, _ := getWorkflowFunctionName(wc.registry, workflow)
-		rpcScope := metrics.GetMetricsScopeForRPC(wc.metricsScope, fnName, metrics.NoneTagValue, options.TaskQueue)
-		return wc.getWorkflowHistory(fnCtx, workflowID, fnRunID, true, enumspb.HISTORY_EVENT_FILTER_TYPE_CLOSE_EVENT, rpcScope)
+		fnName, _ := getWorkflowFunctionName(w.client.registry, workflow)
+		rpcMetricsHandler := metrics.GetMetricsHandlerForRPC(w.client.metricsHandler, fnName, metrics.NoneTagValue, options.TaskQueue)
+		return w.client.getWorkflowHistory(fnCtx, workflowID, fnRunID, true, enumspb.HISTORY_EVENT_FILTER_TYPE_CLOSE_EVENT, rpcMetricsHandler)
 	}
 
 	curRunIDCell := util.PopulatedOnceCell(runID)
@@ -283,8 +444,9 @@ func (wc *WorkflowClient) ExecuteWorkflow(ctx context.Context, options StartWork
 		firstRunID:    runID,
 		currentRunID:  &curRunIDCell,
 		iterFn:        iterFn,
-		dataConverter: wc.dataConverter,
-		registry:      wc.registry,
+		dataConverter: w.client.dataConverter,
+		registry:      w.client.registry,
+		client:        w.client,
 	}, nil
 }
 
@@ -293,9 +455,13 @@ func (wc *WorkflowClient) ExecuteWorkflow(ctx context.Context, options StartWork
 // The current timeout resolution implementation is in seconds and uses math.Ceil(d.Seconds()) as the duration. But is
 // subjected to change in the future.
 func (wc *WorkflowClient) GetWorkflow(ctx context.Context, workflowID string, runID string) WorkflowRun {
+	return wc.getInterceptor().GetWorkflow(ctx, workflowID, runID)
+}
+
+func (w *workflowClientInterceptor) GetWorkflow(ctx context.Context, workflowID string, runID string) WorkflowRun {
 
 	iterFn := func(fnCtx context.Context, fnRunID string) HistoryEventIterator {
-		return wc.GetWorkflowHistory(fnCtx, workflowID, fnRunID, true, enumspb.HISTORY_EVENT_FILTER_TYPE_CLOSE_EVENT)
+		return w.client.getWorkflowHistory(fnCtx, workflowID, fnRunID, true, enumspb.HISTORY_EVENT_FILTER_TYPE_CLOSE_EVENT, w.client.metricsHandler)
 	}
 
 	// The ID may not actually have been set - if not, we have to (lazily) ask the server for info about the workflow
@@ -305,7 +471,7 @@ func (wc *WorkflowClient) GetWorkflow(ctx context.Context, workflowID string, ru
 	var runIDCell util.OnceCell
 	if runID == "" {
 		fetcher := func() string {
-			execData, _ := wc.DescribeWorkflowExecution(ctx, workflowID, runID)
+			execData, _ := w.client.describeWorkflowExecution(ctx, workflowID, runID)
 			wei := execData.GetWorkflowExecutionInfo()
 			if wei != nil {
 				execution := wei.GetExecution()
@@ -325,32 +491,37 @@ func (wc *WorkflowClient) GetWorkflow(ctx context.Context, workflowID string, ru
 		firstRunID:    runID,
 		currentRunID:  &runIDCell,
 		iterFn:        iterFn,
-		dataConverter: wc.dataConverter,
-		registry:      wc.registry,
+		dataConverter: w.client.dataConverter,
+		registry:      w.client.registry,
+		client:        w.client,
 	}
 }
 
 // SignalWorkflow signals a workflow in execution.
 func (wc *WorkflowClient) SignalWorkflow(ctx context.Context, workflowID string, runID string, signalName string, arg interface{}) error {
-	input, err := encodeArg(wc.dataConverter, arg)
+	return wc.getInterceptor().SignalWorkflow(ctx, workflowID, runID, signalName, arg)
+}
+
+func (w *workflowClientInterceptor) SignalWorkflow(ctx context.Context, workflowID string, runID string, signalName string, arg interface{}) error {
+	input, err := encodeArg(w.client.dataConverter, arg)
 	if err != nil {
 		return err
 	}
 
 	request := &workflowservice.SignalWorkflowExecutionRequest{
-		Namespace: wc.namespace,
+		Namespace: w.client.namespace,
 		WorkflowExecution: &commonpb.WorkflowExecution{
 			WorkflowId: workflowID,
 			RunId:      runID,
 		},
 		SignalName: signalName,
 		Input:      input,
-		Identity:   wc.identity,
+		Identity:   w.client.identity,
 	}
 
 	grpcCtx, cancel := newGRPCContext(ctx, defaultGrpcRetryParameters(ctx))
 	defer cancel()
-	_, err = wc.workflowService.SignalWorkflowExecution(grpcCtx, request)
+	_, err = w.client.workflowService.SignalWorkflowExecution(grpcCtx, request)
 	return err
 }
 
@@ -358,8 +529,13 @@ func (wc *WorkflowClient) SignalWorkflow(ctx context.Context, workflowID string,
 // If the workflow is not running or not found, it starts the workflow and then sends the signal in transaction.
 func (wc *WorkflowClient) SignalWithStartWorkflow(ctx context.Context, workflowID string, signalName string, signalArg interface{},
 	options StartWorkflowOptions, workflowFunc interface{}, workflowArgs ...interface{}) (WorkflowRun, error) {
+	return wc.getInterceptor().SignalWithStartWorkflow(ctx, workflowID, signalName, signalArg, options, workflowFunc, workflowArgs...)
+}
+
+func (w *workflowClientInterceptor) SignalWithStartWorkflow(ctx context.Context, workflowID string, signalName string, signalArg interface{},
+	options StartWorkflowOptions, workflowFunc interface{}, workflowArgs ...interface{}) (WorkflowRun, error) {
 
-	signalInput, err := encodeArg(wc.dataConverter, signalArg)
+	signalInput, err := encodeArg(w.client.dataConverter, signalArg)
 	if err != nil {
 		return nil, err
 	}
@@ -373,30 +549,34 @@ func (wc *WorkflowClient) SignalWithStartWorkflow(ctx context.Context, workflowI
 	taskTimeout := options.WorkflowTaskTimeout
 
 	// Validate type and its arguments.
-	workflowType, input, err := getValidatedWorkflowFunction(workflowFunc, workflowArgs, wc.dataConverter, wc.registry)
+	workflowType, input, err := getValidatedWorkflowFunction(workflowFunc, workflowArgs, w.client.dataConverter, w.client.registry)
 	if err != nil {
 		return nil, err
 	}
 
-	memo, err := getWorkflowMemo(options.Memo, wc.dataConverter)
+	memo, err := getWorkflowMemo(options.Memo, w.client.dataConverter)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := w.client.validateSearchAttributes(ctx, options.SearchAttributes); err != nil {
+		return nil, err
+	}
+
 	searchAttr, err := serializeSearchAttributes(options.SearchAttributes)
 	if err != nil {
 		return nil, err
 	}
 
 	// create a workflow start span and attach it to the context object. finish it immediately
-	ctx, span := createOpenTracingWorkflowSpan(ctx, wc.tracer, time.Now(), fmt.Sprintf("SignalWithStartWorkflow-%s", workflowType.Name), workflowID)
+	ctx, span := createOpenTracingWorkflowSpan(ctx, w.client.tracer, time.Now(), fmt.Sprintf("SignalWithStartWorkflow-%s", workflowType.Name), workflowID)
 	span.Finish()
 
 	// get workflow headers from the context
-	header := wc.getWorkflowHeader(ctx)
+	header := w.client.getWorkflowHeader(ctx)
 
 	signalWithStartRequest := &workflowservice.SignalWithStartWorkflowExecutionRequest{
-		Namespace:                wc.namespace,
+		Namespace:                w.client.namespace,
 		RequestId:                uuid.New(),
 		WorkflowId:               workflowID,
 		WorkflowType:             &commonpb.WorkflowType{Name: workflowType.Name},
@@ -407,7 +587,7 @@ func (wc *WorkflowClient) SignalWithStartWorkflow(ctx context.Context, workflowI
 		WorkflowTaskTimeout:      &taskTimeout,
 		SignalName:               signalName,
 		SignalInput:              signalInput,
-		Identity:                 wc.identity,
+		Identity:                 w.client.identity,
 		RetryPolicy:              convertToPBRetryPolicy(options.RetryPolicy),
 		CronSchedule:             options.CronSchedule,
 		Memo:                     memo,
@@ -422,14 +602,14 @@ func (wc *WorkflowClient) SignalWithStartWorkflow(ctx context.Context, workflowI
 	grpcCtx, cancel := newGRPCContext(ctx, defaultGrpcRetryParameters(ctx))
 	defer cancel()
 
-	response, err = wc.workflowService.SignalWithStartWorkflowExecution(grpcCtx, signalWithStartRequest)
+	response, err = w.client.workflowService.SignalWithStartWorkflowExecution(grpcCtx, signalWithStartRequest)
 	if err != nil {
 		return nil, err
 	}
 
 	iterFn := func(fnCtx context.Context, fnRunID string) HistoryEventIterator {
-		rpcScope := metrics.GetMetricsScopeForRPC(wc.metricsScope, workflowType.Name, metrics.NoneTagValue, options.TaskQueue)
-		return wc.getWorkflowHistory(fnCtx, workflowID, fnRunID, true, enumspb.HISTORY_EVENT_FILTER_TYPE_CLOSE_EVENT, rpcScope)
+		rpcMetricsHandler := metrics.GetMetricsHandlerForRPC(w.client.metricsHandler, workflowType.Name, metrics.NoneTagValue, options.TaskQueue)
+		return w.client.getWorkflowHistory(fnCtx, workflowID, fnRunID, true, enumspb.HISTORY_EVENT_FILTER_TYPE_CLOSE_EVENT, rpcMetricsHandler)
 	}
 
 	curRunIDCell := util.PopulatedOnceCell(response.GetRunId())
@@ -439,8 +619,9 @@ func (wc *WorkflowClient) SignalWithStartWorkflow(ctx context.Context, workflowI
 		firstRunID:    response.GetRunId(),
 		currentRunID:  &curRunIDCell,
 		iterFn:        iterFn,
-		dataConverter: wc.dataConverter,
-		registry:      wc.registry,
+		dataConverter: w.client.dataConverter,
+		registry:      w.client.registry,
+		client:        w.client,
 	}, nil
 }
 
@@ -448,17 +629,21 @@ func (wc *WorkflowClient) SignalWithStartWorkflow(ctx context.Context, workflowI
 // workflowID is required, other parameters are optional.
 // If runID is omit, it will terminate currently running workflow (if there is one) based on the workflowID.
 func (wc *WorkflowClient) CancelWorkflow(ctx context.Context, workflowID string, runID string) error {
+	return wc.getInterceptor().CancelWorkflow(ctx, workflowID, runID)
+}
+
+func (w *workflowClientInterceptor) CancelWorkflow(ctx context.Context, workflowID string, runID string) error {
 	request := &workflowservice.RequestCancelWorkflowExecutionRequest{
-		Namespace: wc.namespace,
+		Namespace: w.client.namespace,
 		WorkflowExecution: &commonpb.WorkflowExecution{
 			WorkflowId: workflowID,
 			RunId:      runID,
 		},
-		Identity: wc.identity,
+		Identity: w.client.identity,
 	}
 	grpcCtx, cancel := newGRPCContext(ctx, defaultGrpcRetryParameters(ctx))
 	defer cancel()
-	_, err := wc.workflowService.RequestCancelWorkflowExecution(grpcCtx, request)
+	_, err := w.client.workflowService.RequestCancelWorkflowExecution(grpcCtx, request)
 	return err
 }
 
@@ -466,25 +651,29 @@ func (wc *WorkflowClient) CancelWorkflow(ctx context.Context, workflowID string,
 // workflowID is required, other parameters are optional.
 // If runID is omit, it will terminate currently running workflow (if there is one) based on the workflowID.
 func (wc *WorkflowClient) TerminateWorkflow(ctx context.Context, workflowID string, runID string, reason string, details ...interface{}) error {
-	datailsPayload, err := wc.dataConverter.ToPayloads(details...)
+	return wc.getInterceptor().TerminateWorkflow(ctx, workflowID, runID, reason, details...)
+}
+
+func (w *workflowClientInterceptor) TerminateWorkflow(ctx context.Context, workflowID string, runID string, reason string, details ...interface{}) error {
+	datailsPayload, err := w.client.dataConverter.ToPayloads(details...)
 	if err != nil {
 		return err
 	}
 
 	request := &workflowservice.TerminateWorkflowExecutionRequest{
-		Namespace: wc.namespace,
+		Namespace: w.client.namespace,
 		WorkflowExecution: &commonpb.WorkflowExecution{
 			WorkflowId: workflowID,
 			RunId:      runID,
 		},
 		Reason:   reason,
-		Identity: wc.identity,
+		Identity: w.client.identity,
 		Details:  datailsPayload,
 	}
 
 	grpcCtx, cancel := newGRPCContext(ctx, defaultGrpcRetryParameters(ctx))
 	defer cancel()
-	_, err = wc.workflowService.TerminateWorkflowExecution(grpcCtx, request)
+	_, err = w.client.workflowService.TerminateWorkflowExecution(grpcCtx, request)
 	return err
 }
 
@@ -496,7 +685,17 @@ func (wc *WorkflowClient) GetWorkflowHistory(
 	isLongPoll bool,
 	filterType enumspb.HistoryEventFilterType,
 ) HistoryEventIterator {
-	return wc.getWorkflowHistory(ctx, workflowID, runID, isLongPoll, filterType, wc.metricsScope)
+	return wc.getInterceptor().GetWorkflowHistory(ctx, workflowID, runID, isLongPoll, filterType)
+}
+
+func (w *workflowClientInterceptor) GetWorkflowHistory(
+	ctx context.Context,
+	workflowID string,
+	runID string,
+	isLongPoll bool,
+	filterType enumspb.HistoryEventFilterType,
+) HistoryEventIterator {
+	return w.client.getWorkflowHistory(ctx, workflowID, runID, isLongPoll, filterType, w.client.metricsHandler)
 }
 
 func (wc *WorkflowClient) getWorkflowHistory(
@@ -505,7 +704,7 @@ func (wc *WorkflowClient) getWorkflowHistory(
 	runID string,
 	isLongPoll bool,
 	filterType enumspb.HistoryEventFilterType,
-	rpcMetricsScope tally.Scope,
+	rpcMetricsHandler metrics.Handler,
 ) HistoryEventIterator {
 	namespace := wc.namespace
 	paginate := func(nextToken []byte) (*workflowservice.GetWorkflowExecutionHistoryResponse, error) {
@@ -525,7 +724,7 @@ func (wc *WorkflowClient) getWorkflowHistory(
 		var err error
 	Loop:
 		for {
-			response, err = wc.getWorkflowExecutionHistory(ctx, rpcMetricsScope, isLongPoll, request, filterType)
+			response, err = wc.getWorkflowExecutionHistory(ctx, rpcMetricsHandler, isLongPoll, request, filterType)
 			if err != nil {
 				return nil, err
 			}
@@ -543,9 +742,9 @@ func (wc *WorkflowClient) getWorkflowHistory(
 	}
 }
 
-func (wc *WorkflowClient) getWorkflowExecutionHistory(ctx context.Context, rpcMetricsScope tally.Scope, isLongPoll bool,
+func (wc *WorkflowClient) getWorkflowExecutionHistory(ctx context.Context, rpcMetricsHandler metrics.Handler, isLongPoll bool,
 	request *workflowservice.GetWorkflowExecutionHistoryRequest, filterType enumspb.HistoryEventFilterType) (*workflowservice.GetWorkflowExecutionHistoryResponse, error) {
-	grpcCtx, cancel := newGRPCContext(ctx, grpcMetricsScope(rpcMetricsScope), grpcLongPoll(isLongPoll), defaultGrpcRetryParameters(ctx), func(builder *grpcContextBuilder) {
+	grpcCtx, cancel := newGRPCContext(ctx, grpcMetricsHandler(rpcMetricsHandler), grpcLongPoll(isLongPoll), defaultGrpcRetryParameters(ctx), func(builder *grpcContextBuilder) {
 		if isLongPoll {
 			builder.Timeout = defaultGetHistoryTimeout
 		}
@@ -574,6 +773,10 @@ func (wc *WorkflowClient) getWorkflowExecutionHistory(ctx context.Context, rpcMe
 // completed event will be reported; if err is CanceledError, activity task canceled event will be reported; otherwise,
 // activity task failed event will be reported.
 func (wc *WorkflowClient) CompleteActivity(ctx context.Context, taskToken []byte, result interface{}, err error) error {
+	return wc.getInterceptor().CompleteActivity(ctx, taskToken, result, err)
+}
+
+func (w *workflowClientInterceptor) CompleteActivity(ctx context.Context, taskToken []byte, result interface{}, err error) error {
 	if taskToken == nil {
 		return errors.New("invalid task token provided")
 	}
@@ -581,19 +784,24 @@ func (wc *WorkflowClient) CompleteActivity(ctx context.Context, taskToken []byte
 	var data *commonpb.Payloads
 	if result != nil {
 		var err0 error
-		data, err0 = encodeArg(wc.dataConverter, result)
+		data, err0 = encodeArg(w.client.dataConverter, result)
 		if err0 != nil {
 			return err0
 		}
 	}
-	request := convertActivityResultToRespondRequest(wc.identity, taskToken, data, err, wc.dataConverter, wc.namespace)
-	return reportActivityComplete(ctx, wc.workflowService, request, wc.metricsScope)
+	request := convertActivityResultToRespondRequest(w.client.identity, taskToken, data, err, w.client.dataConverter, w.client.namespace)
+	return reportActivityComplete(ctx, w.client.workflowService, request, w.client.metricsHandler)
 }
 
 // CompleteActivityByID reports activity completed. Similar to CompleteActivity
 // It takes namespace name, workflowID, runID, activityID as arguments.
 func (wc *WorkflowClient) CompleteActivityByID(ctx context.Context, namespace, workflowID, runID, activityID string,
 	result interface{}, err error) error {
+	return wc.getInterceptor().CompleteActivityByID(ctx, namespace, workflowID, runID, activityID, result, err)
+}
+
+func (w *workflowClientInterceptor) CompleteActivityByID(ctx context.Context, namespace, workflowID, runID, activityID string,
+	result interface{}, err error) error {
 
 	if activityID == "" || workflowID == "" || namespace == "" {
 		return errors.New("empty activity or workflow id or namespace")
@@ -602,33 +810,42 @@ func (wc *WorkflowClient) CompleteActivityByID(ctx context.Context, namespace, w
 	var data *commonpb.Payloads
 	if result != nil {
 		var err0 error
-		data, err0 = encodeArg(wc.dataConverter, result)
+		data, err0 = encodeArg(w.client.dataConverter, result)
 		if err0 != nil {
 			return err0
 		}
 	}
 
-	request := convertActivityResultToRespondRequestByID(wc.identity, namespace, workflowID, runID, activityID, data, err, wc.dataConverter)
-	return reportActivityCompleteByID(ctx, wc.workflowService, request, wc.metricsScope)
+	request := convertActivityResultToRespondRequestByID(w.client.identity, namespace, workflowID, runID, activityID, data, err, w.client.dataConverter)
+	return reportActivityCompleteByID(ctx, w.client.workflowService, request, w.client.metricsHandler)
 }
 
 // RecordActivityHeartbeat records heartbeat for an activity.
 func (wc *WorkflowClient) RecordActivityHeartbeat(ctx context.Context, taskToken []byte, details ...interface{}) error {
-	data, err := encodeArgs(wc.dataConverter, details)
+	return wc.getInterceptor().RecordActivityHeartbeat(ctx, taskToken, details...)
+}
+
+func (w *workflowClientInterceptor) RecordActivityHeartbeat(ctx context.Context, taskToken []byte, details ...interface{}) error {
+	data, err := encodeArgs(w.client.dataConverter, details)
 	if err != nil {
 		return err
 	}
-	return recordActivityHeartbeat(ctx, wc.workflowService, wc.metricsScope, wc.identity, taskToken, data)
+	return recordActivityHeartbeat(ctx, w.client.workflowService, w.client.metricsHandler, w.client.identity, taskToken, data)
 }
 
 // RecordActivityHeartbeatByID records heartbeat for an activity.
 func (wc *WorkflowClient) RecordActivityHeartbeatByID(ctx context.Context,
 	namespace, workflowID, runID, activityID string, details ...interface{}) error {
-	data, err := encodeArgs(wc.dataConverter, details)
+	return wc.getInterceptor().RecordActivityHeartbeatByID(ctx, namespace, workflowID, runID, activityID, details...)
+}
+
+func (w *workflowClientInterceptor) RecordActivityHeartbeatByID(ctx context.Context,
+	namespace, workflowID, runID, activityID string, details ...interface{}) error {
+	data, err := encodeArgs(w.client.dataConverter, details)
 	if err != nil {
 		return err
 	}
-	return recordActivityHeartbeatByID(ctx, wc.workflowService, wc.metricsScope, wc.identity, namespace, workflowID, runID, activityID, data)
+	return recordActivityHeartbeatByID(ctx, w.client.workflowService, w.client.metricsHandler, w.client.identity, namespace, workflowID, runID, activityID, data)
 }
 
 // ListClosedWorkflow gets closed workflow executions based on request filters
@@ -637,12 +854,16 @@ func (wc *WorkflowClient) RecordActivityHeartbeatByID(ctx context.Context,
 //  - InternalServiceError
 //  - EntityNotExistError
 func (wc *WorkflowClient) ListClosedWorkflow(ctx context.Context, request *workflowservice.ListClosedWorkflowExecutionsRequest) (*workflowservice.ListClosedWorkflowExecutionsResponse, error) {
+	return wc.getInterceptor().ListClosedWorkflow(ctx, request)
+}
+
+func (w *workflowClientInterceptor) ListClosedWorkflow(ctx context.Context, request *workflowservice.ListClosedWorkflowExecutionsRequest) (*workflowservice.ListClosedWorkflowExecutionsResponse, error) {
 	if request.GetNamespace() == "" {
-		request.Namespace = wc.namespace
+		request.Namespace = w.client.namespace
 	}
 	grpcCtx, cancel := newGRPCContext(ctx, defaultGrpcRetryParameters(ctx))
 	defer cancel()
-	response, err := wc.workflowService.ListClosedWorkflowExecutions(grpcCtx, request)
+	response, err := w.client.workflowService.ListClosedWorkflowExecutions(grpcCtx, request)
 	if err != nil {
 		return nil, err
 	}
@@ -655,12 +876,16 @@ func (wc *WorkflowClient) ListClosedWorkflow(ctx context.Context, request *workf
 //  - InternalServiceError
 //  - EntityNotExistError
 func (wc *WorkflowClient) ListOpenWorkflow(ctx context.Context, request *workflowservice.ListOpenWorkflowExecutionsRequest) (*workflowservice.ListOpenWorkflowExecutionsResponse, error) {
+	return wc.getInterceptor().ListOpenWorkflow(ctx, request)
+}
+
+func (w *workflowClientInterceptor) ListOpenWorkflow(ctx context.Context, request *workflowservice.ListOpenWorkflowExecutionsRequest) (*workflowservice.ListOpenWorkflowExecutionsResponse, error) {
 	if request.GetNamespace() == "" {
-		request.Namespace = wc.namespace
+		request.Namespace = w.client.namespace
 	}
 	grpcCtx, cancel := newGRPCContext(ctx, defaultGrpcRetryParameters(ctx))
 	defer cancel()
-	response, err := wc.workflowService.ListOpenWorkflowExecutions(grpcCtx, request)
+	response, err := w.client.workflowService.ListOpenWorkflowExecutions(grpcCtx, request)
 	if err != nil {
 		return nil, err
 	}
@@ -669,12 +894,16 @@ func (wc *WorkflowClient) ListOpenWorkflow(ctx context.Context, request *workflo
 
 // ListWorkflow implementation
 func (wc *WorkflowClient) ListWorkflow(ctx context.Context, request *workflowservice.ListWorkflowExecutionsRequest) (*workflowservice.ListWorkflowExecutionsResponse, error) {
+	return wc.getInterceptor().ListWorkflow(ctx, request)
+}
+
+func (w *workflowClientInterceptor) ListWorkflow(ctx context.Context, request *workflowservice.ListWorkflowExecutionsRequest) (*workflowservice.ListWorkflowExecutionsResponse, error) {
 	if request.GetNamespace() == "" {
-		request.Namespace = wc.namespace
+		request.Namespace = w.client.namespace
 	}
 	grpcCtx, cancel := newGRPCContext(ctx, defaultGrpcRetryParameters(ctx))
 	defer cancel()
-	response, err := wc.workflowService.ListWorkflowExecutions(grpcCtx, request)
+	response, err := w.client.workflowService.ListWorkflowExecutions(grpcCtx, request)
 	if err != nil {
 		return nil, err
 	}
@@ -683,8 +912,12 @@ func (wc *WorkflowClient) ListWorkflow(ctx context.Context, request *workflowser
 
 // ListArchivedWorkflow implementation
 func (wc *WorkflowClient) ListArchivedWorkflow(ctx context.Context, request *workflowservice.ListArchivedWorkflowExecutionsRequest) (*workflowservice.ListArchivedWorkflowExecutionsResponse, error) {
+	return wc.getInterceptor().ListArchivedWorkflow(ctx, request)
+}
+
+func (w *workflowClientInterceptor) ListArchivedWorkflow(ctx context.Context, request *workflowservice.ListArchivedWorkflowExecutionsRequest) (*workflowservice.ListArchivedWorkflowExecutionsResponse, error) {
 	if request.GetNamespace() == "" {
-		request.Namespace = wc.namespace
+		request.Namespace = w.client.namespace
 	}
 	timeout := maxListArchivedWorkflowTimeout
 	now := time.Now()
@@ -700,7 +933,7 @@ func (wc *WorkflowClient) ListArchivedWorkflow(ctx context.Context, request *wor
 	}
 	grpcCtx, cancel := newGRPCContext(ctx, grpcTimeout(timeout), defaultGrpcRetryParameters(ctx))
 	defer cancel()
-	response, err := wc.workflowService.ListArchivedWorkflowExecutions(grpcCtx, request)
+	response, err := w.client.workflowService.ListArchivedWorkflowExecutions(grpcCtx, request)
 	if err != nil {
 		return nil, err
 	}
@@ -709,12 +942,16 @@ func (wc *WorkflowClient) ListArchivedWorkflow(ctx context.Context, request *wor
 
 // ScanWorkflow implementation
 func (wc *WorkflowClient) ScanWorkflow(ctx context.Context, request *workflowservice.ScanWorkflowExecutionsRequest) (*workflowservice.ScanWorkflowExecutionsResponse, error) {
+	return wc.getInterceptor().ScanWorkflow(ctx, request)
+}
+
+func (w *workflowClientInterceptor) ScanWorkflow(ctx context.Context, request *workflowservice.ScanWorkflowExecutionsRequest) (*workflowservice.ScanWorkflowExecutionsResponse, error) {
 	if request.GetNamespace() == "" {
-		request.Namespace = wc.namespace
+		request.Namespace = w.client.namespace
 	}
 	grpcCtx, cancel := newGRPCContext(ctx, defaultGrpcRetryParameters(ctx))
 	defer cancel()
-	response, err := wc.workflowService.ScanWorkflowExecutions(grpcCtx, request)
+	response, err := w.client.workflowService.ScanWorkflowExecutions(grpcCtx, request)
 	if err != nil {
 		return nil, err
 	}
@@ -723,35 +960,145 @@ func (wc *WorkflowClient) ScanWorkflow(ctx context.Context, request *workflowser
 
 // CountWorkflow implementation
 func (wc *WorkflowClient) CountWorkflow(ctx context.Context, request *workflowservice.CountWorkflowExecutionsRequest) (*workflowservice.CountWorkflowExecutionsResponse, error) {
+	return wc.getInterceptor().CountWorkflow(ctx, request)
+}
+
+func (w *workflowClientInterceptor) CountWorkflow(ctx context.Context, request *workflowservice.CountWorkflowExecutionsRequest) (*workflowservice.CountWorkflowExecutionsResponse, error) {
 	if request.GetNamespace() == "" {
-		request.Namespace = wc.namespace
+		request.Namespace = w.client.namespace
 	}
 	grpcCtx, cancel := newGRPCContext(ctx, defaultGrpcRetryParameters(ctx))
 	defer cancel()
-	response, err := wc.workflowService.CountWorkflowExecutions(grpcCtx, request)
+	response, err := w.client.workflowService.CountWorkflowExecutions(grpcCtx, request)
 	if err != nil {
 		return nil, err
 	}
 	return response, nil
 }
 
+// ListWorkflowExecutions returns an iterator over workflow executions matching options.Query.
+// Pages are fetched through ListWorkflow, ScanWorkflow or ListArchivedWorkflow depending on options.Source.
+func (wc *WorkflowClient) ListWorkflowExecutions(ctx context.Context, options ListWorkflowOptions) WorkflowExecutionIterator {
+	return wc.getInterceptor().ListWorkflowExecutions(ctx, options)
+}
+
+func (w *workflowClientInterceptor) ListWorkflowExecutions(ctx context.Context, options ListWorkflowOptions) WorkflowExecutionIterator {
+	paginate := func(nextToken []byte) ([]*workflowpb.WorkflowExecutionInfo, []byte, error) {
+		switch options.Source {
+		case ListWorkflowSourceList:
+			response, err := w.ListWorkflow(ctx, &workflowservice.ListWorkflowExecutionsRequest{
+				PageSize:      options.PageSize,
+				NextPageToken: nextToken,
+				Query:         options.Query,
+			})
+			if err != nil {
+				return nil, nil, err
+			}
+			return response.GetExecutions(), response.GetNextPageToken(), nil
+		case ListWorkflowSourceScan:
+			response, err := w.ScanWorkflow(ctx, &workflowservice.ScanWorkflowExecutionsRequest{
+				PageSize:      options.PageSize,
+				NextPageToken: nextToken,
+				Query:         options.Query,
+			})
+			if err != nil {
+				return nil, nil, err
+			}
+			return response.GetExecutions(), response.GetNextPageToken(), nil
+		case ListWorkflowSourceArchived:
+			response, err := w.ListArchivedWorkflow(ctx, &workflowservice.ListArchivedWorkflowExecutionsRequest{
+				PageSize:      options.PageSize,
+				NextPageToken: nextToken,
+				Query:         options.Query,
+			})
+			if err != nil {
+				return nil, nil, err
+			}
+			return response.GetExecutions(), response.GetNextPageToken(), nil
+		default:
+			return nil, nil, fmt.Errorf("unknown list workflow source: %v", options.Source)
+		}
+	}
+
+	return &workflowExecutionIteratorImpl{
+		paginate:      paginate,
+		dataConverter: w.client.dataConverter,
+	}
+}
+
+// CountWorkflowExecutions returns the number of workflow executions matching the query.
+func (wc *WorkflowClient) CountWorkflowExecutions(ctx context.Context, query string) (int64, error) {
+	return wc.getInterceptor().CountWorkflowExecutions(ctx, query)
+}
+
+func (w *workflowClientInterceptor) CountWorkflowExecutions(ctx context.Context, query string) (int64, error) {
+	response, err := w.CountWorkflow(ctx, &workflowservice.CountWorkflowExecutionsRequest{Query: query})
+	if err != nil {
+		return 0, err
+	}
+	return response.GetCount(), nil
+}
+
 // GetSearchAttributes implementation
 func (wc *WorkflowClient) GetSearchAttributes(ctx context.Context) (*workflowservice.GetSearchAttributesResponse, error) {
+	return wc.getInterceptor().GetSearchAttributes(ctx)
+}
+
+func (w *workflowClientInterceptor) GetSearchAttributes(ctx context.Context) (*workflowservice.GetSearchAttributesResponse, error) {
 	grpcCtx, cancel := newGRPCContext(ctx, defaultGrpcRetryParameters(ctx))
 	defer cancel()
-	response, err := wc.workflowService.GetSearchAttributes(grpcCtx, &workflowservice.GetSearchAttributesRequest{})
+	response, err := w.client.workflowService.GetSearchAttributes(grpcCtx, &workflowservice.GetSearchAttributesRequest{})
 	if err != nil {
 		return nil, err
 	}
 	return response, nil
 }
 
+// loadSearchAttributesSchema returns search attributes registered on the server. The result is cached for the
+// lifetime of the client.
+func (wc *WorkflowClient) loadSearchAttributesSchema(ctx context.Context) (map[string]enumspb.IndexedValueType, error) {
+	wc.searchAttributesLock.Lock()
+	defer wc.searchAttributesLock.Unlock()
+	if wc.searchAttributesSchema != nil {
+		return wc.searchAttributesSchema, nil
+	}
+	response, err := wc.GetSearchAttributes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	schema := response.GetKeys()
+	if schema == nil {
+		schema = map[string]enumspb.IndexedValueType{}
+	}
+	wc.searchAttributesSchema = schema
+	return schema, nil
+}
+
+func (wc *WorkflowClient) validateSearchAttributes(ctx context.Context, attributes map[string]interface{}) error {
+	if !wc.searchAttributesValidation || len(attributes) == 0 {
+		return nil
+	}
+	schema, err := wc.loadSearchAttributesSchema(ctx)
+	if err != nil {
+		return err
+	}
+	return validateSearchAttributes(attributes, schema)
+}
+
 // DescribeWorkflowExecution returns information about the specified workflow execution.
 // The errors it can return:
 //  - BadRequestError
 //  - InternalServiceError
 //  - EntityNotExistError
 func (wc *WorkflowClient) DescribeWorkflowExecution(ctx context.Context, workflowID, runID string) (*workflowservice.DescribeWorkflowExecutionResponse, error) {
+	return wc.getInterceptor().DescribeWorkflowExecution(ctx, workflowID, runID)
+}
+
+func (w *workflowClientInterceptor) DescribeWorkflowExecution(ctx context.Context, workflowID, runID string) (*workflowservice.DescribeWorkflowExecutionResponse, error) {
+	return w.client.describeWorkflowExecution(ctx, workflowID, runID)
+}
+
+func (wc *WorkflowClient) describeWorkflowExecution(ctx context.Context, workflowID, runID string) (*workflowservice.DescribeWorkflowExecutionResponse, error) {
 	request := &workflowservice.DescribeWorkflowExecutionRequest{
 		Namespace: wc.namespace,
 		Execution: &commonpb.WorkflowExecution{
@@ -837,15 +1184,19 @@ type QueryWorkflowWithOptionsResponse struct {
 //  - EntityNotExistError
 //  - QueryFailError
 func (wc *WorkflowClient) QueryWorkflowWithOptions(ctx context.Context, request *QueryWorkflowWithOptionsRequest) (*QueryWorkflowWithOptionsResponse, error) {
+	return wc.getInterceptor().QueryWorkflowWithOptions(ctx, request)
+}
+
+func (w *workflowClientInterceptor) QueryWorkflowWithOptions(ctx context.Context, request *QueryWorkflowWithOptionsRequest) (*QueryWorkflowWithOptionsResponse, error) {
 	var input *commonpb.Payloads
 	if len(request.Args) > 0 {
 		var err error
-		if input, err = encodeArgs(wc.dataConverter, request.Args); err != nil {
+		if input, err = encodeArgs(w.client.dataConverter, request.Args); err != nil {
 			return nil, err
 		}
 	}
 	req := &workflowservice.QueryWorkflowRequest{
-		Namespace: wc.namespace,
+		Namespace: w.client.namespace,
 		Execution: &commonpb.WorkflowExecution{
 			WorkflowId: request.WorkflowID,
 			RunId:      request.RunID,
@@ -859,7 +1210,7 @@ func (wc *WorkflowClient) QueryWorkflowWithOptions(ctx context.Context, request
 
 	grpcCtx, cancel := newGRPCContext(ctx, defaultGrpcRetryParameters(ctx))
 	defer cancel()
-	resp, err := wc.workflowService.QueryWorkflow(grpcCtx, req)
+	resp, err := w.client.workflowService.QueryWorkflow(grpcCtx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -872,7 +1223,7 @@ func (wc *WorkflowClient) QueryWorkflowWithOptions(ctx context.Context, request
 	}
 	return &QueryWorkflowWithOptionsResponse{
 		QueryRejected: nil,
-		QueryResult:   newEncodedValue(resp.QueryResult, wc.dataConverter),
+		QueryResult:   newEncodedValue(resp.QueryResult, w.client.dataConverter),
 	}, nil
 }
 
@@ -885,15 +1236,19 @@ func (wc *WorkflowClient) QueryWorkflowWithOptions(ctx context.Context, request
 //  - InternalServiceError
 //  - EntityNotExistError
 func (wc *WorkflowClient) DescribeTaskQueue(ctx context.Context, taskQueue string, taskQueueType enumspb.TaskQueueType) (*workflowservice.DescribeTaskQueueResponse, error) {
+	return wc.getInterceptor().DescribeTaskQueue(ctx, taskQueue, taskQueueType)
+}
+
+func (w *workflowClientInterceptor) DescribeTaskQueue(ctx context.Context, taskQueue string, taskQueueType enumspb.TaskQueueType) (*workflowservice.DescribeTaskQueueResponse, error) {
 	request := &workflowservice.DescribeTaskQueueRequest{
-		Namespace:     wc.namespace,
+		Namespace:     w.client.namespace,
 		TaskQueue:     &taskqueuepb.TaskQueue{Name: taskQueue, Kind: enumspb.TASK_QUEUE_KIND_NORMAL},
 		TaskQueueType: taskQueueType,
 	}
 
 	grpcCtx, cancel := newGRPCContext(ctx, defaultGrpcRetryParameters(ctx))
 	defer cancel()
-	resp, err := wc.workflowService.DescribeTaskQueue(grpcCtx, request)
+	resp, err := w.client.workflowService.DescribeTaskQueue(grpcCtx, request)
 	if err != nil {
 		return nil, err
 	}
@@ -905,13 +1260,20 @@ func (wc *WorkflowClient) DescribeTaskQueue(ctx context.Context, taskQueue strin
 // And it will immediately terminating the current execution instance.
 // RequestId is used to deduplicate requests. It will be autogenerated if not set.
 func (wc *WorkflowClient) ResetWorkflowExecution(ctx context.Context, request *workflowservice.ResetWorkflowExecutionRequest) (*workflowservice.ResetWorkflowExecutionResponse, error) {
+	return wc.getInterceptor().ResetWorkflowExecution(ctx, request)
+}
+
+func (w *workflowClientInterceptor) ResetWorkflowExecution(ctx context.Context, request *workflowservice.ResetWorkflowExecutionRequest) (*workflowservice.ResetWorkflowExecutionResponse, error) {
 	if request != nil && request.GetRequestId() == "" {
 		request.RequestId = uuid.New()
 	}
+	if request != nil && request.GetNamespace() == "" {
+		request.Namespace = w.client.namespace
+	}
 
 	grpcCtx, cancel := newGRPCContext(ctx, defaultGrpcRetryParameters(ctx))
 	defer cancel()
-	resp, err := wc.workflowService.ResetWorkflowExecution(grpcCtx, request)
+	resp, err := w.client.workflowService.ResetWorkflowExecution(grpcCtx, request)
 	if err != nil {
 		return nil, err
 	}
@@ -1044,6 +1406,91 @@ func (iter *historyEventIteratorImpl) Next() (*historypb.HistoryEvent, error) {
 	panic("HistoryEventIterator Next() should return either a history event or a err")
 }
 
+func (iter *workflowExecutionIteratorImpl) HasNext() bool {
+	// Visibility (Scan in particular) may return empty page with non empty next page token,
+	// therefore keep fetching pages until one has executions or there are no more pages.
+	for {
+		if iter.nextIndex < len(iter.executions) || iter.err != nil {
+			return true
+		}
+		if iter.initialized && len(iter.nexttoken) == 0 {
+			return false
+		}
+		iter.initialized = true
+		executions, nexttoken, err := iter.paginate(iter.nexttoken)
+		iter.nextIndex = 0
+		iter.executions = executions
+		iter.nexttoken = nexttoken
+		iter.err = err
+	}
+}
+
+func (iter *workflowExecutionIteratorImpl) Next() (*WorkflowExecutionInfo, error) {
+	if !iter.HasNext() {
+		panic("WorkflowExecutionIterator Next() called without checking HasNext()")
+	}
+
+	// we have cached executions
+	if iter.nextIndex < len(iter.executions) {
+		index := iter.nextIndex
+		iter.nextIndex++
+		return convertWorkflowExecutionInfo(iter.executions[index], iter.dataConverter)
+	}
+
+	// we have err, clear that iter.err and return err
+	err := iter.err
+	iter.err = nil
+	return nil, err
+}
+
+func convertWorkflowExecutionInfo(info *workflowpb.WorkflowExecutionInfo, dc converter.DataConverter) (*WorkflowExecutionInfo, error) {
+	memo, err := decodePayloadMap(info.GetMemo().GetFields(), dc)
+	if err != nil {
+		return nil, fmt.Errorf("decode workflow memo error: %w", err)
+	}
+	searchAttributes, err := decodePayloadMap(info.GetSearchAttributes().GetIndexedFields(), converter.GetDefaultDataConverter())
+	if err != nil {
+		return nil, fmt.Errorf("decode search attributes error: %w", err)
+	}
+
+	result := &WorkflowExecutionInfo{
+		WorkflowID:        info.GetExecution().GetWorkflowId(),
+		RunID:             info.GetExecution().GetRunId(),
+		WorkflowType:      info.GetType().GetName(),
+		TaskQueue:         info.GetTaskQueue(),
+		Status:            info.GetStatus(),
+		HistoryLength:     info.GetHistoryLength(),
+		ParentNamespaceID: info.GetParentNamespaceId(),
+		StartTime:         common.TimeValue(info.GetStartTime()),
+		ExecutionTime:     common.TimeValue(info.GetExecutionTime()),
+		CloseTime:         common.TimeValue(info.GetCloseTime()),
+		Memo:              memo,
+		SearchAttributes:  searchAttributes,
+	}
+	if info.ParentExecution != nil {
+		result.ParentExecution = &WorkflowExecution{
+			ID:    info.ParentExecution.GetWorkflowId(),
+			RunID: info.ParentExecution.GetRunId(),
+		}
+	}
+	return result, nil
+}
+
+func decodePayloadMap(fields map[string]*commonpb.Payload, dc converter.DataConverter) (map[string]interface{}, error) {
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	result := make(map[string]interface{}, len(fields))
+	for k, payload := range fields {
+		var value interface{}
+		if err := dc.FromPayload(payload, &value); err != nil {
+			return nil, fmt.Errorf("%s: %w", k, err)
+		}
+		result[k] = value
+	}
+	return result, nil
+}
+
 func (workflowRun *workflowRunImpl) GetRunID() string {
 	return workflowRun.currentRunID.Get()
 }
@@ -1104,6 +1551,152 @@ func (workflowRun *workflowRunImpl) Get(ctx context.Context, valuePtr interface{
 	return err
 }
 
+func (workflowRun *workflowRunImpl) GetWithTimeout(ctx context.Context, timeout time.Duration, valuePtr interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	err := workflowRun.Get(ctx, valuePtr)
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+func (workflowRun *workflowRunImpl) Signal(ctx context.Context, signalName string, arg interface{}) error {
+	runID, err := workflowRun.targetRunID(ctx)
+	if err != nil {
+		return err
+	}
+	return workflowRun.client.SignalWorkflow(ctx, workflowRun.workflowID, runID, signalName, arg)
+}
+
+func (workflowRun *workflowRunImpl) Query(ctx context.Context, queryType string, args ...interface{}) (converter.EncodedValue, error) {
+	runID, err := workflowRun.targetRunID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return workflowRun.client.QueryWorkflow(ctx, workflowRun.workflowID, runID, queryType, args...)
+}
+
+func (workflowRun *workflowRunImpl) Cancel(ctx context.Context) error {
+	runID, err := workflowRun.targetRunID(ctx)
+	if err != nil {
+		return err
+	}
+	return workflowRun.client.CancelWorkflow(ctx, workflowRun.workflowID, runID)
+}
+
+func (workflowRun *workflowRunImpl) Terminate(ctx context.Context, reason string, details ...interface{}) error {
+	runID, err := workflowRun.targetRunID(ctx)
+	if err != nil {
+		return err
+	}
+	return workflowRun.client.TerminateWorkflow(ctx, workflowRun.workflowID, runID, reason, details...)
+}
+
+func (workflowRun *workflowRunImpl) Describe(ctx context.Context) (*WorkflowExecutionDescription, error) {
+	runID, err := workflowRun.targetRunID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	response, err := workflowRun.client.DescribeWorkflowExecution(ctx, workflowRun.workflowID, runID)
+	if err != nil {
+		return nil, err
+	}
+	return convertDescribeWorkflowExecutionResponse(response, workflowRun.dataConverter)
+}
+
+func (workflowRun *workflowRunImpl) GetHistory(ctx context.Context, isLongPoll bool, filterType enumspb.HistoryEventFilterType) HistoryEventIterator {
+	runID, err := workflowRun.targetRunID(ctx)
+	if err != nil {
+		return &historyEventIteratorImpl{
+			paginate: func(nexttoken []byte) (*workflowservice.GetWorkflowExecutionHistoryResponse, error) {
+				return nil, err
+			},
+		}
+	}
+	return workflowRun.client.GetWorkflowHistory(ctx, workflowRun.workflowID, runID, isLongPoll, filterType)
+}
+
+func (workflowRun *workflowRunImpl) FollowContinueAsNew() WorkflowRun {
+	result := *workflowRun
+	result.followRuns = true
+	result.lastRunID = &lastRunIDCache{}
+	return &result
+}
+
+// targetRunID returns run ID the calls are sent to. Empty run ID targets the current run of the workflow ID which
+// is already the last run of continue-as-new chain. The chain is followed from the last resolved run, so only runs
+// which continued as new since the previous call are fetched.
+func (workflowRun *workflowRunImpl) targetRunID(ctx context.Context) (string, error) {
+	runID := workflowRun.firstRunID
+	if !workflowRun.followRuns || runID == "" {
+		return runID, nil
+	}
+	cache := workflowRun.lastRunID
+	cache.lock.Lock()
+	if cache.runID != "" {
+		runID = cache.runID
+	}
+	cache.lock.Unlock()
+	for {
+		iter := workflowRun.client.getWorkflowHistory(ctx, workflowRun.workflowID, runID, false,
+			enumspb.HISTORY_EVENT_FILTER_TYPE_CLOSE_EVENT, workflowRun.client.metricsHandler)
+		if iter.HasNext() {
+			closeEvent, err := iter.Next()
+			if err != nil {
+				return "", err
+			}
+			if closeEvent.GetEventType() == enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_CONTINUED_AS_NEW {
+				runID = closeEvent.GetWorkflowExecutionContinuedAsNewEventAttributes().GetNewExecutionRunId()
+				continue
+			}
+		}
+		cache.lock.Lock()
+		cache.runID = runID
+		cache.lock.Unlock()
+		return runID, nil
+	}
+}
+
+func convertDescribeWorkflowExecutionResponse(response *workflowservice.DescribeWorkflowExecutionResponse, dc converter.DataConverter) (*WorkflowExecutionDescription, error) {
+	info, err := convertWorkflowExecutionInfo(response.GetWorkflowExecutionInfo(), dc)
+	if err != nil {
+		return nil, err
+	}
+	result := &WorkflowExecutionDescription{WorkflowExecutionInfo: *info}
+	if config := response.GetExecutionConfig(); config != nil {
+		result.WorkflowExecutionTimeout = common.DurationValue(config.GetWorkflowExecutionTimeout())
+		result.WorkflowRunTimeout = common.DurationValue(config.GetWorkflowRunTimeout())
+		result.DefaultWorkflowTaskTimeout = common.DurationValue(config.GetDefaultWorkflowTaskTimeout())
+	}
+	for _, activity := range response.GetPendingActivities() {
+		result.PendingActivities = append(result.PendingActivities, &PendingActivityInfo{
+			ActivityID:         activity.GetActivityId(),
+			ActivityType:       activity.GetActivityType().GetName(),
+			State:              activity.GetState(),
+			HeartbeatDetails:   newEncodedValues(activity.GetHeartbeatDetails(), dc),
+			LastHeartbeatTime:  common.TimeValue(activity.GetLastHeartbeatTime()),
+			LastStartedTime:    common.TimeValue(activity.GetLastStartedTime()),
+			Attempt:            activity.GetAttempt(),
+			MaximumAttempts:    activity.GetMaximumAttempts(),
+			ScheduledTime:      common.TimeValue(activity.GetScheduledTime()),
+			ExpirationTime:     common.TimeValue(activity.GetExpirationTime()),
+			LastFailure:        ConvertFailureToError(activity.GetLastFailure(), dc),
+			LastWorkerIdentity: activity.GetLastWorkerIdentity(),
+		})
+	}
+	for _, child := range response.GetPendingChildren() {
+		result.PendingChildren = append(result.PendingChildren, &PendingChildExecutionInfo{
+			WorkflowID:        child.GetWorkflowId(),
+			RunID:             child.GetRunId(),
+			WorkflowType:      child.GetWorkflowTypeName(),
+			InitiatedID:       child.GetInitiatedId(),
+			ParentClosePolicy: child.GetParentClosePolicy(),
+		})
+	}
+	return result, nil
+}
+
 func getWorkflowMemo(input map[string]interface{}, dc converter.DataConverter) (*commonpb.Memo, error) {
 	if input == nil {
 		return nil, nil
@@ -1111,8 +1704,7 @@ func getWorkflowMemo(input map[string]interface{}, dc converter.DataConverter) (
 
 	memo := make(map[string]*commonpb.Payload)
 	for k, v := range input {
-		// TODO (shtin): use dc here???
-		memoBytes, err := converter.GetDefaultDataConverter().ToPayload(v)
+		memoBytes, err := dc.ToPayload(v)
 		if err != nil {
 			return nil, fmt.Errorf("encode workflow memo error: %v", err.Error())
 		}
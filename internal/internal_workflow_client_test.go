@@ -162,10 +162,7 @@ func (s *historyEventIteratorSuite) SetupTest() {
 	s.mockCtrl = gomock.NewController(s.T())
 	s.workflowServiceClient = workflowservicemock.NewMockWorkflowServiceClient(s.mockCtrl)
 
-	s.wfClient = &WorkflowClient{
-		workflowService: s.workflowServiceClient,
-		namespace:       DefaultNamespace,
-	}
+	s.wfClient = NewServiceClient(s.workflowServiceClient, nil, ClientOptions{})
 }
 
 func (s *historyEventIteratorSuite) TearDownTest() {
@@ -955,6 +952,33 @@ func (s *workflowRunSuite) TestGetWorkflowNoRunId() {
 	s.Equal(runID, workflowRunNoRunID.GetRunID())
 }
 
+func (s *workflowRunSuite) TestGetWorkflowDoesNotReenterInterceptors() {
+	var calls []string
+	s.workflowClient = NewServiceClient(s.workflowServiceClient, nil, ClientOptions{
+		Logger:       ilog.NewNopLogger(),
+		Interceptors: []ClientInterceptor{&readRecordingClientInterceptor{calls: &calls}},
+	})
+	describeResp := &workflowservice.DescribeWorkflowExecutionResponse{
+		WorkflowExecutionInfo: &workflowpb.WorkflowExecutionInfo{Execution: &commonpb.WorkflowExecution{RunId: runID}}}
+	s.workflowServiceClient.EXPECT().DescribeWorkflowExecution(gomock.Any(), gomock.Any(), gomock.Any()).Return(describeResp, nil).Times(1)
+	getResponse := &workflowservice.GetWorkflowExecutionHistoryResponse{
+		History: &historypb.History{
+			Events: []*historypb.HistoryEvent{
+				{
+					EventType:  enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_COMPLETED,
+					Attributes: &historypb.HistoryEvent_WorkflowExecutionCompletedEventAttributes{WorkflowExecutionCompletedEventAttributes: &historypb.WorkflowExecutionCompletedEventAttributes{}},
+				},
+			},
+		},
+	}
+	s.workflowServiceClient.EXPECT().GetWorkflowExecutionHistory(gomock.Any(), gomock.Any(), gomock.Any()).Return(getResponse, nil).Times(1)
+
+	workflowRun := s.workflowClient.GetWorkflow(context.Background(), workflowID, "")
+	s.Equal(runID, workflowRun.GetRunID())
+	s.NoError(workflowRun.Get(context.Background(), nil))
+	s.Equal([]string{"GetWorkflow"}, calls)
+}
+
 func (s *workflowRunSuite) TestGetWorkflowNoExtantWorkflowAndNoRunId() {
 	describeResp := &workflowservice.DescribeWorkflowExecutionResponse{
 		WorkflowExecutionInfo: nil}
@@ -967,6 +991,129 @@ func (s *workflowRunSuite) TestGetWorkflowNoExtantWorkflowAndNoRunId() {
 	s.Equal("", workflowRunNoRunID.GetRunID())
 }
 
+func (s *workflowRunSuite) TestWorkflowRun_SignalCancelTerminate() {
+	s.workflowServiceClient.EXPECT().SignalWorkflowExecution(gomock.Any(), gomock.Any(), gomock.Any()).Return(&workflowservice.SignalWorkflowExecutionResponse{}, nil).
+		Do(func(_ interface{}, req *workflowservice.SignalWorkflowExecutionRequest, _ ...interface{}) {
+			s.Equal(workflowID, req.GetWorkflowExecution().GetWorkflowId())
+			s.Equal(runID, req.GetWorkflowExecution().GetRunId())
+			s.Equal("signal", req.GetSignalName())
+		})
+	s.workflowServiceClient.EXPECT().RequestCancelWorkflowExecution(gomock.Any(), gomock.Any(), gomock.Any()).Return(&workflowservice.RequestCancelWorkflowExecutionResponse{}, nil).
+		Do(func(_ interface{}, req *workflowservice.RequestCancelWorkflowExecutionRequest, _ ...interface{}) {
+			s.Equal(runID, req.GetWorkflowExecution().GetRunId())
+		})
+	s.workflowServiceClient.EXPECT().TerminateWorkflowExecution(gomock.Any(), gomock.Any(), gomock.Any()).Return(&workflowservice.TerminateWorkflowExecutionResponse{}, nil).
+		Do(func(_ interface{}, req *workflowservice.TerminateWorkflowExecutionRequest, _ ...interface{}) {
+			s.Equal(runID, req.GetWorkflowExecution().GetRunId())
+			s.Equal("reason", req.GetReason())
+		})
+
+	workflowRun := s.workflowClient.GetWorkflow(context.Background(), workflowID, runID)
+	s.NoError(workflowRun.Signal(context.Background(), "signal", "arg"))
+	s.NoError(workflowRun.Cancel(context.Background()))
+	s.NoError(workflowRun.Terminate(context.Background(), "reason"))
+}
+
+func (s *workflowRunSuite) TestWorkflowRun_FollowContinueAsNew() {
+	newRunID := "some other random run ID"
+	filterType := enumspb.HISTORY_EVENT_FILTER_TYPE_CLOSE_EVENT
+	getRequest1 := getGetWorkflowExecutionHistoryRequest(filterType)
+	getRequest1.WaitNewEvent = false
+	getRequest1.SkipArchival = false
+	getResponse1 := &workflowservice.GetWorkflowExecutionHistoryResponse{
+		History: &historypb.History{
+			Events: []*historypb.HistoryEvent{
+				{
+					EventType: enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_CONTINUED_AS_NEW,
+					Attributes: &historypb.HistoryEvent_WorkflowExecutionContinuedAsNewEventAttributes{WorkflowExecutionContinuedAsNewEventAttributes: &historypb.WorkflowExecutionContinuedAsNewEventAttributes{
+						NewExecutionRunId: newRunID,
+					}},
+				},
+			},
+		},
+	}
+	s.workflowServiceClient.EXPECT().GetWorkflowExecutionHistory(gomock.Any(), getRequest1, gomock.Any()).Return(getResponse1, nil).Times(1)
+	getRequest2 := getGetWorkflowExecutionHistoryRequest(filterType)
+	getRequest2.WaitNewEvent = false
+	getRequest2.SkipArchival = false
+	getRequest2.Execution.RunId = newRunID
+	getResponse2 := &workflowservice.GetWorkflowExecutionHistoryResponse{History: &historypb.History{}}
+	// The resolved run is cached, so the next call checks only the last run.
+	s.workflowServiceClient.EXPECT().GetWorkflowExecutionHistory(gomock.Any(), getRequest2, gomock.Any()).Return(getResponse2, nil).Times(2)
+	s.workflowServiceClient.EXPECT().SignalWorkflowExecution(gomock.Any(), gomock.Any(), gomock.Any()).Return(&workflowservice.SignalWorkflowExecutionResponse{}, nil).
+		Do(func(_ interface{}, req *workflowservice.SignalWorkflowExecutionRequest, _ ...interface{}) {
+			s.Equal(newRunID, req.GetWorkflowExecution().GetRunId())
+		}).Times(2)
+
+	workflowRun := s.workflowClient.GetWorkflow(context.Background(), workflowID, runID).FollowContinueAsNew()
+	s.Equal(runID, workflowRun.GetRunID())
+	s.NoError(workflowRun.Signal(context.Background(), "signal", "arg"))
+	s.NoError(workflowRun.Signal(context.Background(), "signal", "arg"))
+}
+
+func (s *workflowRunSuite) TestWorkflowRun_Describe() {
+	heartbeatDetails, _ := encodeArg(s.dataConverter, "progress")
+	startTime := time.Date(2020, 9, 1, 10, 0, 0, 0, time.UTC)
+	runTimeout := time.Minute
+	describeResp := &workflowservice.DescribeWorkflowExecutionResponse{
+		ExecutionConfig: &workflowpb.WorkflowExecutionConfig{WorkflowRunTimeout: &runTimeout},
+		WorkflowExecutionInfo: &workflowpb.WorkflowExecutionInfo{
+			Execution: &commonpb.WorkflowExecution{WorkflowId: workflowID, RunId: runID},
+			Type:      &commonpb.WorkflowType{Name: workflowType},
+			StartTime: &startTime,
+			Status:    enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING,
+		},
+		PendingActivities: []*workflowpb.PendingActivityInfo{
+			{
+				ActivityId:       "1",
+				ActivityType:     &commonpb.ActivityType{Name: "activityType"},
+				State:            enumspb.PENDING_ACTIVITY_STATE_STARTED,
+				HeartbeatDetails: heartbeatDetails,
+				Attempt:          2,
+			},
+		},
+		PendingChildren: []*workflowpb.PendingChildExecutionInfo{
+			{WorkflowId: "child", RunId: "childRun", WorkflowTypeName: "childType", InitiatedId: 5},
+		},
+	}
+	s.workflowServiceClient.EXPECT().DescribeWorkflowExecution(gomock.Any(), gomock.Any(), gomock.Any()).Return(describeResp, nil).
+		Do(func(_ interface{}, req *workflowservice.DescribeWorkflowExecutionRequest, _ ...interface{}) {
+			s.Equal(runID, req.GetExecution().GetRunId())
+		})
+
+	description, err := s.workflowClient.GetWorkflow(context.Background(), workflowID, runID).Describe(context.Background())
+	s.NoError(err)
+	s.Equal(workflowID, description.WorkflowID)
+	s.Equal(runID, description.RunID)
+	s.Equal(workflowType, description.WorkflowType)
+	s.Equal(startTime, description.StartTime)
+	s.Equal(runTimeout, description.WorkflowRunTimeout)
+	s.Equal(time.Duration(0), description.WorkflowExecutionTimeout)
+	s.Equal(1, len(description.PendingActivities))
+	s.Equal("activityType", description.PendingActivities[0].ActivityType)
+	s.Equal(int32(2), description.PendingActivities[0].Attempt)
+	var progress string
+	s.NoError(description.PendingActivities[0].HeartbeatDetails.Get(&progress))
+	s.Equal("progress", progress)
+	s.Nil(description.PendingActivities[0].LastFailure)
+	s.Equal([]*PendingChildExecutionInfo{
+		{WorkflowID: "child", RunID: "childRun", WorkflowType: "childType", InitiatedID: 5},
+	}, description.PendingChildren)
+}
+
+func (s *workflowRunSuite) TestWorkflowRun_GetWithTimeout() {
+	s.workflowServiceClient.EXPECT().GetWorkflowExecutionHistory(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _ *workflowservice.GetWorkflowExecutionHistoryRequest, _ ...interface{}) (*workflowservice.GetWorkflowExecutionHistoryResponse, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+
+	workflowRun := s.workflowClient.GetWorkflow(context.Background(), workflowID, runID)
+	var result string
+	err := workflowRun.GetWithTimeout(context.Background(), 10*time.Millisecond, &result)
+	s.Equal(context.DeadlineExceeded, err)
+}
+
 func getGetWorkflowExecutionHistoryRequest(filterType enumspb.HistoryEventFilterType) *workflowservice.GetWorkflowExecutionHistoryRequest {
 	request := &workflowservice.GetWorkflowExecutionHistoryRequest{
 		Namespace: DefaultNamespace,
@@ -1127,6 +1274,32 @@ func (s *workflowClientTestSuite) TestStartWorkflow_WithMemoAndSearchAttr() {
 	_, _ = s.client.ExecuteWorkflow(context.Background(), options, wf)
 }
 
+func (s *workflowClientTestSuite) TestStartWorkflow_SearchAttributesValidation() {
+	client := NewServiceClient(s.service, nil, ClientOptions{SearchAttributesValidation: true})
+	options := StartWorkflowOptions{
+		ID:                       workflowID,
+		TaskQueue:                taskqueue,
+		WorkflowExecutionTimeout: timeoutInSeconds,
+		WorkflowTaskTimeout:      timeoutInSeconds,
+		SearchAttributes:         map[string]interface{}{"CustomIntField": "not an int"},
+	}
+	wf := func(ctx Context) string {
+		panic("this is just a stub")
+	}
+
+	// schema is loaded once and cached by the client
+	s.service.EXPECT().GetSearchAttributes(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&workflowservice.GetSearchAttributesResponse{Keys: testSearchAttributesSchema}, nil).Times(1)
+	_, err := client.ExecuteWorkflow(context.Background(), options, wf)
+	s.Error(err)
+
+	options.SearchAttributes = NewSearchAttributes(NewIntKey("CustomIntField").ValueSet(2))
+	s.service.EXPECT().StartWorkflowExecution(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&workflowservice.StartWorkflowExecutionResponse{RunId: runID}, nil).Times(1)
+	_, err = client.ExecuteWorkflow(context.Background(), options, wf)
+	s.NoError(err)
+}
+
 func (s *workflowClientTestSuite) SignalWithStartWorkflowWithMemoAndSearchAttr() {
 	memo := map[string]interface{}{
 		"testMemo": "memo value",
@@ -1298,6 +1471,125 @@ func (s *workflowClientTestSuite) TestCountWorkflow() {
 	s.IsType(&serviceerror.InvalidArgument{}, err)
 }
 
+func (s *workflowClientTestSuite) TestListWorkflowExecutions() {
+	memo, err := getWorkflowMemo(map[string]interface{}{"memoKey": "memoValue"}, s.dataConverter)
+	s.NoError(err)
+	searchAttributes, err := serializeSearchAttributes(map[string]interface{}{"CustomIntField": 1})
+	s.NoError(err)
+	startTime := time.Date(2020, 9, 1, 10, 0, 0, 0, time.UTC)
+	page1 := &workflowservice.ListWorkflowExecutionsResponse{
+		Executions: []*workflowpb.WorkflowExecutionInfo{
+			{
+				Execution:        &commonpb.WorkflowExecution{WorkflowId: "wid1", RunId: "rid1"},
+				Type:             &commonpb.WorkflowType{Name: workflowType},
+				StartTime:        &startTime,
+				Status:           enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING,
+				Memo:             memo,
+				SearchAttributes: searchAttributes,
+			},
+		},
+		NextPageToken: []byte("token"),
+	}
+	page2 := &workflowservice.ListWorkflowExecutionsResponse{
+		Executions: []*workflowpb.WorkflowExecutionInfo{
+			{
+				Execution: &commonpb.WorkflowExecution{WorkflowId: "wid2", RunId: "rid2"},
+				Type:      &commonpb.WorkflowType{Name: workflowType},
+				Status:    enumspb.WORKFLOW_EXECUTION_STATUS_COMPLETED,
+			},
+		},
+	}
+	query := NewVisibilityQuery().WorkflowType(workflowType).String()
+	s.service.EXPECT().ListWorkflowExecutions(gomock.Any(), gomock.Any(), gomock.Any()).Return(page1, nil).
+		Do(func(_ interface{}, req *workflowservice.ListWorkflowExecutionsRequest, _ ...interface{}) {
+			s.Equal(query, req.GetQuery())
+			s.Equal(int32(1), req.GetPageSize())
+			s.Nil(req.GetNextPageToken())
+		})
+	s.service.EXPECT().ListWorkflowExecutions(gomock.Any(), gomock.Any(), gomock.Any()).Return(page2, nil).
+		Do(func(_ interface{}, req *workflowservice.ListWorkflowExecutionsRequest, _ ...interface{}) {
+			s.Equal([]byte("token"), req.GetNextPageToken())
+		})
+
+	iter := s.client.ListWorkflowExecutions(context.Background(), ListWorkflowOptions{Query: query, PageSize: 1})
+	var executions []*WorkflowExecutionInfo
+	for iter.HasNext() {
+		execution, err := iter.Next()
+		s.NoError(err)
+		executions = append(executions, execution)
+	}
+	s.Equal(2, len(executions))
+	s.Equal("wid1", executions[0].WorkflowID)
+	s.Equal("rid1", executions[0].RunID)
+	s.Equal(workflowType, executions[0].WorkflowType)
+	s.Equal(startTime, executions[0].StartTime)
+	s.True(executions[0].CloseTime.IsZero())
+	s.Equal(enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING, executions[0].Status)
+	s.Equal(map[string]interface{}{"memoKey": "memoValue"}, executions[0].Memo)
+	s.Equal(map[string]interface{}{"CustomIntField": float64(1)}, executions[0].SearchAttributes)
+	s.Equal("wid2", executions[1].WorkflowID)
+	s.Nil(executions[1].Memo)
+}
+
+func (s *workflowClientTestSuite) TestListWorkflowExecutions_EmptyPage() {
+	page1 := &workflowservice.ScanWorkflowExecutionsResponse{
+		Executions: []*workflowpb.WorkflowExecutionInfo{
+			{Execution: &commonpb.WorkflowExecution{WorkflowId: "wid1", RunId: "rid1"}},
+		},
+		NextPageToken: []byte("token1"),
+	}
+	page2 := &workflowservice.ScanWorkflowExecutionsResponse{
+		NextPageToken: []byte("token2"),
+	}
+	page3 := &workflowservice.ScanWorkflowExecutionsResponse{
+		Executions: []*workflowpb.WorkflowExecutionInfo{
+			{Execution: &commonpb.WorkflowExecution{WorkflowId: "wid2", RunId: "rid2"}},
+		},
+	}
+	gomock.InOrder(
+		s.service.EXPECT().ScanWorkflowExecutions(gomock.Any(), gomock.Any(), gomock.Any()).Return(page1, nil),
+		s.service.EXPECT().ScanWorkflowExecutions(gomock.Any(), gomock.Any(), gomock.Any()).Return(page2, nil).
+			Do(func(_ interface{}, req *workflowservice.ScanWorkflowExecutionsRequest, _ ...interface{}) {
+				s.Equal([]byte("token1"), req.GetNextPageToken())
+			}),
+		s.service.EXPECT().ScanWorkflowExecutions(gomock.Any(), gomock.Any(), gomock.Any()).Return(page3, nil).
+			Do(func(_ interface{}, req *workflowservice.ScanWorkflowExecutionsRequest, _ ...interface{}) {
+				s.Equal([]byte("token2"), req.GetNextPageToken())
+			}),
+	)
+
+	iter := s.client.ListWorkflowExecutions(context.Background(), ListWorkflowOptions{Source: ListWorkflowSourceScan})
+	var workflowIDs []string
+	for iter.HasNext() {
+		execution, err := iter.Next()
+		s.NoError(err)
+		workflowIDs = append(workflowIDs, execution.WorkflowID)
+	}
+	s.Equal([]string{"wid1", "wid2"}, workflowIDs)
+}
+
+func (s *workflowClientTestSuite) TestListWorkflowExecutions_Error() {
+	s.service.EXPECT().ScanWorkflowExecutions(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, serviceerror.NewInvalidArgument(""))
+
+	iter := s.client.ListWorkflowExecutions(context.Background(), ListWorkflowOptions{Source: ListWorkflowSourceScan})
+	s.True(iter.HasNext())
+	_, err := iter.Next()
+	s.IsType(&serviceerror.InvalidArgument{}, err)
+	s.False(iter.HasNext())
+}
+
+func (s *workflowClientTestSuite) TestCountWorkflowExecutions() {
+	query := NewVisibilityQuery().Open().String()
+	s.service.EXPECT().CountWorkflowExecutions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&workflowservice.CountWorkflowExecutionsResponse{Count: 3}, nil).
+		Do(func(_ interface{}, req *workflowservice.CountWorkflowExecutionsRequest, _ ...interface{}) {
+			s.Equal(DefaultNamespace, req.GetNamespace())
+			s.Equal(query, req.GetQuery())
+		})
+	count, err := s.client.CountWorkflowExecutions(context.Background(), query)
+	s.NoError(err)
+	s.Equal(int64(3), count)
+}
+
 func (s *workflowClientTestSuite) TestGetSearchAttributes() {
 	response := &workflowservice.GetSearchAttributesResponse{}
 	s.service.EXPECT().GetSearchAttributes(gomock.Any(), gomock.Any(), gomock.Any()).Return(response, nil)
@@ -1310,6 +1602,120 @@ func (s *workflowClientTestSuite) TestGetSearchAttributes() {
 	s.IsType(&serviceerror.InvalidArgument{}, err)
 }
 
+type signalRecordingClientInterceptor struct {
+	name  string
+	calls *[]string
+}
+
+func (i *signalRecordingClientInterceptor) InterceptClient(next ClientOutboundInterceptor) ClientOutboundInterceptor {
+	return &signalRecordingClientOutboundInterceptor{
+		ClientOutboundInterceptorBase: ClientOutboundInterceptorBase{Next: next},
+		name:                          i.name,
+		calls:                         i.calls,
+	}
+}
+
+type signalRecordingClientOutboundInterceptor struct {
+	ClientOutboundInterceptorBase
+	name  string
+	calls *[]string
+}
+
+func (i *signalRecordingClientOutboundInterceptor) SignalWorkflow(ctx context.Context, workflowID string, runID string, signalName string, arg interface{}) error {
+	*i.calls = append(*i.calls, i.name)
+	return i.Next.SignalWorkflow(ctx, workflowID, runID, signalName+"-"+i.name, arg)
+}
+
+type readRecordingClientInterceptor struct {
+	calls *[]string
+}
+
+func (i *readRecordingClientInterceptor) InterceptClient(next ClientOutboundInterceptor) ClientOutboundInterceptor {
+	return &readRecordingClientOutboundInterceptor{
+		ClientOutboundInterceptorBase: ClientOutboundInterceptorBase{Next: next},
+		calls:                         i.calls,
+	}
+}
+
+type readRecordingClientOutboundInterceptor struct {
+	ClientOutboundInterceptorBase
+	calls *[]string
+}
+
+func (i *readRecordingClientOutboundInterceptor) GetWorkflow(ctx context.Context, workflowID string, runID string) WorkflowRun {
+	*i.calls = append(*i.calls, "GetWorkflow")
+	return i.Next.GetWorkflow(ctx, workflowID, runID)
+}
+
+func (i *readRecordingClientOutboundInterceptor) GetWorkflowHistory(ctx context.Context, workflowID string, runID string, isLongPoll bool, filterType enumspb.HistoryEventFilterType) HistoryEventIterator {
+	*i.calls = append(*i.calls, "GetWorkflowHistory")
+	return i.Next.GetWorkflowHistory(ctx, workflowID, runID, isLongPoll, filterType)
+}
+
+func (i *readRecordingClientOutboundInterceptor) DescribeWorkflowExecution(ctx context.Context, workflowID, runID string) (*workflowservice.DescribeWorkflowExecutionResponse, error) {
+	*i.calls = append(*i.calls, "DescribeWorkflowExecution")
+	return i.Next.DescribeWorkflowExecution(ctx, workflowID, runID)
+}
+
+func (i *readRecordingClientOutboundInterceptor) CountWorkflow(ctx context.Context, request *workflowservice.CountWorkflowExecutionsRequest) (*workflowservice.CountWorkflowExecutionsResponse, error) {
+	*i.calls = append(*i.calls, "CountWorkflow")
+	return i.Next.CountWorkflow(ctx, request)
+}
+
+func (i *readRecordingClientOutboundInterceptor) CountWorkflowExecutions(ctx context.Context, query string) (int64, error) {
+	*i.calls = append(*i.calls, "CountWorkflowExecutions")
+	return i.Next.CountWorkflowExecutions(ctx, query)
+}
+
+func (s *workflowClientTestSuite) TestClientInterceptors_CountWorkflowExecutions() {
+	var calls []string
+	s.client = NewServiceClient(s.service, nil, ClientOptions{
+		Interceptors: []ClientInterceptor{&readRecordingClientInterceptor{calls: &calls}},
+	})
+	s.service.EXPECT().CountWorkflowExecutions(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&workflowservice.CountWorkflowExecutionsResponse{Count: 3}, nil)
+	count, err := s.client.CountWorkflowExecutions(context.Background(), "WorkflowType = 'MyWorkflow'")
+	s.NoError(err)
+	s.Equal(int64(3), count)
+	s.Equal([]string{"CountWorkflowExecutions"}, calls)
+}
+
+func (s *workflowClientTestSuite) TestClientWithoutInterceptors() {
+	client := &WorkflowClient{workflowService: s.service}
+	response := &workflowservice.GetSearchAttributesResponse{}
+	s.service.EXPECT().GetSearchAttributes(gomock.Any(), gomock.Any(), gomock.Any()).Return(response, nil)
+	resp, err := client.GetSearchAttributes(context.Background())
+	s.NoError(err)
+	s.Equal(response, resp)
+}
+
+func (s *workflowClientTestSuite) TestClientInterceptors() {
+	var calls []string
+	s.client = NewServiceClient(s.service, nil, ClientOptions{
+		Interceptors: []ClientInterceptor{
+			&signalRecordingClientInterceptor{name: "first", calls: &calls},
+			&signalRecordingClientInterceptor{name: "second", calls: &calls},
+		},
+	})
+
+	s.service.EXPECT().SignalWorkflowExecution(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, request *workflowservice.SignalWorkflowExecutionRequest, _ ...interface{}) (*workflowservice.SignalWorkflowExecutionResponse, error) {
+			s.Equal("my signal-first-second", request.GetSignalName())
+			return &workflowservice.SignalWorkflowExecutionResponse{}, nil
+		})
+	err := s.client.SignalWorkflow(context.Background(), workflowID, runID, "my signal", "arg")
+	s.NoError(err)
+	s.Equal([]string{"first", "second"}, calls)
+
+	// Calls that are not overridden are forwarded by the base implementation.
+	response := &workflowservice.GetSearchAttributesResponse{}
+	s.service.EXPECT().GetSearchAttributes(gomock.Any(), gomock.Any(), gomock.Any()).Return(response, nil)
+	resp, err := s.client.GetSearchAttributes(context.Background())
+	s.NoError(err)
+	s.Equal(response, resp)
+	s.Equal([]string{"first", "second"}, calls)
+}
+
 func serializeEvents(events []*historypb.HistoryEvent) *commonpb.DataBlob {
 	blob, _ := serializer.SerializeBatchEvents(events, enumspb.ENCODING_TYPE_PROTO3)
 
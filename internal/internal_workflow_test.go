@@ -36,6 +36,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	commonpb "go.temporal.io/api/common/v1"
 
 	"go.temporal.io/sdk/converter"
 	"go.temporal.io/sdk/internal/common/metrics"
@@ -1330,7 +1331,125 @@ func (t *tracingInboundCallsInterceptor) ExecuteWorkflow(ctx Context, workflowTy
 	return result
 }
 
+func (t *tracingInboundCallsInterceptor) HandleSignal(ctx Context, signalName string, arg *commonpb.Payloads) error {
+	t.trace = append(t.trace, "HandleSignal "+signalName)
+	return t.Next.HandleSignal(ctx, signalName, arg)
+}
+
+func (t *tracingInboundCallsInterceptor) HandleQuery(ctx Context, queryType string, args *commonpb.Payloads) (*commonpb.Payloads, error) {
+	t.trace = append(t.trace, "HandleQuery "+queryType)
+	return t.Next.HandleQuery(ctx, queryType, args)
+}
+
 func (t *tracingOutboundCallsInterceptor) ExecuteActivity(ctx Context, activityType string, args ...interface{}) Future {
 	t.inbound.trace = append(t.inbound.trace, "ExecuteActivity "+activityType)
 	return t.Next.ExecuteActivity(ctx, activityType, args...)
 }
+
+func signalQueryWorkflow(ctx Context) (string, error) {
+	var signal string
+	err := SetQueryHandler(ctx, "signal", func() (string, error) {
+		return signal, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	GetSignalChannel(ctx, "greeting").Receive(ctx, &signal)
+	return signal, nil
+}
+
+func (s *WorkflowUnitTest) Test_SignalQueryInterceptor() {
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(signalQueryWorkflow)
+	tracer := tracingWorkflowInterceptor{}
+	env.SetWorkerOptions(WorkerOptions{WorkflowInterceptorChainFactories: []WorkflowInterceptor{&tracer}})
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow("greeting", "Hello")
+	}, time.Minute)
+	env.ExecuteWorkflow(signalQueryWorkflow)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+
+	encoded, err := env.QueryWorkflow("signal")
+	s.NoError(err)
+	var queryResult string
+	s.NoError(encoded.Get(&queryResult))
+	s.Equal("Hello", queryResult)
+
+	s.Equal(1, len(tracer.instances))
+	s.Equal([]string{
+		"ExecuteWorkflow signalQueryWorkflow begin",
+		"HandleSignal greeting",
+		"ExecuteWorkflow signalQueryWorkflow end",
+		"HandleQuery signal",
+	}, tracer.instances[0].trace)
+}
+
+var _ ActivityInterceptor = (*tracingActivityInterceptor)(nil)
+var _ ActivityOutboundCallsInterceptor = (*tracingActivityOutboundCallsInterceptor)(nil)
+
+type (
+	tracingActivityInterceptor struct {
+		instances []*tracingActivityInboundCallsInterceptor
+	}
+
+	tracingActivityInboundCallsInterceptor struct {
+		ActivityInboundCallsInterceptorBase
+		trace []string
+	}
+
+	tracingActivityOutboundCallsInterceptor struct {
+		ActivityOutboundCallsInterceptorBase
+		inbound *tracingActivityInboundCallsInterceptor
+	}
+)
+
+func (t *tracingActivityInterceptor) InterceptActivity(info *ActivityInfo, next ActivityInboundCallsInterceptor) ActivityInboundCallsInterceptor {
+	result := &tracingActivityInboundCallsInterceptor{
+		ActivityInboundCallsInterceptorBase{
+			Next: next,
+		}, nil,
+	}
+	t.instances = append(t.instances, result)
+	return result
+}
+
+func (t *tracingActivityInboundCallsInterceptor) Init(outbound ActivityOutboundCallsInterceptor) error {
+	return t.Next.Init(&tracingActivityOutboundCallsInterceptor{
+		ActivityOutboundCallsInterceptorBase{Next: outbound}, t})
+}
+
+func (t *tracingActivityInboundCallsInterceptor) ExecuteActivity(ctx context.Context, activityType string, args ...interface{}) (interface{}, error) {
+	t.trace = append(t.trace, "ExecuteActivity "+activityType+" begin")
+	result, err := t.Next.ExecuteActivity(ctx, activityType, args...)
+	t.trace = append(t.trace, "ExecuteActivity "+activityType+" end")
+	return result, err
+}
+
+func (t *tracingActivityOutboundCallsInterceptor) RecordActivityHeartbeat(ctx context.Context, details ...interface{}) {
+	t.inbound.trace = append(t.inbound.trace, "RecordActivityHeartbeat")
+	t.Next.RecordActivityHeartbeat(ctx, details...)
+}
+
+func heartbeatingActivity(ctx context.Context, name string) (string, error) {
+	RecordActivityHeartbeat(ctx, "progress")
+	return "Hello " + name + "!", nil
+}
+
+func (s *WorkflowUnitTest) Test_ActivityInterceptor() {
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(heartbeatingActivity)
+	tracer := tracingActivityInterceptor{}
+	env.SetWorkerOptions(WorkerOptions{ActivityInterceptorChainFactories: []ActivityInterceptor{&tracer}})
+	encoded, err := env.ExecuteActivity(heartbeatingActivity, "Temporal")
+	s.NoError(err)
+	var result string
+	s.NoError(encoded.Get(&result))
+	s.Equal("Hello Temporal!", result)
+	s.Equal(1, len(tracer.instances))
+	s.Equal([]string{
+		"ExecuteActivity heartbeatingActivity begin",
+		"RecordActivityHeartbeat",
+		"ExecuteActivity heartbeatingActivity end",
+	}, tracer.instances[0].trace)
+}
@@ -39,7 +39,6 @@ import (
 	"github.com/opentracing/opentracing-go"
 	"github.com/robfig/cron"
 	"github.com/stretchr/testify/mock"
-	"github.com/uber-go/tally"
 	commandpb "go.temporal.io/api/command/v1"
 	commonpb "go.temporal.io/api/common/v1"
 	enumspb "go.temporal.io/api/enums/v1"
@@ -51,6 +50,7 @@ import (
 
 	"go.temporal.io/sdk/converter"
 	"go.temporal.io/sdk/internal/common"
+	"go.temporal.io/sdk/internal/common/metrics"
 	ilog "go.temporal.io/sdk/internal/log"
 	"go.temporal.io/sdk/log"
 )
@@ -137,7 +137,7 @@ type (
 		mock               *mock.Mock
 		service            workflowservice.WorkflowServiceClient
 		logger             log.Logger
-		metricsScope       tally.Scope
+		metricsHandler     metrics.Handler
 		contextPropagators []ContextPropagator
 		identity           string
 		tracer             opentracing.Tracer
@@ -224,7 +224,7 @@ func newTestWorkflowEnvironmentImpl(s *WorkflowTestSuite, parentRegistry *regist
 			taskQueueSpecificActivities: make(map[string]*taskQueueSpecificActivity),
 
 			logger:            s.logger,
-			metricsScope:      s.scope,
+			metricsHandler:    s.metricsHandler,
 			tracer:            opentracing.NoopTracer{},
 			mockClock:         clock.NewMock(),
 			wallClock:         clock.New(),
@@ -275,8 +275,8 @@ func newTestWorkflowEnvironmentImpl(s *WorkflowTestSuite, parentRegistry *regist
 	if env.logger == nil {
 		env.logger = ilog.NewDefaultLogger()
 	}
-	if env.metricsScope == nil {
-		env.metricsScope = tally.NoopScope
+	if env.metricsHandler == nil {
+		env.metricsHandler = metrics.NopHandler
 	}
 	env.contextPropagators = s.contextPropagators
 	env.header = s.header
@@ -389,6 +389,7 @@ func (env *testWorkflowEnvironmentImpl) newTestWorkflowEnvironmentForChild(param
 func (env *testWorkflowEnvironmentImpl) setWorkerOptions(options WorkerOptions) {
 	env.workerOptions = options
 	env.registry.SetWorkflowInterceptors(options.WorkflowInterceptorChainFactories)
+	env.registry.SetActivityInterceptors(options.ActivityInterceptorChainFactories)
 	if env.workerOptions.EnableSessionWorker && env.sessionEnvironment == nil {
 		env.registry.RegisterActivityWithOptions(sessionCreationActivity, RegisterActivityOptions{
 			Name:                          sessionCreationActivityName,
@@ -619,10 +620,10 @@ func (env *testWorkflowEnvironmentImpl) executeLocalActivity(
 		attempt: 1,
 	}
 	taskHandler := localActivityTaskHandler{
-		userContext:  env.workerOptions.BackgroundActivityContext,
-		metricsScope: env.metricsScope,
-		logger:       env.logger,
-		tracer:       opentracing.NoopTracer{},
+		userContext:    env.workerOptions.BackgroundActivityContext,
+		metricsHandler: env.metricsHandler,
+		logger:         env.logger,
+		tracer:         opentracing.NoopTracer{},
 	}
 
 	result := taskHandler.executeLocalActivityTask(task)
@@ -1014,11 +1015,17 @@ func (env *testWorkflowEnvironmentImpl) CompleteActivity(taskToken []byte, resul
 }
 
 func (env *testWorkflowEnvironmentImpl) GetLogger() log.Logger {
-	return env.logger
+	// Same tags as the workflow logger of the real worker, see newWorkflowExecutionEventHandler.
+	return log.With(env.logger,
+		tagWorkflowType, env.workflowInfo.WorkflowType.Name,
+		tagWorkflowID, env.workflowInfo.WorkflowExecution.ID,
+		tagRunID, env.workflowInfo.WorkflowExecution.RunID,
+		tagAttempt, env.workflowInfo.Attempt,
+	)
 }
 
-func (env *testWorkflowEnvironmentImpl) GetMetricsScope() tally.Scope {
-	return env.metricsScope
+func (env *testWorkflowEnvironmentImpl) GetMetricsHandler() metrics.Handler {
+	return env.metricsHandler
 }
 
 func (env *testWorkflowEnvironmentImpl) GetDataConverter() converter.DataConverter {
@@ -1376,7 +1383,7 @@ func (env *testWorkflowEnvironmentImpl) ExecuteLocalActivity(params ExecuteLocal
 	task := newLocalActivityTask(params, callback, activityID)
 	taskHandler := localActivityTaskHandler{
 		userContext:        env.workerOptions.BackgroundActivityContext,
-		metricsScope:       env.metricsScope,
+		metricsHandler:     env.metricsHandler,
 		logger:             env.logger,
 		dataConverter:      env.dataConverter,
 		tracer:             env.tracer,
@@ -1839,7 +1846,7 @@ func (env *testWorkflowEnvironmentImpl) newTestActivityTaskHandler(taskQueue str
 	params := workerExecutionParameters{
 		TaskQueue:          taskQueue,
 		Identity:           env.identity,
-		MetricsScope:       env.metricsScope,
+		MetricsHandler:     env.metricsHandler,
 		Logger:             env.logger,
 		UserContext:        env.workerOptions.BackgroundActivityContext,
 		DataConverter:      dataConverter,
@@ -2332,6 +2339,10 @@ func (env *testWorkflowEnvironmentImpl) GetRegistry() *registry {
 	return env.registry
 }
 
+func (env *testWorkflowEnvironmentImpl) IsNativeConcurrencyDetectionEnabled() bool {
+	return env.workerOptions.EnableNativeConcurrencyDetection
+}
+
 func (env *testWorkflowEnvironmentImpl) setStartWorkflowOptions(options StartWorkflowOptions) {
 	wf := env.workflowInfo
 	if options.WorkflowExecutionTimeout > 0 {
@@ -29,6 +29,7 @@ import (
 	"errors"
 	"fmt"
 	"runtime"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -637,6 +638,67 @@ func (s *WorkflowTestSuiteUnitTest) Test_LongRunningSideEffect() {
 	s.Nil(env.GetWorkflowError())
 }
 
+func (s *WorkflowTestSuiteUnitTest) Test_LoggerTags() {
+	logger := ilog.NewMemoryLogger()
+	var suite WorkflowTestSuite
+	suite.SetLogger(logger)
+	env := suite.NewTestWorkflowEnvironment()
+
+	localActivityFn := func(ctx context.Context) error {
+		GetActivityLogger(ctx).Info("local activity log")
+		return nil
+	}
+	workflowFn := func(ctx Context) error {
+		GetLogger(ctx).Info("workflow log")
+		ctx = WithLocalActivityOptions(ctx, s.localActivityOptions)
+		return ExecuteLocalActivity(ctx, localActivityFn).Get(ctx, nil)
+	}
+	env.RegisterWorkflow(workflowFn)
+	env.ExecuteWorkflow(workflowFn)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+
+	var workflowLine, activityLine string
+	for _, line := range logger.Lines() {
+		if strings.Contains(line, "workflow log") {
+			workflowLine = line
+		}
+		if strings.Contains(line, "local activity log") {
+			activityLine = line
+		}
+	}
+	s.Contains(workflowLine, "WorkflowID "+defaultTestWorkflowID)
+	s.Contains(workflowLine, "RunID ")
+	s.Contains(workflowLine, "Attempt 1")
+	s.Contains(activityLine, "ActivityID ")
+	s.Contains(activityLine, "WorkflowID "+defaultTestWorkflowID)
+	s.Contains(activityLine, "Attempt 1")
+}
+
+func (s *WorkflowTestSuiteUnitTest) Test_NativeConcurrencyDetection() {
+	ch := make(chan struct{})
+	workflowFn := func(ctx Context) error {
+		// Native receive never yields to the dispatcher, so without detection it would hit the deadlock timeout.
+		<-ch
+		return nil
+	}
+	env := s.NewTestWorkflowEnvironment()
+	env.SetWorkerOptions(WorkerOptions{
+		DeadlockDetectionTimeout:         time.Minute,
+		EnableNativeConcurrencyDetection: true,
+	})
+	env.RegisterWorkflow(workflowFn)
+
+	var panicValue interface{}
+	start := time.Now()
+	func() {
+		defer func() { panicValue = recover() }()
+		env.ExecuteWorkflow(workflowFn)
+	}()
+	s.Less(time.Since(start), time.Minute)
+	s.Contains(fmt.Sprint(panicValue), `Potential non-determinism detected: workflow goroutine "root" is blocked on native "chan receive"`)
+}
+
 func (s *WorkflowTestSuiteUnitTest) Test_SideEffect_WithVersion() {
 	workflowFn := func(ctx Context) error {
 		ctx = WithActivityOptions(ctx, s.activityOptions)
@@ -3679,3 +3741,80 @@ func (s *WorkflowTestSuiteUnitTest) Test_AwaitWithTimeoutTimeout() {
 	_ = env.GetWorkflowResult(&result)
 	s.False(result)
 }
+
+func (s *WorkflowTestSuiteUnitTest) Test_ReceiveWithTimeout() {
+	workflowFn := func(ctx Context) ([]string, error) {
+		var results []string
+		ch := GetSignalChannel(ctx, "approval")
+		for i := 0; i < 2; i++ {
+			var approval string
+			ok, _ := ch.ReceiveWithTimeout(ctx, time.Hour, &approval)
+			if !ok {
+				approval = "timeout"
+			}
+			results = append(results, approval)
+		}
+		return results, nil
+	}
+
+	env := s.NewTestWorkflowEnvironment()
+	var timersScheduled, timersCanceled int
+	env.SetOnTimerScheduledListener(func(timerID string, duration time.Duration) {
+		timersScheduled++
+	})
+	env.SetOnTimerCanceledListener(func(timerID string) {
+		timersCanceled++
+	})
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow("approval", "approved")
+	}, time.Minute)
+	env.ExecuteWorkflow(workflowFn)
+
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	var result []string
+	s.NoError(env.GetWorkflowResult(&result))
+	s.Equal([]string{"approved", "timeout"}, result)
+	s.Equal(2, timersScheduled)
+	s.Equal(1, timersCanceled)
+}
+
+func (s *WorkflowTestSuiteUnitTest) Test_SelectorAddTimeout() {
+	workflowFn := func(ctx Context) ([]string, error) {
+		var results []string
+		ch := NewBufferedChannel(ctx, 1)
+		ch.Send(ctx, "ready")
+		selector := NewSelector(ctx)
+		selector.AddReceive(ch, func(c ReceiveChannel, more bool) {
+			var v string
+			c.Receive(ctx, &v)
+			results = append(results, v)
+		})
+		selector.AddTimeout(time.Minute, func() {
+			results = append(results, "timeout")
+		})
+		// The first Select picks the buffered value without starting a timer.
+		selector.Select(ctx)
+		selector.Select(ctx)
+		return results, nil
+	}
+
+	env := s.NewTestWorkflowEnvironment()
+	var timersScheduled, timersCanceled int
+	env.SetOnTimerScheduledListener(func(timerID string, duration time.Duration) {
+		s.Equal(time.Minute, duration)
+		timersScheduled++
+	})
+	env.SetOnTimerCanceledListener(func(timerID string) {
+		timersCanceled++
+	})
+	env.ExecuteWorkflow(workflowFn)
+
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	var result []string
+	s.NoError(env.GetWorkflowResult(&result))
+	s.Equal([]string{"ready", "timeout"}, result)
+	s.Equal(1, timersScheduled)
+	s.Equal(0, timersCanceled)
+}
@@ -0,0 +1,67 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import (
+	"github.com/uber-go/tally"
+
+	"go.temporal.io/sdk/internal/common/metrics"
+)
+
+type (
+	// MetricsHandler is a handler for metrics emitted by the SDK. Metrics are emitted with the tags of the handler
+	// they were created from.
+	MetricsHandler = metrics.Handler
+
+	// MetricsCounter is an ever-increasing counter.
+	MetricsCounter = metrics.Counter
+
+	// MetricsGauge can be set to any value.
+	MetricsGauge = metrics.Gauge
+
+	// MetricsTimer records the distribution of durations.
+	MetricsTimer = metrics.Timer
+
+	// PrometheusMetricsHandler is a MetricsHandler which keeps metrics in memory and exposes them in the Prometheus
+	// text exposition format.
+	PrometheusMetricsHandler = metrics.PrometheusHandler
+
+	// PrometheusMetricsHandlerOptions are optional parameters for NewPrometheusMetricsHandler.
+	PrometheusMetricsHandlerOptions = metrics.PrometheusHandlerOptions
+)
+
+// MetricsNopHandler is a MetricsHandler that does nothing.
+var MetricsNopHandler = metrics.NopHandler
+
+// NewTallyMetricsHandler returns a MetricsHandler which reports to the given tally scope.
+func NewTallyMetricsHandler(scope tally.Scope) MetricsHandler {
+	return metrics.NewTallyHandler(scope)
+}
+
+// NewPrometheusMetricsHandler returns a MetricsHandler which serves collected metrics in the Prometheus text
+// exposition format. It is a standalone exporter, see PrometheusMetricsHandler.
+func NewPrometheusMetricsHandler(options PrometheusMetricsHandlerOptions) *PrometheusMetricsHandler {
+	return metrics.NewPrometheusHandler(options)
+}
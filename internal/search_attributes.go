@@ -0,0 +1,376 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	commonpb "go.temporal.io/api/common/v1"
+	enumspb "go.temporal.io/api/enums/v1"
+
+	"go.temporal.io/sdk/converter"
+)
+
+type (
+	// SearchAttributeKey is a typed search attribute key. Use NewStringKey, NewKeywordKey, NewIntKey, NewDoubleKey,
+	// NewBoolKey or NewDatetimeKey to create a key.
+	SearchAttributeKey interface {
+		// GetName returns the name of the search attribute.
+		GetName() string
+		// GetValueType returns the type of the search attribute value.
+		GetValueType() enumspb.IndexedValueType
+	}
+
+	// StringKey is a key of a search attribute of String (full text) type.
+	StringKey struct {
+		name string
+	}
+
+	// KeywordKey is a key of a search attribute of Keyword type.
+	KeywordKey struct {
+		name string
+	}
+
+	// IntKey is a key of a search attribute of Int type.
+	IntKey struct {
+		name string
+	}
+
+	// DoubleKey is a key of a search attribute of Double type.
+	DoubleKey struct {
+		name string
+	}
+
+	// BoolKey is a key of a search attribute of Bool type.
+	BoolKey struct {
+		name string
+	}
+
+	// DatetimeKey is a key of a search attribute of Datetime type.
+	DatetimeKey struct {
+		name string
+	}
+
+	// SearchAttributeUpdate is a typed value of a search attribute created by ValueSet method of a key.
+	SearchAttributeUpdate struct {
+		key   SearchAttributeKey
+		value interface{}
+	}
+
+	// TypedSearchAttributes provides typed access to search attributes of a workflow execution.
+	TypedSearchAttributes struct {
+		attributes *commonpb.SearchAttributes
+	}
+)
+
+// NewStringKey creates a key of a search attribute of String type.
+func NewStringKey(name string) StringKey {
+	return StringKey{name: name}
+}
+
+// GetName returns the name of the search attribute.
+func (k StringKey) GetName() string {
+	return k.name
+}
+
+// GetValueType returns enumspb.INDEXED_VALUE_TYPE_STRING.
+func (k StringKey) GetValueType() enumspb.IndexedValueType {
+	return enumspb.INDEXED_VALUE_TYPE_STRING
+}
+
+// ValueSet creates an update which sets the search attribute to the value.
+func (k StringKey) ValueSet(value string) SearchAttributeUpdate {
+	return SearchAttributeUpdate{key: k, value: value}
+}
+
+// NewKeywordKey creates a key of a search attribute of Keyword type.
+func NewKeywordKey(name string) KeywordKey {
+	return KeywordKey{name: name}
+}
+
+// GetName returns the name of the search attribute.
+func (k KeywordKey) GetName() string {
+	return k.name
+}
+
+// GetValueType returns enumspb.INDEXED_VALUE_TYPE_KEYWORD.
+func (k KeywordKey) GetValueType() enumspb.IndexedValueType {
+	return enumspb.INDEXED_VALUE_TYPE_KEYWORD
+}
+
+// ValueSet creates an update which sets the search attribute to the value.
+func (k KeywordKey) ValueSet(value string) SearchAttributeUpdate {
+	return SearchAttributeUpdate{key: k, value: value}
+}
+
+// NewIntKey creates a key of a search attribute of Int type.
+func NewIntKey(name string) IntKey {
+	return IntKey{name: name}
+}
+
+// GetName returns the name of the search attribute.
+func (k IntKey) GetName() string {
+	return k.name
+}
+
+// GetValueType returns enumspb.INDEXED_VALUE_TYPE_INT.
+func (k IntKey) GetValueType() enumspb.IndexedValueType {
+	return enumspb.INDEXED_VALUE_TYPE_INT
+}
+
+// ValueSet creates an update which sets the search attribute to the value.
+func (k IntKey) ValueSet(value int64) SearchAttributeUpdate {
+	return SearchAttributeUpdate{key: k, value: value}
+}
+
+// NewDoubleKey creates a key of a search attribute of Double type.
+func NewDoubleKey(name string) DoubleKey {
+	return DoubleKey{name: name}
+}
+
+// GetName returns the name of the search attribute.
+func (k DoubleKey) GetName() string {
+	return k.name
+}
+
+// GetValueType returns enumspb.INDEXED_VALUE_TYPE_DOUBLE.
+func (k DoubleKey) GetValueType() enumspb.IndexedValueType {
+	return enumspb.INDEXED_VALUE_TYPE_DOUBLE
+}
+
+// ValueSet creates an update which sets the search attribute to the value.
+func (k DoubleKey) ValueSet(value float64) SearchAttributeUpdate {
+	return SearchAttributeUpdate{key: k, value: value}
+}
+
+// NewBoolKey creates a key of a search attribute of Bool type.
+func NewBoolKey(name string) BoolKey {
+	return BoolKey{name: name}
+}
+
+// GetName returns the name of the search attribute.
+func (k BoolKey) GetName() string {
+	return k.name
+}
+
+// GetValueType returns enumspb.INDEXED_VALUE_TYPE_BOOL.
+func (k BoolKey) GetValueType() enumspb.IndexedValueType {
+	return enumspb.INDEXED_VALUE_TYPE_BOOL
+}
+
+// ValueSet creates an update which sets the search attribute to the value.
+func (k BoolKey) ValueSet(value bool) SearchAttributeUpdate {
+	return SearchAttributeUpdate{key: k, value: value}
+}
+
+// NewDatetimeKey creates a key of a search attribute of Datetime type.
+func NewDatetimeKey(name string) DatetimeKey {
+	return DatetimeKey{name: name}
+}
+
+// GetName returns the name of the search attribute.
+func (k DatetimeKey) GetName() string {
+	return k.name
+}
+
+// GetValueType returns enumspb.INDEXED_VALUE_TYPE_DATETIME.
+func (k DatetimeKey) GetValueType() enumspb.IndexedValueType {
+	return enumspb.INDEXED_VALUE_TYPE_DATETIME
+}
+
+// ValueSet creates an update which sets the search attribute to the value.
+func (k DatetimeKey) ValueSet(value time.Time) SearchAttributeUpdate {
+	return SearchAttributeUpdate{key: k, value: value}
+}
+
+// NewSearchAttributes creates search attributes from typed values to be used as StartWorkflowOptions.SearchAttributes,
+// ChildWorkflowOptions.SearchAttributes or passed to UpsertSearchAttributes.
+func NewSearchAttributes(updates ...SearchAttributeUpdate) map[string]interface{} {
+	result := make(map[string]interface{}, len(updates))
+	for _, update := range updates {
+		result[update.key.GetName()] = update.value
+	}
+	return result
+}
+
+// UpsertTypedSearchAttributes is the same as UpsertSearchAttributes but accepts typed values.
+func UpsertTypedSearchAttributes(ctx Context, updates ...SearchAttributeUpdate) error {
+	return UpsertSearchAttributes(ctx, NewSearchAttributes(updates...))
+}
+
+// GetTypedSearchAttributes returns current search attributes of the workflow execution, including the ones
+// upserted by the workflow.
+func GetTypedSearchAttributes(ctx Context) TypedSearchAttributes {
+	return NewTypedSearchAttributes(GetWorkflowInfo(ctx).SearchAttributes)
+}
+
+// NewTypedSearchAttributes creates TypedSearchAttributes from encoded search attributes, for example
+// WorkflowInfo.SearchAttributes.
+func NewTypedSearchAttributes(attributes *commonpb.SearchAttributes) TypedSearchAttributes {
+	return TypedSearchAttributes{attributes: attributes}
+}
+
+// GetString returns the value of the search attribute. False is returned if the search attribute is not set
+// or its value can't be decoded as the key type.
+func (sa TypedSearchAttributes) GetString(key StringKey) (string, bool) {
+	var value string
+	ok := sa.decode(key, &value)
+	return value, ok
+}
+
+// GetKeyword returns the value of the search attribute. False is returned if the search attribute is not set
+// or its value can't be decoded as the key type.
+func (sa TypedSearchAttributes) GetKeyword(key KeywordKey) (string, bool) {
+	var value string
+	ok := sa.decode(key, &value)
+	return value, ok
+}
+
+// GetInt returns the value of the search attribute. False is returned if the search attribute is not set
+// or its value can't be decoded as the key type.
+func (sa TypedSearchAttributes) GetInt(key IntKey) (int64, bool) {
+	var value int64
+	ok := sa.decode(key, &value)
+	return value, ok
+}
+
+// GetDouble returns the value of the search attribute. False is returned if the search attribute is not set
+// or its value can't be decoded as the key type.
+func (sa TypedSearchAttributes) GetDouble(key DoubleKey) (float64, bool) {
+	var value float64
+	ok := sa.decode(key, &value)
+	return value, ok
+}
+
+// GetBool returns the value of the search attribute. False is returned if the search attribute is not set
+// or its value can't be decoded as the key type.
+func (sa TypedSearchAttributes) GetBool(key BoolKey) (bool, bool) {
+	var value bool
+	ok := sa.decode(key, &value)
+	return value, ok
+}
+
+// GetDatetime returns the value of the search attribute. False is returned if the search attribute is not set
+// or its value can't be decoded as the key type.
+func (sa TypedSearchAttributes) GetDatetime(key DatetimeKey) (time.Time, bool) {
+	var value time.Time
+	ok := sa.decode(key, &value)
+	return value, ok
+}
+
+// ContainsKey returns true if the search attribute is set.
+func (sa TypedSearchAttributes) ContainsKey(key SearchAttributeKey) bool {
+	_, ok := sa.attributes.GetIndexedFields()[key.GetName()]
+	return ok
+}
+
+// Size returns the number of set search attributes.
+func (sa TypedSearchAttributes) Size() int {
+	return len(sa.attributes.GetIndexedFields())
+}
+
+func (sa TypedSearchAttributes) decode(key SearchAttributeKey, valuePtr interface{}) bool {
+	payload, ok := sa.attributes.GetIndexedFields()[key.GetName()]
+	if !ok {
+		return false
+	}
+	return converter.GetDefaultDataConverter().FromPayload(payload, valuePtr) == nil
+}
+
+// validateSearchAttributes checks that every search attribute is defined in the schema returned by
+// Client.GetSearchAttributes and its value matches the defined type.
+func validateSearchAttributes(attributes map[string]interface{}, schema map[string]enumspb.IndexedValueType) error {
+	for name, value := range attributes {
+		valueType, ok := schema[name]
+		if !ok {
+			return fmt.Errorf("search attribute %v is not defined", name)
+		}
+		if !isSearchAttributeValueOfType(value, valueType) {
+			return fmt.Errorf("search attribute %v of type %v can't have value of type %T", name, valueType, value)
+		}
+	}
+	return nil
+}
+
+// warnInvalidSearchAttributes logs a warning if search attributes set from workflow code don't match the schema loaded
+// by the worker. Workflow code doesn't fail on validation errors because the schema may change between the original
+// execution and a replay of the same history, which would make the workflow non-deterministic.
+func warnInvalidSearchAttributes(env WorkflowEnvironment, attributes map[string]interface{}) {
+	schema := env.GetRegistry().SearchAttributesSchema()
+	if schema == nil {
+		return
+	}
+	if err := validateSearchAttributes(attributes, schema); err != nil {
+		env.GetLogger().Warn("Search attributes don't match search attributes registered on the server", tagError, err)
+	}
+}
+
+func isSearchAttributeValueOfType(value interface{}, valueType enumspb.IndexedValueType) bool {
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		// search attributes can have multiple values
+		for i := 0; i < v.Len(); i++ {
+			if !isSearchAttributeValueOfType(v.Index(i).Interface(), valueType) {
+				return false
+			}
+		}
+		return true
+	}
+
+	switch valueType {
+	case enumspb.INDEXED_VALUE_TYPE_STRING, enumspb.INDEXED_VALUE_TYPE_KEYWORD:
+		return v.Kind() == reflect.String
+	case enumspb.INDEXED_VALUE_TYPE_INT:
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return true
+		}
+		return false
+	case enumspb.INDEXED_VALUE_TYPE_DOUBLE:
+		switch v.Kind() {
+		case reflect.Float32, reflect.Float64,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return true
+		}
+		return false
+	case enumspb.INDEXED_VALUE_TYPE_BOOL:
+		return v.Kind() == reflect.Bool
+	case enumspb.INDEXED_VALUE_TYPE_DATETIME:
+		switch t := value.(type) {
+		case time.Time:
+			return true
+		case string:
+			_, err := time.Parse(time.RFC3339Nano, t)
+			return err == nil
+		}
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,163 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	enumspb "go.temporal.io/api/enums/v1"
+)
+
+var (
+	testStringKey   = NewStringKey("CustomStringField")
+	testKeywordKey  = NewKeywordKey("CustomKeywordField")
+	testIntKey      = NewIntKey("CustomIntField")
+	testDoubleKey   = NewDoubleKey("CustomDoubleField")
+	testBoolKey     = NewBoolKey("CustomBoolField")
+	testDatetimeKey = NewDatetimeKey("CustomDatetimeField")
+
+	testSearchAttributesSchema = map[string]enumspb.IndexedValueType{
+		"CustomStringField":   enumspb.INDEXED_VALUE_TYPE_STRING,
+		"CustomKeywordField":  enumspb.INDEXED_VALUE_TYPE_KEYWORD,
+		"CustomIntField":      enumspb.INDEXED_VALUE_TYPE_INT,
+		"CustomDoubleField":   enumspb.INDEXED_VALUE_TYPE_DOUBLE,
+		"CustomBoolField":     enumspb.INDEXED_VALUE_TYPE_BOOL,
+		"CustomDatetimeField": enumspb.INDEXED_VALUE_TYPE_DATETIME,
+	}
+)
+
+func TestTypedSearchAttributes(t *testing.T) {
+	now := time.Date(2020, 9, 1, 10, 0, 0, 0, time.UTC)
+	attributes := NewSearchAttributes(
+		testStringKey.ValueSet("some text"),
+		testKeywordKey.ValueSet("seattle"),
+		testIntKey.ValueSet(2),
+		testDoubleKey.ValueSet(3.5),
+		testBoolKey.ValueSet(true),
+		testDatetimeKey.ValueSet(now),
+	)
+	require.NoError(t, validateSearchAttributes(attributes, testSearchAttributesSchema))
+
+	encoded, err := serializeSearchAttributes(attributes)
+	require.NoError(t, err)
+	typed := NewTypedSearchAttributes(encoded)
+	assert.Equal(t, 6, typed.Size())
+
+	s, ok := typed.GetString(testStringKey)
+	assert.True(t, ok)
+	assert.Equal(t, "some text", s)
+	k, ok := typed.GetKeyword(testKeywordKey)
+	assert.True(t, ok)
+	assert.Equal(t, "seattle", k)
+	i, ok := typed.GetInt(testIntKey)
+	assert.True(t, ok)
+	assert.Equal(t, int64(2), i)
+	d, ok := typed.GetDouble(testDoubleKey)
+	assert.True(t, ok)
+	assert.Equal(t, 3.5, d)
+	b, ok := typed.GetBool(testBoolKey)
+	assert.True(t, ok)
+	assert.True(t, b)
+	dt, ok := typed.GetDatetime(testDatetimeKey)
+	assert.True(t, ok)
+	assert.True(t, now.Equal(dt))
+
+	_, ok = typed.GetKeyword(NewKeywordKey("Missing"))
+	assert.False(t, ok)
+	assert.False(t, typed.ContainsKey(NewKeywordKey("Missing")))
+	// value of a different type can't be decoded
+	_, ok = typed.GetInt(NewIntKey("CustomKeywordField"))
+	assert.False(t, ok)
+}
+
+func TestTypedSearchAttributes_Empty(t *testing.T) {
+	typed := NewTypedSearchAttributes(nil)
+	assert.Equal(t, 0, typed.Size())
+	_, ok := typed.GetKeyword(testKeywordKey)
+	assert.False(t, ok)
+}
+
+func TestValidateSearchAttributes(t *testing.T) {
+	tests := []struct {
+		name       string
+		attributes map[string]interface{}
+		valid      bool
+	}{
+		{"keyword", map[string]interface{}{"CustomKeywordField": "seattle"}, true},
+		{"keyword list", map[string]interface{}{"CustomKeywordField": []string{"seattle", "portland"}}, true},
+		{"int", map[string]interface{}{"CustomIntField": 1}, true},
+		{"int as double", map[string]interface{}{"CustomDoubleField": 1}, true},
+		{"datetime string", map[string]interface{}{"CustomDatetimeField": "2020-09-01T10:00:00Z"}, true},
+		{"unknown", map[string]interface{}{"UnknownField": "seattle"}, false},
+		{"wrong type", map[string]interface{}{"CustomIntField": "1"}, false},
+		{"double as int", map[string]interface{}{"CustomIntField": 1.5}, false},
+		{"wrong list element", map[string]interface{}{"CustomBoolField": []interface{}{true, "false"}}, false},
+		{"invalid datetime", map[string]interface{}{"CustomDatetimeField": "yesterday"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSearchAttributes(tt.attributes, testSearchAttributesSchema)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestGetTypedSearchAttributes(t *testing.T) {
+	var suite WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+	env.impl.GetRegistry().SetSearchAttributesSchema(testSearchAttributesSchema)
+
+	workflowFn := func(ctx Context) (string, error) {
+		if err := UpsertTypedSearchAttributes(ctx, testKeywordKey.ValueSet("seattle"), testIntKey.ValueSet(2)); err != nil {
+			return "", err
+		}
+		// Unknown search attributes are only logged from workflow code to keep replay deterministic.
+		if err := UpsertTypedSearchAttributes(ctx, NewKeywordKey("UnknownField").ValueSet("value")); err != nil {
+			return "", err
+		}
+		typed := GetTypedSearchAttributes(ctx)
+		if i, ok := typed.GetInt(testIntKey); !ok || i != 2 {
+			return "", errors.New("unexpected int value")
+		}
+		value, _ := typed.GetKeyword(testKeywordKey)
+		return value, nil
+	}
+	env.RegisterWorkflow(workflowFn)
+	env.ExecuteWorkflow(workflowFn)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var result string
+	require.NoError(t, env.GetWorkflowResult(&result))
+	assert.Equal(t, "seattle", result)
+}
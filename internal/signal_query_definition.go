@@ -0,0 +1,250 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+type (
+	// SignalDefinition declares a signal by its name and argument type. The same definition is meant to be shared by
+	// the workflow code which receives the signal and the code which sends it, so a mismatch of the name or the
+	// argument type is reported before the payload is encoded or decoded instead of being silently dropped.
+	// Use NewSignalDefinition to create a definition.
+	SignalDefinition struct {
+		name    string
+		argType reflect.Type
+	}
+
+	// QueryDefinition declares a query by its name, argument type and result type. The same definition is meant to
+	// be shared by the workflow code which handles the query and the code which sends it.
+	// Use NewQueryDefinition to create a definition.
+	QueryDefinition struct {
+		name       string
+		argType    reflect.Type
+		resultType reflect.Type
+	}
+)
+
+// NewSignalDefinition creates a definition of a signal. The argument type is declared by a typed nil pointer, for
+// example (*Approval)(nil) for a signal which carries an Approval value. Pass nil for a signal without argument.
+// It panics if argPtr is not a pointer, so definitions can be declared as package level variables.
+//  var ApproveSignal = temporal.NewSignalDefinition("approve", (*Approval)(nil))
+func NewSignalDefinition(name string, argPtr interface{}) SignalDefinition {
+	return SignalDefinition{name: name, argType: definitionElemType("argPtr", argPtr)}
+}
+
+// GetName returns the name of the signal.
+func (d SignalDefinition) GetName() string {
+	return d.name
+}
+
+// GetArgType returns the type of the signal argument or nil if the signal has no argument.
+func (d SignalDefinition) GetArgType() reflect.Type {
+	return d.argType
+}
+
+// GetChannel returns the channel the signal is delivered to in workflow code.
+func (d SignalDefinition) GetChannel(ctx Context) ReceiveChannel {
+	return GetSignalChannel(ctx, d.name)
+}
+
+// Receive blocks until the signal is received and assigns its argument to valuePtr. It panics if valuePtr is not a
+// pointer to the argument type of the definition. Returns false when the channel is closed.
+func (d SignalDefinition) Receive(ctx Context, valuePtr interface{}) (more bool) {
+	d.mustValidateValuePtr(valuePtr)
+	return d.GetChannel(ctx).Receive(ctx, valuePtr)
+}
+
+// ReceiveAsync assigns the argument of a pending signal to valuePtr without blocking. Returns false if there is no
+// pending signal. It panics if valuePtr is not a pointer to the argument type of the definition.
+func (d SignalDefinition) ReceiveAsync(ctx Context, valuePtr interface{}) (ok bool) {
+	d.mustValidateValuePtr(valuePtr)
+	return d.GetChannel(ctx).ReceiveAsync(valuePtr)
+}
+
+// ReceiveWithTimeout blocks until the signal is received or the timeout expires. See ReceiveChannel.ReceiveWithTimeout.
+// It panics if valuePtr is not a pointer to the argument type of the definition.
+func (d SignalDefinition) ReceiveWithTimeout(ctx Context, timeout time.Duration, valuePtr interface{}) (ok bool, more bool) {
+	d.mustValidateValuePtr(valuePtr)
+	return d.GetChannel(ctx).ReceiveWithTimeout(ctx, timeout, valuePtr)
+}
+
+// SignalExternalWorkflow sends the signal to a workflow execution from workflow code. The returned Future fails
+// without sending the signal if arg doesn't match the argument type of the definition.
+func (d SignalDefinition) SignalExternalWorkflow(ctx Context, workflowID, runID string, arg interface{}) Future {
+	if err := validateDefinitionArg("signal", d.name, d.argType, arg); err != nil {
+		future, settable := NewFuture(ctx)
+		settable.Set(nil, err)
+		return future
+	}
+	return SignalExternalWorkflow(ctx, workflowID, runID, d.name, arg)
+}
+
+// SignalWorkflow sends the signal to a workflow execution through the client. An error is returned without sending
+// the signal if arg doesn't match the argument type of the definition.
+func (d SignalDefinition) SignalWorkflow(ctx context.Context, c Client, workflowID, runID string, arg interface{}) error {
+	if err := validateDefinitionArg("signal", d.name, d.argType, arg); err != nil {
+		return err
+	}
+	return c.SignalWorkflow(ctx, workflowID, runID, d.name, arg)
+}
+
+func (d SignalDefinition) mustValidateValuePtr(valuePtr interface{}) {
+	if err := validateDefinitionValuePtr("signal", d.name, d.argType, valuePtr); err != nil {
+		panic(err)
+	}
+}
+
+// NewQueryDefinition creates a definition of a query. The argument and the result types are declared by typed nil
+// pointers, for example (*string)(nil). Pass nil as argPtr for a query without argument. It panics if argPtr is not
+// a pointer or resultPtr is not a non nil pointer type, so definitions can be declared as package level variables.
+//  var StatusQuery = temporal.NewQueryDefinition("status", nil, (*Status)(nil))
+func NewQueryDefinition(name string, argPtr interface{}, resultPtr interface{}) QueryDefinition {
+	resultType := definitionElemType("resultPtr", resultPtr)
+	if resultType == nil {
+		panic("resultPtr must be a typed pointer")
+	}
+	return QueryDefinition{name: name, argType: definitionElemType("argPtr", argPtr), resultType: resultType}
+}
+
+// GetName returns the name of the query.
+func (d QueryDefinition) GetName() string {
+	return d.name
+}
+
+// GetArgType returns the type of the query argument or nil if the query has no argument.
+func (d QueryDefinition) GetArgType() reflect.Type {
+	return d.argType
+}
+
+// GetResultType returns the type of the query result.
+func (d QueryDefinition) GetResultType() reflect.Type {
+	return d.resultType
+}
+
+// SetHandler registers the query handler in workflow code. The handler must accept the argument of the definition
+// (or nothing if the definition has no argument) and return the result type of the definition and an error.
+func (d QueryDefinition) SetHandler(ctx Context, handler interface{}) error {
+	fnType := reflect.TypeOf(handler)
+	if fnType == nil || fnType.Kind() != reflect.Func {
+		return fmt.Errorf("query %q handler must be function but was %v", d.name, fnType)
+	}
+	var expectedIn []reflect.Type
+	if d.argType != nil {
+		expectedIn = append(expectedIn, d.argType)
+	}
+	if fnType.NumIn() != len(expectedIn) {
+		return fmt.Errorf("query %q handler must accept %d arguments but accepts %d", d.name, len(expectedIn), fnType.NumIn())
+	}
+	for i, t := range expectedIn {
+		if fnType.In(i) != t {
+			return fmt.Errorf("query %q handler argument must be %v but was %v", d.name, t, fnType.In(i))
+		}
+	}
+	if fnType.NumOut() != 2 || fnType.Out(0) != d.resultType || !isError(fnType.Out(1)) {
+		return fmt.Errorf("query %q handler must return (%v, error) but returns %v", d.name, d.resultType, fnType)
+	}
+	return SetQueryHandler(ctx, d.name, handler)
+}
+
+// QueryWorkflow sends the query to a workflow execution through the client and assigns the result to resultPtr. An
+// error is returned without sending the query if arg or resultPtr don't match the definition.
+func (d QueryDefinition) QueryWorkflow(ctx context.Context, c Client, workflowID, runID string, arg interface{}, resultPtr interface{}) error {
+	if err := validateDefinitionArg("query", d.name, d.argType, arg); err != nil {
+		return err
+	}
+	if err := validateDefinitionValuePtr("query", d.name, d.resultType, resultPtr); err != nil {
+		return err
+	}
+	var args []interface{}
+	if d.argType != nil {
+		args = append(args, arg)
+	}
+	value, err := c.QueryWorkflow(ctx, workflowID, runID, d.name, args...)
+	if err != nil {
+		return err
+	}
+	if resultPtr == nil {
+		return nil
+	}
+	return value.Get(resultPtr)
+}
+
+func definitionElemType(paramName string, ptr interface{}) reflect.Type {
+	if ptr == nil {
+		return nil
+	}
+	t := reflect.TypeOf(ptr)
+	if t.Kind() != reflect.Ptr {
+		panic(fmt.Sprintf("%s must be a pointer but was %v", paramName, t))
+	}
+	return t.Elem()
+}
+
+// validateDefinitionArg checks that arg can be sent as a value of the declared type. Both the value and a pointer to
+// it are accepted the same way encodeArg accepts them.
+func validateDefinitionArg(kind, name string, argType reflect.Type, arg interface{}) error {
+	if argType == nil {
+		if arg != nil {
+			return fmt.Errorf("%s %q has no argument but %T was provided", kind, name, arg)
+		}
+		return nil
+	}
+	if arg == nil {
+		switch argType.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+			return nil
+		}
+		return fmt.Errorf("%s %q requires argument of type %v but nil was provided", kind, name, argType)
+	}
+	t := reflect.TypeOf(arg)
+	if t.AssignableTo(argType) || (t.Kind() == reflect.Ptr && t.Elem().AssignableTo(argType)) {
+		return nil
+	}
+	return fmt.Errorf("%s %q requires argument of type %v but %v was provided", kind, name, argType, t)
+}
+
+// validateDefinitionValuePtr checks that a value of the declared type can be decoded into valuePtr. A nil valuePtr
+// discards the value.
+func validateDefinitionValuePtr(kind, name string, valueType reflect.Type, valuePtr interface{}) error {
+	if valuePtr == nil {
+		return nil
+	}
+	t := reflect.TypeOf(valuePtr)
+	if t.Kind() != reflect.Ptr {
+		return fmt.Errorf("%s %q value must be decoded into a pointer but %v was provided", kind, name, t)
+	}
+	if valueType == nil {
+		return fmt.Errorf("%s %q has no value but %v was provided", kind, name, t)
+	}
+	if !valueType.AssignableTo(t.Elem()) {
+		return fmt.Errorf("%s %q value of type %v cannot be decoded into %v", kind, name, valueType, t)
+	}
+	return nil
+}
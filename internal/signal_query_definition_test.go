@@ -0,0 +1,131 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testApproval struct {
+	Approver string
+	Approved bool
+}
+
+var (
+	testApproveSignal = NewSignalDefinition("approve", (*testApproval)(nil))
+	testCancelSignal  = NewSignalDefinition("cancel", nil)
+	testStatusQuery   = NewQueryDefinition("status", nil, (*string)(nil))
+	testItemQuery     = NewQueryDefinition("item", (*int)(nil), (*string)(nil))
+)
+
+func TestDefinitionConstructors(t *testing.T) {
+	require.Equal(t, "approve", testApproveSignal.GetName())
+	require.Equal(t, "testApproval", testApproveSignal.GetArgType().Name())
+	require.Nil(t, testCancelSignal.GetArgType())
+	require.Equal(t, "string", testStatusQuery.GetResultType().Name())
+	require.Panics(t, func() { NewSignalDefinition("approve", testApproval{}) })
+	require.Panics(t, func() { NewQueryDefinition("status", nil, nil) })
+}
+
+func TestDefinitionArgValidation(t *testing.T) {
+	require.NoError(t, validateDefinitionArg("signal", "approve", testApproveSignal.GetArgType(), testApproval{}))
+	require.NoError(t, validateDefinitionArg("signal", "approve", testApproveSignal.GetArgType(), &testApproval{}))
+	require.Error(t, validateDefinitionArg("signal", "approve", testApproveSignal.GetArgType(), "approved"))
+	require.Error(t, validateDefinitionArg("signal", "approve", testApproveSignal.GetArgType(), nil))
+	require.NoError(t, validateDefinitionArg("signal", "cancel", nil, nil))
+	require.Error(t, validateDefinitionArg("signal", "cancel", nil, "now"))
+
+	var approval testApproval
+	require.NoError(t, validateDefinitionValuePtr("signal", "approve", testApproveSignal.GetArgType(), &approval))
+	require.NoError(t, validateDefinitionValuePtr("signal", "approve", testApproveSignal.GetArgType(), nil))
+	require.Error(t, validateDefinitionValuePtr("signal", "approve", testApproveSignal.GetArgType(), approval))
+	var s string
+	require.Error(t, validateDefinitionValuePtr("signal", "approve", testApproveSignal.GetArgType(), &s))
+}
+
+func TestDefinitionClientValidation(t *testing.T) {
+	// Arguments are validated before the client is used.
+	err := testApproveSignal.SignalWorkflow(context.Background(), nil, "wid", "", "approved")
+	require.EqualError(t, err, `signal "approve" requires argument of type internal.testApproval but string was provided`)
+	var result int
+	err = testStatusQuery.QueryWorkflow(context.Background(), nil, "wid", "", nil, &result)
+	require.EqualError(t, err, `query "status" value of type string cannot be decoded into *int`)
+}
+
+func TestDefinitionWorkflow(t *testing.T) {
+	workflowFn := func(ctx Context) (testApproval, error) {
+		status := "waiting"
+		if err := testStatusQuery.SetHandler(ctx, func() (string, error) { return status, nil }); err != nil {
+			return testApproval{}, err
+		}
+		if err := testItemQuery.SetHandler(ctx, func(i int) (string, error) { return status[:i], nil }); err != nil {
+			return testApproval{}, err
+		}
+		var approval testApproval
+		testApproveSignal.Receive(ctx, &approval)
+		status = "approved"
+		return approval, nil
+	}
+
+	var s WorkflowTestSuite
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterDelayedCallback(func() {
+		value, err := env.QueryWorkflow(testStatusQuery.GetName())
+		require.NoError(t, err)
+		var status string
+		require.NoError(t, value.Get(&status))
+		require.Equal(t, "waiting", status)
+		value, err = env.QueryWorkflow(testItemQuery.GetName(), 4)
+		require.NoError(t, err)
+		require.NoError(t, value.Get(&status))
+		require.Equal(t, "wait", status)
+		env.SignalWorkflow(testApproveSignal.GetName(), testApproval{Approver: "me", Approved: true})
+	}, time.Minute)
+	env.ExecuteWorkflow(workflowFn)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var approval testApproval
+	require.NoError(t, env.GetWorkflowResult(&approval))
+	require.Equal(t, testApproval{Approver: "me", Approved: true}, approval)
+}
+
+func TestDefinitionWorkflowHandlerMismatch(t *testing.T) {
+	workflowFn := func(ctx Context) error {
+		return testStatusQuery.SetHandler(ctx, func() (int, error) { return 0, nil })
+	}
+
+	var s WorkflowTestSuite
+	env := s.NewTestWorkflowEnvironment()
+	env.ExecuteWorkflow(workflowFn)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.Error(t, env.GetWorkflowError())
+	require.Contains(t, env.GetWorkflowError().Error(), `query "status" handler must return (string, error)`)
+}
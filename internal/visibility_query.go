@@ -0,0 +1,151 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	enumspb "go.temporal.io/api/enums/v1"
+)
+
+type (
+	// VisibilityQuery is a builder of visibility queries for Client.ListWorkflowExecutions, Client.CountWorkflowExecutions
+	// and other List/Scan/Count APIs. Conditions are joined with "and", string values are quoted and escaped. Example:
+	//  query := NewVisibilityQuery().
+	//      WorkflowType("orderWorkflow").
+	//      ExecutionStatus(enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING).
+	//      StartTimeAfter(time.Now().Add(-time.Hour)).
+	//      String()
+	VisibilityQuery struct {
+		conditions []string
+		orderBy    string
+	}
+)
+
+// NewVisibilityQuery creates an empty VisibilityQuery which matches all workflow executions.
+func NewVisibilityQuery() *VisibilityQuery {
+	return &VisibilityQuery{}
+}
+
+// Where adds a condition comparing the attribute to the value using the operator (=, !=, >, >=, <, <=).
+// The attribute can be either a system attribute like WorkflowId or a custom search attribute.
+func (q *VisibilityQuery) Where(attribute string, operator string, value interface{}) *VisibilityQuery {
+	q.conditions = append(q.conditions, fmt.Sprintf("%s %s %s", attribute, operator, formatVisibilityValue(value)))
+	return q
+}
+
+// Between adds a condition matching attribute values between from and to inclusive.
+func (q *VisibilityQuery) Between(attribute string, from, to interface{}) *VisibilityQuery {
+	q.conditions = append(q.conditions, fmt.Sprintf("%s between %s and %s", attribute, formatVisibilityValue(from), formatVisibilityValue(to)))
+	return q
+}
+
+// Raw adds a condition as is. It is put into parentheses so it can contain "or".
+func (q *VisibilityQuery) Raw(condition string) *VisibilityQuery {
+	q.conditions = append(q.conditions, "("+condition+")")
+	return q
+}
+
+// WorkflowID matches workflow executions with the workflow ID.
+func (q *VisibilityQuery) WorkflowID(workflowID string) *VisibilityQuery {
+	return q.Where("WorkflowId", "=", workflowID)
+}
+
+// RunID matches workflow execution with the run ID.
+func (q *VisibilityQuery) RunID(runID string) *VisibilityQuery {
+	return q.Where("RunId", "=", runID)
+}
+
+// WorkflowType matches workflow executions of the workflow type.
+func (q *VisibilityQuery) WorkflowType(workflowType string) *VisibilityQuery {
+	return q.Where("WorkflowType", "=", workflowType)
+}
+
+// ExecutionStatus matches workflow executions with the status.
+func (q *VisibilityQuery) ExecutionStatus(status enumspb.WorkflowExecutionStatus) *VisibilityQuery {
+	return q.Where("ExecutionStatus", "=", status)
+}
+
+// Open matches workflow executions which are not closed yet.
+func (q *VisibilityQuery) Open() *VisibilityQuery {
+	q.conditions = append(q.conditions, "CloseTime = missing")
+	return q
+}
+
+// StartTimeAfter matches workflow executions started after t.
+func (q *VisibilityQuery) StartTimeAfter(t time.Time) *VisibilityQuery {
+	return q.Where("StartTime", ">", t)
+}
+
+// StartTimeBefore matches workflow executions started before t.
+func (q *VisibilityQuery) StartTimeBefore(t time.Time) *VisibilityQuery {
+	return q.Where("StartTime", "<", t)
+}
+
+// CloseTimeBetween matches workflow executions closed between from and to.
+func (q *VisibilityQuery) CloseTimeBetween(from, to time.Time) *VisibilityQuery {
+	return q.Between("CloseTime", from, to)
+}
+
+// OrderBy sets the attribute to sort workflow executions by. It is not supported by ScanWorkflow
+// and CountWorkflow.
+func (q *VisibilityQuery) OrderBy(attribute string, descending bool) *VisibilityQuery {
+	q.orderBy = attribute
+	if descending {
+		q.orderBy += " desc"
+	}
+	return q
+}
+
+// String returns the query to be used in Client.ListWorkflowExecutions or ListWorkflowExecutionsRequest.Query.
+func (q *VisibilityQuery) String() string {
+	query := strings.Join(q.conditions, " and ")
+	if q.orderBy != "" {
+		query = strings.TrimSpace(query + " order by " + q.orderBy)
+	}
+	return query
+}
+
+func formatVisibilityValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return quoteVisibilityString(v)
+	case time.Time:
+		return quoteVisibilityString(v.Format(time.RFC3339Nano))
+	case enumspb.WorkflowExecutionStatus:
+		return quoteVisibilityString(v.String())
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, bool:
+		return fmt.Sprint(v)
+	default:
+		return quoteVisibilityString(fmt.Sprint(v))
+	}
+}
+
+func quoteVisibilityString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return "'" + strings.ReplaceAll(s, "'", `\'`) + "'"
+}
@@ -0,0 +1,58 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	enumspb "go.temporal.io/api/enums/v1"
+)
+
+func TestVisibilityQuery(t *testing.T) {
+	startTime := time.Date(2020, 9, 1, 10, 0, 0, 0, time.UTC)
+	query := NewVisibilityQuery().
+		WorkflowType("orderWorkflow").
+		ExecutionStatus(enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING).
+		StartTimeAfter(startTime).
+		Where("CustomIntField", ">=", 10).
+		Raw("WorkflowId = 'wid1' or WorkflowId = 'wid2'").
+		OrderBy("StartTime", true).
+		String()
+	assert.Equal(t, "WorkflowType = 'orderWorkflow' and ExecutionStatus = 'Running' and "+
+		"StartTime > '2020-09-01T10:00:00Z' and CustomIntField >= 10 and "+
+		"(WorkflowId = 'wid1' or WorkflowId = 'wid2') order by StartTime desc", query)
+}
+
+func TestVisibilityQuery_Empty(t *testing.T) {
+	assert.Equal(t, "", NewVisibilityQuery().String())
+	assert.Equal(t, "order by CloseTime", NewVisibilityQuery().OrderBy("CloseTime", false).String())
+}
+
+func TestVisibilityQuery_Escape(t *testing.T) {
+	query := NewVisibilityQuery().WorkflowID(`it's a \ test`).String()
+	assert.Equal(t, `WorkflowId = 'it\'s a \\ test'`, query)
+}
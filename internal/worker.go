@@ -148,6 +148,10 @@ type (
 		// The chain is instantiated per each replay of a workflow execution
 		WorkflowInterceptorChainFactories []WorkflowInterceptor
 
+		// Optional: Specifies factories used to instantiate activity interceptor chain
+		// The chain is instantiated per each activity task execution. Local activities are not intercepted.
+		ActivityInterceptorChainFactories []ActivityInterceptor
+
 		// Optional: If set to true worker would only handle workflow tasks and local activities.
 		// Non-local activities will not be executed by this worker.
 		// default: false
@@ -159,6 +163,25 @@ type (
 
 		// Optional: If set defines maximum amount of time that workflow task will be allowed to run. Defaults to 1 sec.
 		DeadlockDetectionTimeout time.Duration
+
+		// Optional: If set to true the worker inspects workflow coroutines for native go channel, select and sleep
+		// calls and for goroutines started with the go statement, and fails the workflow task with a non-determinism
+		// error instead of waiting for the deadlock detection timeout. Detection of the go statement requires
+		// Go 1.21 or later. Inspection takes a dump of all goroutines, so it is meant for debugging and testing only.
+		// default: false
+		EnableNativeConcurrencyDetection bool
+
+		// Optional: If set the worker serves HTTP on this address, for example ":9090", while it is running:
+		//  /metrics - SDK metrics in the Prometheus text exposition format. If the client MetricsHandler was created
+		//             with NewPrometheusMetricsHandler it is served as is, otherwise worker metrics are reported to
+		//             both the client MetricsHandler and the endpoint. Metrics of the client itself, such as
+		//             temporal_request, are reported to the client MetricsHandler only, so they are served only in
+		//             the first case.
+		//  /healthz - 503 if a poller failed to poll its task queue several times in a row, 200 otherwise.
+		//  /readyz  - 503 if no workflows or activities are registered or the Temporal server is not reachable,
+		//             200 otherwise.
+		// default: no HTTP server
+		HTTPServerAddress string
 	}
 )
 
@@ -37,6 +37,7 @@ import (
 	failurepb "go.temporal.io/api/failure/v1"
 
 	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/internal/common/metrics"
 	"go.temporal.io/sdk/log"
 )
 
@@ -75,6 +76,15 @@ type (
 		// ReceiveAsyncWithMoreFlag is same as ReceiveAsync with extra return value more to indicate if there could be
 		// more value from the Channel. The more is false when Channel is closed.
 		ReceiveAsyncWithMoreFlag(valuePtr interface{}) (ok bool, more bool)
+
+		// ReceiveWithTimeout blocks until it receives a value or the timeout expires. If a value is received it is
+		// assigned to valuePtr and ok is true. The ok is false when the timeout expires or ctx is canceled before a
+		// value arrives. The more is false when Channel is closed. For example:
+		//  var approval string
+		//  if ok, _ := c.ReceiveWithTimeout(ctx, time.Hour, &approval); !ok {
+		//      return errors.New("approval timed out")
+		//  }
+		ReceiveWithTimeout(ctx Context, timeout time.Duration, valuePtr interface{}) (ok bool, more bool)
 	}
 
 	// Channel must be used instead of native go channel by workflow code.
@@ -102,6 +112,12 @@ type (
 		// The callback is called once per ready future even if Select is called multiple times for the same
 		// Selector instance.
 		AddFuture(future Future, f func(f Future)) Selector
+		// AddTimeout registers a callback function to be called when Select(ctx) has been blocked for the timeout
+		// duration without any other branch becoming eligible. The timeout applies to each Select call separately:
+		// the timer is started only when Select blocks and it is canceled when another branch is picked. The
+		// callback is also called if the Select ctx is canceled. AddTimeout has no effect when the default branch
+		// is registered.
+		AddTimeout(timeout time.Duration, f func()) Selector
 		// AddDefault register callback function to be called if none of other branches matched.
 		// The callback is called when Select(ctx) is called.
 		// When the default branch is registered Select never blocks.
@@ -124,6 +140,40 @@ type (
 		Wait(ctx Context)
 	}
 
+	// Mutex must be used instead of native go sync.Mutex by workflow code. Coroutines waiting for the Mutex
+	// acquire it in the order they called Lock. Use workflow.NewMutex(ctx) method to create a new Mutex instance.
+	Mutex interface {
+		// Lock blocks until the Mutex is acquired. Returns CanceledError if ctx is canceled before the Mutex is
+		// acquired.
+		Lock(ctx Context) error
+		// LockWithTimeout blocks until the Mutex is acquired or the timeout expires. Returns ok equal to false if
+		// timed out and err equal to CanceledError if ctx is canceled.
+		LockWithTimeout(ctx Context, timeout time.Duration) (ok bool, err error)
+		// TryLock acquires the Mutex without blocking. Returns false if the Mutex is held by another coroutine.
+		TryLock(ctx Context) bool
+		// Unlock releases the Mutex. It panics if the Mutex is not locked.
+		Unlock()
+		// IsLocked returns true if the Mutex is currently held.
+		IsLocked() bool
+	}
+
+	// Semaphore is a weighted counting semaphore which must be used by workflow code to limit the number of
+	// coroutines accessing a resource, for example the number of concurrently running activities. Coroutines
+	// waiting for the Semaphore acquire it in the order they called Acquire. Use workflow.NewSemaphore(ctx, n)
+	// method to create a new Semaphore instance.
+	Semaphore interface {
+		// Acquire blocks until n units of the Semaphore are acquired. Returns CanceledError if ctx is canceled
+		// before the units are acquired. Acquire panics if n exceeds the Semaphore size.
+		Acquire(ctx Context, n int64) error
+		// AcquireWithTimeout blocks until n units of the Semaphore are acquired or the timeout expires. Returns ok
+		// equal to false if timed out and err equal to CanceledError if ctx is canceled.
+		AcquireWithTimeout(ctx Context, timeout time.Duration, n int64) (ok bool, err error)
+		// TryAcquire acquires n units of the Semaphore without blocking. Returns false if they are not available.
+		TryAcquire(ctx Context, n int64) bool
+		// Release releases n units of the Semaphore. It panics if more units are released than held.
+		Release(n int64)
+	}
+
 	// Future represents the result of an asynchronous computation.
 	Future interface {
 		// Get blocks until the future is ready. When ready it either returns non nil error or assigns result value to
@@ -363,6 +413,23 @@ func NewWaitGroup(ctx Context) WaitGroup {
 	return &waitGroupImpl{future: f, settable: s}
 }
 
+// NewMutex creates a new Mutex instance.
+func NewMutex(ctx Context) Mutex {
+	state := getState(ctx)
+	state.dispatcher.mutexSequence++
+	return &mutexImpl{semaphore: &semaphoreImpl{name: fmt.Sprintf("mutex-%v", state.dispatcher.mutexSequence), size: 1}}
+}
+
+// NewSemaphore creates a new Semaphore instance with the given size.
+func NewSemaphore(ctx Context, n int64) Semaphore {
+	if n <= 0 {
+		panic("semaphore size must be positive")
+	}
+	state := getState(ctx)
+	state.dispatcher.semaphoreSequence++
+	return &semaphoreImpl{name: fmt.Sprintf("semaphore-%v", state.dispatcher.semaphoreSequence), size: n}
+}
+
 // Go creates a new coroutine. It has similar semantic to goroutine in a context of the workflow.
 func Go(ctx Context, f func(ctx Context)) {
 	state := getState(ctx)
@@ -407,6 +474,33 @@ func (wc *workflowEnvironmentInterceptor) Init(outbound WorkflowOutboundCallsInt
 	return nil
 }
 
+func (wc *workflowEnvironmentInterceptor) HandleSignal(ctx Context, signalName string, arg *commonpb.Payloads) error {
+	eo := getWorkflowEnvOptions(ctx)
+	// We don't want this code to be blocked ever, using sendAsync().
+	ch := eo.getSignalChannel(ctx, signalName).(*channelImpl)
+	ok := ch.SendAsync(arg)
+	if !ok {
+		panic(fmt.Sprintf("Exceeded channel buffer size for signal: %v", signalName))
+	}
+	return nil
+}
+
+func (wc *workflowEnvironmentInterceptor) HandleQuery(ctx Context, queryType string, args *commonpb.Payloads) (*commonpb.Payloads, error) {
+	eo := getWorkflowEnvOptions(ctx)
+	if queryType == updateResultQueryType {
+		return eo.updates.queryResult(ctx, args)
+	}
+	handler, ok := eo.queryHandlers[queryType]
+	if !ok {
+		keys := []string{QueryTypeStackTrace, QueryTypeOpenSessions, updateResultQueryType}
+		for k := range eo.queryHandlers {
+			keys = append(keys, k)
+		}
+		return nil, fmt.Errorf("unknown queryType %v. KnownQueryTypes=%v", queryType, keys)
+	}
+	return handler(args)
+}
+
 // ExecuteActivity requests activity execution in the context of a workflow.
 // Context can be used to pass the settings for this activity.
 // For example: task queue that this need to be routed, timeouts that need to be configured.
@@ -757,6 +851,7 @@ func (wc *workflowEnvironmentInterceptor) ExecuteChildWorkflow(ctx Context, chil
 	options.ContextPropagators = workflowOptionsFromCtx.ContextPropagators
 	options.Memo = workflowOptionsFromCtx.Memo
 	options.SearchAttributes = workflowOptionsFromCtx.SearchAttributes
+	warnInvalidSearchAttributes(env, options.SearchAttributes)
 
 	params := ExecuteWorkflowParams{
 		WorkflowOptions: *options,
@@ -864,14 +959,28 @@ func (wc *workflowEnvironmentInterceptor) GetLogger(ctx Context) log.Logger {
 	return wc.env.GetLogger()
 }
 
-// GetMetricsScope returns a metrics scope to be used in workflow's context
+// GetMetricsHandler returns a metrics handler to be used in workflow's context
+func GetMetricsHandler(ctx Context) MetricsHandler {
+	i := getWorkflowOutboundCallsInterceptor(ctx)
+	return i.GetMetricsHandler(ctx)
+}
+
+func (wc *workflowEnvironmentInterceptor) GetMetricsHandler(ctx Context) MetricsHandler {
+	return wc.env.GetMetricsHandler()
+}
+
+// GetMetricsScope returns a metrics scope to be used in workflow's context,
+// reporting to the metrics handler. Histograms keep their buckets only if the client MetricsHandler was created with
+// NewTallyMetricsHandler, otherwise histogram values are reported as gauges and histogram durations as timers.
+//
+// Deprecated: use GetMetricsHandler instead.
 func GetMetricsScope(ctx Context) tally.Scope {
 	i := getWorkflowOutboundCallsInterceptor(ctx)
 	return i.GetMetricsScope(ctx)
 }
 
 func (wc *workflowEnvironmentInterceptor) GetMetricsScope(ctx Context) tally.Scope {
-	return wc.env.GetMetricsScope()
+	return metrics.NewTallyScope(wc.env.GetMetricsHandler(), wc.env)
 }
 
 // Now returns the current time in UTC. It corresponds to the time when the workflow task is started or replayed.
@@ -1071,6 +1180,7 @@ func (wc *workflowEnvironmentInterceptor) UpsertSearchAttributes(ctx Context, at
 	if _, ok := attributes[TemporalChangeVersion]; ok {
 		return errors.New("TemporalChangeVersion is a reserved key that cannot be set, please use other key")
 	}
+	warnInvalidSearchAttributes(wc.env, attributes)
 	return wc.env.UpsertSearchAttributes(attributes)
 }
 
@@ -1427,7 +1537,7 @@ func (wc *workflowEnvironmentInterceptor) SetQueryHandler(ctx Context, queryType
 // this flag as it is going to break workflow determinism requirement.
 // The only reasonable use case for this flag is to avoid some external actions during replay, like custom logging or
 // metric reporting. Please note that Temporal already provide standard logging/metric via workflow.GetLogger(ctx) and
-// workflow.GetMetricsScope(ctx), and those standard mechanism are replay-aware and it will automatically suppress during
+// workflow.GetMetricsHandler(ctx), and those standard mechanism are replay-aware and it will automatically suppress during
 // replay. Only use this flag if you need custom logging/metrics reporting, for example if you want to log to kafka.
 //
 // Warning! Any action protected by this flag should not fail or if it does fail should ignore that failure or panic
@@ -39,6 +39,7 @@ import (
 
 	"go.temporal.io/api/serviceerror"
 	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/internal/common/metrics"
 	"go.temporal.io/sdk/log"
 )
 
@@ -55,7 +56,7 @@ type (
 	// WorkflowTestSuite is the test suite to run unit tests for workflow/activity.
 	WorkflowTestSuite struct {
 		logger             log.Logger
-		scope              tally.Scope
+		metricsHandler     metrics.Handler
 		contextPropagators []ContextPropagator
 		header             *commonpb.Header
 	}
@@ -145,8 +146,15 @@ func (s *WorkflowTestSuite) GetLogger() log.Logger {
 
 // SetMetricsScope sets the metrics scope for this WorkflowTestSuite. If you don't set scope, test suite will use
 // tally.NoopScope
+// Deprecated: use SetMetricsHandler with NewTallyMetricsHandler instead.
 func (s *WorkflowTestSuite) SetMetricsScope(scope tally.Scope) {
-	s.scope = scope
+	s.metricsHandler = metrics.NewTallyHandler(scope)
+}
+
+// SetMetricsHandler sets the metrics handler for this WorkflowTestSuite. If you don't set handler, test suite will use
+// a handler that does nothing.
+func (s *WorkflowTestSuite) SetMetricsHandler(metricsHandler MetricsHandler) {
+	s.metricsHandler = metricsHandler
 }
 
 // SetContextPropagators sets the context propagators for this WorkflowTestSuite. If you don't set context propagators,
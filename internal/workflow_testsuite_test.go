@@ -32,6 +32,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+
+	"go.temporal.io/sdk/internal/common/metrics"
 )
 
 func TestSetMemoOnStart(t *testing.T) {
@@ -201,3 +203,29 @@ func TestLocalActivityExecutionByActivityNameAliasMissingRegistration(t *testing
 	}, "Hello")
 	require.NotNil(t, env.GetWorkflowError())
 }
+
+func TestDeprecatedMetricsScopeUsesWorkflowClock(t *testing.T) {
+	scope, closer, reporter := metrics.NewTaggedMetricsScope()
+	var s WorkflowTestSuite
+	s.SetMetricsScope(scope)
+	env := s.NewTestWorkflowEnvironment()
+	env.ExecuteWorkflow(func(ctx Context) error {
+		stopwatch := GetMetricsScope(ctx).Timer("workflow-stopwatch").Start()
+		if err := Sleep(ctx, time.Hour); err != nil {
+			return err
+		}
+		stopwatch.Stop()
+		return nil
+	})
+	require.NoError(t, env.GetWorkflowError())
+	require.NoError(t, closer.Close())
+
+	var found bool
+	for _, timer := range reporter.Timers() {
+		if timer.Name() == "workflow-stopwatch" {
+			found = true
+			require.Equal(t, time.Hour, timer.Value())
+		}
+	}
+	require.True(t, found)
+}
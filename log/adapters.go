@@ -0,0 +1,150 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package log
+
+import "fmt"
+
+type (
+	// ZapSugaredLogger is the subset of *zap.SugaredLogger methods used by the zap adapter.
+	// It is declared here so this package doesn't depend on zap.
+	ZapSugaredLogger interface {
+		Debugw(msg string, keysAndValues ...interface{})
+		Infow(msg string, keysAndValues ...interface{})
+		Warnw(msg string, keysAndValues ...interface{})
+		Errorw(msg string, keysAndValues ...interface{})
+	}
+
+	// LogrusEntry is the subset of *logrus.Entry methods used by the logrus adapter.
+	// It is declared here so this package doesn't depend on logrus.
+	LogrusEntry interface {
+		Debug(args ...interface{})
+		Info(args ...interface{})
+		Warn(args ...interface{})
+		Error(args ...interface{})
+	}
+
+	zapAdapter struct {
+		logger  ZapSugaredLogger
+		keyvals []interface{}
+	}
+
+	logrusAdapter struct {
+		withFields func(fields map[string]interface{}) LogrusEntry
+		fields     map[string]interface{}
+	}
+)
+
+// badKey is used as a key for the value without a key at the end of odd length keyvals.
+const badKey = "!BADKEY"
+
+// NewZapAdapter creates Logger which writes to zap sugared logger:
+//  logger := log.NewZapAdapter(zapLogger.Sugar())
+func NewZapAdapter(logger ZapSugaredLogger) Logger {
+	return &zapAdapter{logger: logger}
+}
+
+func (l *zapAdapter) prependKeyvals(keyvals []interface{}) []interface{} {
+	result := make([]interface{}, 0, len(l.keyvals)+len(keyvals))
+	result = append(result, l.keyvals...)
+	return append(result, keyvals...)
+}
+
+// Debug writes message to the log.
+func (l *zapAdapter) Debug(msg string, keyvals ...interface{}) {
+	l.logger.Debugw(msg, l.prependKeyvals(keyvals)...)
+}
+
+// Info writes message to the log.
+func (l *zapAdapter) Info(msg string, keyvals ...interface{}) {
+	l.logger.Infow(msg, l.prependKeyvals(keyvals)...)
+}
+
+// Warn writes message to the log.
+func (l *zapAdapter) Warn(msg string, keyvals ...interface{}) {
+	l.logger.Warnw(msg, l.prependKeyvals(keyvals)...)
+}
+
+// Error writes message to the log.
+func (l *zapAdapter) Error(msg string, keyvals ...interface{}) {
+	l.logger.Errorw(msg, l.prependKeyvals(keyvals)...)
+}
+
+// With returns new logger that prepend every log entry with keyvals.
+func (l *zapAdapter) With(keyvals ...interface{}) Logger {
+	return &zapAdapter{logger: l.logger, keyvals: l.prependKeyvals(keyvals)}
+}
+
+// NewLogrusAdapter creates Logger which writes to logrus. Keyvals are converted to logrus fields.
+// withFields must return an entry with the fields, it is a function so this package doesn't depend on logrus:
+//  logger := log.NewLogrusAdapter(func(fields map[string]interface{}) log.LogrusEntry {
+//      return logrusLogger.WithFields(fields)
+//  })
+func NewLogrusAdapter(withFields func(fields map[string]interface{}) LogrusEntry) Logger {
+	return &logrusAdapter{withFields: withFields}
+}
+
+func (l *logrusAdapter) entry(keyvals []interface{}) LogrusEntry {
+	return l.withFields(l.mergeFields(keyvals))
+}
+
+func (l *logrusAdapter) mergeFields(keyvals []interface{}) map[string]interface{} {
+	fields := make(map[string]interface{}, len(l.fields)+len(keyvals)/2)
+	for k, v := range l.fields {
+		fields[k] = v
+	}
+	for i := 0; i < len(keyvals); i += 2 {
+		if i+1 == len(keyvals) {
+			fields[badKey] = keyvals[i]
+			break
+		}
+		fields[fmt.Sprint(keyvals[i])] = keyvals[i+1]
+	}
+	return fields
+}
+
+// Debug writes message to the log.
+func (l *logrusAdapter) Debug(msg string, keyvals ...interface{}) {
+	l.entry(keyvals).Debug(msg)
+}
+
+// Info writes message to the log.
+func (l *logrusAdapter) Info(msg string, keyvals ...interface{}) {
+	l.entry(keyvals).Info(msg)
+}
+
+// Warn writes message to the log.
+func (l *logrusAdapter) Warn(msg string, keyvals ...interface{}) {
+	l.entry(keyvals).Warn(msg)
+}
+
+// Error writes message to the log.
+func (l *logrusAdapter) Error(msg string, keyvals ...interface{}) {
+	l.entry(keyvals).Error(msg)
+}
+
+// With returns new logger that adds keyvals to fields of every log entry.
+func (l *logrusAdapter) With(keyvals ...interface{}) Logger {
+	return &logrusAdapter{withFields: l.withFields, fields: l.mergeFields(keyvals)}
+}
@@ -0,0 +1,106 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package log
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testZapLogger struct {
+	lines []string
+}
+
+func (l *testZapLogger) Debugw(msg string, keysAndValues ...interface{}) {
+	l.write("debug", msg, keysAndValues)
+}
+
+func (l *testZapLogger) Infow(msg string, keysAndValues ...interface{}) {
+	l.write("info", msg, keysAndValues)
+}
+
+func (l *testZapLogger) Warnw(msg string, keysAndValues ...interface{}) {
+	l.write("warn", msg, keysAndValues)
+}
+
+func (l *testZapLogger) Errorw(msg string, keysAndValues ...interface{}) {
+	l.write("error", msg, keysAndValues)
+}
+
+func (l *testZapLogger) write(level, msg string, keysAndValues []interface{}) {
+	l.lines = append(l.lines, fmt.Sprint(level, " ", msg, " ", keysAndValues))
+}
+
+type testLogrusEntry struct {
+	fields map[string]interface{}
+	lines  *[]string
+}
+
+func (e *testLogrusEntry) Debug(args ...interface{}) { e.write("debug", args) }
+func (e *testLogrusEntry) Info(args ...interface{})  { e.write("info", args) }
+func (e *testLogrusEntry) Warn(args ...interface{})  { e.write("warn", args) }
+func (e *testLogrusEntry) Error(args ...interface{}) { e.write("error", args) }
+
+func (e *testLogrusEntry) write(level string, args []interface{}) {
+	*e.lines = append(*e.lines, fmt.Sprint(level, " ", fmt.Sprint(args...), " ", e.fields))
+}
+
+func TestZapAdapter(t *testing.T) {
+	zapLogger := &testZapLogger{}
+	logger := NewZapAdapter(zapLogger)
+	logger.Debug("m1", "k1", 1)
+	workflowLogger := With(logger, "WorkflowID", "wid")
+	workflowLogger.Info("m2")
+	With(workflowLogger, "Attempt", 2).Warn("m3", "k3", "v3")
+	logger.Error("m4")
+
+	assert.Equal(t, []string{
+		"debug m1 [k1 1]",
+		"info m2 [WorkflowID wid]",
+		"warn m3 [WorkflowID wid Attempt 2 k3 v3]",
+		"error m4 []",
+	}, zapLogger.lines)
+}
+
+func TestLogrusAdapter(t *testing.T) {
+	var lines []string
+	logger := NewLogrusAdapter(func(fields map[string]interface{}) LogrusEntry {
+		return &testLogrusEntry{fields: fields, lines: &lines}
+	})
+	logger.Debug("m1", "k1", 1)
+	workflowLogger := With(logger, "WorkflowID", "wid")
+	workflowLogger.Info("m2", "odd")
+	With(workflowLogger, "Attempt", 2).Warn("m3", "WorkflowID", "override")
+	logger.Error("m4")
+
+	assert.Equal(t, []string{
+		"debug m1 map[k1:1]",
+		"info m2 map[!BADKEY:odd WorkflowID:wid]",
+		"warn m3 map[Attempt:2 WorkflowID:override]",
+		"error m4 map[]",
+	}, lines)
+}
@@ -48,7 +48,15 @@ func newWithLogger(logger Logger, keyvals ...interface{}) *withLogger {
 }
 
 func (l *withLogger) prependKeyvals(keyvals []interface{}) []interface{} {
-	return append(l.keyvals, keyvals...)
+	// Copy to not share the backing array of l.keyvals between concurrent log calls.
+	result := make([]interface{}, 0, len(l.keyvals)+len(keyvals))
+	result = append(result, l.keyvals...)
+	return append(result, keyvals...)
+}
+
+// With returns new logger that prepend every log entry with keyvals in addition to already prepended ones.
+func (l *withLogger) With(keyvals ...interface{}) Logger {
+	return newWithLogger(l.logger, l.prependKeyvals(keyvals)...)
 }
 
 // Debug writes message to the log.
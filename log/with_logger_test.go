@@ -37,3 +37,23 @@ func TestWithLogger(t *testing.T) {
 	allKeys := wl.prependKeyvals([]interface{}{"p4", 4})
 	assert.Equal(t, []interface{}{"p1", 1, "p2", "v2", "p4", 4}, allKeys)
 }
+
+func TestWithLoggerWith(t *testing.T) {
+	var lines []string
+	logger := NewLogrusAdapter(func(fields map[string]interface{}) LogrusEntry {
+		return &testLogrusEntry{fields: fields, lines: &lines}
+	})
+	wl := newWithLogger(logger, "p1", 1)
+	nested := With(wl, "p2", 2)
+	assert.IsType(t, (*withLogger)(nil), nested)
+	nested.Info("m1")
+	wl.Info("m2")
+	assert.Equal(t, []string{"info m1 map[p1:1 p2:2]", "info m2 map[p1:1]"}, lines)
+
+	// prepended keyvals must not be shared between loggers
+	base := &withLogger{keyvals: make([]interface{}, 2, 10)}
+	a := base.prependKeyvals([]interface{}{"a", 1})
+	b := base.prependKeyvals([]interface{}{"b", 2})
+	assert.Equal(t, "a", a[2])
+	assert.Equal(t, "b", b[2])
+}
@@ -109,6 +109,27 @@ func (_m *Client) CountWorkflow(ctx context.Context, request *workflowservice.Co
 	return r0, r1
 }
 
+// CountWorkflowExecutions provides a mock function with given fields: ctx, query
+func (_m *Client) CountWorkflowExecutions(ctx context.Context, query string) (int64, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = rf(ctx, query)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DescribeTaskQueue provides a mock function with given fields: ctx, taskqueue, taskqueueType
 func (_m *Client) DescribeTaskQueue(ctx context.Context, taskqueue string, taskqueueType enumspb.TaskQueueType) (*workflowservice.DescribeTaskQueueResponse, error) {
 	ret := _m.Called(ctx, taskqueue, taskqueueType)
@@ -155,6 +176,29 @@ func (_m *Client) DescribeWorkflowExecution(ctx context.Context, workflowID stri
 	return r0, r1
 }
 
+// ExecuteBatchOperation provides a mock function with given fields: ctx, options
+func (_m *Client) ExecuteBatchOperation(ctx context.Context, options client.BatchOperationOptions) (*client.BatchOperationReport, error) {
+	ret := _m.Called(ctx, options)
+
+	var r0 *client.BatchOperationReport
+	if rf, ok := ret.Get(0).(func(context.Context, client.BatchOperationOptions) *client.BatchOperationReport); ok {
+		r0 = rf(ctx, options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*client.BatchOperationReport)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, client.BatchOperationOptions) error); ok {
+		r1 = rf(ctx, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ExecuteWorkflow provides a mock function with given fields: ctx, options, workflow, args
 func (_m *Client) ExecuteWorkflow(ctx context.Context, options client.StartWorkflowOptions, workflow interface{}, args ...interface{}) (client.WorkflowRun, error) {
 	var _ca []interface{}
@@ -328,6 +372,22 @@ func (_m *Client) ListArchivedWorkflow(ctx context.Context, request *workflowser
 	return r0, r1
 }
 
+// ListWorkflowExecutions provides a mock function with given fields: ctx, options
+func (_m *Client) ListWorkflowExecutions(ctx context.Context, options client.ListWorkflowOptions) client.WorkflowExecutionIterator {
+	ret := _m.Called(ctx, options)
+
+	var r0 client.WorkflowExecutionIterator
+	if rf, ok := ret.Get(0).(func(context.Context, client.ListWorkflowOptions) client.WorkflowExecutionIterator); ok {
+		r0 = rf(ctx, options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(client.WorkflowExecutionIterator)
+		}
+	}
+
+	return r0
+}
+
 // QueryWorkflow provides a mock function with given fields: ctx, workflowID, runID, queryType, args
 func (_m *Client) QueryWorkflow(ctx context.Context, workflowID string, runID string, queryType string, args ...interface{}) (converter.EncodedValue, error) {
 	var _ca []interface{}
@@ -490,6 +550,55 @@ func (_m *Client) TerminateWorkflow(ctx context.Context, workflowID string, runI
 	return r0
 }
 
+// UpdateWorkflow provides a mock function with given fields: ctx, workflowID, runID, updateName, args
+func (_m *Client) UpdateWorkflow(ctx context.Context, workflowID string, runID string, updateName string, args ...interface{}) (converter.EncodedValue, error) {
+	var _ca []interface{}
+	_ca = append(_ca, ctx, workflowID, runID, updateName)
+	_ca = append(_ca, args...)
+	ret := _m.Called(_ca...)
+
+	var r0 converter.EncodedValue
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, ...interface{}) converter.EncodedValue); ok {
+		r0 = rf(ctx, workflowID, runID, updateName, args...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(converter.EncodedValue)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, ...interface{}) error); ok {
+		r1 = rf(ctx, workflowID, runID, updateName, args...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateWorkflowWithOptions provides a mock function with given fields: ctx, request
+func (_m *Client) UpdateWorkflowWithOptions(ctx context.Context, request *client.UpdateWorkflowWithOptionsRequest) (converter.EncodedValue, error) {
+	ret := _m.Called(ctx, request)
+
+	var r0 converter.EncodedValue
+	if rf, ok := ret.Get(0).(func(context.Context, *client.UpdateWorkflowWithOptionsRequest) converter.EncodedValue); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(converter.EncodedValue)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *client.UpdateWorkflowWithOptionsRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ResetWorkflowExecution provides a mock function with given fields: request
 func (_m *Client) ResetWorkflowExecution(ctx context.Context, request *workflowservice.ResetWorkflowExecutionRequest) (*workflowservice.ResetWorkflowExecutionResponse, error) {
 	ret := _m.Called(ctx, request)
@@ -27,8 +27,13 @@ package mocks
 
 import (
 	context "context"
+	time "time"
 
 	mock "github.com/stretchr/testify/mock"
+	enumspb "go.temporal.io/api/enums/v1"
+
+	client "go.temporal.io/sdk/client"
+	converter "go.temporal.io/sdk/converter"
 )
 
 // WorkflowRun is an autogenerated mock type for the WorkflowRun type
@@ -36,6 +41,59 @@ type WorkflowRun struct {
 	mock.Mock
 }
 
+// Cancel provides a mock function with given fields: ctx
+func (_m *WorkflowRun) Cancel(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Describe provides a mock function with given fields: ctx
+func (_m *WorkflowRun) Describe(ctx context.Context) (*client.WorkflowExecutionDescription, error) {
+	ret := _m.Called(ctx)
+
+	var r0 *client.WorkflowExecutionDescription
+	if rf, ok := ret.Get(0).(func(context.Context) *client.WorkflowExecutionDescription); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*client.WorkflowExecutionDescription)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FollowContinueAsNew provides a mock function with given fields:
+func (_m *WorkflowRun) FollowContinueAsNew() client.WorkflowRun {
+	ret := _m.Called()
+
+	var r0 client.WorkflowRun
+	if rf, ok := ret.Get(0).(func() client.WorkflowRun); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(client.WorkflowRun)
+		}
+	}
+
+	return r0
+}
+
 // Get provides a mock function with given fields: ctx, valuePtr
 func (_m *WorkflowRun) Get(ctx context.Context, valuePtr interface{}) error {
 	ret := _m.Called(ctx, valuePtr)
@@ -50,6 +108,22 @@ func (_m *WorkflowRun) Get(ctx context.Context, valuePtr interface{}) error {
 	return r0
 }
 
+// GetHistory provides a mock function with given fields: ctx, isLongPoll, filterType
+func (_m *WorkflowRun) GetHistory(ctx context.Context, isLongPoll bool, filterType enumspb.HistoryEventFilterType) client.HistoryEventIterator {
+	ret := _m.Called(ctx, isLongPoll, filterType)
+
+	var r0 client.HistoryEventIterator
+	if rf, ok := ret.Get(0).(func(context.Context, bool, enumspb.HistoryEventFilterType) client.HistoryEventIterator); ok {
+		r0 = rf(ctx, isLongPoll, filterType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(client.HistoryEventIterator)
+		}
+	}
+
+	return r0
+}
+
 // GetID provides a mock function with given fields:
 func (_m *WorkflowRun) GetID() string {
 	ret := _m.Called()
@@ -77,3 +151,74 @@ func (_m *WorkflowRun) GetRunID() string {
 
 	return r0
 }
+
+// GetWithTimeout provides a mock function with given fields: ctx, timeout, valuePtr
+func (_m *WorkflowRun) GetWithTimeout(ctx context.Context, timeout time.Duration, valuePtr interface{}) error {
+	ret := _m.Called(ctx, timeout, valuePtr)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration, interface{}) error); ok {
+		r0 = rf(ctx, timeout, valuePtr)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Query provides a mock function with given fields: ctx, queryType, args
+func (_m *WorkflowRun) Query(ctx context.Context, queryType string, args ...interface{}) (converter.EncodedValue, error) {
+	var _ca []interface{}
+	_ca = append(_ca, ctx, queryType)
+	_ca = append(_ca, args...)
+	ret := _m.Called(_ca...)
+
+	var r0 converter.EncodedValue
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...interface{}) converter.EncodedValue); ok {
+		r0 = rf(ctx, queryType, args...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(converter.EncodedValue)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, ...interface{}) error); ok {
+		r1 = rf(ctx, queryType, args...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Signal provides a mock function with given fields: ctx, signalName, arg
+func (_m *WorkflowRun) Signal(ctx context.Context, signalName string, arg interface{}) error {
+	ret := _m.Called(ctx, signalName, arg)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, interface{}) error); ok {
+		r0 = rf(ctx, signalName, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Terminate provides a mock function with given fields: ctx, reason, details
+func (_m *WorkflowRun) Terminate(ctx context.Context, reason string, details ...interface{}) error {
+	var _ca []interface{}
+	_ca = append(_ca, ctx, reason)
+	_ca = append(_ca, details...)
+	ret := _m.Called(_ca...)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...interface{}) error); ok {
+		r0 = rf(ctx, reason, details...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
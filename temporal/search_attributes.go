@@ -0,0 +1,106 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package temporal
+
+import (
+	commonpb "go.temporal.io/api/common/v1"
+
+	"go.temporal.io/sdk/internal"
+)
+
+type (
+	// SearchAttributeKey is a typed search attribute key.
+	SearchAttributeKey = internal.SearchAttributeKey
+
+	// StringKey is a key of a search attribute of String (full text) type.
+	StringKey = internal.StringKey
+
+	// KeywordKey is a key of a search attribute of Keyword type.
+	KeywordKey = internal.KeywordKey
+
+	// IntKey is a key of a search attribute of Int type.
+	IntKey = internal.IntKey
+
+	// DoubleKey is a key of a search attribute of Double type.
+	DoubleKey = internal.DoubleKey
+
+	// BoolKey is a key of a search attribute of Bool type.
+	BoolKey = internal.BoolKey
+
+	// DatetimeKey is a key of a search attribute of Datetime type.
+	DatetimeKey = internal.DatetimeKey
+
+	// SearchAttributeUpdate is a typed value of a search attribute created by ValueSet method of a key.
+	SearchAttributeUpdate = internal.SearchAttributeUpdate
+
+	// TypedSearchAttributes provides typed access to search attributes of a workflow execution.
+	TypedSearchAttributes = internal.TypedSearchAttributes
+)
+
+// NewStringKey creates a key of a search attribute of String type.
+func NewStringKey(name string) StringKey {
+	return internal.NewStringKey(name)
+}
+
+// NewKeywordKey creates a key of a search attribute of Keyword type.
+func NewKeywordKey(name string) KeywordKey {
+	return internal.NewKeywordKey(name)
+}
+
+// NewIntKey creates a key of a search attribute of Int type.
+func NewIntKey(name string) IntKey {
+	return internal.NewIntKey(name)
+}
+
+// NewDoubleKey creates a key of a search attribute of Double type.
+func NewDoubleKey(name string) DoubleKey {
+	return internal.NewDoubleKey(name)
+}
+
+// NewBoolKey creates a key of a search attribute of Bool type.
+func NewBoolKey(name string) BoolKey {
+	return internal.NewBoolKey(name)
+}
+
+// NewDatetimeKey creates a key of a search attribute of Datetime type.
+func NewDatetimeKey(name string) DatetimeKey {
+	return internal.NewDatetimeKey(name)
+}
+
+// NewSearchAttributes creates search attributes from typed values to be used as StartWorkflowOptions.SearchAttributes,
+// ChildWorkflowOptions.SearchAttributes or passed to workflow.UpsertSearchAttributes.
+//  searchAttributes := temporal.NewSearchAttributes(
+//  	temporal.NewKeywordKey("CustomKeywordField").ValueSet("seattle"),
+//  	temporal.NewIntKey("CustomIntField").ValueSet(2),
+//  )
+func NewSearchAttributes(updates ...SearchAttributeUpdate) map[string]interface{} {
+	return internal.NewSearchAttributes(updates...)
+}
+
+// NewTypedSearchAttributes creates TypedSearchAttributes from encoded search attributes, for example
+// WorkflowExecutionInfo.SearchAttributes returned by DescribeWorkflowExecution.
+func NewTypedSearchAttributes(attributes *commonpb.SearchAttributes) TypedSearchAttributes {
+	return internal.NewTypedSearchAttributes(attributes)
+}
@@ -0,0 +1,67 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package temporal
+
+import (
+	"go.temporal.io/sdk/internal"
+)
+
+type (
+	// SignalDefinition declares a signal by its name and argument type. It is shared by the workflow code which
+	// receives the signal and the client or workflow code which sends it.
+	SignalDefinition = internal.SignalDefinition
+
+	// QueryDefinition declares a query by its name, argument type and result type. It is shared by the workflow code
+	// which handles the query and the client code which sends it.
+	QueryDefinition = internal.QueryDefinition
+)
+
+// NewSignalDefinition creates a definition of a signal. The argument type is declared by a typed nil pointer. Pass
+// nil for a signal without argument.
+//  var ApproveSignal = temporal.NewSignalDefinition("approve", (*Approval)(nil))
+//
+//  // workflow code
+//  var approval Approval
+//  ApproveSignal.Receive(ctx, &approval)
+//
+//  // client code
+//  err := ApproveSignal.SignalWorkflow(ctx, c, workflowID, "", Approval{Approver: "me"})
+func NewSignalDefinition(name string, argPtr interface{}) SignalDefinition {
+	return internal.NewSignalDefinition(name, argPtr)
+}
+
+// NewQueryDefinition creates a definition of a query. The argument and the result types are declared by typed nil
+// pointers. Pass nil as argPtr for a query without argument.
+//  var StatusQuery = temporal.NewQueryDefinition("status", nil, (*string)(nil))
+//
+//  // workflow code
+//  err := StatusQuery.SetHandler(ctx, func() (string, error) { return status, nil })
+//
+//  // client code
+//  var status string
+//  err := StatusQuery.QueryWorkflow(ctx, c, workflowID, "", nil, &status)
+func NewQueryDefinition(name string, argPtr interface{}, resultPtr interface{}) QueryDefinition {
+	return internal.NewQueryDefinition(name, argPtr, resultPtr)
+}
@@ -51,6 +51,7 @@ type AsyncBindingsTestSuite struct {
 	worker        worker.Worker
 	taskQueueName string
 	seq           int64
+	devServer     *devServer
 }
 
 func SimplestWorkflow(ctx workflow.Context) error {
@@ -65,6 +66,10 @@ func (ts *AsyncBindingsTestSuite) SetupSuite() {
 	ts.Assertions = require.New(ts.T())
 	ts.config = NewConfig()
 	var err error
+	if ts.config.ServiceAddr == "" {
+		ts.devServer, ts.config.ServiceAddr, err = startDevServer()
+		ts.NoError(err)
+	}
 	ts.client, err = client.NewClient(client.Options{
 		HostPort:  ts.config.ServiceAddr,
 		Namespace: namespace,
@@ -88,6 +93,9 @@ func (ts *AsyncBindingsTestSuite) registerNamespace() {
 		return
 	}
 	ts.NoError(err)
+	if ts.devServer != nil {
+		return
+	}
 	time.Sleep(namespaceCacheRefreshInterval) // wait for namespace cache refresh on temporal-server
 	// bellow is used to guarantee namespace is ready
 	var dummyReturn string
@@ -146,6 +154,9 @@ func (ts *AsyncBindingsTestSuite) startWorkflowOptions(wfID string) client.Start
 func (ts *AsyncBindingsTestSuite) TearDownSuite() {
 	ts.Assertions = require.New(ts.T())
 	ts.client.Close()
+	if ts.devServer != nil {
+		ts.devServer.stop()
+	}
 }
 
 func (ts *AsyncBindingsTestSuite) SetupTest() {
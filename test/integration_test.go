@@ -71,6 +71,7 @@ type IntegrationTestSuite struct {
 	trafficController  *test.SimpleTrafficController
 	metricsScopeCloser io.Closer
 	metricsReporter    *metrics.CapturingStatsReporter
+	devServer          *devServer
 }
 
 const (
@@ -91,12 +92,27 @@ func (ts *IntegrationTestSuite) SetupSuite() {
 	ts.config = NewConfig()
 	ts.activities = newActivities()
 	ts.workflows = &Workflows{}
+	if ts.config.ServiceAddr == "" {
+		var err error
+		ts.devServer, ts.config.ServiceAddr, err = startDevServer()
+		ts.NoError(err)
+	}
 	ts.NoError(WaitForTCP(time.Minute, ts.config.ServiceAddr))
 	ts.registerNamespace()
 }
 
+// skipOnDevServer skips the test if the suite runs against the dev server which doesn't support the tested feature.
+func (ts *IntegrationTestSuite) skipOnDevServer(reason string) {
+	if ts.devServer != nil {
+		ts.T().Skip("not supported by the dev server: " + reason)
+	}
+}
+
 func (ts *IntegrationTestSuite) TearDownSuite() {
 	ts.Assertions = require.New(ts.T())
+	if ts.devServer != nil {
+		ts.devServer.stop()
+	}
 
 	// allow the pollers to stop, and ensure there are no goroutine leaks.
 	// this will wait for up to 1 minute for leaks to subside, but exit relatively quickly if possible.
@@ -233,6 +249,7 @@ func (ts *IntegrationTestSuite) TestDeadlockDetectionViaLocalActivity() {
 }
 
 func (ts *IntegrationTestSuite) TestActivityRetryOnError() {
+	ts.skipOnDevServer("retry backoff is skipped, so the metrics are not reported yet when they are asserted")
 	var expected []string
 	err := ts.executeWorkflow("test-activity-retry-on-error", ts.workflows.ActivityRetryOnError, &expected)
 	ts.NoError(err)
@@ -249,6 +266,7 @@ func (ts *IntegrationTestSuite) TestActivityRetryOnError() {
 }
 
 func (ts *IntegrationTestSuite) TestActivityNotRegisteredRetry() {
+	ts.skipOnDevServer("retry backoff is skipped, so the metrics are not reported yet when they are asserted")
 	var expected string
 	err := ts.executeWorkflow("test-activity-retry-on-error", ts.workflows.CallUnregisteredActivityRetry, &expected)
 	ts.NoError(err)
@@ -260,6 +278,7 @@ func (ts *IntegrationTestSuite) TestActivityNotRegisteredRetry() {
 }
 
 func (ts *IntegrationTestSuite) TestActivityRetryOnTimeoutStableError() {
+	ts.skipOnDevServer("retry backoff is skipped while the worker still runs the timed out attempt")
 	var expected []string
 	err := ts.executeWorkflow("test-activity-retry-on-timeout-stable-error", ts.workflows.RetryTimeoutStableErrorWorkflow, &expected)
 	ts.Nil(err)
@@ -273,6 +292,7 @@ func (ts *IntegrationTestSuite) TestActivityRetryOptionsChange() {
 }
 
 func (ts *IntegrationTestSuite) TestActivityRetryOnStartToCloseTimeout() {
+	ts.skipOnDevServer("retry backoff is skipped while the worker still runs the timed out attempt")
 	var expected []string
 	err := ts.executeWorkflow(
 		"test-activity-retry-on-start2close-timeout",
@@ -285,6 +305,7 @@ func (ts *IntegrationTestSuite) TestActivityRetryOnStartToCloseTimeout() {
 }
 
 func (ts *IntegrationTestSuite) TestActivityRetryOnHBTimeout() {
+	ts.skipOnDevServer("retry backoff is skipped while the worker still runs the timed out attempt")
 	var expected []string
 	err := ts.executeWorkflow("test-activity-retry-on-hbtimeout", ts.workflows.ActivityRetryOnHBTimeout, &expected)
 	ts.NoError(err)
@@ -350,6 +371,7 @@ func (ts *IntegrationTestSuite) TestCancellation() {
 }
 
 func (ts *IntegrationTestSuite) TestCascadingCancellation() {
+	ts.skipOnDevServer("time skipping completes the workflow before it is canceled")
 	workflowID := "test-cascading-cancellation-" + uuid.New()
 	childWorkflowID := workflowID + "-child"
 	ctx, cancel := context.WithTimeout(context.Background(), ctxTimeout)
@@ -564,12 +586,14 @@ func (ts *IntegrationTestSuite) TestWorkflowIDReuseAllowDuplicate() {
 }
 
 func (ts *IntegrationTestSuite) TestChildWFRetryOnError() {
+	ts.skipOnDevServer("workflow retries")
 	err := ts.executeWorkflow("test-childwf-retry-on-error", ts.workflows.ChildWorkflowRetryOnError, nil)
 	ts.Error(err)
 	ts.EqualValues([]string{"toUpper", "toUpper", "toUpper"}, ts.activities.invoked())
 }
 
 func (ts *IntegrationTestSuite) TestChildWFRetryOnTimeout() {
+	ts.skipOnDevServer("workflow retries")
 	err := ts.executeWorkflow("test-childwf-retry-on-timeout", ts.workflows.ChildWorkflowRetryOnTimeout, nil)
 	ts.Error(err)
 	ts.EqualValues([]string{"sleep", "sleep", "sleep"}, ts.activities.invoked())
@@ -733,6 +757,7 @@ func (ts *IntegrationTestSuite) TestCancelActivityImmediately() {
 }
 
 func (ts *IntegrationTestSuite) TestCancelMultipleCommandsOverMultipleTasks() {
+	ts.skipOnDevServer("time skipping completes the workflow before it is canceled")
 	ctx, cancel := context.WithTimeout(context.Background(), ctxTimeout)
 	defer cancel()
 	run, err := ts.client.ExecuteWorkflow(ctx,
@@ -933,6 +958,7 @@ func (ts *IntegrationTestSuite) TestContextPropagator() {
 const CronWorkflowID = "test-cron"
 
 func (ts *IntegrationTestSuite) TestFailurePropagation() {
+	ts.skipOnDevServer("cron schedules")
 	var expected int
 	err := ts.executeWorkflow(CronWorkflowID, ts.workflows.CronWorkflow, &expected)
 	// Workflow asks to be cancelled
@@ -963,6 +989,7 @@ func (ts *IntegrationTestSuite) TestTimerCancellationConcurrentWithOtherCommandD
 }
 
 func (ts *IntegrationTestSuite) TestResetWorkflowExecution() {
+	ts.skipOnDevServer("workflow reset")
 	var originalResult []string
 	err := ts.executeWorkflow("basic-reset-workflow-execution", ts.workflows.Basic, &originalResult)
 	ts.NoError(err)
@@ -999,6 +1026,9 @@ func (ts *IntegrationTestSuite) registerNamespace() {
 		return
 	}
 	ts.NoError(err)
+	if ts.devServer != nil {
+		return
+	}
 	time.Sleep(namespaceCacheRefreshInterval) // wait for namespace cache refresh on temporal-server
 	// bellow is used to guarantee namespace is ready
 	var dummyReturn string
@@ -1134,6 +1164,14 @@ func (t *tracingInboundCallsInterceptor) ExecuteWorkflow(ctx workflow.Context, w
 	return result
 }
 
+func (t *tracingInboundCallsInterceptor) HandleSignal(ctx workflow.Context, signalName string, arg *commonpb.Payloads) error {
+	return t.Next.HandleSignal(ctx, signalName, arg)
+}
+
+func (t *tracingInboundCallsInterceptor) HandleQuery(ctx workflow.Context, queryType string, args *commonpb.Payloads) (*commonpb.Payloads, error) {
+	return t.Next.HandleQuery(ctx, queryType, args)
+}
+
 func (ts *IntegrationTestSuite) assertMetricsCounters(keyValuePairs ...interface{}) {
 	counters := make(map[string]int64, len(ts.metricsReporter.Counts()))
 	for _, counter := range ts.metricsReporter.Counts() {
@@ -33,31 +33,38 @@ import (
 	"strings"
 	"time"
 
-	"go.temporal.io/sdk/client"
+	"google.golang.org/grpc"
+
 	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/testsuite"
 	"go.temporal.io/sdk/workflow"
 )
 
 type (
 	// Config contains the integration test configuration
 	Config struct {
+		// ServiceAddr is the address of Temporal server. If SERVICE_ADDR environment variable is not set, it is empty
+		// and tests run against in-memory dev server (see testsuite.DevServer).
 		ServiceAddr          string
 		maxWorkflowCacheSize int
 		Debug                bool
 	}
 	// context.WithValue need this type instead of basic type string to avoid lint error
 	contextKey string
+
+	// devServer is in-memory dev server served on a loopback listener.
+	devServer struct {
+		server     *testsuite.DevServer
+		grpcServer *grpc.Server
+	}
 )
 
 // NewConfig creates new Config instance
 func NewConfig() Config {
 	cfg := Config{
-		ServiceAddr:          client.DefaultHostPort,
+		ServiceAddr:          getEnvServiceAddr(),
 		maxWorkflowCacheSize: 10000,
 	}
-	if addr := getEnvServiceAddr(); addr != "" {
-		cfg.ServiceAddr = addr
-	}
 	if siz := getEnvCacheSize(); siz != "" {
 		asInt, err := strconv.Atoi(siz)
 		if err != nil {
@@ -104,6 +111,25 @@ func WaitForTCP(timeout time.Duration, addr string) error {
 	}
 }
 
+// startDevServer starts in-memory dev server on a loopback listener and returns its address.
+// It is used to run integration tests without Temporal server.
+func startDevServer() (*devServer, string, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, "", err
+	}
+	s := &devServer{server: testsuite.NewDevServer(testsuite.DevServerOptions{})}
+	s.grpcServer = s.server.NewGRPCServer()
+	go func() { _ = s.grpcServer.Serve(listener) }()
+	return s, listener.Addr().String(), nil
+}
+
+// stop fails pending long polls of the dev server and stops gRPC server.
+func (s *devServer) stop() {
+	s.server.Close()
+	s.grpcServer.Stop()
+}
+
 // keysPropagator propagates the list of keys across a workflow,
 // interpreting the payloads as strings.
 // TODO: BORROWED FROM 'internal' PACKAGE TESTS.
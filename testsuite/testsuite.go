@@ -41,7 +41,20 @@ type (
 
 	// MockCallWrapper is a wrapper to mock.Call. It offers the ability to wait on workflow's clock instead of wall clock.
 	MockCallWrapper = internal.MockCallWrapper
+
+	// DevServer is an in-memory implementation of the Temporal service. Clients created by DevServer.NewClient and
+	// workers created from them run workflows end-to-end without network. DevServer.NewGRPCServer serves it to
+	// clients in other processes. Workflow retries and cron schedules, reset and archival are not supported.
+	DevServer = internal.DevServer
+
+	// DevServerOptions are optional parameters of DevServer.
+	DevServerOptions = internal.DevServerOptions
 )
 
+// NewDevServer creates an in-memory Temporal service with "default" namespace registered.
+func NewDevServer(options DevServerOptions) *DevServer {
+	return internal.NewDevServer(options)
+}
+
 // ErrMockStartChildWorkflowFailed is special error used to indicate the mocked child workflow should fail to start.
 var ErrMockStartChildWorkflowFailed = internal.ErrMockStartChildWorkflowFailed
@@ -58,6 +58,15 @@ type (
 	// WaitGroup is used to wait for a collection of
 	// coroutines to finish
 	WaitGroup = internal.WaitGroup
+
+	// Mutex is used to serialize coroutines which access shared workflow state,
+	// for example signal handlers. Mutex is held until Unlock is called, so it
+	// can protect code which blocks on activities or timers.
+	Mutex = internal.Mutex
+
+	// Semaphore is used to limit the number of coroutines which run concurrently,
+	// for example to cap the number of activities executing at the same time.
+	Semaphore = internal.Semaphore
 )
 
 // Await blocks the calling thread until condition() returns true.
@@ -133,6 +142,51 @@ func NewWaitGroup(ctx Context) WaitGroup {
 	return internal.NewWaitGroup(ctx)
 }
 
+// NewMutex creates a new Mutex instance.
+// Coroutines blocked on the Mutex appear in the __stack_trace query output.
+//  mutex := workflow.NewMutex(ctx)
+//  if err := mutex.Lock(ctx); err != nil {
+//      return err
+//  }
+//  defer mutex.Unlock()
+func NewMutex(ctx Context) Mutex {
+	return internal.NewMutex(ctx)
+}
+
+// NewSemaphore creates a new Semaphore instance with n units available.
+// Coroutines blocked on the Semaphore appear in the __stack_trace query output.
+//  sem := workflow.NewSemaphore(ctx, 10)
+//  if err := sem.Acquire(ctx, 1); err != nil {
+//      return err
+//  }
+//  defer sem.Release(1)
+func NewSemaphore(ctx Context, n int64) Semaphore {
+	return internal.NewSemaphore(ctx, n)
+}
+
+// DeterministicKeys returns the keys of the map m sorted in ascending order.
+// Ranging over a map directly in workflow code is not deterministic, range over the returned keys instead:
+//  for _, k := range workflow.DeterministicKeys(m) {
+//      v := m[k.(string)]
+//      ...
+//  }
+// Keys must be strings, integers, floats or bools. Use DeterministicKeysFunc for other key types.
+func DeterministicKeys(m interface{}) []interface{} {
+	return internal.DeterministicKeys(m)
+}
+
+// DeterministicKeysFunc returns the keys of the map m sorted by the less function,
+// which must define a strict total order over the keys.
+func DeterministicKeysFunc(m interface{}, less func(a, b interface{}) bool) []interface{} {
+	return internal.DeterministicKeysFunc(m, less)
+}
+
+// DeterministicRange calls f for each key and value of the map m in the order returned by DeterministicKeys.
+// Iteration stops when f returns false.
+func DeterministicRange(m interface{}, f func(key, value interface{}) bool) {
+	internal.DeterministicRange(m, f)
+}
+
 // Go creates a new coroutine. It has similar semantic to goroutine in a context of the workflow.
 func Go(ctx Context, f func(ctx Context)) {
 	internal.Go(ctx, f)
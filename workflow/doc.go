@@ -115,7 +115,10 @@ A simplistic way to think about these requirements is that the workflow code:
   - Should do all logging via the logger provided by the Temporal client
     library (i.e. workflow.GetLogger())
   - Should not iterate over maps using range as order of map iteration is
-    randomized
+    randomized, it should instead range over workflow.DeterministicKeys()
+
+Setting worker.Options.EnableNativeConcurrencyDetection while testing makes the worker fail the workflow task when
+workflow code blocks on a native chan, select or time.Sleep, or starts a goroutine with the go statement.
 
 Now that we laid out the ground rules we can take a look at how to implement some common patterns inside workflows.
 
@@ -132,6 +135,11 @@ Coroutine related constructs:
     provides support for both buffered and unbuffered channels
   - workflow.Selector : This is a replacement for the select statement
 
+Map iteration functions:
+
+  - workflow.DeterministicKeys() : This returns map keys sorted for use in place of range over a map
+  - workflow.DeterministicRange() : This is a replacement for range over a map
+
 Time related functions:
 
   - workflow.Now() : This is a replacement for time.Now()
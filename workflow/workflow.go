@@ -29,9 +29,11 @@ import (
 
 	"github.com/uber-go/tally"
 
+	"go.temporal.io/sdk/client"
 	"go.temporal.io/sdk/converter"
 	"go.temporal.io/sdk/internal"
 	"go.temporal.io/sdk/log"
+	"go.temporal.io/sdk/temporal"
 )
 
 type (
@@ -190,7 +192,16 @@ func GetLogger(ctx Context) log.Logger {
 	return internal.GetLogger(ctx)
 }
 
-// GetMetricsScope returns a metrics scope to be used in workflow's context
+// GetMetricsHandler returns a metrics handler to be used in workflow's context
+func GetMetricsHandler(ctx Context) client.MetricsHandler {
+	return internal.GetMetricsHandler(ctx)
+}
+
+// GetMetricsScope returns a metrics scope to be used in workflow's context,
+// reporting to the metrics handler. Histograms keep their buckets only if the client MetricsHandler was created with
+// client.NewTallyMetricsHandler, otherwise histogram values are reported as gauges and histogram durations as timers.
+//
+// Deprecated: use GetMetricsHandler instead.
 func GetMetricsScope(ctx Context) tally.Scope {
 	return internal.GetMetricsScope(ctx)
 }
@@ -389,13 +400,35 @@ func SetQueryHandler(ctx Context, queryType string, handler interface{}) error {
 	return internal.SetQueryHandler(ctx, queryType, handler)
 }
 
+// SetUpdateHandler sets the handler of updates sent by Client.UpdateWorkflow with the given name. The handler must be a
+// function which accepts workflow.Context followed by any number of serializable parameters and returns either an error
+// or a serializable result and an error. Unlike a query handler, an update handler runs as a workflow coroutine, so it
+// can mutate the workflow state, execute activities and block. Each update request is handled in its own coroutine.
+// The result of the handler is returned to the caller of Client.UpdateWorkflow.
+// Handlers must be set before the workflow code blocks for the first time. An update received when no handler is set
+// for its name fails with an unknown update error.
+//  func MyWorkflow(ctx workflow.Context) error {
+//    approved := false
+//    err := workflow.SetUpdateHandler(ctx, "approve", func(ctx workflow.Context, approver string) (string, error) {
+//      approved = true
+//      return "approved by " + approver, nil
+//    })
+//    if err != nil {
+//      return err
+//    }
+//    return workflow.Await(ctx, func() bool { return approved })
+//  }
+func SetUpdateHandler(ctx Context, updateName string, handler interface{}) error {
+	return internal.SetUpdateHandler(ctx, updateName, handler)
+}
+
 // IsReplaying returns whether the current workflow code is replaying.
 //
 // Warning! Never make commands, like schedule activity/childWorkflow/timer or send/wait on future/channel, based on
 // this flag as it is going to break workflow determinism requirement.
 // The only reasonable use case for this flag is to avoid some external actions during replay, like custom logging or
 // metric reporting. Please note that Temporal already provide standard logging/metric via workflow.GetLogger(ctx) and
-// workflow.GetMetricsScope(ctx), and those standard mechanism are replay-aware and it will automatically suppress during
+// workflow.GetMetricsHandler(ctx), and those standard mechanism are replay-aware and it will automatically suppress during
 // replay. Only use this flag if you need custom logging/metrics reporting, for example if you want to log to kafka.
 //
 // Warning! Any action protected by this flag should not fail or if it does fail should ignore that failure or panic
@@ -463,6 +496,19 @@ func UpsertSearchAttributes(ctx Context, attributes map[string]interface{}) erro
 	return internal.UpsertSearchAttributes(ctx, attributes)
 }
 
+// UpsertTypedSearchAttributes is the same as UpsertSearchAttributes but accepts typed values created by
+// temporal.NewKeywordKey("CustomKeywordField").ValueSet("seattle") and the like.
+func UpsertTypedSearchAttributes(ctx Context, updates ...temporal.SearchAttributeUpdate) error {
+	return internal.UpsertTypedSearchAttributes(ctx, updates...)
+}
+
+// GetTypedSearchAttributes returns current search attributes of the workflow execution, including the ones
+// upserted by the workflow. For example:
+//  city, ok := workflow.GetTypedSearchAttributes(ctx).GetKeyword(temporal.NewKeywordKey("CustomKeywordField"))
+func GetTypedSearchAttributes(ctx Context) temporal.TypedSearchAttributes {
+	return internal.GetTypedSearchAttributes(ctx)
+}
+
 // NewContinueAsNewError creates ContinueAsNewError instance
 // If the workflow main function returns this error then the current execution is ended and
 // the new execution with same workflow ID is started automatically with options